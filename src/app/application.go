@@ -2,10 +2,16 @@ package app
 
 import (
 	"fmt"
+	"path/filepath"
+	"time"
 
 	assets "godo/resources"
+	"godo/src/caldav"
+	"godo/src/models"
 	"godo/src/persistence"
+	"godo/src/services"
 	"godo/src/ui"
+	"godo/src/ui/threading"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
@@ -13,9 +19,14 @@ import (
 
 // Application represents the main todo list application
 type Application struct {
-	fyneApp fyne.App
-	window  fyne.Window
-	dataDir string
+	fyneApp    fyne.App
+	window     fyne.Window
+	dataDir    string
+	pomodoro   *services.PomodoroService
+	api        *services.APIServer
+	hooks      *services.Hooks
+	reminders  *services.ReminderService
+	mainWindow *ui.MainWindow
 }
 
 // New creates a new Application instance
@@ -64,10 +75,10 @@ func (a *Application) setWindowIcon() {
 	}
 }
 
-// RunMigration runs the one-shot migration from TXT to YAML format
+// RunMigration upgrades all monthly files (including legacy TXT) to the current schema
 func (a *Application) RunMigration() error {
 	migrator := persistence.NewMonthlyManager(a.dataDir)
-	if err := migrator.MigrateAllToYAML(); err != nil {
+	if err := migrator.MigrateAll(); err != nil {
 		// Migration is non-fatal, just log the error
 		fmt.Printf("Warning: migration failed: %v\n", err)
 	}
@@ -76,12 +87,77 @@ func (a *Application) RunMigration() error {
 
 // CreateMainUI creates and initializes the main user interface
 func (a *Application) CreateMainUI() {
-	dataManager := persistence.NewMonthlyManager(a.dataDir)
 	configManager := persistence.NewConfigManager(a.dataDir)
-	ui.NewMainWindow(a.window, dataManager, configManager)
+	// Scripts in the hooks folder run on changes to todos, whoever makes them
+	a.hooks = services.NewHooks(filepath.Join(a.dataDir, "hooks"))
+	dataManager := services.NewHookedRepository(a.newTodoRepository(configManager), a.hooks)
+	a.pomodoro = services.NewPomodoroService(persistence.NewPomodoroStore(a.dataDir))
+	a.hooks.WatchPomodoro(a.pomodoro)
+	a.api = services.NewAPIServer(dataManager, a.pomodoro)
+	syncer := persistence.NewSyncer(dataManager, a.dataDir)
+	calendar := caldav.NewSyncer(dataManager, a.dataDir)
+	a.mainWindow = ui.NewMainWindow(a.window, dataManager, configManager, a.pomodoro, a.api, syncer, calendar)
+
+	// Resume a session interrupted by quitting; the main window has applied
+	// the saved preset, so intervals started during catch-up use it
+	if err := a.pomodoro.Restore(); err != nil {
+		fmt.Printf("Warning: failed to restore pomodoro session: %v\n", err)
+	}
+	a.pomodoro.Start(time.Second)
+
+	a.reminders = services.NewReminderService(dataManager)
+	a.reminders.SetDispatch(threading.RunOnMainThreadAndWait)
+	a.reminders.SetOnReminder(func(todo *models.TodoItem) {
+		a.hooks.FireTodo(services.HookReminder, todo, nil)
+	})
+	a.reminders.Start(30 * time.Second)
+}
+
+// newTodoRepository returns the configured todo store: a todo.txt file, a
+// folder of Markdown notes, or the month files of the data directory
+func (a *Application) newTodoRepository(configManager *persistence.ConfigManager) persistence.TodoRepository {
+	config, err := configManager.LoadConfig()
+	if err != nil {
+		return persistence.NewMonthlyManager(a.dataDir)
+	}
+	if config.TodoTxt.File != "" {
+		if err := persistence.CheckTodoTxtFile(config.TodoTxt.File); err != nil {
+			fmt.Printf("Warning: not using todo.txt file: %v\n", err)
+			return persistence.NewMonthlyManager(a.dataDir)
+		}
+		return persistence.NewTodoTxtRepository(config.TodoTxt.File)
+	}
+	if config.Markdown.Folder != "" {
+		if err := persistence.CheckMarkdownFolder(config.Markdown.Folder); err != nil {
+			fmt.Printf("Warning: not using Markdown folder: %v\n", err)
+			return persistence.NewMonthlyManager(a.dataDir)
+		}
+		return persistence.NewMarkdownFolderRepository(config.Markdown.Folder)
+	}
+	return persistence.NewMonthlyManager(a.dataDir)
 }
 
 // Run starts the application event loop
 func (a *Application) Run() {
 	a.window.ShowAndRun()
+
+	if a.api != nil {
+		if err := a.api.Stop(); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+	}
+
+	if a.pomodoro != nil {
+		if err := a.pomodoro.Stop(); err != nil {
+			fmt.Printf("Warning: failed to save pomodoro session: %v\n", err)
+		}
+	}
+
+	if a.reminders != nil {
+		a.reminders.Stop()
+	}
+	if a.hooks != nil {
+		// Let the hooks of the last changes finish
+		a.hooks.Stop()
+	}
 }
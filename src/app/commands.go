@@ -0,0 +1,86 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"godo/src/ui/threading"
+	"godo/src/utils"
+)
+
+// CommandUsage describes the command-line arguments
+const CommandUsage = `Usage: godo [--show] [--date YYYY-MM-DD] [--add "todo"]
+
+  --show        bring the window to the front
+  --date DAY    show the given day
+  --add TEXT    open the add form with TEXT as the name
+
+If Go Do is already running, the arguments are passed on to it.`
+
+// Command is what a launch asks the application to do
+type Command struct {
+	Show bool      // Bring the window to the front
+	Date time.Time // Day to show; zero keeps the current one
+	Add  string    // Name for the add form; "" opens no form
+}
+
+// ParseCommand reads a command from command-line arguments
+func ParseCommand(args []string) (Command, error) {
+	flags := flag.NewFlagSet("godo", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	show := flags.Bool("show", false, "")
+	date := flags.String("date", "", "")
+	add := flags.String("add", "", "")
+	if err := flags.Parse(args); err != nil {
+		return Command{}, fmt.Errorf("failed to parse arguments: %w", err)
+	}
+	if flags.NArg() > 0 {
+		return Command{}, fmt.Errorf("failed to parse arguments: unexpected %q", flags.Arg(0))
+	}
+
+	cmd := Command{Show: *show, Add: *add}
+	if *date != "" {
+		day, err := time.ParseInLocation("2006-01-02", *date, time.Local)
+		if err != nil {
+			return Command{}, fmt.Errorf("failed to parse date %q: %w", *date, err)
+		}
+		cmd.Date = day
+	}
+	return cmd, nil
+}
+
+// Execute carries out cmd in the main window
+func (a *Application) Execute(cmd Command) {
+	if a.mainWindow == nil {
+		return
+	}
+	if cmd.Show {
+		a.mainWindow.Raise()
+	}
+	if !cmd.Date.IsZero() {
+		a.mainWindow.ShowDay(cmd.Date)
+	}
+	if cmd.Add != "" {
+		a.mainWindow.QuickAdd(cmd.Add)
+	}
+}
+
+// ListenForCommands executes the arguments of later launches. Forwarded
+// launches always bring the window to the front, like a launch would.
+func (a *Application) ListenForCommands(instanceLock *utils.SingleInstance) {
+	err := instanceLock.Listen(func(args []string) {
+		cmd, err := ParseCommand(args)
+		if err != nil {
+			fmt.Printf("Warning: ignoring forwarded arguments: %v\n", err)
+		}
+		cmd.Show = true
+		threading.RunOnMainThread(func() {
+			a.Execute(cmd)
+		})
+	})
+	if err != nil {
+		fmt.Printf("Warning: later launches cannot forward arguments: %v\n", err)
+	}
+}
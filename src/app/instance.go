@@ -1,6 +1,8 @@
 package app
 
 import (
+	"fmt"
+
 	"godo/src/utils"
 
 	"fyne.io/fyne/v2"
@@ -10,16 +12,28 @@ import (
 
 // CheckSingleInstance checks if another instance of the application is running.
 // Returns the instance lock and a boolean indicating if the lock was acquired.
-// If the lock cannot be acquired, it displays an error dialog and returns nil, false.
-func CheckSingleInstance() (*utils.SingleInstance, bool) {
-	instanceLock := utils.NewSingleInstance("todo-list-app")
+// If the lock cannot be acquired, args are forwarded to the running instance;
+// only if that fails too an error dialog is shown. Either way it returns nil, false.
+func CheckSingleInstance(args []string) (*utils.SingleInstance, bool) {
+	// One instance per user and data directory, so portable copies with
+	// their own data can run side by side
+	dataDir, err := GetDataDirectory()
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+		return nil, false
+	}
+	instanceLock := utils.NewSingleInstanceForDir("todo-list-app", dataDir)
 	locked, err := instanceLock.TryLock()
 	if err != nil {
 		// If we can't check the lock, fail fast
 		return nil, false
 	}
 	if !locked {
-		// Another instance is already running - show dialog
+		// Another instance is already running - let it handle the launch
+		if err = instanceLock.Forward(args); err == nil {
+			return nil, false
+		}
+		fmt.Printf("Warning: %v\n", err)
 		showAlreadyRunningDialog()
 		return nil, false
 	}
@@ -7,7 +7,9 @@ import (
 )
 
 // GetDataDirectory returns the data directory path, creating it if it doesn't exist.
-// The data directory is located in the same directory as the executable.
+// The data directory is located in the same directory as the executable and
+// only its owner may enter it, since the config holds the local API token and
+// the calendar password.
 func GetDataDirectory() (string, error) {
 	execPath, err := os.Executable()
 	if err != nil {
@@ -15,9 +17,13 @@ func GetDataDirectory() (string, error) {
 	}
 
 	dataDir := filepath.Join(filepath.Dir(execPath), "data")
-	if err := os.MkdirAll(dataDir, 0755); err != nil {
+	if err := os.MkdirAll(dataDir, 0700); err != nil {
 		return "", fmt.Errorf("failed to create data directory: %w", err)
 	}
+	// Earlier releases created it open to everyone
+	if err := os.Chmod(dataDir, 0700); err != nil {
+		fmt.Printf("Failed to restrict data directory permissions: %v\n", err)
+	}
 
 	return dataDir, nil
 }
@@ -0,0 +1,272 @@
+// Package caldav syncs Go Do todos with a calendar collection on a CalDAV
+// server: tasks as VTODO and events as VEVENT resources.
+package caldav
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// ErrPreconditionFailed is returned when a resource changed on the server
+// since its ETag was read
+var ErrPreconditionFailed = errors.New("resource changed on the server")
+
+// requestTimeout bounds each request, so an unreachable server cannot hang a sync
+const requestTimeout = 30 * time.Second
+
+// Resource is a calendar object on the server
+type Resource struct {
+	Href string // Path of the resource on the server
+	ETag string
+	Data []byte // iCalendar data
+}
+
+// Client talks WebDAV and CalDAV to one calendar collection
+type Client struct {
+	collection *url.URL
+	username   string
+	password   string
+	http       *http.Client
+}
+
+// NewClient creates a client for the collection at collectionURL, such as
+// https://example.com/dav/calendars/me/work/. An empty username sends no
+// credentials.
+func NewClient(collectionURL, username, password string) (*Client, error) {
+	u, err := url.Parse(collectionURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("failed to use calendar URL %q: not an http(s) URL", collectionURL)
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	return &Client{
+		collection: u,
+		username:   username,
+		password:   password,
+		http:       &http.Client{Timeout: requestTimeout},
+	}, nil
+}
+
+// List returns the ETag of every calendar object in the collection, by href
+func (c *Client) List() (map[string]string, error) {
+	body := `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:"><d:prop><d:getetag/><d:resourcetype/></d:prop></d:propfind>`
+
+	responses, err := c.multistatus("PROPFIND", c.collection.Path, "1", body)
+	if err != nil {
+		return nil, err
+	}
+
+	etags := make(map[string]string)
+	for _, response := range responses {
+		href, prop := c.resolve(response.Href), response.found()
+		if href == c.collection.Path || prop.ResourceType.Collection != nil {
+			continue
+		}
+		etags[href] = prop.ETag
+	}
+	return etags, nil
+}
+
+// Fetch returns the resources at hrefs with their ETags. Hrefs that vanished
+// meanwhile are left out.
+func (c *Client) Fetch(hrefs []string) ([]Resource, error) {
+	if len(hrefs) == 0 {
+		return nil, nil
+	}
+
+	var body strings.Builder
+	body.WriteString(`<?xml version="1.0" encoding="utf-8"?>
+<c:calendar-multiget xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav"><d:prop><d:getetag/><c:calendar-data/></d:prop>`)
+	for _, href := range hrefs {
+		body.WriteString("<d:href>")
+		xml.EscapeText(&body, []byte(href))
+		body.WriteString("</d:href>")
+	}
+	body.WriteString("</c:calendar-multiget>")
+
+	responses, err := c.multistatus("REPORT", c.collection.Path, "1", body.String())
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []Resource
+	for _, response := range responses {
+		prop := response.found()
+		if prop.CalendarData == "" {
+			continue
+		}
+		resources = append(resources, Resource{
+			Href: c.resolve(response.Href),
+			ETag: prop.ETag,
+			Data: []byte(prop.CalendarData),
+		})
+	}
+	return resources, nil
+}
+
+// Put stores data at href. With an etag the resource is only replaced while
+// unchanged; without one it is only created if it does not exist yet. It
+// returns the new ETag, asking the server for it when the reply has none.
+func (c *Client) Put(href string, data []byte, etag string) (string, error) {
+	req, err := c.newRequest(http.MethodPut, href, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "text/calendar; charset=utf-8")
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	} else {
+		req.Header.Set("If-None-Match", "*")
+	}
+
+	resp, err := c.do(req, http.StatusCreated, http.StatusNoContent, http.StatusOK)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	if newETag := resp.Header.Get("ETag"); newETag != "" {
+		return newETag, nil
+	}
+
+	responses, err := c.multistatus("PROPFIND", href, "0",
+		`<?xml version="1.0" encoding="utf-8"?><d:propfind xmlns:d="DAV:"><d:prop><d:getetag/></d:prop></d:propfind>`)
+	if err != nil {
+		return "", err
+	}
+	for _, response := range responses {
+		if c.resolve(response.Href) == href {
+			return response.found().ETag, nil
+		}
+	}
+	return "", fmt.Errorf("failed to read ETag of %s", href)
+}
+
+// Delete removes the resource at href while its ETag is still etag
+func (c *Client) Delete(href, etag string) error {
+	req, err := c.newRequest(http.MethodDelete, href, nil)
+	if err != nil {
+		return err
+	}
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	}
+
+	resp, err := c.do(req, http.StatusNoContent, http.StatusOK, http.StatusNotFound)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Href returns the path of a new resource named after uid, which must be
+// safe to use in a URL path
+func (c *Client) Href(uid string) string {
+	return path.Join(c.collection.Path, uid+".ics")
+}
+
+// davResponse is one response of a WebDAV multistatus reply
+type davResponse struct {
+	Href     string `xml:"DAV: href"`
+	Propstat []struct {
+		Status string  `xml:"DAV: status"`
+		Prop   davProp `xml:"DAV: prop"`
+	} `xml:"DAV: propstat"`
+}
+
+// davProp holds the properties Go Do asks for
+type davProp struct {
+	ETag         string `xml:"DAV: getetag"`
+	ResourceType struct {
+		Collection *struct{} `xml:"DAV: collection"`
+	} `xml:"DAV: resourcetype"`
+	CalendarData string `xml:"urn:ietf:params:xml:ns:caldav calendar-data"`
+}
+
+// found returns the properties the server has; servers list the missing
+// ones in a separate propstat with status 404
+func (r davResponse) found() davProp {
+	for _, propstat := range r.Propstat {
+		if strings.Contains(propstat.Status, " 200 ") {
+			return propstat.Prop
+		}
+	}
+	return davProp{}
+}
+
+// multistatus sends a PROPFIND or REPORT and returns its responses
+func (c *Client) multistatus(method, href, depth, body string) ([]davResponse, error) {
+	req, err := c.newRequest(method, href, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Depth", depth)
+
+	resp, err := c.do(req, http.StatusMultiStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Responses []davResponse `xml:"DAV: response"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to read %s reply: %w", method, err)
+	}
+	return result.Responses, nil
+}
+
+// newRequest creates a request for href on the collection's server
+func (c *Client) newRequest(method, href string, body io.Reader) (*http.Request, error) {
+	target := *c.collection
+	target.Path = href
+	target.RawPath = ""
+	req, err := http.NewRequest(method, target.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s request: %w", method, err)
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	return req, nil
+}
+
+// do sends req and fails unless the reply has one of the expected statuses
+func (c *Client) do(req *http.Request, expected ...int) (*http.Response, error) {
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach calendar server: %w", err)
+	}
+	for _, status := range expected {
+		if resp.StatusCode == status {
+			return resp, nil
+		}
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		return nil, ErrPreconditionFailed
+	}
+	return nil, fmt.Errorf("failed to %s %s: %s", req.Method, req.URL.Path, resp.Status)
+}
+
+// resolve turns an href from the server into a path on the collection's server
+func (c *Client) resolve(href string) string {
+	u, err := c.collection.Parse(href)
+	if err != nil {
+		return href
+	}
+	return u.Path
+}
@@ -0,0 +1,464 @@
+package caldav
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"godo/src/models"
+	"godo/src/utils"
+)
+
+// iCalendar date-time layouts (RFC 5545 section 3.3)
+const (
+	icalDate     = "20060102"
+	icalDateTime = "20060102T150405"
+	icalUTC      = "20060102T150405Z"
+)
+
+// Go Do fields without an iCalendar property travel as extensions, so a
+// todo read back from the server is equal to the one sent
+const (
+	propDone      = "X-GODO-DONE"
+	propStarred   = "X-GODO-STARRED"
+	propOrder     = "X-GODO-ORDER"
+	propEstimate  = "X-GODO-ESTIMATE"
+	propPomodoros = "X-GODO-POMODOROS"
+)
+
+// EncodeTodo returns todo as an iCalendar object with the given UID. Tasks
+// become VTODOs, events VEVENTs.
+func EncodeTodo(todo *models.TodoItem, uid string) []byte {
+	component := "VEVENT"
+	if todo.Kind == 1 {
+		component = "VTODO"
+	}
+
+	var b icalBuilder
+	b.line("BEGIN", nil, "VCALENDAR")
+	b.line("VERSION", nil, "2.0")
+	b.line("PRODID", nil, "-//Go Do//Go Do//EN")
+	b.line("BEGIN", nil, component)
+	b.line("UID", nil, uid)
+	b.line("DTSTAMP", nil, time.Now().UTC().Format(icalUTC))
+	b.text("SUMMARY", todo.Name)
+	if todo.Content != "" {
+		b.text("DESCRIPTION", todo.Content)
+	}
+	if todo.Place != "" {
+		b.text("LOCATION", todo.Place)
+	}
+	if todo.Label != "" {
+		b.text("CATEGORIES", todo.Label)
+	}
+	if priority := levelToPriority(todo.Level); priority > 0 {
+		b.line("PRIORITY", nil, strconv.Itoa(priority))
+	}
+
+	start := todo.TodoTime
+	end := start.Add(time.Duration(todo.Duration) * time.Minute)
+	if todo.AllDay {
+		end = start.AddDate(0, 0, 1)
+	}
+	if component == "VEVENT" {
+		b.time("DTSTART", todo, start)
+		if todo.AllDay || todo.Duration > 0 {
+			b.time("DTEND", todo, end)
+		}
+		if todo.Done {
+			b.line(propDone, nil, "TRUE")
+		}
+	} else {
+		// A task with a length starts at its time and is due at its end
+		if todo.AllDay || todo.Duration > 0 {
+			b.time("DTSTART", todo, start)
+			b.time("DUE", todo, end)
+		} else {
+			b.time("DUE", todo, start)
+		}
+		if todo.Done {
+			b.line("STATUS", nil, "COMPLETED")
+		} else {
+			b.line("STATUS", nil, "NEEDS-ACTION")
+		}
+	}
+
+	if todo.Starred {
+		b.line(propStarred, nil, "TRUE")
+	}
+	if todo.Order != 0 {
+		b.line(propOrder, nil, strconv.Itoa(todo.Order))
+	}
+	if todo.Estimate != 0 {
+		b.line(propEstimate, nil, strconv.Itoa(todo.Estimate))
+	}
+	if todo.Pomodoros != 0 {
+		b.line(propPomodoros, nil, strconv.Itoa(todo.Pomodoros))
+	}
+	if todo.WarnTime > 0 {
+		b.line("BEGIN", nil, "VALARM")
+		b.line("ACTION", nil, "DISPLAY")
+		b.text("DESCRIPTION", todo.Name)
+		b.line("TRIGGER", nil, fmt.Sprintf("-PT%dM", todo.WarnTime))
+		b.line("END", nil, "VALARM")
+	}
+
+	b.line("END", nil, component)
+	b.line("END", nil, "VCALENDAR")
+	return []byte(b.String())
+}
+
+// DecodeTodo reads the first VTODO or VEVENT of an iCalendar object and
+// returns it as a todo together with its UID
+func DecodeTodo(data []byte) (*models.TodoItem, string, error) {
+	props, component, err := parseComponent(string(data))
+	if err != nil {
+		return nil, "", err
+	}
+
+	todo := models.NewTodoItem()
+	if component == "VTODO" {
+		todo.Kind = 1
+	}
+	var uid string
+	var start, end, due *icalProp
+	for i := range props {
+		p := &props[i]
+		switch p.name {
+		case "UID":
+			uid = p.value
+		case "SUMMARY":
+			todo.Name = unescapeText(p.value)
+		case "DESCRIPTION":
+			todo.Content = unescapeText(p.value)
+		case "LOCATION":
+			todo.Place = unescapeText(p.value)
+		case "CATEGORIES":
+			todo.Label = unescapeText(p.value)
+		case "PRIORITY":
+			priority, _ := strconv.Atoi(p.value)
+			todo.Level = priorityToLevel(priority)
+		case "DTSTART":
+			start = p
+		case "DTEND":
+			end = p
+		case "DUE":
+			due = p
+		case "STATUS":
+			todo.Done = todo.Done || strings.EqualFold(p.value, "COMPLETED")
+		case propDone:
+			todo.Done = strings.EqualFold(p.value, "TRUE")
+		case propStarred:
+			todo.Starred = strings.EqualFold(p.value, "TRUE")
+		case propOrder:
+			todo.Order, _ = strconv.Atoi(p.value)
+		case propEstimate:
+			todo.Estimate, _ = strconv.Atoi(p.value)
+		case propPomodoros:
+			todo.Pomodoros, _ = strconv.Atoi(p.value)
+		case "TRIGGER":
+			if minutes, ok := parseTrigger(p.value); ok && todo.WarnTime == 0 {
+				todo.WarnTime = minutes
+			}
+		}
+	}
+	if uid == "" {
+		return nil, "", errors.New("invalid calendar object: no UID")
+	}
+
+	// Events end at DTEND; tasks with a start are due at their end
+	if start == nil {
+		start, due = due, nil
+	}
+	if end == nil {
+		end = due
+	}
+	if start == nil {
+		return nil, uid, errors.New("invalid calendar object: no date")
+	}
+	if err := applyStart(todo, start); err != nil {
+		return nil, uid, err
+	}
+	if end != nil && !todo.AllDay {
+		endTime, _, _, err := parseTime(end)
+		if err != nil {
+			return nil, uid, err
+		}
+		if minutes := int(endTime.Sub(todo.TodoTime) / time.Minute); minutes > 0 {
+			todo.Duration = minutes
+		}
+	}
+	return todo, uid, nil
+}
+
+// applyStart sets the todo's time from DTSTART (or DUE) and how it is zoned
+func applyStart(todo *models.TodoItem, p *icalProp) error {
+	start, zone, kind, err := parseTime(p)
+	if err != nil {
+		return err
+	}
+	switch kind {
+	case timeDate:
+		todo.SetAllDay(start)
+	case timeFloating:
+		todo.SetFloatingTime(start)
+	case timeZoned:
+		todo.SetZonedTime(start, zone)
+	default:
+		// Show UTC times in the local zone, as todos added here are
+		todo.SetZonedTime(start.In(time.Local), "")
+	}
+	return nil
+}
+
+// timeKind is how an iCalendar time is anchored
+type timeKind int
+
+const (
+	timeUTC      timeKind = iota // Ends in Z
+	timeZoned                    // Has a known TZID
+	timeFloating                 // Local wall clock without a zone
+	timeDate                     // VALUE=DATE, a whole day
+)
+
+// parseTime reads a DATE or DATE-TIME property. Floating and date values are
+// returned as wall clock in UTC, the way floating todos store them.
+func parseTime(p *icalProp) (time.Time, string, timeKind, error) {
+	value := p.value
+	if p.params["VALUE"] == "DATE" || len(value) == len(icalDate) {
+		t, err := time.Parse(icalDate, value)
+		return t, "", timeDate, wrapTimeErr(p, err)
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(icalUTC, value)
+		return t, "", timeUTC, wrapTimeErr(p, err)
+	}
+	if zone := p.params["TZID"]; zone != "" {
+		if loc := utils.LoadZone(zone); loc != nil {
+			t, err := time.ParseInLocation(icalDateTime, value, loc)
+			return t, zone, timeZoned, wrapTimeErr(p, err)
+		}
+		// Unknown zone names (Outlook uses Windows names) fall back to local time
+		t, err := time.ParseInLocation(icalDateTime, value, time.Local)
+		return t.UTC(), "", timeUTC, wrapTimeErr(p, err)
+	}
+	t, err := time.Parse(icalDateTime, value)
+	return t, "", timeFloating, wrapTimeErr(p, err)
+}
+
+func wrapTimeErr(p *icalProp, err error) error {
+	if err != nil {
+		return fmt.Errorf("invalid calendar object: bad %s %q", p.name, p.value)
+	}
+	return nil
+}
+
+// parseTrigger reads a reminder like -PT15M, -PT1H or -P1D as minutes before
+// the start. Triggers after the start or at absolute times are ignored.
+func parseTrigger(value string) (int, bool) {
+	if !strings.HasPrefix(value, "-P") {
+		return 0, false
+	}
+	value = strings.TrimPrefix(value, "-P")
+	minutes := 0
+	number := 0
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			number = number*10 + int(r-'0')
+		case r == 'W':
+			minutes += number * 7 * 24 * 60
+			number = 0
+		case r == 'D':
+			minutes += number * 24 * 60
+			number = 0
+		case r == 'H':
+			minutes += number * 60
+			number = 0
+		case r == 'M':
+			minutes += number
+			number = 0
+		case r == 'S', r == 'T':
+			number = 0
+		default:
+			return 0, false
+		}
+	}
+	return minutes, minutes > 0
+}
+
+// levelToPriority maps Go Do's priority levels onto iCalendar's 1 (highest)
+// to 9 (lowest); low priority is sent as undefined (0)
+func levelToPriority(level int) int {
+	switch level {
+	case 3:
+		return 1
+	case 2:
+		return 3
+	case 1:
+		return 5
+	default:
+		return 0
+	}
+}
+
+// priorityToLevel is the inverse of levelToPriority for any iCalendar value
+func priorityToLevel(priority int) int {
+	switch {
+	case priority >= 1 && priority <= 2:
+		return 3
+	case priority >= 3 && priority <= 4:
+		return 2
+	case priority >= 5 && priority <= 6:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// icalProp is one content line: NAME;PARAM=VALUE:value
+type icalProp struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// parseComponent returns the properties of the first VTODO or VEVENT in an
+// iCalendar object, plus the triggers of its alarms
+func parseComponent(data string) ([]icalProp, string, error) {
+	// Unfold continuation lines, which start with a space or tab
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	data = strings.ReplaceAll(data, "\n ", "")
+	data = strings.ReplaceAll(data, "\n\t", "")
+
+	var props []icalProp
+	component := ""
+	nested := 0 // Depth of alarms inside the component
+	for _, line := range strings.Split(data, "\n") {
+		if line == "" {
+			continue
+		}
+		p, ok := parseProp(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case component == "" && p.name == "BEGIN" && (p.value == "VTODO" || p.value == "VEVENT"):
+			component = p.value
+		case component == "":
+			// VCALENDAR and VTIMEZONE lines
+		case p.name == "BEGIN":
+			nested++
+		case p.name == "END" && nested > 0:
+			nested--
+		case p.name == "END" && p.value == component:
+			return props, component, nil
+		case nested == 0 || p.name == "TRIGGER":
+			// Of the alarms only the reminder time matters
+			props = append(props, p)
+		}
+	}
+	if component == "" {
+		return nil, "", errors.New("invalid calendar object: no VTODO or VEVENT")
+	}
+	return nil, "", fmt.Errorf("invalid calendar object: %s not closed", component)
+}
+
+// parseProp splits a content line into name, parameters and value
+func parseProp(line string) (icalProp, bool) {
+	// The value starts at the first colon outside a quoted parameter
+	quoted := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return icalProp{}, false
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	p := icalProp{name: strings.ToUpper(parts[0]), params: make(map[string]string), value: line[colon+1:]}
+	for _, param := range parts[1:] {
+		if key, value, ok := strings.Cut(param, "="); ok {
+			p.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return p, true
+}
+
+// icalBuilder writes folded content lines
+type icalBuilder struct {
+	strings.Builder
+}
+
+// line writes NAME;PARAMS:value, folded at 75 octets as RFC 5545 requires
+func (b *icalBuilder) line(name string, params []string, value string) {
+	line := name
+	for _, param := range params {
+		line += ";" + param
+	}
+	line += ":" + value
+
+	for len(line) > 75 {
+		cut := 75
+		// Never split a UTF-8 sequence
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+	}
+	b.WriteString(line + "\r\n")
+}
+
+// text writes a TEXT property, escaping its value
+func (b *icalBuilder) text(name, value string) {
+	b.line(name, nil, escapeText(value))
+}
+
+// time writes a DATE or DATE-TIME property anchored the way todo is
+func (b *icalBuilder) time(name string, todo *models.TodoItem, t time.Time) {
+	switch {
+	case todo.AllDay:
+		b.line(name, []string{"VALUE=DATE"}, t.Format(icalDate))
+	case todo.Floating:
+		b.line(name, nil, t.Format(icalDateTime))
+	case todo.TimeZone != "" && utils.LoadZone(todo.TimeZone) != nil:
+		b.line(name, []string{"TZID=" + todo.TimeZone}, t.In(utils.LoadZone(todo.TimeZone)).Format(icalDateTime))
+	default:
+		b.line(name, nil, t.UTC().Format(icalUTC))
+	}
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+func unescapeText(s string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped && (r == 'n' || r == 'N'):
+			b.WriteRune('\n')
+			escaped = false
+		case escaped:
+			b.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
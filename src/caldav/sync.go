@@ -0,0 +1,548 @@
+package caldav
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"godo/src/models"
+	"godo/src/persistence"
+	"godo/src/utils"
+)
+
+// stateDirName is the folder inside the data directory holding the sync
+// state of each calendar collection
+const stateDirName = "caldav"
+
+// entry links a todo to its calendar object
+type entry struct {
+	UID  string           `json:"uid"`
+	Href string           `json:"href"`
+	ETag string           `json:"etag"`
+	Todo *models.TodoItem `json:"todo"` // Version both sides had after the last sync
+}
+
+// syncState is what the last sync left behind for one collection
+type syncState struct {
+	Collection string   `json:"collection"`
+	Entries    []*entry `json:"entries"`
+}
+
+// Syncer keeps the todos of the data directory and a calendar collection in
+// step. It remembers the ETag and version of every object it synced, so only
+// objects with a new ETag are downloaded, only todos changed since are
+// uploaded, and a todo changed on both sides is reported as a conflict.
+type Syncer struct {
+	local    persistence.TodoRepository
+	dataDir  string
+	dispatch func(func()) // Runs repository access on the thread owning it
+
+	client    *Client
+	statePath string
+	running   sync.Mutex
+}
+
+// NewSyncer creates a sync engine for the todos in dataDir. It has no server
+// until Configure is called.
+func NewSyncer(local persistence.TodoRepository, dataDir string) *Syncer {
+	return &Syncer{
+		local:    local,
+		dataDir:  dataDir,
+		dispatch: func(fn func()) { fn() },
+	}
+}
+
+// SetDispatch sets how repository access is run. The UI shares the todo
+// cache with the syncer, so it passes a function running on its thread.
+func (s *Syncer) SetDispatch(dispatch func(func())) {
+	s.dispatch = dispatch
+}
+
+// Configure points the syncer at the collection in config; an empty URL
+// turns syncing off. Call it on the dispatch thread.
+func (s *Syncer) Configure(config models.CalDAVConfig) error {
+	if config.URL == "" {
+		s.client, s.statePath = nil, ""
+		return nil
+	}
+	client, err := NewClient(config.URL, config.Username, config.Password)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256([]byte(config.Username + "\n" + client.collection.String()))
+	s.client = client
+	s.statePath = filepath.Join(s.dataDir, stateDirName, fmt.Sprintf("%x.json", sum[:4]))
+	return nil
+}
+
+// HasServer reports whether a collection is configured
+func (s *Syncer) HasServer() bool {
+	return s.client != nil
+}
+
+// Sync exchanges changes with the server. It may run on any goroutine; the
+// repository is only touched through the dispatch function. Conflicts are
+// returned in the report and left alone until passed back with a Choice.
+func (s *Syncer) Sync(resolved []*persistence.SyncConflict) (*persistence.SyncReport, error) {
+	if !s.running.TryLock() {
+		return nil, errors.New("failed to sync with calendar server: a sync is already running")
+	}
+	defer s.running.Unlock()
+
+	var client *Client
+	var statePath string
+	var local map[int64]*models.TodoItem
+	var shared []*models.TodoItem
+	var err error
+	s.dispatch(func() {
+		client, statePath = s.client, s.statePath
+		local, shared, err = s.loadLocal()
+	})
+	if client == nil {
+		return nil, errors.New("failed to sync: no calendar server set")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := loadState(statePath, client.collection.String())
+	if err != nil {
+		return nil, err
+	}
+	etags, err := client.List()
+	if err != nil {
+		return nil, err
+	}
+
+	run := &syncRun{
+		Syncer:    s,
+		client:    client,
+		local:     local,
+		claimed:   make(map[int64]bool),
+		shared:    make(map[int64]bool),
+		decisions: make(map[int64]*persistence.SyncConflict),
+		report:    &persistence.SyncReport{},
+	}
+	for _, todo := range shared {
+		run.shared[todo.TodoTime.UnixNano()] = true
+		run.fail(todo, errShared)
+	}
+	for _, conflict := range resolved {
+		if conflict.Choice != persistence.SyncUndecided {
+			run.decisions[conflict.Time().UnixNano()] = conflict
+		}
+	}
+
+	// Download every object that is new or has a new ETag
+	known := make(map[string]*entry)
+	var changed []string
+	for _, e := range state.Entries {
+		known[e.Href] = e
+	}
+	for href, etag := range etags {
+		if e := known[href]; e == nil || e.ETag != etag {
+			changed = append(changed, href)
+		}
+	}
+	resources, err := client.Fetch(changed)
+	if err != nil {
+		return nil, err
+	}
+	remote := make(map[string]*Resource)
+	for i := range resources {
+		remote[resources[i].Href] = &resources[i]
+	}
+
+	var entries []*entry
+	for _, e := range state.Entries {
+		if run.syncEntry(e, etags, remote) {
+			entries = append(entries, e)
+		}
+	}
+	for _, href := range changed {
+		if known[href] == nil {
+			if e := run.addRemote(href, remote[href]); e != nil {
+				entries = append(entries, e)
+			}
+		}
+	}
+	entries = append(entries, run.pushNew()...)
+
+	state.Entries = entries
+	if err := saveState(statePath, state); err != nil {
+		return nil, err
+	}
+	return run.report, nil
+}
+
+// errShared is reported for todos left out because they share their time
+var errShared = errors.New("shares its time with another todo; move one of them to sync it")
+
+// loadLocal returns a copy of every local todo by the instant of its time.
+// Calendar objects are linked to todos by time, so todos sharing a time are
+// returned in shared instead and left out of the sync. A month that cannot
+// be read stops the sync, as its todos would otherwise look deleted.
+func (s *Syncer) loadLocal() (map[int64]*models.TodoItem, []*models.TodoItem, error) {
+	months, err := s.local.GetAllMonths()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byTime := make(map[int64][]*models.TodoItem)
+	for _, dateKey := range months {
+		year, month := utils.ParseDateKey(dateKey)
+		if year == 0 {
+			continue
+		}
+		todos, err := s.local.GetTodosForMonth(year, month)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, todo := range todos {
+			copied := *todo
+			key := todo.TodoTime.UnixNano()
+			byTime[key] = append(byTime[key], &copied)
+		}
+	}
+
+	local := make(map[int64]*models.TodoItem, len(byTime))
+	var shared []*models.TodoItem
+	for key, todos := range byTime {
+		if len(todos) == 1 {
+			local[key] = todos[0]
+		} else {
+			shared = append(shared, todos...)
+		}
+	}
+	sort.Slice(shared, func(i, j int) bool {
+		if !shared[i].TodoTime.Equal(shared[j].TodoTime) {
+			return shared[i].TodoTime.Before(shared[j].TodoTime)
+		}
+		return shared[i].Name < shared[j].Name
+	})
+	return local, shared, nil
+}
+
+// syncRun is the working state of one Sync call
+type syncRun struct {
+	*Syncer
+	client    *Client
+	local     map[int64]*models.TodoItem // Local todos, updated as changes are pulled
+	claimed   map[int64]bool             // Local todos linked to a calendar object
+	shared    map[int64]bool             // Times of several local todos, left out of the sync
+	decisions map[int64]*persistence.SyncConflict
+	report    *persistence.SyncReport
+}
+
+// syncEntry brings one previously synced todo up to date and reports whether
+// it is still linked to a calendar object
+func (r *syncRun) syncEntry(e *entry, etags map[string]string, remote map[string]*Resource) bool {
+	key := e.Todo.TodoTime.UnixNano()
+	if r.shared[key] {
+		// Which of the todos is linked is unknown; keep the link as it is
+		return true
+	}
+	local := r.local[key]
+	r.claimed[key] = true
+
+	etag, onServer := etags[e.Href]
+	localChanged := !models.SameTodo(local, e.Todo)
+	remoteChanged := !onServer || etag != e.ETag
+
+	var remoteTodo *models.TodoItem
+	if onServer && remoteChanged {
+		resource := remote[e.Href]
+		if resource == nil {
+			return true // Vanished while fetching; next sync
+		}
+		todo, err := r.decode(resource)
+		if err != nil {
+			return true
+		}
+		remoteTodo, etag = todo, resource.ETag
+	}
+
+	switch {
+	case !localChanged && !remoteChanged:
+		return true
+	case !remoteChanged:
+		return r.push(e, local, e.ETag)
+	case !localChanged:
+		return r.pull(e, local, remoteTodo, etag)
+	case models.SameTodo(local, remoteTodo):
+		// Both sides made the same change
+		if local == nil {
+			return false
+		}
+		e.ETag, e.Todo = etag, remoteTodo
+		return true
+	}
+
+	decision := r.decisions[conflictTime(local, remoteTodo, e.Todo).UnixNano()]
+	if decision == nil || !models.SameTodo(decision.Local, local) || !models.SameTodo(decision.Remote, remoteTodo) {
+		r.conflict(e.Todo, local, remoteTodo)
+		return true
+	}
+	if decision.Choice == persistence.SyncKeepRemote {
+		return r.pull(e, local, remoteTodo, etag)
+	}
+	if remoteTodo == nil {
+		etag = "" // Deleted on the server, so recreate it
+	}
+	return r.push(e, local, etag)
+}
+
+// push sends the local version of a linked todo, or deletes the calendar
+// object if the todo was deleted. etag is the server version it replaces.
+func (r *syncRun) push(e *entry, local *models.TodoItem, etag string) bool {
+	if local == nil {
+		if err := r.client.Delete(e.Href, etag); err != nil {
+			r.fail(e.Todo, err)
+			return true
+		}
+		r.report.Pushed++
+		return false
+	}
+
+	newETag, err := r.client.Put(e.Href, EncodeTodo(local, e.UID), etag)
+	if err != nil {
+		// A precondition failure shows up as a remote change next time
+		r.fail(local, err)
+		return true
+	}
+	e.ETag, e.Todo = newETag, local
+	r.report.Pushed++
+	return true
+}
+
+// pull applies the server version of a linked todo locally, or deletes the
+// todo if the calendar object was deleted
+func (r *syncRun) pull(e *entry, local, remote *models.TodoItem, etag string) bool {
+	if remote == nil {
+		if local != nil && !r.removeLocal(local) {
+			return true
+		}
+		return false
+	}
+	if !r.replaceLocal(local, remote) {
+		return true
+	}
+	e.ETag, e.Todo = etag, remote
+	return true
+}
+
+// addRemote links a calendar object seen for the first time, adding it
+// locally unless a todo at the same time exists already
+func (r *syncRun) addRemote(href string, resource *Resource) *entry {
+	if resource == nil {
+		return nil
+	}
+	remote, err := r.decode(resource)
+	if err != nil {
+		return nil
+	}
+	_, uid, _ := DecodeTodo(resource.Data)
+	e := &entry{UID: uid, Href: href, ETag: resource.ETag, Todo: remote}
+
+	key := remote.TodoTime.UnixNano()
+	local := r.local[key]
+	switch {
+	case r.shared[key]:
+		r.fail(remote, errors.New("another todo exists at this time"))
+		return nil
+	case local == nil:
+		if !r.replaceLocal(nil, remote) {
+			return nil
+		}
+		return e
+	case r.claimed[key]:
+		r.fail(remote, errors.New("another todo exists at this time"))
+		return nil
+	}
+	r.claimed[key] = true
+
+	if models.SameTodo(local, remote) {
+		return e
+	}
+	decision := r.decisions[conflictTime(local, remote, nil).UnixNano()]
+	if decision == nil || !models.SameTodo(decision.Local, local) || !models.SameTodo(decision.Remote, remote) {
+		r.conflict(nil, local, remote)
+		return nil
+	}
+	if decision.Choice == persistence.SyncKeepRemote {
+		if !r.replaceLocal(local, remote) {
+			return nil
+		}
+		return e
+	}
+	if !r.push(e, local, resource.ETag) {
+		return nil
+	}
+	return e
+}
+
+// pushNew uploads the local todos that are not linked to a calendar object
+func (r *syncRun) pushNew() []*entry {
+	var entries []*entry
+	for key, local := range r.local {
+		if r.claimed[key] {
+			continue
+		}
+		uid, err := newUID()
+		if err != nil {
+			r.fail(local, err)
+			continue
+		}
+		e := &entry{UID: uid, Href: r.client.Href(uid)}
+		if r.push(e, local, "") && e.Todo != nil {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// replaceLocal swaps the local version of a todo, if any, for the server's
+// version
+func (r *syncRun) replaceLocal(local, remote *models.TodoItem) bool {
+	key := remote.TodoTime.UnixNano()
+	if occupant := r.local[key]; r.shared[key] || (occupant != nil && occupant != local) {
+		// Todos are identified by their time, so two cannot share one
+		r.fail(remote, errors.New("another todo exists at this time"))
+		return false
+	}
+
+	var err error
+	r.dispatch(func() {
+		if local != nil {
+			if err = r.Syncer.local.RemoveTodo(local.TodoTime); err != nil {
+				return
+			}
+		}
+		if remote != nil {
+			copied := *remote
+			err = r.Syncer.local.AddTodo(&copied)
+		}
+	})
+	if err != nil {
+		r.fail(remote, err)
+		return false
+	}
+
+	if local != nil {
+		delete(r.local, local.TodoTime.UnixNano())
+	}
+	r.local[key] = remote
+	r.claimed[key] = true
+	r.report.Pulled++
+	return true
+}
+
+// removeLocal deletes a todo that was deleted on the server
+func (r *syncRun) removeLocal(local *models.TodoItem) bool {
+	var err error
+	r.dispatch(func() {
+		err = r.Syncer.local.RemoveTodo(local.TodoTime)
+	})
+	if err != nil {
+		r.fail(local, err)
+		return false
+	}
+	delete(r.local, local.TodoTime.UnixNano())
+	r.report.Pulled++
+	return true
+}
+
+// decode reads a downloaded calendar object, reporting objects Go Do cannot use
+func (r *syncRun) decode(resource *Resource) (*models.TodoItem, error) {
+	todo, _, err := DecodeTodo(resource.Data)
+	if err != nil {
+		r.report.Failed = append(r.report.Failed, fmt.Sprintf("%s: %v", resource.Href, err))
+	}
+	return todo, err
+}
+
+// conflict reports a todo changed differently on both sides
+func (r *syncRun) conflict(base, local, remote *models.TodoItem) {
+	t := conflictTime(local, remote, base)
+	r.report.Conflicts = append(r.report.Conflicts, &persistence.SyncConflict{
+		Month:  utils.FormatDateKey(t.Year(), int(t.Month())),
+		Base:   base,
+		Local:  local,
+		Remote: remote,
+	})
+}
+
+// fail records a todo that could not be synced this time
+func (r *syncRun) fail(todo *models.TodoItem, err error) {
+	name := ""
+	if todo != nil {
+		name = fmt.Sprintf("%s (%s)", todo.Name, todo.TodoTime.Format(time.RFC3339))
+	}
+	r.report.Failed = append(r.report.Failed, fmt.Sprintf("%s: %v", name, err))
+}
+
+// conflictTime is the time SyncConflict.Time reports for these versions
+func conflictTime(local, remote, base *models.TodoItem) time.Time {
+	conflict := persistence.SyncConflict{Local: local, Remote: remote, Base: base}
+	return conflict.Time()
+}
+
+// newUID returns a random UID for a todo created in Go Do
+func newUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to create UID: %w", err)
+	}
+	return fmt.Sprintf("%x-godo", b), nil
+}
+
+// loadState reads the sync state of collection; a missing file or the state
+// of another collection is an empty state
+func loadState(path, collection string) (*syncState, error) {
+	state := &syncState{Collection: collection}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read calendar sync state: %w", err)
+	}
+
+	var saved syncState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse calendar sync state: %w", err)
+	}
+	if saved.Collection != collection {
+		return state, nil
+	}
+	return &saved, nil
+}
+
+// saveState writes the sync state atomically
+func saveState(path string, state *syncState) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create calendar sync state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal calendar sync state: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write calendar sync state: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to save calendar sync state: %w", err)
+	}
+	return nil
+}
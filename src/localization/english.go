@@ -33,6 +33,11 @@ var English = map[string]string{
 	"field_type":                 "Type:",
 	"field_priority":             "Priority:",
 	"field_reminder":             "Reminder:",
+	"field_end_time":             "Until:",
+	"field_end_time_placeholder": "HH:MM (optional)",
+	"field_all_day":              "All day",
+	"field_estimate":             "Pomodoros:",
+	"estimate_none":              "No estimate",
 	"select_datetime":            "Select Date/Time",
 
 	// Priority Levels
@@ -45,6 +50,173 @@ var English = map[string]string{
 	"type_event": "Event",
 	"type_task":  "Task",
 
+	// Time Ranges
+	"time_all_day": "All day",
+
+	// Conflict Dialog
+	"conflict_title":       "Overlapping Events",
+	"conflict_message":     "%s overlaps with:\n\n%s\n\nSave anyway?",
+	"conflict_button_save": "Save Anyway",
+
+	// Pomodoro Estimates
+	"estimate_reached_title":   "Estimate Reached",
+	"estimate_reached_message": "%s has used %d of %d estimated Pomodoros.\n\nIs it done, or does it need more time?",
+	"estimate_button_done":     "Mark Done",
+	"estimate_button_extend":   "One More",
+	"estimate_button_later":    "Later",
+	"pomodoro_working_on":      "Working on: %s",
+
+	// Pomodoro Interruptions
+	"interruption_title":            "Log Interruption",
+	"interruption_kind_internal":    "Internal (my own urge)",
+	"interruption_kind_external":    "External (someone else)",
+	"interruption_note_placeholder": "What came up? (optional)",
+	"interruption_button_continue":  "Note & Continue",
+	"interruption_button_void":      "Void Pomodoro",
+	"interruption_button_cancel":    "Cancel",
+	"interruption_button_close":     "Close",
+	"interruption_not_working":      "Interruptions can only be logged during a work session.",
+	"interruption_log_title":        "Interruptions",
+	"interruption_log_empty":        "No interruptions on this day.",
+	"interruption_log_summary":      "%d internal, %d external, %d voided",
+	"interruption_voided":           "voided",
+	"interruption_button_todo":      "To Todo",
+
+	// Pomodoro Statistics
+	"stats_title":       "Pomodoro Statistics",
+	"stats_export_csv":  "Export CSV",
+	"stats_summary":     "Focus: %s\nCompleted: %d · Abandoned: %d (%.0f%% completed)\nInterruptions per Pomodoro: %.1f",
+	"stats_per_day":     "Focus per day",
+	"stats_per_week":    "Focus per week",
+	"stats_heatmap":     "When you focus",
+	"stats_by_label":    "Focus by label",
+	"stats_no_sessions": "No completed Pomodoros in this period.",
+	"stats_no_label":    "No label",
+
+	// System Tray
+	"tray_open_todos":        "Today: %d open",
+	"tray_pomodoro":          "Pomodoro: %s",
+	"tray_quick_add":         "Quick Add…",
+	"tray_pomodoro_start":    "Start Pomodoro",
+	"tray_pomodoro_pause":    "Pause Pomodoro",
+	"tray_pomodoro_resume":   "Resume Pomodoro",
+	"tray_show_window":       "Show Window",
+	"tray_hide_window":       "Hide Window",
+	"tray_minimize_to_tray":  "Minimize to Tray on Close",
+	"tray_sync_now":          "Sync Now",
+	"tray_sync_settings":     "Sync…",
+	"tray_calendar_settings": "Calendar Server…",
+	"tray_local_api":         "Local API…",
+	"tray_import":            "Import",
+	"tray_export":            "Export",
+	"tray_todotxt_file":      "Use todo.txt File…",
+	"tray_markdown_folder":   "Use Markdown Folder…",
+	"tray_quit":              "Quit",
+
+	// Local REST API
+	"api_title":            "Local API",
+	"api_enable":           "Let scripts and other apps use Go Do over HTTP",
+	"api_port":             "Port",
+	"api_token":            "Token",
+	"api_address":          "http://127.0.0.1:%s/api · description at /api/openapi.yaml",
+	"api_button_copy":      "Copy",
+	"api_button_new_token": "New Token",
+	"api_button_save":      "Save",
+	"api_button_cancel":    "Cancel",
+	"api_invalid_port":     "The port must be a number from 1 to 65535.",
+
+	// Sync
+	"sync_title":              "Sync",
+	"sync_folder":             "Folder",
+	"sync_folder_placeholder": "Shared or mounted folder; empty turns sync off",
+	"sync_button_browse":      "Browse…",
+	"sync_interval":           "Every (minutes)",
+	"sync_hint":               "Todos are merged with the folder at start, every interval (0 = never) and on Sync Now. Todos changed on both sides are asked about.",
+	"sync_button_save":        "Save",
+	"sync_button_cancel":      "Cancel",
+	"sync_invalid_interval":   "The interval must be a whole number of minutes, 0 or more.",
+	"sync_invalid_folder":     "The sync folder does not exist.",
+	"sync_done_title":         "Sync",
+	"sync_done_message":       "Synced: %d todo change(s) received, %d sent, %d month(s) failed.",
+	"sync_conflicts_title":    "Sync Conflicts",
+	"sync_conflicts_message":  "%d todo(s) were changed both here and in the sync folder. Pick the version to keep; undecided todos keep their version on each side until you decide.",
+	"sync_conflict_base":      "Last synced: %s",
+	"sync_conflict_local":     "This device",
+	"sync_conflict_remote":    "Sync folder",
+	"sync_conflict_calendar":  "Calendar server",
+	"sync_version_deleted":    "(deleted)",
+	"sync_version_done":       "done",
+	"sync_button_later":       "Decide Later",
+	"sync_button_apply":       "Apply",
+
+	// CalDAV
+	"caldav_title":           "Calendar Server",
+	"caldav_url":             "Calendar URL",
+	"caldav_url_placeholder": "https://example.com/dav/calendars/me/work/; empty turns it off",
+	"caldav_username":        "Username",
+	"caldav_password":        "Password",
+	"caldav_hint":            "Tasks are synced as to-dos and events as calendar events. The password is stored in the config file, readable only by your user account.",
+
+	// todo.txt
+	"todotxt_title":            "todo.txt File",
+	"todotxt_file":             "File",
+	"todotxt_file_placeholder": "Path to todo.txt; empty keeps todos in the data folder",
+	"todotxt_hint":             "Go Do reads and writes this file instead of its data folder, and picks up edits made by other todo.txt tools. Only lines with a due: date are shown; other lines are kept as they are. Notes are not stored in todo.txt.",
+	"todotxt_restart":          "Restart Go Do to switch where todos are stored.",
+
+	// Markdown folder
+	"markdown_folder_title":       "Markdown Folder",
+	"markdown_folder":             "Folder",
+	"markdown_folder_placeholder": "Folder such as a notes vault; empty keeps todos in the data folder",
+	"markdown_folder_hint":        "Go Do keeps each todo as a Markdown note in year and month folders below this folder, with its details in the front-matter and its notes as the body. Notes may be edited, renamed or moved between month folders in other tools; Go Do picks up the changes. This replaces a todo.txt file.",
+
+	// Import and export
+	"format_markdown":     "Markdown",
+	"format_csv":          "CSV",
+	"format_todotxt":      "todo.txt",
+	"export_title":        "Export %s",
+	"export_range_day":    "Shown day (%s)",
+	"export_range_month":  "Shown month (%s)",
+	"export_range_dates":  "Dates",
+	"export_range_all":    "Everything",
+	"export_from":         "From",
+	"export_to":           "To",
+	"export_invalid_date": "Dates must be given as DD.MM.YYYY.",
+	"export_button":       "Export…",
+	"import_done_title":   "Import",
+	"import_done_message": "Imported %d todo(s). %d already existed, %d could not be read.",
+	"import_skipped_line": "Line %d: %v",
+	"import_more_skipped": "… and %d more",
+	"import_button_close": "Close",
+
+	// Import from other task tools
+	"format_todoist":           "Todoist",
+	"format_trello":            "Trello",
+	"format_googletasks":       "Google Tasks",
+	"import_preview_title":     "Import from %s",
+	"import_preview_message":   "%d task(s) will be added, %d were imported before and %d could not be read. Tasks without a due date go on %s.",
+	"import_preview_undated":   "No due date",
+	"import_preview_label":     "Label: %s",
+	"import_preview_reminder":  "Reminder %d min before",
+	"import_preview_duplicate": "Imported before",
+	"import_button":            "Import",
+
+	// Eisenhower matrix
+	"matrix_title":            "Priority Matrix",
+	"matrix_button":           "Matrix",
+	"matrix_scope_day":        "Day",
+	"matrix_scope_week":       "Week",
+	"matrix_scope_all":        "All open",
+	"matrix_quadrant_0":       "Eliminate",
+	"matrix_quadrant_1":       "Delegate",
+	"matrix_quadrant_2":       "Schedule",
+	"matrix_quadrant_3":       "Do",
+	"matrix_heading":          "%s (%d)",
+	"matrix_unscheduled_only": "Unscheduled only",
+	"matrix_add_placeholder":  "Add to %s…",
+	"matrix_empty":            "Nothing here",
+	"matrix_hint":             "Drag a card to another quadrant to change its priority. New tasks are added without a time on %s.",
+
 	// Reminder Messages
 	"reminder_none":   "No reminder",
 	"reminder_format": "Remind %s before",
@@ -52,10 +224,20 @@ var English = map[string]string{
 	// Status Messages
 	"status_empty_list":    "No todos yet. Click + to add your first todo!",
 	"status_loading_error": "Error loading todos: %s",
+	"status_corrupt_file":  "⚠ The data file for this month is damaged and was not loaded.\n%s (line %d): %s\nYour todos were not overwritten.",
+
+	// Corrupt File Dialogs
+	"corrupt_title":         "Damaged Data File",
+	"corrupt_message":       "%s could not be read.\n\nLine %d: %s\n\nA copy of the file was saved to:\n%s\n\nRepair keeps every readable todo and fixes invalid values.",
+	"corrupt_button_repair": "Repair",
+	"corrupt_button_later":  "Later",
+	"repair_done_title":     "Repair Complete",
+	"repair_done_message":   "Recovered %d todos.\nDropped %d unreadable, normalized %d, removed %d duplicates.",
 
 	// Error Messages
 	"error_name_required":    "Name is required",
 	"error_invalid_datetime": "Invalid date/time format. Use DD.MM.YYYY HH:MM",
+	"error_invalid_end_time": "Invalid end time. Use HH:MM after the start time",
 	"error_save_failed":      "Failed to save todo: %s",
 	"error_load_failed":      "Failed to load todos: %s",
 
@@ -1,14 +1,22 @@
 package main
 
 import (
+	"fmt"
 	"log"
+	"os"
 
 	"godo/src/app"
 )
 
 func main() {
-	// Check for single instance
-	instanceLock, locked := app.CheckSingleInstance()
+	command, err := app.ParseCommand(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n\n%s\n", err, app.CommandUsage)
+		os.Exit(2)
+	}
+
+	// Check for single instance; a second launch hands its arguments over
+	instanceLock, locked := app.CheckSingleInstance(os.Args[1:])
 	if !locked {
 		// Another instance is already running or check failed
 		return
@@ -26,13 +34,15 @@ func main() {
 		log.Fatal(err)
 	}
 
-	// Run one-shot migration from TXT to YAML on startup (non-fatal)
+	// Upgrade data files to the current schema on startup (non-fatal)
 	if err := application.RunMigration(); err != nil {
 		log.Printf("Warning: Migration failed: %v", err)
 	}
 
 	// Create the main UI
 	application.CreateMainUI()
+	application.Execute(command)
+	application.ListenForCommands(instanceLock)
 
 	// Show the window and run the application
 	application.Run()
@@ -4,17 +4,73 @@ import "time"
 
 // Config represents the application configuration
 type Config struct {
-	Version string    `json:"version"`
-	UI      UIConfig  `json:"ui"`
+	Version  string           `json:"version"`
+	UI       UIConfig         `json:"ui"`
+	Pomodoro PomodoroSettings `json:"pomodoro"`
+	API      APIConfig        `json:"api"`
+	Sync     SyncConfig       `json:"sync"`
+	CalDAV   CalDAVConfig     `json:"caldav"`
+	TodoTxt  TodoTxtConfig    `json:"todoTxt"`
+	Markdown MarkdownConfig   `json:"markdownFolder"`
+}
+
+// DefaultAPIPort is the port of the local REST API unless configured otherwise
+const DefaultAPIPort = 8732
+
+// APIConfig configures the local REST API. It is off by default; clients
+// authenticate with Token as a bearer token.
+type APIConfig struct {
+	Enabled bool   `json:"enabled"`
+	Port    int    `json:"port,omitempty"` // 0 = DefaultAPIPort
+	Token   string `json:"token,omitempty"`
+}
+
+// GetPort returns the configured port, or DefaultAPIPort
+func (c APIConfig) GetPort() int {
+	if c.Port == 0 {
+		return DefaultAPIPort
+	}
+	return c.Port
+}
+
+// SyncConfig configures syncing the data directory with another folder
+type SyncConfig struct {
+	RemoteDir string `json:"remoteDir,omitempty"` // Folder to sync with ("" = off)
+	Interval  int    `json:"interval,omitempty"`  // Minutes between syncs (0 = at start and on demand only)
+}
+
+// CalDAVConfig configures syncing todos with a calendar collection on a
+// CalDAV server. The password is kept in the config file, which only the user
+// may read, like the API token.
+type CalDAVConfig struct {
+	URL      string `json:"url,omitempty"` // Calendar collection ("" = off)
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Interval int    `json:"interval,omitempty"` // Minutes between syncs (0 = at start and on demand only)
+}
+
+// TodoTxtConfig selects a todo.txt file to store todos in instead of the month
+// files of the data directory. It takes effect at the next start.
+type TodoTxtConfig struct {
+	File string `json:"file,omitempty"` // Path of the todo.txt file ("" = month files)
+}
+
+// MarkdownConfig selects a folder, such as a notes vault, to store each todo
+// in as a Markdown note instead of the month files of the data directory. It
+// takes effect at the next start.
+type MarkdownConfig struct {
+	Folder string `json:"folder,omitempty"` // Root of the YYYY/MM note folders ("" = month files)
 }
 
 // UIConfig stores UI state preferences
 type UIConfig struct {
-	Theme           string    `json:"theme"`           // "light" or "dark"
-	ViewMode        string    `json:"viewMode"`        // "all", "incomplete", "complete", "starred"
-	CurrentDate     time.Time `json:"currentDate"`     // Last viewed date
-	WindowWidth     float32   `json:"windowWidth"`     // Window dimensions (for future)
-	WindowHeight    float32   `json:"windowHeight"`    // Window dimensions (for future)
+	Theme          string    `json:"theme"`                    // "light" or "dark"
+	ViewMode       string    `json:"viewMode"`                 // "all", "incomplete", "complete", "starred"
+	CurrentDate    time.Time `json:"currentDate"`              // Last viewed date
+	DayLayout      string    `json:"dayLayout,omitempty"`      // "list" or "planner" ("" = list)
+	MinimizeToTray bool      `json:"minimizeToTray,omitempty"` // Closing the window hides it in the system tray
+	WindowWidth    float32   `json:"windowWidth"`              // Window dimensions (for future)
+	WindowHeight   float32   `json:"windowHeight"`             // Window dimensions (for future)
 }
 
 // NewDefaultConfig creates a default configuration
@@ -60,3 +116,26 @@ func (c *Config) GetCurrentDate() time.Time {
 func (c *Config) SetCurrentDate(date time.Time) {
 	c.UI.CurrentDate = date
 }
+
+// GetDayLayout returns how the day is shown: "list" or "planner"
+func (c *Config) GetDayLayout() string {
+	if c.UI.DayLayout == "" {
+		return "list"
+	}
+	return c.UI.DayLayout
+}
+
+// SetDayLayout sets how the day is shown
+func (c *Config) SetDayLayout(layout string) {
+	c.UI.DayLayout = layout
+}
+
+// GetMinimizeToTray reports whether closing the window keeps the app in the tray
+func (c *Config) GetMinimizeToTray() bool {
+	return c.UI.MinimizeToTray
+}
+
+// SetMinimizeToTray sets whether closing the window keeps the app in the tray
+func (c *Config) SetMinimizeToTray(minimize bool) {
+	c.UI.MinimizeToTray = minimize
+}
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"sync"
+	"time"
+)
 
 // PomodoroState represents the current state of the pomodoro timer
 type PomodoroState int
@@ -13,12 +16,47 @@ const (
 	PomodoroPaused
 )
 
+// IsBreak reports whether the state is a short or long break
+func (s PomodoroState) IsBreak() bool {
+	return s == PomodoroShortBreak || s == PomodoroLongBreak
+}
+
+// IsRunning reports whether the state is an interval that is being timed
+func (s PomodoroState) IsRunning() bool {
+	return s == PomodoroWork || s.IsBreak()
+}
+
+// String returns a human-readable state name
+func (s PomodoroState) String() string {
+	switch s {
+	case PomodoroIdle:
+		return "Ready"
+	case PomodoroWork:
+		return "Work"
+	case PomodoroShortBreak:
+		return "Short Break"
+	case PomodoroLongBreak:
+		return "Long Break"
+	case PomodoroPaused:
+		return "Paused"
+	default:
+		return "Unknown"
+	}
+}
+
 // PomodoroConfig holds the configuration for pomodoro sessions
 type PomodoroConfig struct {
-	WorkDuration      int // in minutes
-	ShortBreakDuration int // in minutes
-	LongBreakDuration  int // in minutes
-	SessionsUntilLongBreak int // number of work sessions before long break
+	WorkDuration           int  // in minutes
+	ShortBreakDuration     int  // in minutes
+	LongBreakDuration      int  // in minutes
+	SessionsUntilLongBreak int  // number of work sessions before long break
+	AutoStartBreaks        bool // start the break as soon as work finishes
+	AutoStartWork          bool // start the next work session as soon as a break finishes
+
+	Mode           PomodoroMode   // "" behaves like ModeClassic
+	FlowtimeRatio  int            // Flowtime: minutes of focus per minute of break
+	Sequence       []PomodoroStep // Sequence: the intervals to run in order
+	SequenceRepeat int            // Sequence: number of rounds (0 = until reset)
 }
 
 // NewDefaultPomodoroConfig creates a default configuration
@@ -28,133 +66,604 @@ func NewDefaultPomodoroConfig() *PomodoroConfig {
 		ShortBreakDuration:     5,
 		LongBreakDuration:      15,
 		SessionsUntilLongBreak: 4,
+		AutoStartBreaks:        true,
+		AutoStartWork:          false,
+	}
+}
+
+// sequence returns the custom steps, or nil when the sequence mode is not active
+func (c *PomodoroConfig) sequence() []PomodoroStep {
+	if c.Mode != ModeSequence {
+		return nil
+	}
+	return c.Sequence
+}
+
+// Duration returns the configured length of an interval state
+func (c *PomodoroConfig) Duration(state PomodoroState) time.Duration {
+	switch state {
+	case PomodoroWork:
+		return time.Duration(c.WorkDuration) * time.Minute
+	case PomodoroShortBreak:
+		return time.Duration(c.ShortBreakDuration) * time.Minute
+	case PomodoroLongBreak:
+		return time.Duration(c.LongBreakDuration) * time.Minute
+	default:
+		return 0
+	}
+}
+
+// Clock provides the current time; tests inject a fake one
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// SystemClock is the wall clock used outside of tests
+var SystemClock Clock = systemClock{}
+
+// PomodoroEventType identifies a timer transition
+type PomodoroEventType int
+
+const (
+	EventWorkStarted PomodoroEventType = iota
+	EventWorkFinished
+	EventBreakStarted
+	EventBreakFinished
+	EventPaused
+	EventResumed
+	EventSkipped
+	EventReset
+	EventVoided
+)
+
+// String returns a readable event name
+func (e PomodoroEventType) String() string {
+	switch e {
+	case EventWorkStarted:
+		return "work started"
+	case EventWorkFinished:
+		return "work finished"
+	case EventBreakStarted:
+		return "break started"
+	case EventBreakFinished:
+		return "break finished"
+	case EventPaused:
+		return "paused"
+	case EventResumed:
+		return "resumed"
+	case EventSkipped:
+		return "skipped"
+	case EventReset:
+		return "reset"
+	case EventVoided:
+		return "voided"
+	default:
+		return "unknown"
+	}
+}
+
+// PomodoroEvent describes a transition of the timer
+type PomodoroEvent struct {
+	Type     PomodoroEventType
+	From     PomodoroState // State before the transition
+	To       PomodoroState // State after the transition
+	Sessions int           // Completed work sessions after the transition
+	At       time.Time     // When the transition happened (interval end for finished events)
+	Start    time.Time     // When the interval the event refers to began, not shifted by pauses
+	Duration time.Duration // Planned length of the interval the event refers to
+}
+
+// PomodoroStatus is a snapshot of the timer for rendering
+type PomodoroStatus struct {
+	State      PomodoroState
+	PausedFrom PomodoroState // Interval that was paused (Idle unless State is Paused)
+	Next       PomodoroState // Interval Start begins when the timer is idle
+	Remaining  time.Duration
+	Elapsed    time.Duration
+	Total      time.Duration // Length of the current (or next) interval; one lap of the ring when open-ended
+	OpenEnded  bool          // Flowtime work that counts up until FinishWork
+	Sessions   int
+	Step       int // Position in a custom sequence
+	Steps      int // Length of the custom sequence (0 outside the sequence mode)
+}
+
+// Interval returns the interval shown: the paused one, the running one or the next one
+func (s PomodoroStatus) Interval() PomodoroState {
+	switch s.State {
+	case PomodoroPaused:
+		return s.PausedFrom
+	case PomodoroIdle:
+		return s.Next
+	default:
+		return s.State
+	}
+}
+
+// InWork reports whether a work session is running or paused
+func (s PomodoroStatus) InWork() bool {
+	return s.State != PomodoroIdle && s.Interval() == PomodoroWork
+}
+
+// Progress returns the elapsed fraction of the current interval (0 when idle).
+// Open-ended intervals have no end, so the fraction restarts every lap.
+func (s PomodoroStatus) Progress() float32 {
+	if s.State == PomodoroIdle || s.Total <= 0 {
+		return 0
+	}
+	if s.OpenEnded {
+		return float32((s.Elapsed % s.Total).Seconds() / s.Total.Seconds())
+	}
+	p := float32((s.Total - s.Remaining).Seconds() / s.Total.Seconds())
+	if p < 0 {
+		return 0
+	}
+	if p > 1 {
+		return 1
 	}
+	return p
 }
 
-// PomodoroTimer manages the pomodoro timer state 
+// PomodoroTimer is the pomodoro state machine.
+//
+//	Idle --Start--> Work --finish--> Short/Long break --finish--> Work ...
+//	running --Pause--> Paused --Resume--> the paused interval
+//	break --SkipBreak--> Work (or Idle waiting for work)
+//	Flowtime work --FinishWork--> earned break
+//
+// Finishing an interval starts the next one immediately when the matching
+// AutoStart option is set, otherwise the timer waits in Idle with Next set.
+// In the sequence mode the intervals come from the configured steps, and the
+// timer stops in Idle once the last round is done.
 type PomodoroTimer struct {
-	Config           *PomodoroConfig
-	State            PomodoroState
-	TimeRemaining    time.Duration
-	SessionsCompleted int
-	StartTime        time.Time
-	PausedAt         time.Time
+	mu sync.Mutex
+
+	config     *PomodoroConfig
+	clock      Clock
+	state      PomodoroState
+	pausedFrom PomodoroState
+	next       PomodoroState
+	sessions   int
+	startTime  time.Time     // Start of the running interval, shifted by pauses
+	began      time.Time     // Start of the running interval as it happened
+	pausedAt   time.Time     // When the timer was paused
+	duration   time.Duration // Length of the current interval, fixed when it starts (0 = open-ended)
+	step       int           // Position in the custom sequence
+	round      int           // Completed rounds of the custom sequence
+	earned     time.Duration // Flowtime break earned by the last work session
+
+	subscribers map[int]func(PomodoroEvent)
+	nextSubID   int
+	pending     []PomodoroEvent
 }
 
-// NewPomodoroTimer creates a new pomodoro timer
+// NewPomodoroTimer creates a new pomodoro timer using the system clock
 func NewPomodoroTimer(config *PomodoroConfig) *PomodoroTimer {
+	return NewPomodoroTimerWithClock(config, SystemClock)
+}
+
+// NewPomodoroTimerWithClock creates a new pomodoro timer driven by clock
+func NewPomodoroTimerWithClock(config *PomodoroConfig, clock Clock) *PomodoroTimer {
 	return &PomodoroTimer{
-		Config:           config,
-		State:            PomodoroIdle,
-		TimeRemaining:    0,
-		SessionsCompleted: 0,
+		config:      config,
+		clock:       clock,
+		state:       PomodoroIdle,
+		next:        PomodoroWork,
+		subscribers: make(map[int]func(PomodoroEvent)),
+	}
+}
+
+// Subscribe registers fn for every transition and returns a function that
+// removes it again. Callbacks run after the timer lock is released, so they
+// may call back into the timer.
+func (pt *PomodoroTimer) Subscribe(fn func(PomodoroEvent)) func() {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	id := pt.nextSubID
+	pt.nextSubID++
+	pt.subscribers[id] = fn
+	return func() {
+		pt.mu.Lock()
+		defer pt.mu.Unlock()
+		delete(pt.subscribers, id)
+	}
+}
+
+// Config returns the configuration used for new intervals
+func (pt *PomodoroTimer) Config() *PomodoroConfig {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	return pt.config
+}
+
+// SetConfig replaces the configuration. The running interval keeps its length.
+func (pt *PomodoroTimer) SetConfig(config *PomodoroConfig) {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	pt.config = config
+
+	steps := config.sequence()
+	if pt.step >= len(steps) {
+		pt.step, pt.round = 0, 0
+	}
+	if pt.state == PomodoroIdle && len(steps) > 0 {
+		pt.next = steps[pt.step].Kind
 	}
 }
 
-// Start begins a new pomodoro work session
+// Start begins the next interval when idle, or resumes a paused one
 func (pt *PomodoroTimer) Start() {
-	pt.State = PomodoroWork
-	pt.TimeRemaining = time.Duration(pt.Config.WorkDuration) * time.Minute
-	pt.StartTime = time.Now()
+	pt.mu.Lock()
+	switch pt.state {
+	case PomodoroIdle:
+		pt.begin(pt.next, pt.clock.Now())
+	case PomodoroPaused:
+		pt.resume()
+	}
+	pt.unlockAndDispatch()
 }
 
-// Pause pauses the current timer
+// Pause pauses the running interval
 func (pt *PomodoroTimer) Pause() {
-	if pt.State != PomodoroIdle && pt.State != PomodoroPaused {
-		pt.State = PomodoroPaused
-		pt.PausedAt = time.Now()
+	pt.mu.Lock()
+	if pt.state.IsRunning() {
+		now := pt.clock.Now()
+		pt.advanceTo(now)
+		if pt.state.IsRunning() {
+			pt.pausedFrom = pt.state
+			pt.pausedAt = now
+			pt.transition(EventPaused, PomodoroPaused, now)
+		}
 	}
+	pt.unlockAndDispatch()
 }
 
-// Resume resumes a paused timer
+// Resume continues the paused interval where it stopped
 func (pt *PomodoroTimer) Resume() {
-	if pt.State == PomodoroPaused {
-		// Calculate pause duration and adjust start time
-		pauseDuration := time.Since(pt.PausedAt)
-		pt.StartTime = pt.StartTime.Add(pauseDuration)
-		pt.State = PomodoroWork // Resume to work state
+	pt.mu.Lock()
+	if pt.state == PomodoroPaused {
+		pt.resume()
 	}
+	pt.unlockAndDispatch()
 }
 
-// Reset resets the timer to idle state
-func (pt *PomodoroTimer) Reset() {
-	pt.State = PomodoroIdle
-	pt.TimeRemaining = 0
-	pt.SessionsCompleted = 0
+// SkipBreak ends the current, paused or upcoming break without waiting for it
+func (pt *PomodoroTimer) SkipBreak() {
+	pt.mu.Lock()
+	now := pt.clock.Now()
+	pt.advanceTo(now)
+
+	switch {
+	case pt.state.IsBreak() || (pt.state == PomodoroPaused && pt.pausedFrom.IsBreak()):
+		pt.pausedFrom = PomodoroIdle
+		next, done := pt.advanceSequence(PomodoroShortBreak)
+		pt.finishInto(EventSkipped, next, pt.autoStart(next) && !done, now)
+	case pt.state == PomodoroIdle && pt.next.IsBreak():
+		next, _ := pt.advanceSequence(PomodoroShortBreak)
+		pt.finishInto(EventSkipped, next, false, now)
+	}
+	pt.unlockAndDispatch()
+}
+
+// FinishWork ends an open-ended Flowtime work session, running or paused,
+// and moves on to the break it earned
+func (pt *PomodoroTimer) FinishWork() {
+	pt.mu.Lock()
+	now := pt.clock.Now()
+	interval := pt.state
+	end := now
+	if pt.state == PomodoroPaused {
+		interval = pt.pausedFrom
+		end = pt.pausedAt
+	}
+	if interval == PomodoroWork && pt.duration == 0 {
+		// Record the focus time as the interval length so the finished
+		// event describes the session that actually happened
+		pt.duration = end.Sub(pt.startTime)
+		pt.earned = FlowtimeBreak(pt.duration, pt.config.FlowtimeRatio)
+		pt.state = PomodoroWork
+		pt.pausedFrom = PomodoroIdle
+		pt.finish(now)
+	}
+	pt.unlockAndDispatch()
 }
 
-// StartBreak starts a break session
-func (pt *PomodoroTimer) StartBreak() {
-	pt.SessionsCompleted++
+// Void abandons the running or paused work session. It does not count as a
+// finished session and no break is earned; the timer waits to start work again.
+func (pt *PomodoroTimer) Void() {
+	pt.mu.Lock()
+	now := pt.clock.Now()
+	end := now
+	interval := pt.state
+	if pt.state == PomodoroPaused {
+		interval = pt.pausedFrom
+		end = pt.pausedAt
+	}
+	if interval == PomodoroWork {
+		// Like FinishWork, the event reports how long was actually worked
+		pt.duration = end.Sub(pt.startTime)
+		pt.state = PomodoroWork
+		pt.pausedFrom = PomodoroIdle
+		pt.finishInto(EventVoided, PomodoroWork, false, now)
+	}
+	pt.unlockAndDispatch()
+}
 
-	if pt.SessionsCompleted % pt.Config.SessionsUntilLongBreak == 0 {
-		// Long break
-		pt.State = PomodoroLongBreak
-		pt.TimeRemaining = time.Duration(pt.Config.LongBreakDuration) * time.Minute
-	} else {
-		// Short break
-		pt.State = PomodoroShortBreak
-		pt.TimeRemaining = time.Duration(pt.Config.ShortBreakDuration) * time.Minute
+// Reset stops the timer and clears the completed sessions
+func (pt *PomodoroTimer) Reset() {
+	pt.mu.Lock()
+	from := pt.state
+	pt.state = PomodoroIdle
+	pt.pausedFrom = PomodoroIdle
+	pt.next = PomodoroWork
+	pt.sessions = 0
+	pt.duration = 0
+	pt.step, pt.round, pt.earned = 0, 0, 0
+	if steps := pt.config.sequence(); len(steps) > 0 {
+		pt.next = steps[0].Kind
 	}
-	pt.StartTime = time.Now()
+	pt.pending = append(pt.pending, PomodoroEvent{Type: EventReset, From: from, To: PomodoroIdle, At: pt.clock.Now()})
+	pt.unlockAndDispatch()
 }
 
-// Update updates the timer state
+// Update advances the timer to the current time, finishing every interval
+// that ended since the last call
 func (pt *PomodoroTimer) Update() {
-	if pt.State == PomodoroIdle || pt.State == PomodoroPaused {
-		return
-	}
+	pt.mu.Lock()
+	pt.advanceTo(pt.clock.Now())
+	pt.unlockAndDispatch()
+}
 
-	elapsed := time.Since(pt.StartTime)
-	duration := pt.GetCurrentDuration()
+// Snapshot updates the timer and returns its state for rendering
+func (pt *PomodoroTimer) Snapshot() PomodoroStatus {
+	pt.mu.Lock()
+	now := pt.clock.Now()
+	pt.advanceTo(now)
 
-	if elapsed >= duration {
-		// Timer completed
-		pt.OnTimerComplete()
-	} else {
-		pt.TimeRemaining = duration - elapsed
+	status := PomodoroStatus{
+		State:      pt.state,
+		PausedFrom: pt.pausedFrom,
+		Next:       pt.next,
+		Sessions:   pt.sessions,
+		Step:       pt.step,
+		Steps:      len(pt.config.sequence()),
+	}
+	switch {
+	case pt.state == PomodoroIdle:
+		status.Total = pt.intervalLength(pt.next)
+		status.Remaining = status.Total
+	case pt.state == PomodoroPaused:
+		status.Total = pt.duration
+		status.Elapsed = pt.pausedAt.Sub(pt.startTime)
+	default:
+		status.Total = pt.duration
+		status.Elapsed = now.Sub(pt.startTime)
+	}
+	if pt.state != PomodoroIdle {
+		status.Remaining = status.Total - status.Elapsed
+		if status.Total == 0 {
+			// Flowtime work: show the ring in laps of a classic work session
+			status.OpenEnded = true
+			status.Total = pt.config.Duration(PomodoroWork)
+			status.Remaining = 0
+		}
+	}
+	if status.Remaining < 0 {
+		status.Remaining = 0
 	}
+	pt.unlockAndDispatch()
+	return status
 }
 
-// GetCurrentDuration returns the total duration for current state
-func (pt *PomodoroTimer) GetCurrentDuration() time.Duration {
-	switch pt.State {
-	case PomodoroWork:
-		return time.Duration(pt.Config.WorkDuration) * time.Minute
-	case PomodoroShortBreak:
-		return time.Duration(pt.Config.ShortBreakDuration) * time.Minute
-	case PomodoroLongBreak:
-		return time.Duration(pt.Config.LongBreakDuration) * time.Minute
-	default:
-		return 0
+// ExportState returns the timer state for persisting it
+func (pt *PomodoroTimer) ExportState() PomodoroTimerState {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	return PomodoroTimerState{
+		State:      pt.state,
+		PausedFrom: pt.pausedFrom,
+		Next:       pt.next,
+		Sessions:   pt.sessions,
+		StartTime:  pt.startTime,
+		Began:      pt.began,
+		PausedAt:   pt.pausedAt,
+		Duration:   pt.duration,
+		Step:       pt.step,
+		Round:      pt.round,
+		Earned:     pt.earned,
 	}
 }
 
-// OnTimerComplete handles timer completion
-func (pt *PomodoroTimer) OnTimerComplete() {
-	switch pt.State {
-	case PomodoroWork:
-		pt.StartBreak()
-	case PomodoroShortBreak, PomodoroLongBreak:
-		pt.State = PomodoroIdle
-		pt.TimeRemaining = 0
+// RestoreState continues from a persisted state. Intervals that ended while
+// the timer was not running are finished with their original end times, so
+// subscribers receive the events they missed.
+func (pt *PomodoroTimer) RestoreState(saved PomodoroTimerState) {
+	pt.mu.Lock()
+	pt.state = saved.State
+	pt.pausedFrom = saved.PausedFrom
+	pt.next = saved.Next
+	pt.sessions = saved.Sessions
+	pt.startTime = saved.StartTime
+	pt.began = saved.Began
+	if pt.began.IsZero() {
+		// Sessions saved before the real start was kept
+		pt.began = saved.StartTime
+	}
+	pt.pausedAt = saved.PausedAt
+	pt.duration = saved.Duration
+	pt.step = saved.Step
+	pt.round = saved.Round
+	pt.earned = saved.Earned
+	if pt.step < 0 || pt.step >= len(pt.config.sequence()) {
+		pt.step, pt.round = 0, 0
+	}
+
+	// Drop states that cannot be continued; only work may be open-ended
+	interval := pt.state
+	if pt.state == PomodoroPaused {
+		interval = pt.pausedFrom
+	}
+	invalid := !interval.IsRunning() || pt.duration < 0 || (pt.duration == 0 && interval != PomodoroWork)
+	if pt.state != PomodoroIdle && invalid {
+		pt.state = PomodoroIdle
+		pt.pausedFrom = PomodoroIdle
+		pt.duration = 0
 	}
+	if !pt.next.IsRunning() {
+		pt.next = PomodoroWork
+	}
+
+	pt.advanceTo(pt.clock.Now())
+	pt.unlockAndDispatch()
 }
 
 // GetStateString returns a human-readable state string
 func (pt *PomodoroTimer) GetStateString() string {
-	switch pt.State {
-	case PomodoroIdle:
-		return "Ready"
-	case PomodoroWork:
-		return "Work"
-	case PomodoroShortBreak:
-		return "Short Break"
-	case PomodoroLongBreak:
-		return "Long Break"
-	case PomodoroPaused:
-		return "Paused"
-	default:
-		return "Unknown"
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	return pt.state.String()
+}
+
+// advanceTo finishes intervals that ended before now. With auto-start, the
+// next interval begins when the previous one ended, so time spent without
+// updates (e.g. a sleeping laptop) is accounted for correctly.
+func (pt *PomodoroTimer) advanceTo(now time.Time) {
+	for pt.state.IsRunning() && pt.duration > 0 {
+		end := pt.startTime.Add(pt.duration)
+		if now.Before(end) {
+			return
+		}
+		pt.finish(end)
+	}
+}
+
+// finish completes the running interval at the given time
+func (pt *PomodoroTimer) finish(at time.Time) {
+	from := pt.state
+	eventType := EventBreakFinished
+	if from == PomodoroWork {
+		pt.sessions++
+		eventType = EventWorkFinished
+	}
+	next, done := pt.advanceSequence(from)
+	pt.finishInto(eventType, next, pt.autoStart(next) && !done, at)
+}
+
+// advanceSequence returns the interval that follows from. In the sequence
+// mode it moves to the next step and reports done after the last round.
+func (pt *PomodoroTimer) advanceSequence(from PomodoroState) (PomodoroState, bool) {
+	steps := pt.config.sequence()
+	if len(steps) == 0 {
+		if from == PomodoroWork {
+			return pt.nextBreak(), false
+		}
+		return PomodoroWork, false
+	}
+
+	pt.step++
+	done := false
+	if pt.step >= len(steps) {
+		pt.step = 0
+		pt.round++
+		if pt.config.SequenceRepeat > 0 && pt.round >= pt.config.SequenceRepeat {
+			pt.round = 0
+			done = true
+		}
+	}
+	return steps[pt.step].Kind, done
+}
+
+// autoStart reports whether next begins without waiting for Start
+func (pt *PomodoroTimer) autoStart(next PomodoroState) bool {
+	if next.IsBreak() {
+		return pt.config.AutoStartBreaks
+	}
+	return pt.config.AutoStartWork
+}
+
+// intervalLength returns how long an interval of the given state lasts when
+// it starts now; 0 means it counts up until FinishWork
+func (pt *PomodoroTimer) intervalLength(state PomodoroState) time.Duration {
+	if steps := pt.config.sequence(); len(steps) > 0 {
+		return steps[pt.step].Duration()
+	}
+	if pt.config.Mode == ModeFlowtime {
+		if state == PomodoroWork {
+			return 0
+		}
+		return pt.earned
+	}
+	return pt.config.Duration(state)
+}
+
+// finishInto ends the current interval with the given event, then either
+// starts next right away or waits in Idle for Start
+func (pt *PomodoroTimer) finishInto(eventType PomodoroEventType, next PomodoroState, autoStart bool, at time.Time) {
+	pt.next = next
+	pt.transition(eventType, PomodoroIdle, at)
+	pt.duration = 0
+	if autoStart {
+		pt.begin(next, at)
+	}
+}
+
+// begin starts an interval at the given time
+func (pt *PomodoroTimer) begin(state PomodoroState, at time.Time) {
+	pt.next = state
+	pt.startTime = at
+	pt.began = at
+	pt.duration = pt.intervalLength(state)
+	eventType := EventWorkStarted
+	if state.IsBreak() {
+		eventType = EventBreakStarted
+	}
+	pt.transition(eventType, state, at)
+}
+
+// resume continues the paused interval, shifting its start by the pause
+func (pt *PomodoroTimer) resume() {
+	now := pt.clock.Now()
+	pt.startTime = pt.startTime.Add(now.Sub(pt.pausedAt))
+	to := pt.pausedFrom
+	pt.pausedFrom = PomodoroIdle
+	pt.transition(EventResumed, to, now)
+}
+
+// nextBreak returns the break following the work session just completed
+func (pt *PomodoroTimer) nextBreak() PomodoroState {
+	every := pt.config.SessionsUntilLongBreak
+	if every > 0 && pt.sessions%every == 0 {
+		return PomodoroLongBreak
+	}
+	return PomodoroShortBreak
+}
+
+// transition moves to state and queues the event
+func (pt *PomodoroTimer) transition(eventType PomodoroEventType, to PomodoroState, at time.Time) {
+	from := pt.state
+	pt.state = to
+	pt.pending = append(pt.pending, PomodoroEvent{Type: eventType, From: from, To: to, Sessions: pt.sessions, At: at, Start: pt.began, Duration: pt.duration})
+}
+
+// unlockAndDispatch releases the lock and delivers queued events in order
+func (pt *PomodoroTimer) unlockAndDispatch() {
+	events := pt.pending
+	pt.pending = nil
+	subscribers := make([]func(PomodoroEvent), 0, len(pt.subscribers))
+	for id := 0; id < pt.nextSubID; id++ {
+		if fn, ok := pt.subscribers[id]; ok {
+			subscribers = append(subscribers, fn)
+		}
+	}
+	pt.mu.Unlock()
+
+	for _, event := range events {
+		for _, fn := range subscribers {
+			fn(event)
+		}
 	}
 }
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// InterruptionKind tells where an interruption came from
+type InterruptionKind string
+
+const (
+	// InterruptionInternal is an urge of your own, like checking mail
+	InterruptionInternal InterruptionKind = "internal"
+	// InterruptionExternal is someone or something else asking for attention
+	InterruptionExternal InterruptionKind = "external"
+)
+
+// Symbol returns the mark the Pomodoro Technique uses on the daily sheet:
+// an apostrophe for internal and a dash for external interruptions
+func (k InterruptionKind) Symbol() string {
+	if k == InterruptionExternal {
+		return "-"
+	}
+	return "'"
+}
+
+// Interruption is a logged break in focus during a work session. Following
+// the Pomodoro Technique, the session is either continued and the interruption
+// dealt with later, or voided: a Pomodoro is indivisible, so a voided one
+// does not count and work starts over.
+type Interruption struct {
+	Kind      InterruptionKind `json:"kind"`
+	At        time.Time        `json:"at"`
+	Note      string           `json:"note,omitempty"`
+	Voided    bool             `json:"voided,omitempty"`    // The work session was abandoned
+	Task      *PomodoroTask    `json:"task,omitempty"`      // Todo being worked on
+	Converted bool             `json:"converted,omitempty"` // The note was turned into a todo
+}
+
+// InterruptionsOn returns the interruptions logged on the calendar day of day,
+// in its location, oldest first
+func InterruptionsOn(interruptions []Interruption, day time.Time) []Interruption {
+	var result []Interruption
+	y, m, d := day.Date()
+	for _, i := range interruptions {
+		iy, im, id := i.At.In(day.Location()).Date()
+		if iy == y && im == m && id == d {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// Todo creates a task from the note, due at the given time. The first line
+// of the note becomes the name and the whole note the content.
+func (i Interruption) Todo(at time.Time) *TodoItem {
+	todo := NewTodoItem()
+	name := strings.TrimSpace(i.Note)
+	if line := strings.IndexByte(name, '\n'); line >= 0 {
+		name = strings.TrimSpace(name[:line])
+	}
+	todo.SetName(name)
+	todo.SetContent(strings.TrimSpace(i.Note))
+	todo.SetKind(1) // Task
+	todo.SetTime(at)
+	return todo
+}
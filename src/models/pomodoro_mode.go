@@ -0,0 +1,107 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PomodoroMode selects how the timer chooses its intervals
+type PomodoroMode string
+
+const (
+	// ModeClassic alternates work and short breaks with a long break every N sessions
+	ModeClassic PomodoroMode = "classic"
+	// ModeFlowtime counts work up until the user takes a break; the break
+	// length is the focus time divided by FlowtimeRatio
+	ModeFlowtime PomodoroMode = "flowtime"
+	// ModeSequence runs a user-defined list of intervals
+	ModeSequence PomodoroMode = "sequence"
+)
+
+// DefaultFlowtimeRatio earns one minute of break per five minutes of focus
+const DefaultFlowtimeRatio = 5
+
+// maxStepMinutes bounds a single interval of a custom sequence
+const maxStepMinutes = 240
+
+// PomodoroStep is one interval of a custom sequence
+type PomodoroStep struct {
+	Kind    PomodoroState // Work, ShortBreak or LongBreak
+	Minutes int
+}
+
+// Duration returns the length of the step
+func (s PomodoroStep) Duration() time.Duration {
+	return time.Duration(s.Minutes) * time.Minute
+}
+
+// FlowtimeBreak returns the break earned by focusing for elapsed
+func FlowtimeBreak(elapsed time.Duration, ratio int) time.Duration {
+	if ratio <= 0 {
+		ratio = DefaultFlowtimeRatio
+	}
+	earned := (elapsed / time.Duration(ratio)).Round(time.Second)
+	if earned < time.Minute {
+		earned = time.Minute
+	}
+	return earned
+}
+
+// ParsePomodoroSequence parses the sequence notation used in presets:
+// comma-separated minutes that alternate focus and break, starting with focus,
+// and an optional repeat count, e.g. "90, 20 x3". A break may be marked as a
+// long break with an "L" suffix ("25, 5, 25, 15L"). A missing repeat count
+// repeats the sequence until the timer is reset.
+func ParsePomodoroSequence(text string) ([]PomodoroStep, int, error) {
+	text = strings.ToLower(strings.TrimSpace(strings.ReplaceAll(text, "×", "x")))
+	repeat := 0
+	if i := strings.LastIndex(text, "x"); i >= 0 {
+		n, err := strconv.Atoi(strings.TrimSpace(text[i+1:]))
+		if err != nil || n < 1 {
+			return nil, 0, fmt.Errorf("invalid repeat count in %q", text)
+		}
+		repeat = n
+		text = strings.TrimSpace(text[:i])
+	}
+	if text == "" {
+		return nil, 0, errors.New("sequence is empty")
+	}
+
+	var steps []PomodoroStep
+	for i, field := range strings.Split(text, ",") {
+		field = strings.ToUpper(strings.TrimSpace(field))
+		kind := PomodoroWork
+		if i%2 == 1 {
+			kind = PomodoroShortBreak
+			if strings.HasSuffix(field, "L") {
+				kind = PomodoroLongBreak
+				field = strings.TrimSpace(strings.TrimSuffix(field, "L"))
+			}
+		}
+		minutes, err := strconv.Atoi(field)
+		if err != nil || minutes < 1 || minutes > maxStepMinutes {
+			return nil, 0, fmt.Errorf("invalid interval %q: expected 1-%d minutes", field, maxStepMinutes)
+		}
+		steps = append(steps, PomodoroStep{Kind: kind, Minutes: minutes})
+	}
+	return steps, repeat, nil
+}
+
+// FormatPomodoroSequence writes steps in the notation read by ParsePomodoroSequence
+func FormatPomodoroSequence(steps []PomodoroStep, repeat int) string {
+	parts := make([]string, len(steps))
+	for i, step := range steps {
+		parts[i] = strconv.Itoa(step.Minutes)
+		if step.Kind == PomodoroLongBreak {
+			parts[i] += "L"
+		}
+	}
+	text := strings.Join(parts, ", ")
+	if repeat > 0 {
+		text += fmt.Sprintf(" x%d", repeat)
+	}
+	return text
+}
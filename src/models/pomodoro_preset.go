@@ -0,0 +1,117 @@
+package models
+
+// CustomPresetName is the preset that collects edits made to a built-in preset
+const CustomPresetName = "Custom"
+
+// PomodoroPreset is a named set of Pomodoro durations
+type PomodoroPreset struct {
+	Name                   string `json:"name"`
+	WorkDuration           int    `json:"work"`           // in minutes
+	ShortBreakDuration     int    `json:"shortBreak"`     // in minutes
+	LongBreakDuration      int    `json:"longBreak"`      // in minutes
+	SessionsUntilLongBreak int    `json:"longBreakEvery"` // work sessions before a long break
+
+	Mode          PomodoroMode `json:"mode,omitempty"`      // "" is the classic mode
+	FlowtimeRatio int          `json:"flowRatio,omitempty"` // Flowtime: focus minutes per break minute
+	Sequence      string       `json:"sequence,omitempty"`  // Sequence: notation read by ParsePomodoroSequence
+}
+
+// WindowPosition is a screen position in pixels
+type WindowPosition struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// PomodoroSettings stores the Pomodoro presets, the one last used and where
+// the compact window was left
+type PomodoroSettings struct {
+	Presets      []PomodoroPreset `json:"presets,omitempty"`
+	ActivePreset string           `json:"activePreset,omitempty"`
+	MiniPosition *WindowPosition  `json:"miniPosition,omitempty"`
+}
+
+// DefaultPomodoroPresets returns the built-in presets
+func DefaultPomodoroPresets() []PomodoroPreset {
+	return []PomodoroPreset{
+		{Name: "Classic 25/5", WorkDuration: 25, ShortBreakDuration: 5, LongBreakDuration: 15, SessionsUntilLongBreak: 4},
+		{Name: "Deep work 50/10", WorkDuration: 50, ShortBreakDuration: 10, LongBreakDuration: 30, SessionsUntilLongBreak: 3},
+		{Name: "Quick 15/3", WorkDuration: 15, ShortBreakDuration: 3, LongBreakDuration: 10, SessionsUntilLongBreak: 4},
+		{Name: "Flowtime", WorkDuration: 25, ShortBreakDuration: 5, LongBreakDuration: 15, SessionsUntilLongBreak: 4,
+			Mode: ModeFlowtime, FlowtimeRatio: DefaultFlowtimeRatio},
+		{Name: "Ultradian 90/20", WorkDuration: 90, ShortBreakDuration: 20, LongBreakDuration: 20, SessionsUntilLongBreak: 3,
+			Mode: ModeSequence, Sequence: "90, 20 x3"},
+	}
+}
+
+// IsBuiltinPreset reports whether name is one of the built-in presets
+func IsBuiltinPreset(name string) bool {
+	for _, p := range DefaultPomodoroPresets() {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Config returns a timer configuration for the preset. A sequence that does
+// not parse falls back to the classic mode.
+func (p PomodoroPreset) Config() *PomodoroConfig {
+	config := NewDefaultPomodoroConfig()
+	config.WorkDuration = p.WorkDuration
+	config.ShortBreakDuration = p.ShortBreakDuration
+	config.LongBreakDuration = p.LongBreakDuration
+	config.SessionsUntilLongBreak = p.SessionsUntilLongBreak
+	config.Mode = p.Mode
+	config.FlowtimeRatio = p.FlowtimeRatio
+	if p.Mode == ModeSequence {
+		steps, repeat, err := ParsePomodoroSequence(p.Sequence)
+		if err != nil {
+			config.Mode = ModeClassic
+		}
+		config.Sequence = steps
+		config.SequenceRepeat = repeat
+	}
+	return config
+}
+
+// GetPomodoroPresets returns the built-in presets followed by the saved custom ones
+func (c *Config) GetPomodoroPresets() []PomodoroPreset {
+	presets := DefaultPomodoroPresets()
+	for _, p := range c.Pomodoro.Presets {
+		if !IsBuiltinPreset(p.Name) {
+			presets = append(presets, p)
+		}
+	}
+	return presets
+}
+
+// GetActivePomodoroPreset returns the last used preset, or the first built-in one
+func (c *Config) GetActivePomodoroPreset() PomodoroPreset {
+	presets := c.GetPomodoroPresets()
+	for _, p := range presets {
+		if p.Name == c.Pomodoro.ActivePreset {
+			return p
+		}
+	}
+	return presets[0]
+}
+
+// SetActivePomodoroPreset remembers the preset to restore on the next start
+func (c *Config) SetActivePomodoroPreset(name string) {
+	c.Pomodoro.ActivePreset = name
+}
+
+// SavePomodoroPreset adds or replaces a custom preset. Built-in presets are
+// fixed, so saving one under a built-in name is ignored.
+func (c *Config) SavePomodoroPreset(preset PomodoroPreset) {
+	if IsBuiltinPreset(preset.Name) {
+		return
+	}
+	for i, p := range c.Pomodoro.Presets {
+		if p.Name == preset.Name {
+			c.Pomodoro.Presets[i] = preset
+			return
+		}
+	}
+	c.Pomodoro.Presets = append(c.Pomodoro.Presets, preset)
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// stateKeys are the stable names used when a state is written to disk
+var stateKeys = map[PomodoroState]string{
+	PomodoroIdle:       "idle",
+	PomodoroWork:       "work",
+	PomodoroShortBreak: "short_break",
+	PomodoroLongBreak:  "long_break",
+	PomodoroPaused:     "paused",
+}
+
+// MarshalText writes the state as a stable key
+func (s PomodoroState) MarshalText() ([]byte, error) {
+	key, ok := stateKeys[s]
+	if !ok {
+		return nil, fmt.Errorf("unknown pomodoro state %d", int(s))
+	}
+	return []byte(key), nil
+}
+
+// UnmarshalText parses a state key
+func (s *PomodoroState) UnmarshalText(text []byte) error {
+	for state, key := range stateKeys {
+		if key == string(text) {
+			*s = state
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown pomodoro state %q", text)
+}
+
+// PomodoroTimerState is the persisted form of a PomodoroTimer
+type PomodoroTimerState struct {
+	State      PomodoroState `json:"state"`
+	PausedFrom PomodoroState `json:"pausedFrom"`
+	Next       PomodoroState `json:"next"`
+	Sessions   int           `json:"sessions"`
+	StartTime  time.Time     `json:"startTime"`       // Start of the interval, shifted by pauses
+	Began      time.Time     `json:"began,omitempty"` // Start of the interval as it happened
+	PausedAt   time.Time     `json:"pausedAt"`
+	Duration   time.Duration `json:"duration"`         // Length of the current interval in nanoseconds (0 = open-ended)
+	Step       int           `json:"step,omitempty"`   // Position in a custom sequence
+	Round      int           `json:"round,omitempty"`  // Completed rounds of a custom sequence
+	Earned     time.Duration `json:"earned,omitempty"` // Flowtime break earned by the last work session
+}
+
+// PomodoroTask identifies the todo the timer is working on. Todos are found
+// by their stored time and name, like everywhere else in the repository.
+type PomodoroTask struct {
+	Time time.Time `json:"time"`
+	Name string    `json:"name"`
+}
+
+// NewPomodoroTask creates the task reference for todo
+func NewPomodoroTask(todo *TodoItem) *PomodoroTask {
+	return &PomodoroTask{Time: todo.TodoTime, Name: todo.Name}
+}
+
+// Matches reports whether todo is the referenced item
+func (t *PomodoroTask) Matches(todo *TodoItem) bool {
+	return t != nil && todo != nil && todo.TodoTime.Equal(t.Time) && todo.Name == t.Name
+}
+
+// PomodoroRecord is a completed work session or break
+type PomodoroRecord struct {
+	Kind   PomodoroState `json:"kind"` // Work, ShortBreak or LongBreak
+	Start  time.Time     `json:"start"`
+	End    time.Time     `json:"end"`
+	Task   *PomodoroTask `json:"task,omitempty"`   // Todo a work session was spent on
+	Voided bool          `json:"voided,omitempty"` // Work abandoned after an interruption
+}
+
+// PomodoroSession is everything persisted about the Pomodoro timer: its
+// current state, the todo being worked on, the intervals completed so far and
+// the interruptions logged during work
+type PomodoroSession struct {
+	Timer         PomodoroTimerState `json:"timer"`
+	Task          *PomodoroTask      `json:"task,omitempty"`
+	Completed     []PomodoroRecord   `json:"completed,omitempty"`
+	Interruptions []Interruption     `json:"interruptions,omitempty"`
+}
+
+// NewPomodoroRecord creates the record for a finished or voided event, or
+// returns false if the event does not end an interval. The record starts when
+// the interval began and lasts as long as it ran; pauses are left out, so
+// an interval ended while paused does not count the time since.
+func NewPomodoroRecord(event PomodoroEvent) (PomodoroRecord, bool) {
+	if event.Type != EventWorkFinished && event.Type != EventBreakFinished && event.Type != EventVoided {
+		return PomodoroRecord{}, false
+	}
+	start := event.Start
+	if start.IsZero() {
+		start = event.At.Add(-event.Duration)
+	}
+	record := PomodoroRecord{Kind: event.From, Start: start, End: start.Add(event.Duration)}
+	record.Voided = event.Type == EventVoided
+	return record, true
+}
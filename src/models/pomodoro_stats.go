@@ -0,0 +1,147 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// FocusPeriod is the focused time of one day or one week
+type FocusPeriod struct {
+	Start time.Time // Midnight of the day, or of the Monday starting the week
+	Focus time.Duration
+}
+
+// LabelFocus is the focused time spent on todos with one label
+type LabelFocus struct {
+	Label string // "" for work on todos without a label or without a todo
+	Focus time.Duration
+}
+
+// PomodoroStats summarizes the work sessions of a date range
+type PomodoroStats struct {
+	From, To      time.Time            // Midnights bounding the range; To is exclusive
+	Total         time.Duration        // Focused time of completed work sessions
+	Days          []FocusPeriod        // Every day of the range, oldest first
+	Weeks         []FocusPeriod        // Every week touching the range, oldest first
+	Heatmap       [7][24]time.Duration // Focus by time.Weekday and hour of day
+	Labels        []LabelFocus         // Largest first
+	Completed     int                  // Finished work sessions
+	Abandoned     int                  // Voided work sessions
+	Interruptions int
+}
+
+// ComputePomodoroStats summarizes the work records and interruptions between
+// the midnights of from and to (exclusive), in the location of from. Focus is
+// the time of completed work sessions, split across the hours it fell into.
+// labelOf returns the label of a task's todo; it may be nil.
+func ComputePomodoroStats(records []PomodoroRecord, interruptions []Interruption, from, to time.Time, labelOf func(*PomodoroTask) string) PomodoroStats {
+	loc := from.Location()
+	stats := PomodoroStats{From: startOfDay(from), To: startOfDay(to.In(loc))}
+
+	dayIndex := make(map[time.Time]int)
+	for day := stats.From; day.Before(stats.To); day = day.AddDate(0, 0, 1) {
+		dayIndex[day] = len(stats.Days)
+		stats.Days = append(stats.Days, FocusPeriod{Start: day})
+	}
+	weekIndex := make(map[time.Time]int)
+	for _, day := range stats.Days {
+		week := startOfWeek(day.Start)
+		if _, ok := weekIndex[week]; !ok {
+			weekIndex[week] = len(stats.Weeks)
+			stats.Weeks = append(stats.Weeks, FocusPeriod{Start: week})
+		}
+	}
+
+	labels := make(map[string]time.Duration)
+	for _, record := range records {
+		if record.Kind != PomodoroWork {
+			continue
+		}
+		start, end := record.Start.In(loc), record.End.In(loc)
+		if !end.After(stats.From) || !start.Before(stats.To) {
+			continue
+		}
+		if record.Voided {
+			stats.Abandoned++
+			continue
+		}
+		stats.Completed++
+
+		if start.Before(stats.From) {
+			start = stats.From
+		}
+		if end.After(stats.To) {
+			end = stats.To
+		}
+		focus := end.Sub(start)
+		stats.Total += focus
+		if labelOf != nil && record.Task != nil {
+			labels[labelOf(record.Task)] += focus
+		} else {
+			labels[""] += focus
+		}
+
+		// Split the session at every full hour
+		for t := start; t.Before(end); {
+			next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			if next.After(end) {
+				next = end
+			}
+			part := next.Sub(t)
+			stats.Heatmap[t.Weekday()][t.Hour()] += part
+			day := startOfDay(t)
+			stats.Days[dayIndex[day]].Focus += part
+			stats.Weeks[weekIndex[startOfWeek(day)]].Focus += part
+			t = next
+		}
+	}
+
+	for _, interruption := range interruptions {
+		at := interruption.At.In(loc)
+		if !at.Before(stats.From) && at.Before(stats.To) {
+			stats.Interruptions++
+		}
+	}
+
+	for label, focus := range labels {
+		stats.Labels = append(stats.Labels, LabelFocus{Label: label, Focus: focus})
+	}
+	sort.Slice(stats.Labels, func(i, j int) bool {
+		if stats.Labels[i].Focus != stats.Labels[j].Focus {
+			return stats.Labels[i].Focus > stats.Labels[j].Focus
+		}
+		return stats.Labels[i].Label < stats.Labels[j].Label
+	})
+	return stats
+}
+
+// CompletionRate returns the share of work sessions that were completed
+// rather than abandoned (0 without sessions)
+func (s PomodoroStats) CompletionRate() float64 {
+	sessions := s.Completed + s.Abandoned
+	if sessions == 0 {
+		return 0
+	}
+	return float64(s.Completed) / float64(sessions)
+}
+
+// InterruptionsPerPomodoro returns the average number of interruptions per
+// work session, completed or abandoned
+func (s PomodoroStats) InterruptionsPerPomodoro() float64 {
+	sessions := s.Completed + s.Abandoned
+	if sessions == 0 {
+		return 0
+	}
+	return float64(s.Interruptions) / float64(sessions)
+}
+
+// startOfDay returns midnight of t's day in its location
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// startOfWeek returns midnight of the Monday starting t's week
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return startOfDay(t).AddDate(0, 0, -offset)
+}
@@ -59,3 +59,16 @@ func (p PriorityLevel) GetShortLabel() string {
 		return "Unknown"
 	}
 }
+
+// MatrixLevels lists the levels in the order the Eisenhower matrix shows
+// them: Do and Schedule on top, Delegate and Eliminate below
+var MatrixLevels = []PriorityLevel{PriorityUrgent, PriorityHigh, PriorityMedium, PriorityLow}
+
+// PriorityOf returns the level of todo; levels out of range count as low
+func PriorityOf(todo *TodoItem) PriorityLevel {
+	level := PriorityLevel(todo.Level)
+	if level < PriorityLow || level > PriorityUrgent {
+		return PriorityLow
+	}
+	return level
+}
@@ -32,3 +32,29 @@ func SortTodosByOrder(todos []*TodoItem) {
 		return a.TodoTime.After(b.TodoTime)
 	})
 }
+
+// MoveTodo moves the item at index from to index to, clamped to the list, and
+// numbers the Order of all items from 1 so the new position sticks
+func MoveTodo(todos []*TodoItem, from, to int) {
+	if from < 0 || from >= len(todos) {
+		return
+	}
+	if to < 0 {
+		to = 0
+	}
+	if to >= len(todos) {
+		to = len(todos) - 1
+	}
+
+	item := todos[from]
+	if to > from {
+		copy(todos[from:], todos[from+1:to+1])
+	} else {
+		copy(todos[to+1:], todos[to:from])
+	}
+	todos[to] = item
+
+	for i, t := range todos {
+		t.Order = i + 1
+	}
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// MinBlockDuration is the time a point-in-time item occupies when checking for
+// overlaps and when drawn in the day planner
+const MinBlockDuration = 15 * time.Minute
+
+// TimeBlock is an item placed on the hourly day planner
+type TimeBlock struct {
+	Todo  *TodoItem
+	Start time.Time // Clipped to the planned day
+	End   time.Time // Clipped to the planned day
+	Lane  int       // Column within its group of overlapping blocks
+	Lanes int       // Number of columns in that group
+}
+
+// blockSpan returns the interval an item occupies in loc, giving point-in-time
+// items a minimal length so two items at the same minute still collide
+func blockSpan(todo *TodoItem, loc *time.Location) (time.Time, time.Time) {
+	start, end := todo.TimeRange(loc)
+	if end.Sub(start) < MinBlockDuration {
+		end = start.Add(MinBlockDuration)
+	}
+	return start, end
+}
+
+// Overlaps reports whether two timed items share any time, as seen in loc.
+// All-day items never overlap timed ones.
+func (t *TodoItem) Overlaps(other *TodoItem, loc *time.Location) bool {
+	if t.AllDay || other.AllDay {
+		return false
+	}
+	aStart, aEnd := blockSpan(t, loc)
+	bStart, bEnd := blockSpan(other, loc)
+	return aStart.Before(bEnd) && bStart.Before(aEnd)
+}
+
+// FindConflicts returns the events in items that overlap candidate.
+// Only timed events (Kind 0) block time; tasks and all-day items are ignored.
+func FindConflicts(candidate *TodoItem, items []*TodoItem, loc *time.Location) []*TodoItem {
+	if candidate.Kind != 0 || candidate.AllDay {
+		return nil
+	}
+
+	var conflicts []*TodoItem
+	for _, item := range items {
+		if item == candidate || item.Kind != 0 || item.AllDay {
+			continue
+		}
+		if candidate.Overlaps(item, loc) {
+			conflicts = append(conflicts, item)
+		}
+	}
+	return conflicts
+}
+
+// PlanDay lays out the items of a calendar day for the hourly planner.
+// All-day items are returned separately; timed items become blocks clipped to
+// the day, and overlapping blocks are spread over side-by-side lanes.
+func PlanDay(items []*TodoItem, day time.Time, loc *time.Location) ([]*TodoItem, []TimeBlock) {
+	dayStart := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+
+	var allDay []*TodoItem
+	var blocks []TimeBlock
+	for _, item := range items {
+		if item.AllDay {
+			allDay = append(allDay, item)
+			continue
+		}
+		start, end := blockSpan(item, loc)
+		if !start.Before(dayEnd) || !end.After(dayStart) {
+			continue
+		}
+		if start.Before(dayStart) {
+			start = dayStart
+		}
+		if end.After(dayEnd) {
+			end = dayEnd
+		}
+		blocks = append(blocks, TimeBlock{Todo: item, Start: start, End: end})
+	}
+
+	sort.SliceStable(blocks, func(i, j int) bool {
+		if blocks[i].Start.Equal(blocks[j].Start) {
+			return blocks[i].End.After(blocks[j].End)
+		}
+		return blocks[i].Start.Before(blocks[j].Start)
+	})
+	assignLanes(blocks)
+	return allDay, blocks
+}
+
+// assignLanes puts each block in the first free lane of its overlap group.
+// blocks must be sorted by start time.
+func assignLanes(blocks []TimeBlock) {
+	groupStart := 0
+	var groupEnd time.Time
+	var laneEnds []time.Time
+
+	closeGroup := func(end int) {
+		for i := groupStart; i < end; i++ {
+			blocks[i].Lanes = len(laneEnds)
+		}
+	}
+
+	for i := range blocks {
+		if i > 0 && !blocks[i].Start.Before(groupEnd) {
+			// No overlap with anything before: start a new group
+			closeGroup(i)
+			groupStart = i
+			laneEnds = laneEnds[:0]
+		}
+
+		lane := -1
+		for l, end := range laneEnds {
+			if !blocks[i].Start.Before(end) {
+				lane = l
+				break
+			}
+		}
+		if lane == -1 {
+			lane = len(laneEnds)
+			laneEnds = append(laneEnds, time.Time{})
+		}
+		laneEnds[lane] = blocks[i].End
+		blocks[i].Lane = lane
+
+		if blocks[i].End.After(groupEnd) {
+			groupEnd = blocks[i].End
+		}
+	}
+	closeGroup(len(blocks))
+}
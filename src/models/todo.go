@@ -3,22 +3,30 @@ package models
 import (
 	"image/color"
 	"time"
+
+	"godo/src/utils"
 )
 
 // TodoItem represents a single todo item with all its properties
 // This struct matches the original C++ TodoItem class structure
 type TodoItem struct {
-	Name     string    `json:"name"`                                   // Todo item name
-	Content  string    `json:"content"`                                // Detailed content/description
-	Place    string    `json:"place"`                                  // Location information
-	Label    string    `json:"label"`                                  // Custom label/tag
-	Kind     int       `json:"kind"`                                   // Type: 0=Event, 1=Task
-	Level    int       `json:"level"`                                  // Priority level: 0=Low, 1=Medium, 2=High, 3=Urgent
-	TodoTime time.Time `json:"todoTime"`                               // Due date and time
-	Done     bool      `json:"done"`                                   // Completion status
-	WarnTime int       `json:"warnTime"`                               // Reminder time in minutes before due time
-	Starred  bool      `json:"starred"`                                // Mark as important
-	Order    int       `json:"order,omitempty" yaml:"order,omitempty"` // Implicit UI order within a day (0 = unset)
+	Name      string    `json:"name"`                                           // Todo item name
+	Content   string    `json:"content"`                                        // Detailed content/description
+	Place     string    `json:"place"`                                          // Location information
+	Label     string    `json:"label"`                                          // Custom label/tag
+	Kind      int       `json:"kind"`                                           // Type: 0=Event, 1=Task
+	Level     int       `json:"level"`                                          // Priority level: 0=Low, 1=Medium, 2=High, 3=Urgent
+	TodoTime  time.Time `json:"todoTime"`                                       // Due date and time
+	Done      bool      `json:"done"`                                           // Completion status
+	WarnTime  int       `json:"warnTime"`                                       // Reminder time in minutes before due time
+	Starred   bool      `json:"starred"`                                        // Mark as important
+	Order     int       `json:"order,omitempty" yaml:"order,omitempty"`         // Implicit UI order within a day (0 = unset)
+	TimeZone  string    `json:"timeZone,omitempty" yaml:"timezone,omitempty"`   // IANA zone the time was scheduled in ("" = stored offset only)
+	Floating  bool      `json:"floating,omitempty" yaml:"floating,omitempty"`   // Wall-clock time without a zone (all-day items)
+	Duration  int       `json:"duration,omitempty" yaml:"duration,omitempty"`   // Length in minutes (0 = point in time)
+	AllDay    bool      `json:"allDay,omitempty" yaml:"allday,omitempty"`       // Spans the whole calendar day
+	Estimate  int       `json:"estimate,omitempty" yaml:"estimate,omitempty"`   // Planned Pomodoros (0 = no estimate)
+	Pomodoros int       `json:"pomodoros,omitempty" yaml:"pomodoros,omitempty"` // Finished Pomodoros spent on the item
 }
 
 // NewTodoItem creates a new TodoItem with default values
@@ -62,6 +70,52 @@ func (t *TodoItem) SetTime(todoTime time.Time) {
 	t.TodoTime = todoTime
 }
 
+// SetZonedTime sets the time and records the IANA zone it was scheduled in.
+// An empty zone keeps only the UTC offset of todoTime.
+func (t *TodoItem) SetZonedTime(todoTime time.Time, zone string) {
+	t.TodoTime = todoTime
+	t.TimeZone = zone
+	t.Floating = false
+}
+
+// SetFloatingTime stores only the wall clock of todoTime, so the item stays on
+// the same calendar day and hour in whatever zone it is viewed.
+func (t *TodoItem) SetFloatingTime(todoTime time.Time) {
+	year, month, day := todoTime.Date()
+	t.TodoTime = time.Date(year, month, day, todoTime.Hour(), todoTime.Minute(), todoTime.Second(), 0, time.UTC)
+	t.TimeZone = ""
+	t.Floating = true
+}
+
+// SetDuration sets the length of the item in minutes
+func (t *TodoItem) SetDuration(minutes int) {
+	if minutes < 0 {
+		minutes = 0
+	}
+	t.Duration = minutes
+}
+
+// SetAllDay turns the item into an all-day entry on the calendar day of day.
+// All-day items are floating so they stay on that date in every zone.
+func (t *TodoItem) SetAllDay(day time.Time) {
+	t.SetFloatingTime(time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location()))
+	t.AllDay = true
+	t.Duration = 0
+}
+
+// SetEstimate sets the number of Pomodoros planned for the item
+func (t *TodoItem) SetEstimate(pomodoros int) {
+	if pomodoros < 0 {
+		pomodoros = 0
+	}
+	t.Estimate = pomodoros
+}
+
+// EstimateReached reports whether the finished Pomodoros meet the estimate
+func (t *TodoItem) EstimateReached() bool {
+	return t.Estimate > 0 && t.Pomodoros >= t.Estimate
+}
+
 func (t *TodoItem) SetWarnTime(warnTime int) {
 	t.WarnTime = warnTime
 }
@@ -122,6 +176,52 @@ func (t *TodoItem) HaveDone() bool {
 	return t.IsDone()
 }
 
+// ZonedTime returns the time in the zone the item was scheduled in.
+// Month files are bucketed by this value so travelling does not move items.
+func (t *TodoItem) ZonedTime() time.Time {
+	if t.Floating {
+		return t.TodoTime
+	}
+	if loc := utils.LoadZone(t.TimeZone); loc != nil {
+		return t.TodoTime.In(loc)
+	}
+	return t.TodoTime
+}
+
+// DisplayTime returns the time as seen in loc: zoned items are converted,
+// floating items keep their wall clock.
+func (t *TodoItem) DisplayTime(loc *time.Location) time.Time {
+	if t.Floating {
+		year, month, day := t.TodoTime.Date()
+		return time.Date(year, month, day, t.TodoTime.Hour(), t.TodoTime.Minute(), t.TodoTime.Second(), 0, loc)
+	}
+	return t.TodoTime.In(loc)
+}
+
+// OccursOn reports whether the item falls on the calendar day of day, as seen in loc.
+// Comparing calendar dates keeps 23- and 25-hour DST days correct.
+func (t *TodoItem) OccursOn(day time.Time, loc *time.Location) bool {
+	return utils.SameDay(t.DisplayTime(loc), day)
+}
+
+// TimeRange returns the start and end of the item as seen in loc.
+// All-day items cover their whole calendar day; items without a duration
+// start and end at the same instant.
+func (t *TodoItem) TimeRange(loc *time.Location) (time.Time, time.Time) {
+	start := t.DisplayTime(loc)
+	if t.AllDay {
+		start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+		return start, start.AddDate(0, 0, 1)
+	}
+	return start, start.Add(time.Duration(t.Duration) * time.Minute)
+}
+
+// EndTime returns the end of the item as seen in loc
+func (t *TodoItem) EndTime(loc *time.Location) time.Time {
+	_, end := t.TimeRange(loc)
+	return end
+}
+
 // IsBefore returns true if this todo item comes before the other item chronologically
 func (t *TodoItem) IsBefore(other *TodoItem) bool {
 	return t.TodoTime.Before(other.TodoTime)
@@ -173,6 +273,27 @@ func (t *TodoItem) ShouldRemind(currentTime time.Time) bool {
 		return false
 	}
 
-	remindTime := t.TodoTime.Add(-time.Duration(t.WarnTime) * time.Minute)
-	return currentTime.After(remindTime) && currentTime.Before(t.TodoTime)
+	dueTime := t.DisplayTime(currentTime.Location())
+	return currentTime.After(t.RemindTime(currentTime.Location())) && currentTime.Before(dueTime)
+}
+
+// RemindTime returns when the reminder of this item is due, as seen in loc.
+// It is only meaningful when WarnTime is set.
+func (t *TodoItem) RemindTime(loc *time.Location) time.Time {
+	return t.DisplayTime(loc).Add(-time.Duration(t.WarnTime) * time.Minute)
+}
+
+// SameTodo reports whether two versions of a todo are equal; nil stands for
+// a missing todo. Times are compared as instants, so a todo read back in
+// another location still matches.
+func SameTodo(a, b *TodoItem) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if !a.TodoTime.Equal(b.TodoTime) {
+		return false
+	}
+	ac, bc := *a, *b
+	ac.TodoTime, bc.TodoTime = time.Time{}, time.Time{}
+	return ac == bc
 }
@@ -2,6 +2,7 @@ package persistence
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,6 +10,13 @@ import (
 	"godo/src/models"
 )
 
+// The config holds the local API token and the calendar password, so only the
+// user may read it or enter the data directory
+const (
+	configFileMode os.FileMode = 0600
+	dataDirMode    os.FileMode = 0700
+)
+
 // ConfigManager manages application configuration persistence
 type ConfigManager struct {
 	configPath string
@@ -22,13 +30,22 @@ func NewConfigManager(dataDir string) *ConfigManager {
 }
 
 // LoadConfig loads the configuration from disk
-// Returns default config if file doesn't exist
+// Returns default config if file doesn't exist. Older config versions are
+// upgraded and written back; a config from a newer release is refused with
+// *NewerVersionError so it is never overwritten.
 func (cm *ConfigManager) LoadConfig() (*models.Config, error) {
 	// Check if config file exists
-	if _, err := os.Stat(cm.configPath); os.IsNotExist(err) {
+	info, err := os.Stat(cm.configPath)
+	if os.IsNotExist(err) {
 		// Return default config if file doesn't exist
 		return models.NewDefaultConfig(), nil
 	}
+	// Earlier releases wrote the file readable by everyone
+	if err == nil && info.Mode().Perm()&^configFileMode != 0 {
+		if err := os.Chmod(cm.configPath, configFileMode); err != nil {
+			fmt.Printf("Failed to restrict config file permissions: %v\n", err)
+		}
+	}
 
 	// Read file
 	data, err := os.ReadFile(cm.configPath)
@@ -36,42 +53,58 @@ func (cm *ConfigManager) LoadConfig() (*models.Config, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
+	// Upgrade older schema versions
+	version, err := detectConfigVersion(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	upgraded, err := configMigrations.Migrate(data, version)
+	if err != nil {
+		var newer *NewerVersionError
+		if errors.As(err, &newer) {
+			newer.Path = cm.configPath
+			return nil, newer
+		}
+		return nil, fmt.Errorf("failed to migrate config file: %w", err)
+	}
+
 	// Parse JSON
 	var config models.Config
-	if err := json.Unmarshal(data, &config); err != nil {
+	if err := json.Unmarshal(upgraded, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
+	if version < configMigrations.Current() {
+		if err := writeFileAtomicPerm(cm.configPath, upgraded, configFileMode); err != nil {
+			return nil, fmt.Errorf("failed to save migrated config file: %w", err)
+		}
+	}
+
 	return &config, nil
 }
 
-// SaveConfig saves the configuration to disk
+// SaveConfig saves the configuration to disk, stamped with the schema
+// version of this build
 func (cm *ConfigManager) SaveConfig(config *models.Config) error {
 	// Ensure data directory exists
 	dataDir := filepath.Dir(cm.configPath)
-	if err := os.MkdirAll(dataDir, 0755); err != nil {
+	if err := os.MkdirAll(dataDir, dataDirMode); err != nil {
 		return fmt.Errorf("failed to create data directory: %w", err)
 	}
 
 	// Marshal to JSON with indentation for readability
-	data, err := json.MarshalIndent(config, "", "  ")
+	stamped := *config
+	stamped.Version = configVersionString(CurrentConfigVersion)
+	data, err := json.MarshalIndent(&stamped, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
 	// Write to temporary file first (atomic write pattern)
-	tmpPath := cm.configPath + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+	if err := writeFileAtomicPerm(cm.configPath, data, configFileMode); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
-	// Rename temporary file to actual config file
-	if err := os.Rename(tmpPath, cm.configPath); err != nil {
-		// Clean up temp file on error
-		os.Remove(tmpPath)
-		return fmt.Errorf("failed to rename config file: %w", err)
-	}
-
 	return nil
 }
 
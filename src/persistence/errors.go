@@ -0,0 +1,46 @@
+package persistence
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// CorruptFileError reports a monthly data file that could not be parsed.
+// Loading stops instead of returning an empty list, so the damaged file is
+// never overwritten by a subsequent save.
+type CorruptFileError struct {
+	Path           string // File that failed to parse
+	Line           int    // 1-based line of the first problem (0 if unknown)
+	Reason         string // Human-readable description of the problem
+	QuarantinePath string // Copy of the damaged file kept aside (empty if copying failed)
+	Err            error  // Underlying parser error, if any
+}
+
+func (e *CorruptFileError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("corrupt data file %s (line %d): %s", e.Path, e.Line, e.Reason)
+	}
+	return fmt.Sprintf("corrupt data file %s: %s", e.Path, e.Reason)
+}
+
+func (e *CorruptFileError) Unwrap() error {
+	return e.Err
+}
+
+// yamlLinePattern extracts the line number from yaml.v3 error messages
+// such as "yaml: line 7: did not find expected key".
+var yamlLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// yamlErrorLine returns the first line number mentioned by a YAML error, or 0.
+func yamlErrorLine(err error) int {
+	if err == nil {
+		return 0
+	}
+	match := yamlLinePattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return 0
+	}
+	line, _ := strconv.Atoi(match[1])
+	return line
+}
@@ -0,0 +1,135 @@
+package persistence
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"godo/src/models"
+	"godo/src/utils"
+)
+
+// TodoChange is the kind of change a TodoEvent reports
+type TodoChange int
+
+const (
+	TodosAdded    TodoChange = iota // Items were added
+	TodosUpdated                    // Items were changed, possibly moving to another month
+	TodosRemoved                    // Items were removed
+	TodosSaved                      // Months were replaced as a whole; Items is their new content
+	TodosReloaded                   // Another program or a repair changed the files; Items is empty
+)
+
+// String returns a readable change name
+func (c TodoChange) String() string {
+	switch c {
+	case TodosAdded:
+		return "added"
+	case TodosUpdated:
+		return "updated"
+	case TodosRemoved:
+		return "removed"
+	case TodosSaved:
+		return "saved"
+	case TodosReloaded:
+		return "reloaded"
+	default:
+		return "unknown"
+	}
+}
+
+// TodoEvent describes a change to the todos of a repository
+type TodoEvent struct {
+	Change TodoChange
+	Months []string           // Affected months as sorted YYYYMM keys; empty when unknown
+	Items  []*models.TodoItem // Copies of the affected todos as they are now, or as they were when removed
+}
+
+// newTodoEvent creates an event for items, copying them so subscribers on
+// other goroutines never share the repository's cache. The months of items
+// are added to months.
+func newTodoEvent(change TodoChange, items []*models.TodoItem, months ...string) TodoEvent {
+	event := TodoEvent{Change: change}
+	seen := make(map[string]bool)
+	addMonth := func(dateKey string) {
+		if !seen[dateKey] {
+			seen[dateKey] = true
+			event.Months = append(event.Months, dateKey)
+		}
+	}
+	for _, dateKey := range months {
+		addMonth(dateKey)
+	}
+	for _, item := range items {
+		copied := *item
+		event.Items = append(event.Items, &copied)
+		addMonth(utils.FormatDateKey(todoMonth(item)))
+	}
+	sort.Strings(event.Months)
+	return event
+}
+
+// AffectsDay reports whether the event may change the todos shown for the
+// calendar day of day. Items are filed by the zone they were scheduled in, so
+// a day also shows items of the neighbouring months.
+func (e TodoEvent) AffectsDay(day time.Time) bool {
+	if len(e.Months) == 0 {
+		return true
+	}
+	for _, near := range []time.Time{day.AddDate(0, 0, -1), day, day.AddDate(0, 0, 1)} {
+		dateKey := utils.FormatDateKey(near.Year(), int(near.Month()))
+		for _, month := range e.Months {
+			if month == dateKey {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TodoEvents delivers the changes of a repository to its subscribers. Events
+// are delivered on the goroutine that made the change, which is a background
+// goroutine for edits noticed by Watch; subscribers that touch widgets must
+// move to the main thread themselves.
+type TodoEvents struct {
+	mu          sync.Mutex
+	subscribers map[int]func(TodoEvent)
+	nextSubID   int
+}
+
+// NewTodoEvents creates an event bus without subscribers
+func NewTodoEvents() *TodoEvents {
+	return &TodoEvents{subscribers: make(map[int]func(TodoEvent))}
+}
+
+// Subscribe registers fn for every event and returns a function that
+// unregisters it
+func (e *TodoEvents) Subscribe(fn func(TodoEvent)) func() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	id := e.nextSubID
+	e.nextSubID++
+	e.subscribers[id] = fn
+	return func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		delete(e.subscribers, id)
+	}
+}
+
+// Publish delivers event to the subscribers in the order they subscribed
+func (e *TodoEvents) Publish(event TodoEvent) {
+	e.mu.Lock()
+	subscribers := make([]func(TodoEvent), 0, len(e.subscribers))
+	for id := 0; id < e.nextSubID; id++ {
+		if fn, ok := e.subscribers[id]; ok {
+			subscribers = append(subscribers, fn)
+		}
+	}
+	e.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(event)
+	}
+}
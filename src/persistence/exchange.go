@@ -0,0 +1,91 @@
+package persistence
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"godo/src/models"
+	"godo/src/utils"
+)
+
+// Helpers shared by the readers and writers of todo.txt, Markdown and CSV
+// files, which other tools and people read and write.
+
+// LineError reports an entry of an imported file that could not be read as
+// a todo
+type LineError struct {
+	Line int    // 1-based line number, or position of the entry in JSON
+	Text string // The entry as read
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// formatMinutes writes minutes in the largest whole unit
+func formatMinutes(minutes int) string {
+	switch {
+	case minutes%(24*60) == 0:
+		return fmt.Sprintf("%dd", minutes/(24*60))
+	case minutes%60 == 0:
+		return fmt.Sprintf("%dh", minutes/60)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+// parseMinutes reads minutes written with an optional m, h or d unit
+func parseMinutes(value string) (int, error) {
+	if value == "" {
+		return 0, errors.New("not a number of minutes")
+	}
+	unit := 1
+	switch value[len(value)-1] {
+	case 'm':
+		value = value[:len(value)-1]
+	case 'h':
+		unit, value = 60, value[:len(value)-1]
+	case 'd':
+		unit, value = 24*60, value[:len(value)-1]
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, errors.New("not a number of minutes")
+	}
+	return n * unit, nil
+}
+
+// wallClock returns the time of todo as written to files: in the zone it was
+// scheduled in, as its own wall clock if floating, and in local time otherwise
+func wallClock(todo *models.TodoItem) time.Time {
+	if !todo.Floating && todo.TimeZone == "" {
+		return todo.TodoTime.In(time.Local)
+	}
+	return todo.ZonedTime()
+}
+
+// setWallClock sets the time of todo to the wall clock of at read in zone,
+// or in local time when zone is "". An all-day todo only keeps the date.
+func setWallClock(todo *models.TodoItem, at time.Time, allDay bool, zone string) error {
+	loc := time.Local
+	if zone != "" {
+		if loc = utils.LoadZone(zone); loc == nil {
+			return fmt.Errorf("unknown time zone %q", zone)
+		}
+	}
+
+	at = time.Date(at.Year(), at.Month(), at.Day(), at.Hour(), at.Minute(), at.Second(), 0, loc)
+	if allDay {
+		todo.SetAllDay(at)
+	} else {
+		todo.SetZonedTime(at, zone)
+	}
+	return nil
+}
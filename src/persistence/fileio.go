@@ -2,7 +2,10 @@ package persistence
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -15,6 +18,12 @@ import (
 	"godo/src/utils"
 )
 
+// monthlyYAML is the on-disk wrapper of a monthly file, kept for future extensions
+type monthlyYAML struct {
+	Version int                `yaml:"version"`
+	Todos   []*models.TodoItem `yaml:"todos"`
+}
+
 // FileIOManager handles file operations for todo data persistence
 type FileIOManager struct {
 	dataDir string
@@ -29,7 +38,7 @@ func NewFileIOManager(dataDir string) *FileIOManager {
 
 // EnsureDataDirectory creates the data directory if it doesn't exist
 func (f *FileIOManager) EnsureDataDirectory() error {
-	return os.MkdirAll(f.dataDir, 0755)
+	return os.MkdirAll(f.dataDir, dataDirMode)
 }
 
 // getYamlFilePath returns YAML file path for a specific year/month
@@ -55,38 +64,15 @@ func (f *FileIOManager) SaveTodos(year, month int, todos []*models.TodoItem) err
 		return fmt.Errorf("failed to create data directory: %w", err)
 	}
 
-	// YAML format with a simple wrapper for future extensions
-	type monthlyYAML struct {
-		Version int                `yaml:"version"`
-		Todos   []*models.TodoItem `yaml:"todos"`
-	}
-
-	content := monthlyYAML{Version: 1, Todos: todos}
+	content := monthlyYAML{Version: CurrentMonthlyVersion, Todos: todos}
 
 	data, err := yaml.Marshal(&content)
 	if err != nil {
 		return fmt.Errorf("failed to marshal YAML: %w", err)
 	}
 
-	filePath := f.getYamlFilePath(year, month)
-	tempPath := filePath + ".tmp"
-
 	// Write atomically
-	if err := os.WriteFile(tempPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write temp YAML: %w", err)
-	}
-
-	if _, err := os.Stat(filePath); err == nil {
-		if err := os.Remove(filePath); err != nil {
-			return fmt.Errorf("failed to remove existing YAML: %w", err)
-		}
-	}
-
-	if err := os.Rename(tempPath, filePath); err != nil {
-		return fmt.Errorf("failed to rename temp YAML: %w", err)
-	}
-
-	return nil
+	return writeFileAtomic(f.getYamlFilePath(year, month), data)
 }
 
 // writeTodoItem writes a single todo item to the file
@@ -159,48 +145,83 @@ func (f *FileIOManager) writeMultiLineString(writer *bufio.Writer, s string) err
 	return nil
 }
 
-// LoadTodos loads todo items from a monthly file
+// LoadTodos loads todo items from a monthly file.
+// Older schema versions are upgraded through the monthly migration registry and
+// written back, files from a newer release are refused with *NewerVersionError,
+// and a file that cannot be parsed is copied to the quarantine folder and
+// reported as *CorruptFileError rather than being treated as an empty month.
 func (f *FileIOManager) LoadTodos(year, month int) ([]*models.TodoItem, error) {
 	// Prefer YAML
 	yamlPath := f.getYamlFilePath(year, month)
-	if file, err := os.ReadFile(yamlPath); err == nil {
-		// Try wrapper format first
-		type monthlyYAML struct {
-			Version int                `yaml:"version"`
-			Todos   []*models.TodoItem `yaml:"todos"`
+	if data, err := os.ReadFile(yamlPath); err == nil {
+		version, perr := detectMonthlyVersion(data)
+		if perr != nil {
+			return nil, f.corruptFile(yamlPath, data, perr)
 		}
-		var wrapper monthlyYAML
-		if err := yaml.Unmarshal(file, &wrapper); err == nil && wrapper.Todos != nil {
-			// Ensure deterministic order: newest first
-			return wrapper.Todos, nil
-		}
-		// Fallback: direct list
-		var list []*models.TodoItem
-		if err := yaml.Unmarshal(file, &list); err == nil {
-			return list, nil
-		}
-		return []*models.TodoItem{}, nil
+		return f.loadMonthly(year, month, yamlPath, data, version)
 	} else if !os.IsNotExist(err) {
 		return nil, fmt.Errorf("failed to read YAML: %w", err)
 	}
 
-	// Fallback to legacy TXT
-	return f.loadTodosTxt(year, month)
-}
-
-// loadTodosTxt loads legacy TXT format and is robust to trailing blank lines
-func (f *FileIOManager) loadTodosTxt(year, month int) ([]*models.TodoItem, error) {
-	filePath := f.getTxtFilePath(year, month)
-	file, err := os.Open(filePath)
+	// Fallback to legacy TXT, which is schema version 0
+	txtPath := f.getTxtFilePath(year, month)
+	data, err := os.ReadFile(txtPath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return []*models.TodoItem{}, nil
 		}
 		return nil, fmt.Errorf("failed to open legacy TXT file: %w", err)
 	}
-	defer file.Close()
+	return f.loadMonthly(year, month, txtPath, data, 0)
+}
 
-	scanner := bufio.NewScanner(file)
+// loadMonthly upgrades data to the current schema, parses it and persists the
+// upgraded form as YAML when a migration was applied.
+func (f *FileIOManager) loadMonthly(year, month int, path string, data []byte, version int) ([]*models.TodoItem, error) {
+	upgraded, err := monthlyMigrations.Migrate(data, version)
+	if err != nil {
+		var newer *NewerVersionError
+		if errors.As(err, &newer) {
+			newer.Path = path
+			return nil, newer
+		}
+		var perr *CorruptFileError
+		if errors.As(err, &perr) {
+			return nil, f.corruptFile(path, data, perr)
+		}
+		return nil, fmt.Errorf("failed to migrate %s: %w", filepath.Base(path), err)
+	}
+
+	todos, perr := parseTodosYAML(upgraded)
+	if perr != nil {
+		return nil, f.corruptFile(path, data, perr)
+	}
+
+	if version < monthlyMigrations.Current() {
+		// Write back once so the upgrade does not run on every load
+		if err := writeFileAtomic(f.getYamlFilePath(year, month), upgraded); err != nil {
+			return nil, fmt.Errorf("failed to save migrated %s: %w", filepath.Base(path), err)
+		}
+	}
+	return todos, nil
+}
+
+// parseTodosYAML decodes a current-version monthly file
+func parseTodosYAML(data []byte) ([]*models.TodoItem, *CorruptFileError) {
+	var wrapper monthlyYAML
+	if err := yaml.Unmarshal(data, &wrapper); err != nil {
+		return nil, &CorruptFileError{Line: yamlErrorLine(err), Reason: "invalid todo record", Err: err}
+	}
+	if wrapper.Todos == nil {
+		return []*models.TodoItem{}, nil
+	}
+	return wrapper.Todos, nil
+}
+
+// parseTodosTxt parses the legacy TXT format. On failure it returns the records
+// read before the first bad one together with the error describing it.
+func parseTodosTxt(data []byte) ([]*models.TodoItem, *CorruptFileError) {
+	scanner := newLineScanner(bytes.NewReader(data))
 
 	// Read first line: count
 	if !scanner.Scan() {
@@ -208,17 +229,20 @@ func (f *FileIOManager) loadTodosTxt(year, month int) ([]*models.TodoItem, error
 	}
 	countStr := strings.TrimSpace(scanner.Text())
 	count, err := strconv.Atoi(countStr)
-	if err != nil {
-		// corrupted file, ignore
-		return []*models.TodoItem{}, nil
+	if err != nil || count < 0 {
+		return []*models.TodoItem{}, &CorruptFileError{Line: scanner.Line(), Reason: fmt.Sprintf("invalid record count %q", countStr), Err: err}
 	}
 
 	todos := make([]*models.TodoItem, 0, count)
 	for i := 0; i < count; i++ {
-		todo, _, err := f.readTodoItem(scanner)
+		startLine := scanner.Line() + 1
+		todo, _, err := readTodoItem(scanner)
 		if err != nil {
-			// stop on parse error and return what we have so far
-			break
+			return todos, &CorruptFileError{
+				Line:   scanner.Line(),
+				Reason: fmt.Sprintf("record %d (starting at line %d): %v", i+1, startLine, err),
+				Err:    err,
+			}
 		}
 		todos = append(todos, todo)
 	}
@@ -227,12 +251,12 @@ func (f *FileIOManager) loadTodosTxt(year, month int) ([]*models.TodoItem, error
 }
 
 // readTodoItem reads a single todo item from the scanner
-func (f *FileIOManager) readTodoItem(scanner *bufio.Scanner) (*models.TodoItem, int, error) {
+func readTodoItem(scanner *lineScanner) (*models.TodoItem, int, error) {
 	todo := models.NewTodoItem()
 	linesRead := 0
 
 	// Read name
-	name, lines, err := f.readMultiLineString(scanner)
+	name, lines, err := readMultiLineString(scanner)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to read name: %w", err)
 	}
@@ -240,7 +264,7 @@ func (f *FileIOManager) readTodoItem(scanner *bufio.Scanner) (*models.TodoItem,
 	linesRead += lines
 
 	// Read label
-	label, lines, err := f.readMultiLineString(scanner)
+	label, lines, err := readMultiLineString(scanner)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to read label: %w", err)
 	}
@@ -282,7 +306,7 @@ func (f *FileIOManager) readTodoItem(scanner *bufio.Scanner) (*models.TodoItem,
 	linesRead++
 
 	// Read place
-	place, lines, err := f.readMultiLineString(scanner)
+	place, lines, err := readMultiLineString(scanner)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to read place: %w", err)
 	}
@@ -290,7 +314,7 @@ func (f *FileIOManager) readTodoItem(scanner *bufio.Scanner) (*models.TodoItem,
 	linesRead += lines
 
 	// Read content
-	content, lines, err := f.readMultiLineString(scanner)
+	content, lines, err := readMultiLineString(scanner)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to read content: %w", err)
 	}
@@ -324,7 +348,7 @@ func (f *FileIOManager) readTodoItem(scanner *bufio.Scanner) (*models.TodoItem,
 }
 
 // readMultiLineString reads a multi-line string from the scanner
-func (f *FileIOManager) readMultiLineString(scanner *bufio.Scanner) (string, int, error) {
+func readMultiLineString(scanner *lineScanner) (string, int, error) {
 	if !scanner.Scan() {
 		return "", 0, fmt.Errorf("unexpected end of file reading line count")
 	}
@@ -334,10 +358,17 @@ func (f *FileIOManager) readMultiLineString(scanner *bufio.Scanner) (string, int
 		return "", 0, fmt.Errorf("invalid line count: %w", err)
 	}
 
+	if lineCount < 0 {
+		return "", 0, fmt.Errorf("invalid line count: %d", lineCount)
+	}
+
 	var result strings.Builder
 	linesRead := 1
 
-	for i := 0; i < lineCount && scanner.Scan(); i++ {
+	for i := 0; i < lineCount; i++ {
+		if !scanner.Scan() {
+			return "", 0, fmt.Errorf("unexpected end of file reading text")
+		}
 		line := scanner.Text()
 
 		// Handle Windows/Unix line endings
@@ -427,3 +458,29 @@ func (f *FileIOManager) GetAllMonthlyFiles() ([]string, error) {
 	}
 	return monthlyFiles, nil
 }
+
+// lineScanner wraps bufio.Scanner and tracks the current 1-based line number
+// so parse errors can point at the offending line.
+type lineScanner struct {
+	*bufio.Scanner
+	line int
+}
+
+// newLineScanner creates a line-counting scanner over r
+func newLineScanner(r io.Reader) *lineScanner {
+	return &lineScanner{Scanner: bufio.NewScanner(r)}
+}
+
+// Scan advances to the next line
+func (s *lineScanner) Scan() bool {
+	if !s.Scanner.Scan() {
+		return false
+	}
+	s.line++
+	return true
+}
+
+// Line returns the number of the line most recently scanned
+func (s *lineScanner) Line() int {
+	return s.line
+}
@@ -0,0 +1,123 @@
+package persistence
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"godo/src/models"
+)
+
+// Helpers shared by the readers of exports from other task tools (Todoist,
+// Trello, Google Tasks). Those tools group tasks in projects, boards or
+// lists, which become the label of the todo; their own labels are listed in
+// the content, as Go Do keeps a single label.
+
+// ForeignTask is a task read from another tool's export, mapped onto a todo
+type ForeignTask struct {
+	Todo    *models.TodoItem
+	Project string   // Project, board or list of the task, also in Todo.Label
+	Labels  []string // Labels of the task in the other tool
+	Dated   bool     // Whether the task has a due date; without one its time is zero
+}
+
+// newForeignTask returns an undated task of project
+func newForeignTask(name, project string) *ForeignTask {
+	todo := models.NewTodoItem()
+	todo.Name = strings.TrimSpace(name)
+	todo.Kind = 1
+	todo.Label = project
+	return &ForeignTask{Todo: todo, Project: project}
+}
+
+// setLabels records labels, listing them in the content, and takes the
+// priority from a label naming one when the tool has no priorities of its own
+func (t *ForeignTask) setLabels(labels []string, levelFromLabels bool) {
+	for _, label := range labels {
+		if label = strings.TrimSpace(label); label != "" {
+			t.Labels = append(t.Labels, label)
+		}
+	}
+	if len(t.Labels) == 0 {
+		return
+	}
+	t.addContent("Labels: " + strings.Join(t.Labels, ", "))
+	if levelFromLabels {
+		for _, label := range t.Labels {
+			if level, ok := labelLevel(label); ok && level > t.Todo.Level {
+				t.Todo.Level = level
+			}
+		}
+	}
+}
+
+// addContent appends a paragraph to the content
+func (t *ForeignTask) addContent(text string) {
+	if text = strings.TrimSpace(text); text == "" {
+		return
+	}
+	if t.Todo.Content != "" {
+		t.Todo.Content += "\n\n"
+	}
+	t.Todo.Content += text
+}
+
+// setDay makes the task due on a whole day
+func (t *ForeignTask) setDay(day time.Time) {
+	t.Todo.SetAllDay(time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local))
+	t.Dated = true
+}
+
+// setDue makes the task due at an instant, shown in zone when it names one
+func (t *ForeignTask) setDue(at time.Time, zone *time.Location) {
+	if zone != nil && zone != time.Local {
+		t.Todo.SetZonedTime(at.In(zone), zone.String())
+	} else {
+		t.Todo.SetZonedTime(at.In(time.Local), "")
+	}
+	t.Dated = true
+}
+
+// labelLevel reads a priority from labels such as "High priority" or "urgent"
+func labelLevel(label string) (int, bool) {
+	words := strings.FieldsFunc(strings.ToLower(label), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+	for _, word := range words {
+		switch word {
+		case "urgent", "critical", "p1":
+			return 3, true
+		case "high", "important", "p2":
+			return 2, true
+		case "medium", "normal", "p3":
+			return 1, true
+		case "low", "p4":
+			return 0, true
+		}
+	}
+	return 0, false
+}
+
+// parseForeignTime reads a due date as exported by another tool: a date, a
+// date and time without an offset, read in loc, or an RFC 3339 instant
+func parseForeignTime(text string, loc *time.Location) (at time.Time, allDay bool, err error) {
+	text = strings.TrimSpace(text)
+	if day, err := time.ParseInLocation("2006-01-02", text, loc); err == nil {
+		return day, true, nil
+	}
+	if at, err := time.Parse(time.RFC3339Nano, text); err == nil {
+		return at, false, nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04"} {
+		if at, err := time.ParseInLocation(layout, text, loc); err == nil {
+			return at, false, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("invalid due date %q", text)
+}
+
+// entryError reports an entry of a JSON export, which has no useful line
+// numbers, by its position
+func entryError(index int, name string, err error) *LineError {
+	return &LineError{Line: index + 1, Text: name, Err: err}
+}
@@ -0,0 +1,75 @@
+package persistence
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Google Takeout exports Google Tasks as JSON with every task list and its
+// tasks; the Tasks API returns a single list the same way. Google Tasks keeps
+// only the date of a due time, so tasks are due all day. Lists become the
+// label; deleted tasks are left out.
+
+type googleTasksEntry struct {
+	Kind    string             `json:"kind"`
+	Title   string             `json:"title"`
+	Notes   string             `json:"notes"`
+	Status  string             `json:"status"`
+	Due     string             `json:"due"`
+	Deleted bool               `json:"deleted"`
+	Items   []googleTasksEntry `json:"items"`
+}
+
+// ReadGoogleTasks reads a Google Tasks export. list names the list when the
+// export holds the tasks of a single list, usually its file name.
+func ReadGoogleTasks(r io.Reader, list string) ([]*ForeignTask, []*LineError, error) {
+	var export googleTasksEntry
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, nil, fmt.Errorf("failed to read Google Tasks export: %w", err)
+	}
+	if export.Items == nil {
+		return nil, nil, errors.New("failed to read Google Tasks export: no task lists or tasks found")
+	}
+
+	var tasks []*ForeignTask
+	var skipped []*LineError
+	read := func(entries []googleTasksEntry, list string) {
+		for i, entry := range entries {
+			if entry.Deleted {
+				continue
+			}
+			task := newForeignTask(entry.Title, list)
+			if task.Todo.Name == "" {
+				// Google Tasks keeps empty tasks the user never typed into
+				continue
+			}
+			task.Todo.Done = entry.Status == "completed"
+			task.addContent(entry.Notes)
+			if entry.Due != "" {
+				due, err := time.Parse(time.RFC3339Nano, entry.Due)
+				if err != nil {
+					skipped = append(skipped, entryError(i, entry.Title, fmt.Errorf("invalid due date %q", entry.Due)))
+					continue
+				}
+				// The date is written as midnight UTC
+				task.setDay(due.UTC())
+			}
+			tasks = append(tasks, task)
+		}
+	}
+	lists := false
+	for _, entry := range export.Items {
+		lists = lists || entry.Kind == "tasks#taskList" || entry.Items != nil
+	}
+	if !lists {
+		read(export.Items, list)
+		return tasks, skipped, nil
+	}
+	for _, entry := range export.Items {
+		read(entry.Items, entry.Title)
+	}
+	return tasks, skipped, nil
+}
@@ -8,6 +8,7 @@ import (
 
 type TodoRepository interface {
 	GetTodosForMonth(year, month int) ([]*models.TodoItem, error)
+	GetTodosForDay(day time.Time) ([]*models.TodoItem, error)
 	SaveTodosForMonth(year, month int, todos []*models.TodoItem) error
 	AddTodo(todo *models.TodoItem) error
 	UpdateTodo(todo *models.TodoItem, originalTime time.Time) error
@@ -16,7 +17,19 @@ type TodoRepository interface {
 	GetTodoByTime(todoTime time.Time) (*models.TodoItem, error)
 	GetAllMonths() ([]string, error)
 	ClearCache()
-	MigrateAllToYAML() error
+	RepairMonth(year, month int) (*RepairReport, error)
+	MigrateAll() error
+	// Events returns the bus changes made through the repository are
+	// published on
+	Events() *TodoEvents
+}
+
+// WatchedRepository is a TodoRepository whose backing file other programs may
+// edit. Watch publishes a TodosReloaded event and calls onChanged, unless nil,
+// on its own goroutine after such an edit.
+type WatchedRepository interface {
+	TodoRepository
+	Watch(interval time.Duration, onChanged func()) (stop func())
 }
 
 type ConfigRepository interface {
@@ -25,7 +38,16 @@ type ConfigRepository interface {
 	GetConfigPath() string
 }
 
+type PomodoroRepository interface {
+	LoadSession() (*models.PomodoroSession, error)
+	SaveSession(session *models.PomodoroSession) error
+}
+
 var (
-	_ TodoRepository   = (*MonthlyManager)(nil)
-	_ ConfigRepository = (*ConfigManager)(nil)
+	_ TodoRepository     = (*MonthlyManager)(nil)
+	_ WatchedRepository  = (*TodoTxtRepository)(nil)
+	_ WatchedRepository  = (*MarkdownFolderRepository)(nil)
+	_ ConfigRepository   = (*ConfigManager)(nil)
+	_ PomodoroRepository = (*PomodoroStore)(nil)
+	_ SyncStore          = (*FileIOManager)(nil)
 )
@@ -0,0 +1,346 @@
+package persistence
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"godo/src/models"
+)
+
+// Markdown day plans use GitHub task lists under one heading per day, so they
+// can be pasted into tickets and read back:
+//
+//	## 2025-11-03 Monday
+//
+//	- [ ] 09:30 **Standup** — priority: high · place: Room 1 · label: work
+//	  Notes are indented below the todo
+//	- [x] all day **Holiday** — priority: low · type: task
+//
+// Times are the wall clock of the zone a todo was scheduled in (see the zone
+// value) or local time. The order within a day is not kept.
+
+const (
+	markdownDayHeading = "## "
+	markdownAllDay     = "all day"
+	markdownSeparator  = " — "
+	markdownMetaJoin   = " · "
+	markdownIndent     = "  "
+)
+
+// markdownLevels names the priority levels, indexed by Level
+var markdownLevels = []string{"low", "medium", "high", "urgent"}
+
+// WriteTodoMarkdown writes todos as a Markdown day plan, earliest first
+func WriteTodoMarkdown(w io.Writer, todos []*models.TodoItem) error {
+	sorted := append([]*models.TodoItem(nil), todos...)
+	// Days follow the wall clock the times are written in
+	sort.SliceStable(sorted, func(i, j int) bool {
+		dayI, dayJ := wallClock(sorted[i]).Format(todoTxtDate), wallClock(sorted[j]).Format(todoTxtDate)
+		if dayI != dayJ {
+			return dayI < dayJ
+		}
+		return sorted[i].TodoTime.Before(sorted[j].TodoTime)
+	})
+
+	writer := bufio.NewWriter(w)
+	day := ""
+	for _, todo := range sorted {
+		at := wallClock(todo)
+		if heading := at.Format(todoTxtDate); heading != day {
+			if day != "" {
+				writer.WriteString("\n")
+			}
+			day = heading
+			fmt.Fprintf(writer, "%s%s %s\n\n", markdownDayHeading, day, at.Weekday())
+		}
+		writer.WriteString(formatMarkdownTodo(todo, at) + "\n")
+		if content := strings.TrimRight(todo.Content, "\n"); content != "" {
+			for _, line := range strings.Split(content, "\n") {
+				writer.WriteString(markdownIndent + line + "\n")
+			}
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write Markdown: %w", err)
+	}
+	return nil
+}
+
+// formatMarkdownTodo writes the task list line of todo, whose time is at
+func formatMarkdownTodo(todo *models.TodoItem, at time.Time) string {
+	check := " "
+	if todo.Done {
+		check = "x"
+	}
+	clock := at.Format("15:04")
+	if todo.AllDay {
+		clock = markdownAllDay
+	} else if at.Second() != 0 {
+		clock = at.Format("15:04:05")
+	}
+
+	var meta []string
+	if todo.Level >= 0 && todo.Level < len(markdownLevels) {
+		meta = append(meta, "priority: "+markdownLevels[todo.Level])
+	}
+	if todo.Kind == 1 {
+		meta = append(meta, "type: task")
+	} else {
+		meta = append(meta, "type: event")
+	}
+	if place := markdownValue(todo.Place); place != "" {
+		meta = append(meta, "place: "+place)
+	}
+	if label := markdownValue(todo.Label); label != "" {
+		meta = append(meta, "label: "+label)
+	}
+	if todo.WarnTime > 0 {
+		meta = append(meta, "reminder: "+formatMinutes(todo.WarnTime))
+	}
+	if todo.Duration > 0 && !todo.AllDay {
+		meta = append(meta, "duration: "+formatMinutes(todo.Duration))
+	}
+	if todo.Estimate > 0 {
+		meta = append(meta, "estimate: "+strconv.Itoa(todo.Estimate))
+	}
+	if todo.Pomodoros > 0 {
+		meta = append(meta, "pomodoros: "+strconv.Itoa(todo.Pomodoros))
+	}
+	if todo.TimeZone != "" && !todo.Floating {
+		meta = append(meta, "zone: "+todo.TimeZone)
+	}
+	if todo.Starred {
+		meta = append(meta, "starred")
+	}
+
+	return fmt.Sprintf("- [%s] %s **%s**%s%s", check, clock, escapeMarkdown(markdownValue(todo.Name)),
+		markdownSeparator, strings.Join(meta, markdownMetaJoin))
+}
+
+// markdownValue keeps a value on one line and out of the metadata separator
+func markdownValue(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	return strings.ReplaceAll(text, strings.TrimSpace(markdownMetaJoin), "-")
+}
+
+// escapeMarkdown escapes what would end the bold name early
+func escapeMarkdown(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `*`, `\*`).Replace(text)
+}
+
+// ReadTodoMarkdown reads the todos of a Markdown day plan. Task list items
+// must follow a day heading; other headings are ignored. Lines that cannot be
+// read are returned as skipped.
+func ReadTodoMarkdown(r io.Reader) ([]*models.TodoItem, []*LineError, error) {
+	var todos []*models.TodoItem
+	var skipped []*LineError
+	var day time.Time
+	var current *models.TodoItem // Todo whose notes may follow
+	var notes []string
+	blank := 0 // Blank lines since the last note
+
+	finish := func() {
+		if current != nil {
+			current.Content = strings.Join(notes, "\n")
+		}
+		current, notes, blank = nil, nil, 0
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		switch {
+		case line == "":
+			blank++
+			continue
+
+		case current != nil && strings.HasPrefix(line, markdownIndent):
+			for ; blank > 0; blank-- {
+				notes = append(notes, "")
+			}
+			notes = append(notes, strings.TrimPrefix(line, markdownIndent))
+			continue
+		}
+		finish()
+
+		switch {
+		case strings.HasPrefix(line, markdownDayHeading):
+			heading := strings.TrimPrefix(line, markdownDayHeading)
+			parsed, err := time.Parse(todoTxtDate, strings.SplitN(heading, " ", 2)[0])
+			if err != nil {
+				skipped = append(skipped, &LineError{Line: number, Text: line, Err: errors.New("day heading without a YYYY-MM-DD date")})
+				day = time.Time{}
+				continue
+			}
+			day = parsed
+
+		case strings.HasPrefix(line, "#"):
+			// Titles and other headings
+
+		default:
+			todo, err := parseMarkdownTodo(line, day)
+			if err != nil {
+				skipped = append(skipped, &LineError{Line: number, Text: line, Err: err})
+				continue
+			}
+			todos = append(todos, todo)
+			current = todo
+		}
+	}
+	finish()
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read Markdown: %w", err)
+	}
+	return todos, skipped, nil
+}
+
+// parseMarkdownTodo reads a task list line of the given day
+func parseMarkdownTodo(line string, day time.Time) (*models.TodoItem, error) {
+	todo := models.NewTodoItem()
+	rest := line
+	if len(rest) > 2 && (rest[0] == '-' || rest[0] == '*') && rest[1] == ' ' {
+		rest = rest[2:]
+	} else {
+		return nil, errors.New("not a task list item")
+	}
+	switch {
+	case strings.HasPrefix(rest, "[ ] "):
+	case strings.HasPrefix(rest, "[x] "), strings.HasPrefix(rest, "[X] "):
+		todo.Done = true
+	default:
+		return nil, errors.New("task list item without a [ ] or [x] box")
+	}
+	rest = rest[4:]
+	if day.IsZero() {
+		return nil, errors.New("todo before the first day heading")
+	}
+
+	allDay := strings.HasPrefix(rest, markdownAllDay+" ")
+	var clock time.Time
+	if allDay {
+		rest = strings.TrimPrefix(rest, markdownAllDay+" ")
+	} else {
+		text, after, _ := strings.Cut(rest, " ")
+		var err error
+		if clock, err = time.Parse("15:04", text); err != nil {
+			if clock, err = time.Parse("15:04:05", text); err != nil {
+				return nil, fmt.Errorf("invalid time %q: want HH:MM or %q", text, markdownAllDay)
+			}
+		}
+		rest = after
+	}
+
+	name, meta, err := cutMarkdownName(rest)
+	if err != nil {
+		return nil, err
+	}
+	todo.Name = name
+
+	zone := ""
+	for _, entry := range strings.Split(meta, markdownMetaJoin) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if entry == "starred" {
+			todo.Starred = true
+			continue
+		}
+		key, value, found := strings.Cut(entry, ": ")
+		if !found {
+			return nil, fmt.Errorf("invalid metadata %q: want key: value", entry)
+		}
+		var err error
+		switch key {
+		case "priority":
+			todo.Level = -1
+			for level, levelName := range markdownLevels {
+				if value == levelName {
+					todo.Level = level
+				}
+			}
+			if todo.Level < 0 {
+				err = fmt.Errorf("want one of %s", strings.Join(markdownLevels, ", "))
+			}
+		case "type":
+			switch value {
+			case "event":
+				todo.Kind = 0
+			case "task":
+				todo.Kind = 1
+			default:
+				err = errors.New("want event or task")
+			}
+		case "place":
+			todo.Place = value
+		case "label":
+			todo.Label = value
+		case "reminder":
+			todo.WarnTime, err = parseMinutes(value)
+		case "duration":
+			todo.Duration, err = parseMinutes(value)
+		case "estimate":
+			todo.Estimate, err = parseCount(value)
+		case "pomodoros":
+			todo.Pomodoros, err = parseCount(value)
+		case "zone":
+			zone = value
+		default:
+			err = errors.New("unknown key")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %v", key, value, err)
+		}
+	}
+
+	at := time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, time.UTC)
+	if err := setWallClock(todo, at, allDay, zone); err != nil {
+		return nil, err
+	}
+	if allDay {
+		todo.Duration = 0
+	}
+	return todo, nil
+}
+
+// cutMarkdownName splits "**name** — metadata" into the unescaped name and
+// the metadata
+func cutMarkdownName(text string) (string, string, error) {
+	if !strings.HasPrefix(text, "**") {
+		return "", "", errors.New("name must be in **bold**")
+	}
+	var name strings.Builder
+	for i := 2; i < len(text); i++ {
+		switch {
+		case text[i] == '\\' && i+1 < len(text):
+			i++
+			name.WriteByte(text[i])
+		case strings.HasPrefix(text[i:], "**"):
+			rest := strings.TrimSpace(text[i+2:])
+			rest = strings.TrimSpace(strings.TrimPrefix(rest, strings.TrimSpace(markdownSeparator)))
+			if strings.TrimSpace(name.String()) == "" {
+				return "", "", errors.New("name is empty")
+			}
+			return name.String(), rest, nil
+		default:
+			name.WriteByte(text[i])
+		}
+	}
+	return "", "", errors.New("name must be in **bold**")
+}
+
+// parseCount reads a whole number that cannot be negative
+func parseCount(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, errors.New("not a whole number")
+	}
+	return n, nil
+}
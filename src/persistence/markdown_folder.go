@@ -0,0 +1,727 @@
+package persistence
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"godo/src/models"
+	"godo/src/utils"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A Markdown folder keeps each todo in its own note, so the todos can live in
+// a notes vault and be read by any Markdown tool:
+//
+//	2025/11/2025-11-03 0930 Standup.md
+//
+//	---
+//	name: Standup
+//	kind: 0
+//	level: 2
+//	todotime: 2025-11-03T09:30:00+01:00
+//	done: false
+//	place: Room 1
+//	---
+//
+//	Notes of the todo
+//
+// The front-matter holds the TodoItem fields under the keys of the month
+// files and the body is the content. Notes are found in the YYYY/MM folders
+// by their front-matter, not their name, so they may be renamed or moved
+// between those folders freely. Other front-matter keys, such as tags added
+// in another tool, are kept when Go Do rewrites a note.
+
+const (
+	markdownFrontMatterFence = "---"
+	markdownNoteExt          = ".md"
+	markdownNoteNameMax      = 80 // Runes of the todo name used in file names
+)
+
+// markdownFrontMatter is the front-matter of a todo note
+type markdownFrontMatter struct {
+	Name      string    `yaml:"name"`
+	Place     string    `yaml:"place,omitempty"`
+	Label     string    `yaml:"label,omitempty"`
+	Kind      int       `yaml:"kind"`
+	Level     int       `yaml:"level"`
+	TodoTime  time.Time `yaml:"todotime"`
+	Done      bool      `yaml:"done"`
+	WarnTime  int       `yaml:"warntime,omitempty"`
+	Starred   bool      `yaml:"starred,omitempty"`
+	Order     int       `yaml:"order,omitempty"`
+	TimeZone  string    `yaml:"timezone,omitempty"`
+	Floating  bool      `yaml:"floating,omitempty"`
+	Duration  int       `yaml:"duration,omitempty"`
+	AllDay    bool      `yaml:"allday,omitempty"`
+	Estimate  int       `yaml:"estimate,omitempty"`
+	Pomodoros int       `yaml:"pomodoros,omitempty"`
+}
+
+// markdownFrontMatterKeys are the keys Go Do owns in the front-matter
+var markdownFrontMatterKeys = map[string]bool{
+	"name": true, "place": true, "label": true, "kind": true, "level": true, "todotime": true, "done": true,
+	"warntime": true, "starred": true, "order": true, "timezone": true, "floating": true, "duration": true,
+	"allday": true, "estimate": true, "pomodoros": true,
+}
+
+// markdownNote is a Markdown file in a month folder
+type markdownNote struct {
+	path  string
+	stamp fileStamp
+	todo  *models.TodoItem // Todo of the note; nil for other notes, kept as they are
+	read  *models.TodoItem // Copy of todo as last read or written
+	front *yaml.Node       // Front-matter mapping as read, with keys Go Do does not know
+}
+
+// MarkdownFolderRepository stores each todo as a Markdown note in YYYY/MM
+// folders below a root folder, such as a notes vault. Notes are read again
+// whenever they change on disk, and only notes of todos that changed are
+// written. Like MonthlyManager it is not safe for concurrent use, except for
+// Watch.
+type MarkdownFolderRepository struct {
+	root   string
+	notes  []*markdownNote
+	loaded bool
+
+	mu     sync.Mutex
+	stamps map[string]fileStamp // Versions of the notes as last read or written
+	events *TodoEvents
+}
+
+// NewMarkdownFolderRepository creates a repository keeping notes below root
+func NewMarkdownFolderRepository(root string) *MarkdownFolderRepository {
+	return &MarkdownFolderRepository{root: root, events: NewTodoEvents()}
+}
+
+// Events returns the bus the repository publishes its changes on
+func (r *MarkdownFolderRepository) Events() *TodoEvents {
+	return r.events
+}
+
+// GetPath returns the root folder
+func (r *MarkdownFolderRepository) GetPath() string {
+	return r.root
+}
+
+// scan returns the notes in the month folders with their versions
+func (r *MarkdownFolderRepository) scan() (map[string]fileStamp, error) {
+	paths, err := filepath.Glob(filepath.Join(r.root, "[0-9][0-9][0-9][0-9]", "[0-9][0-9]", "*"+markdownNoteExt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notes in %s: %w", r.root, err)
+	}
+	stamps := make(map[string]fileStamp, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue // Removed while listing
+			}
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if info.Mode().IsRegular() {
+			stamps[path] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+	return stamps, nil
+}
+
+// load reads the notes that changed on disk since they were last read
+func (r *MarkdownFolderRepository) load() error {
+	stamps, err := r.scan()
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	current := r.loaded && sameStamps(stamps, r.stamps)
+	r.mu.Unlock()
+	if current {
+		return nil
+	}
+
+	known := make(map[string]*markdownNote, len(r.notes))
+	if r.loaded {
+		for _, note := range r.notes {
+			known[note.path] = note
+		}
+	}
+	notes := make([]*markdownNote, 0, len(stamps))
+	for path, stamp := range stamps {
+		if note, ok := known[path]; ok && note.stamp == stamp {
+			notes = append(notes, note)
+			continue
+		}
+		note, err := readMarkdownNote(path, stamp)
+		if err != nil {
+			return err
+		}
+		notes = append(notes, note)
+	}
+	// Keep a stable order for todos at the same time
+	sort.Slice(notes, func(i, j int) bool { return notes[i].path < notes[j].path })
+
+	r.notes = notes
+	r.loaded = true
+	r.mu.Lock()
+	r.stamps = stamps
+	r.mu.Unlock()
+	return nil
+}
+
+// sameStamps reports whether two scans found the same versions of the same notes
+func sameStamps(a, b map[string]fileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, stamp := range a {
+		if other, ok := b[path]; !ok || other != stamp {
+			return false
+		}
+	}
+	return true
+}
+
+// readMarkdownNote reads a note. Notes that are not todos are returned
+// without one, so they are not read again until they change.
+func readMarkdownNote(path string, stamp fileStamp) (*markdownNote, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	note := &markdownNote{path: path, stamp: stamp}
+	todo, front, err := parseMarkdownNote(data)
+	if err != nil {
+		if err != errNotTodoNote {
+			fmt.Printf("Skipping note %s: %v\n", path, err)
+		}
+		return note, nil
+	}
+	note.todo, note.front = todo, front
+	copied := *todo
+	note.read = &copied
+	return note, nil
+}
+
+// errNotTodoNote is returned for notes without the front-matter of a todo
+var errNotTodoNote = errors.New("no todo front-matter")
+
+// parseMarkdownNote reads the todo of a note, and its front-matter mapping
+func parseMarkdownNote(data []byte) (*models.TodoItem, *yaml.Node, error) {
+	text := strings.ReplaceAll(string(bytes.TrimPrefix(data, []byte("\ufeff"))), "\r\n", "\n")
+	if !strings.HasPrefix(text, markdownFrontMatterFence+"\n") {
+		return nil, nil, errNotTodoNote
+	}
+	rest := text[len(markdownFrontMatterFence)+1:]
+	var front, body string
+	if strings.HasPrefix(rest, markdownFrontMatterFence+"\n") || rest == markdownFrontMatterFence {
+		front, body = "", strings.TrimPrefix(rest, markdownFrontMatterFence)
+	} else {
+		end := strings.Index(rest, "\n"+markdownFrontMatterFence+"\n")
+		if end < 0 {
+			if !strings.HasSuffix(rest, "\n"+markdownFrontMatterFence) {
+				return nil, nil, errors.New("the front-matter is not closed")
+			}
+			end = len(rest) - len(markdownFrontMatterFence) - 1
+		}
+		front = rest[:end+1]
+		body = rest[end+1+len(markdownFrontMatterFence):]
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(front), &doc); err != nil {
+		return nil, nil, fmt.Errorf("invalid front-matter: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, errNotTodoNote
+	}
+	mapping := doc.Content[0]
+	var fields markdownFrontMatter
+	if err := mapping.Decode(&fields); err != nil {
+		return nil, nil, fmt.Errorf("invalid front-matter: %w", err)
+	}
+	if fields.Name == "" || fields.TodoTime.IsZero() {
+		return nil, nil, errNotTodoNote
+	}
+
+	todo := &models.TodoItem{
+		Name:      fields.Name,
+		Content:   strings.TrimRight(strings.TrimPrefix(strings.TrimPrefix(body, "\n"), "\n"), "\n"),
+		Place:     fields.Place,
+		Label:     fields.Label,
+		Kind:      fields.Kind,
+		Level:     fields.Level,
+		TodoTime:  fields.TodoTime,
+		Done:      fields.Done,
+		WarnTime:  fields.WarnTime,
+		Starred:   fields.Starred,
+		Order:     fields.Order,
+		TimeZone:  fields.TimeZone,
+		Floating:  fields.Floating,
+		Duration:  fields.Duration,
+		AllDay:    fields.AllDay,
+		Estimate:  fields.Estimate,
+		Pomodoros: fields.Pomodoros,
+	}
+	return todo, mapping, nil
+}
+
+// formatMarkdownNote writes the note of todo. Keys of front that Go Do does
+// not own are kept in their place.
+func formatMarkdownNote(todo *models.TodoItem, front *yaml.Node) ([]byte, error) {
+	var fields yaml.Node
+	err := fields.Encode(markdownFrontMatter{
+		Name:      todo.Name,
+		Place:     todo.Place,
+		Label:     todo.Label,
+		Kind:      todo.Kind,
+		Level:     todo.Level,
+		TodoTime:  todo.TodoTime,
+		Done:      todo.Done,
+		WarnTime:  todo.WarnTime,
+		Starred:   todo.Starred,
+		Order:     todo.Order,
+		TimeZone:  todo.TimeZone,
+		Floating:  todo.Floating,
+		Duration:  todo.Duration,
+		AllDay:    todo.AllDay,
+		Estimate:  todo.Estimate,
+		Pomodoros: todo.Pomodoros,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to format note of %q: %w", todo.Name, err)
+	}
+	if front != nil {
+		fields = *mergeFrontMatter(front, &fields)
+	}
+	data, err := yaml.Marshal(&fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format note of %q: %w", todo.Name, err)
+	}
+
+	var b bytes.Buffer
+	b.WriteString(markdownFrontMatterFence + "\n")
+	b.Write(data)
+	b.WriteString(markdownFrontMatterFence + "\n")
+	if todo.Content != "" {
+		b.WriteString("\n" + todo.Content + "\n")
+	}
+	return b.Bytes(), nil
+}
+
+// mergeFrontMatter returns old with the keys Go Do owns replaced by those of
+// fields. Owned keys missing from fields were cleared, so they are dropped.
+func mergeFrontMatter(old, fields *yaml.Node) *yaml.Node {
+	values := make(map[string]*yaml.Node, len(fields.Content)/2)
+	for i := 0; i+1 < len(fields.Content); i += 2 {
+		values[fields.Content[i].Value] = fields.Content[i+1]
+	}
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	written := make(map[string]bool, len(values))
+	for i := 0; i+1 < len(old.Content); i += 2 {
+		key := old.Content[i].Value
+		if value, ok := values[key]; ok {
+			merged.Content = append(merged.Content, old.Content[i], value)
+			written[key] = true
+		} else if !markdownFrontMatterKeys[key] {
+			merged.Content = append(merged.Content, old.Content[i], old.Content[i+1])
+		}
+	}
+	for i := 0; i+1 < len(fields.Content); i += 2 {
+		if !written[fields.Content[i].Value] {
+			merged.Content = append(merged.Content, fields.Content[i], fields.Content[i+1])
+		}
+	}
+	return merged
+}
+
+// noteFileName returns the name Go Do gives the note of todo
+func noteFileName(todo *models.TodoItem) string {
+	at := wallClock(todo)
+	prefix := at.Format("2006-01-02")
+	if !todo.AllDay {
+		prefix += at.Format(" 1504")
+	}
+
+	name := strings.Map(func(r rune) rune {
+		// Characters file systems or Markdown links do not allow
+		if unicode.IsControl(r) || strings.ContainsRune(`/\:*?"<>|#^[]`, r) {
+			return '-'
+		}
+		return r
+	}, todo.Name)
+	name = strings.Join(strings.Fields(name), " ")
+	if runes := []rune(name); len(runes) > markdownNoteNameMax {
+		name = string(runes[:markdownNoteNameMax])
+	}
+	name = strings.Trim(name, " .")
+	if name == "" {
+		return prefix + markdownNoteExt
+	}
+	return prefix + " " + name + markdownNoteExt
+}
+
+// notePath returns the path Go Do gives the note of todo
+func (r *MarkdownFolderRepository) notePath(todo *models.TodoItem) string {
+	year, month := todoMonth(todo)
+	return filepath.Join(r.root, fmt.Sprintf("%04d", year), fmt.Sprintf("%02d", month), noteFileName(todo))
+}
+
+// uniquePath returns path, numbered if another note already uses it
+func (r *MarkdownFolderRepository) uniquePath(path string, self *markdownNote) string {
+	taken := func(candidate string) bool {
+		for _, note := range r.notes {
+			if note != self && note.path == candidate {
+				return true
+			}
+		}
+		_, err := os.Stat(candidate)
+		return err == nil && (self == nil || self.path != candidate)
+	}
+	base := strings.TrimSuffix(path, markdownNoteExt)
+	for n := 2; taken(path); n++ {
+		path = base + " " + strconv.Itoa(n) + markdownNoteExt
+	}
+	return path
+}
+
+// saveNote writes the note of a new or changed todo. A todo given a new name
+// or time gets a new file name; notes renamed in another tool keep theirs
+// otherwise.
+func (r *MarkdownFolderRepository) saveNote(note *markdownNote) error {
+	if note.read != nil && models.SameTodo(note.todo, note.read) {
+		return nil
+	}
+	path := note.path
+	if path == "" || filepath.Dir(path) != filepath.Dir(r.notePath(note.todo)) ||
+		noteFileName(note.todo) != noteFileName(note.read) {
+		path = r.uniquePath(r.notePath(note.todo), note)
+	}
+
+	data, err := formatMarkdownNote(note.todo, note.front)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to save todos: %w", err)
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return fmt.Errorf("failed to save todos: %w", err)
+	}
+	if note.path != "" && note.path != path {
+		if err := os.Remove(note.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rename %s: %w", note.path, err)
+		}
+		r.forget(note.path)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to save todos: %w", err)
+	}
+	note.path = path
+	note.stamp = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	copied := *note.todo
+	note.read = &copied
+	r.mu.Lock()
+	if r.stamps == nil {
+		r.stamps = make(map[string]fileStamp)
+	}
+	r.stamps[path] = note.stamp
+	r.mu.Unlock()
+	return nil
+}
+
+// removeNote deletes the note of a todo
+func (r *MarkdownFolderRepository) removeNote(note *markdownNote) error {
+	if err := os.Remove(note.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s: %w", note.path, err)
+	}
+	r.forget(note.path)
+	return nil
+}
+
+// forget drops a note that is gone from the versions on disk
+func (r *MarkdownFolderRepository) forget(path string) {
+	r.mu.Lock()
+	delete(r.stamps, path)
+	r.mu.Unlock()
+}
+
+// Watch publishes a TodosReloaded event and calls onChanged, unless nil, on
+// its own goroutine whenever another program added, changed, renamed or
+// removed a note, checking every interval. The next read picks up the change.
+// Call the returned function to stop watching.
+func (r *MarkdownFolderRepository) Watch(interval time.Duration, onChanged func()) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var reported map[string]fileStamp
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			stamps, err := r.scan()
+			if err != nil {
+				continue
+			}
+			r.mu.Lock()
+			changed := !sameStamps(stamps, r.stamps)
+			r.mu.Unlock()
+			// Report each change once, even before it is read
+			if changed && !sameStamps(stamps, reported) {
+				reported = stamps
+				r.events.Publish(newTodoEvent(TodosReloaded, nil))
+				if onChanged != nil {
+					onChanged()
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+// todos returns the todos matching keep, latest first like MonthlyManager
+func (r *MarkdownFolderRepository) todos(keep func(*models.TodoItem) bool) ([]*models.TodoItem, error) {
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	var todos []*models.TodoItem
+	for _, note := range r.notes {
+		if note.todo != nil && keep(note.todo) {
+			todos = append(todos, note.todo)
+		}
+	}
+	sort.SliceStable(todos, func(i, j int) bool {
+		return todos[i].TodoTime.After(todos[j].TodoTime)
+	})
+	return todos, nil
+}
+
+// GetTodosForMonth returns the todos scheduled in a month
+func (r *MarkdownFolderRepository) GetTodosForMonth(year, month int) ([]*models.TodoItem, error) {
+	return r.todos(func(todo *models.TodoItem) bool {
+		y, m := todoMonth(todo)
+		return y == year && m == month
+	})
+}
+
+// GetTodosForDay returns the todos that fall on the calendar day of day, as
+// seen in day's location
+func (r *MarkdownFolderRepository) GetTodosForDay(day time.Time) ([]*models.TodoItem, error) {
+	return r.todos(func(todo *models.TodoItem) bool {
+		return todo.OccursOn(day, day.Location())
+	})
+}
+
+// SaveTodosForMonth replaces the todos of a month with todos. Todos that keep
+// their time keep their note; notes of todos left out are removed.
+func (r *MarkdownFolderRepository) SaveTodosForMonth(year, month int, todos []*models.TodoItem) error {
+	if err := r.load(); err != nil {
+		return err
+	}
+
+	pending := make(map[int64]*models.TodoItem, len(todos))
+	for _, todo := range todos {
+		pending[todo.TodoTime.UnixNano()] = todo
+	}
+	notes := r.notes[:0]
+	var removed []*markdownNote
+	for _, note := range r.notes {
+		if note.todo != nil {
+			if y, m := todoMonth(note.todo); y == year && m == month {
+				todo, ok := pending[note.todo.TodoTime.UnixNano()]
+				if !ok {
+					removed = append(removed, note)
+					continue
+				}
+				delete(pending, note.todo.TodoTime.UnixNano())
+				note.todo = todo
+			}
+		}
+		notes = append(notes, note)
+	}
+	for _, todo := range todos {
+		if _, ok := pending[todo.TodoTime.UnixNano()]; ok {
+			notes = append(notes, &markdownNote{todo: todo})
+		}
+	}
+	r.notes = notes
+
+	for _, note := range removed {
+		if err := r.removeNote(note); err != nil {
+			return err
+		}
+	}
+	for _, note := range r.notes {
+		if note.todo != nil {
+			if err := r.saveNote(note); err != nil {
+				return err
+			}
+		}
+	}
+	r.events.Publish(newTodoEvent(TodosSaved, todos, utils.FormatDateKey(year, month)))
+	return nil
+}
+
+// AddTodo adds a note for todo
+func (r *MarkdownFolderRepository) AddTodo(todo *models.TodoItem) error {
+	if err := r.load(); err != nil {
+		return err
+	}
+	note := &markdownNote{todo: todo}
+	r.notes = append(r.notes, note)
+	if err := r.saveNote(note); err != nil {
+		return err
+	}
+	r.events.Publish(newTodoEvent(TodosAdded, []*models.TodoItem{todo}))
+	return nil
+}
+
+// UpdateTodo replaces the todo stored at originalTime, preferring one of the
+// same name. A todo given a new name keeps its note, which is renamed.
+func (r *MarkdownFolderRepository) UpdateTodo(todo *models.TodoItem, originalTime time.Time) error {
+	if err := r.load(); err != nil {
+		return err
+	}
+	var found *markdownNote
+	for _, note := range r.notes {
+		if note.todo != nil && note.todo.TodoTime.Equal(originalTime) {
+			if note.todo.Name == todo.Name {
+				found = note
+				break
+			}
+			if found == nil {
+				found = note
+			}
+		}
+	}
+	if found == nil {
+		return fmt.Errorf("todo item not found for update")
+	}
+	original := utils.FormatDateKey(todoMonth(found.todo))
+	found.todo = todo
+	if err := r.saveNote(found); err != nil {
+		return err
+	}
+	r.events.Publish(newTodoEvent(TodosUpdated, []*models.TodoItem{todo}, original))
+	return nil
+}
+
+// RemoveTodo removes the todo stored at todoTime
+func (r *MarkdownFolderRepository) RemoveTodo(todoTime time.Time) error {
+	return r.RemoveTodos([]time.Time{todoTime})
+}
+
+// RemoveTodos removes the notes of the todos stored at todoTimes
+func (r *MarkdownFolderRepository) RemoveTodos(todoTimes []time.Time) error {
+	if err := r.load(); err != nil {
+		return err
+	}
+	remove := make(map[int64]bool, len(todoTimes))
+	for _, todoTime := range todoTimes {
+		remove[todoTime.UnixNano()] = true
+	}
+	notes := r.notes[:0]
+	var removed []*markdownNote
+	for _, note := range r.notes {
+		if note.todo != nil && remove[note.todo.TodoTime.UnixNano()] {
+			// Only the first todo at a time is removed, as in MonthlyManager
+			delete(remove, note.todo.TodoTime.UnixNano())
+			removed = append(removed, note)
+			continue
+		}
+		notes = append(notes, note)
+	}
+	r.notes = notes
+	todos := make([]*models.TodoItem, 0, len(removed))
+	for _, note := range removed {
+		if err := r.removeNote(note); err != nil {
+			return err
+		}
+		todos = append(todos, note.todo)
+	}
+	if len(todos) > 0 {
+		r.events.Publish(newTodoEvent(TodosRemoved, todos))
+	}
+	return nil
+}
+
+// GetTodoByTime finds the todo stored at todoTime
+func (r *MarkdownFolderRepository) GetTodoByTime(todoTime time.Time) (*models.TodoItem, error) {
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	for _, note := range r.notes {
+		if note.todo != nil && note.todo.TodoTime.Equal(todoTime) {
+			return note.todo, nil
+		}
+	}
+	return nil, fmt.Errorf("todo item not found")
+}
+
+// GetAllMonths returns the months that have todos, as YYYYMM keys
+func (r *MarkdownFolderRepository) GetAllMonths() ([]string, error) {
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	months := []string{}
+	for _, note := range r.notes {
+		if note.todo == nil {
+			continue
+		}
+		dateKey := utils.FormatDateKey(todoMonth(note.todo))
+		if !seen[dateKey] {
+			seen[dateKey] = true
+			months = append(months, dateKey)
+		}
+	}
+	sort.Strings(months)
+	return months, nil
+}
+
+// ClearCache makes the next read load every note again
+func (r *MarkdownFolderRepository) ClearCache() {
+	r.loaded = false
+}
+
+// RepairMonth has nothing to repair: notes that cannot be read are left as
+// they are rather than failing the whole month
+func (r *MarkdownFolderRepository) RepairMonth(year, month int) (*RepairReport, error) {
+	return &RepairReport{Path: filepath.Join(r.root, fmt.Sprintf("%04d", year), fmt.Sprintf("%02d", month))}, nil
+}
+
+// MigrateAll has nothing to migrate; notes have no format versions
+func (r *MarkdownFolderRepository) MigrateAll() error {
+	return nil
+}
+
+// CheckMarkdownFolder reports whether path can hold todo notes: an existing
+// folder
+func CheckMarkdownFolder(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("the folder %s does not exist", path)
+		}
+		return fmt.Errorf("failed to check %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a folder", path)
+	}
+	return nil
+}
@@ -0,0 +1,472 @@
+package persistence
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"godo/src/models"
+	"godo/src/utils"
+)
+
+// Schema versions written by this build
+const (
+	CurrentMonthlyVersion  = 4
+	CurrentConfigVersion   = 11
+	CurrentPomodoroVersion = 5
+)
+
+// NewerVersionError is returned for files written by a newer Go Do release.
+// Such files are never rewritten, so downgrading cannot corrupt them.
+type NewerVersionError struct {
+	Path      string // File that was refused
+	Kind      string // "monthly", "config" or "pomodoro"
+	Version   int    // Version found in the file
+	Supported int    // Newest version this build understands
+}
+
+func (e *NewerVersionError) Error() string {
+	return fmt.Sprintf("%s file %s has version %d, but this build only supports up to version %d; please update Go Do",
+		e.Kind, e.Path, e.Version, e.Supported)
+}
+
+// Migration upgrades a serialized document from version From to From+1
+type Migration struct {
+	From        int
+	Description string
+	Apply       func(data []byte) ([]byte, error)
+}
+
+// MigrationRegistry holds the ordered upgrade steps for one kind of file
+type MigrationRegistry struct {
+	kind    string
+	current int
+	steps   map[int]Migration
+}
+
+// NewMigrationRegistry creates an empty registry for files of the given kind
+func NewMigrationRegistry(kind string, current int) *MigrationRegistry {
+	return &MigrationRegistry{
+		kind:    kind,
+		current: current,
+		steps:   make(map[int]Migration),
+	}
+}
+
+// Register adds an upgrade step. Registering two steps from the same version
+// is a programming error and panics.
+func (r *MigrationRegistry) Register(m Migration) {
+	if _, exists := r.steps[m.From]; exists {
+		panic(fmt.Sprintf("duplicate %s migration from version %d", r.kind, m.From))
+	}
+	r.steps[m.From] = m
+}
+
+// Current returns the version produced by a full upgrade
+func (r *MigrationRegistry) Current() int {
+	return r.current
+}
+
+// Steps returns the registered steps ordered by source version
+func (r *MigrationRegistry) Steps() []Migration {
+	steps := make([]Migration, 0, len(r.steps))
+	for _, m := range r.steps {
+		steps = append(steps, m)
+	}
+	sort.Slice(steps, func(i, j int) bool { return steps[i].From < steps[j].From })
+	return steps
+}
+
+// Migrate upgrades data from version from to the current version one step at
+// a time. Data that is already current is returned unchanged.
+func (r *MigrationRegistry) Migrate(data []byte, from int) ([]byte, error) {
+	if from > r.current {
+		return nil, &NewerVersionError{Kind: r.kind, Version: from, Supported: r.current}
+	}
+	for version := from; version < r.current; version++ {
+		step, ok := r.steps[version]
+		if !ok {
+			return nil, fmt.Errorf("no %s migration from version %d", r.kind, version)
+		}
+		upgraded, err := step.Apply(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s migration %d->%d (%s): %w", r.kind, version, version+1, step.Description, err)
+		}
+		data = upgraded
+	}
+	return data, nil
+}
+
+// monthlyMigrations upgrades monthly todo files.
+// Version 0 covers the legacy TXT format and bare YAML lists without a wrapper.
+var monthlyMigrations = newMonthlyMigrations()
+
+// configMigrations upgrades config.json
+var configMigrations = newConfigMigrations()
+
+// pomodoroMigrations upgrades pomodoro.json
+var pomodoroMigrations = newPomodoroMigrations()
+
+// MonthlyMigrations returns the registry used for monthly files
+func MonthlyMigrations() *MigrationRegistry {
+	return monthlyMigrations
+}
+
+// ConfigMigrations returns the registry used for the configuration file
+func ConfigMigrations() *MigrationRegistry {
+	return configMigrations
+}
+
+// PomodoroMigrations returns the registry used for the Pomodoro session file
+func PomodoroMigrations() *MigrationRegistry {
+	return pomodoroMigrations
+}
+
+func newMonthlyMigrations() *MigrationRegistry {
+	r := NewMigrationRegistry("monthly", CurrentMonthlyVersion)
+	r.Register(Migration{From: 0, Description: "wrap legacy TXT or bare YAML list", Apply: migrateMonthlyV0})
+	r.Register(Migration{From: 1, Description: "record the time zone of todo times", Apply: migrateMonthlyV1})
+	r.Register(Migration{From: 2, Description: "allow durations and all-day items", Apply: migrateMonthlyV2})
+	r.Register(Migration{From: 3, Description: "allow pomodoro estimates", Apply: migrateMonthlyV3})
+	return r
+}
+
+func newConfigMigrations() *MigrationRegistry {
+	r := NewMigrationRegistry("config", CurrentConfigVersion)
+	r.Register(Migration{From: 0, Description: "stamp unversioned config", Apply: migrateConfigV0})
+	r.Register(Migration{From: 1, Description: "allow the day planner layout", Apply: stampConfigVersion(2)})
+	r.Register(Migration{From: 2, Description: "allow Pomodoro presets", Apply: stampConfigVersion(3)})
+	r.Register(Migration{From: 3, Description: "allow Flowtime and sequence presets", Apply: stampConfigVersion(4)})
+	r.Register(Migration{From: 4, Description: "remember the compact Pomodoro window position", Apply: stampConfigVersion(5)})
+	r.Register(Migration{From: 5, Description: "allow minimizing to the tray", Apply: stampConfigVersion(6)})
+	r.Register(Migration{From: 6, Description: "allow the local API settings", Apply: stampConfigVersion(7)})
+	r.Register(Migration{From: 7, Description: "allow folder sync settings", Apply: stampConfigVersion(8)})
+	r.Register(Migration{From: 8, Description: "allow CalDAV settings", Apply: stampConfigVersion(9)})
+	r.Register(Migration{From: 9, Description: "allow a todo.txt file as storage", Apply: stampConfigVersion(10)})
+	r.Register(Migration{From: 10, Description: "allow a Markdown folder as storage", Apply: stampConfigVersion(11)})
+	return r
+}
+
+func newPomodoroMigrations() *MigrationRegistry {
+	r := NewMigrationRegistry("pomodoro", CurrentPomodoroVersion)
+	r.Register(Migration{From: 0, Description: "stamp unversioned session", Apply: stampPomodoroVersion(1)})
+	r.Register(Migration{From: 1, Description: "remember the todo worked on", Apply: stampPomodoroVersion(2)})
+	r.Register(Migration{From: 2, Description: "remember the sequence step, round and earned break", Apply: stampPomodoroVersion(3)})
+	r.Register(Migration{From: 3, Description: "log interruptions and voided sessions", Apply: stampPomodoroVersion(4)})
+	r.Register(Migration{From: 4, Description: "remember when the interval really began", Apply: stampPomodoroVersion(5)})
+	return r
+}
+
+// migrateMonthlyV0 converts the legacy TXT format, a bare YAML list or a
+// wrapper written without a version into the versioned YAML wrapper.
+func migrateMonthlyV0(data []byte) ([]byte, error) {
+	var todos []*models.TodoItem
+	if isYAMLMapping(data) {
+		var wrapper monthlyYAML
+		if err := yaml.Unmarshal(data, &wrapper); err != nil {
+			return nil, &CorruptFileError{Line: yamlErrorLine(err), Reason: "invalid todo record", Err: err}
+		}
+		todos = wrapper.Todos
+	} else if isYAMLList(data) {
+		if err := yaml.Unmarshal(data, &todos); err != nil {
+			return nil, &CorruptFileError{Line: yamlErrorLine(err), Reason: "invalid todo record", Err: err}
+		}
+	} else {
+		var perr *CorruptFileError
+		todos, perr = parseTodosTxt(data)
+		if perr != nil {
+			return nil, perr
+		}
+	}
+	if todos == nil {
+		todos = []*models.TodoItem{}
+	}
+	return yaml.Marshal(&monthlyYAML{Version: 1, Todos: todos})
+}
+
+// migrateMonthlyV1 records the zone of every todo time. Legacy TXT files and
+// early builds stored local wall-clock times tagged as UTC ("Z"); those are
+// reinterpreted in the local zone. Times whose offset matches the local zone
+// are stamped with its name, any other offset is kept as an explicit offset.
+func migrateMonthlyV1(data []byte) ([]byte, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, &CorruptFileError{Line: yamlErrorLine(err), Reason: "invalid YAML syntax", Err: err}
+	}
+	if len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return nil, &CorruptFileError{Reason: "expected a versioned todo list"}
+	}
+	doc := root.Content[0]
+
+	loc, zone := utils.LocalZone()
+	if items := mappingValue(doc, "todos"); items != nil && items.Kind == yaml.SequenceNode {
+		for _, item := range items.Content {
+			if item.Kind == yaml.MappingNode {
+				stampTodoZone(item, loc, zone)
+			}
+		}
+	}
+
+	setMonthlyVersion(doc, 2)
+	return yaml.Marshal(&root)
+}
+
+// migrateMonthlyV2 only stamps the new version. Records keep their layout, but
+// builds that do not know durations and all-day items must not rewrite them.
+func migrateMonthlyV2(data []byte) ([]byte, error) {
+	return stampMonthlyVersion(data, 3)
+}
+
+// migrateMonthlyV3 only stamps the new version so that builds without
+// pomodoro estimates and counts do not drop them.
+func migrateMonthlyV3(data []byte) ([]byte, error) {
+	return stampMonthlyVersion(data, 4)
+}
+
+// stampMonthlyVersion sets the version of a wrapped monthly file without
+// touching its records
+func stampMonthlyVersion(data []byte, version int) ([]byte, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, &CorruptFileError{Line: yamlErrorLine(err), Reason: "invalid YAML syntax", Err: err}
+	}
+	if len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return nil, &CorruptFileError{Reason: "expected a versioned todo list"}
+	}
+	setMonthlyVersion(root.Content[0], version)
+	return yaml.Marshal(&root)
+}
+
+// setMonthlyVersion updates the version key of a monthly document
+func setMonthlyVersion(doc *yaml.Node, version int) {
+	if node := mappingValue(doc, "version"); node != nil {
+		node.Value = strconv.Itoa(version)
+	}
+}
+
+// stampTodoZone fixes up the todotime of a single YAML todo record
+func stampTodoZone(item *yaml.Node, loc *time.Location, zone string) {
+	if mappingValue(item, "timezone") != nil {
+		return
+	}
+	if floating := mappingValue(item, "floating"); floating != nil && floating.Value == "true" {
+		return
+	}
+	node := mappingValue(item, "todotime")
+	if node == nil {
+		return
+	}
+	t, err := time.Parse(time.RFC3339Nano, node.Value)
+	if err != nil || t.IsZero() {
+		return
+	}
+
+	if strings.HasSuffix(node.Value, "Z") {
+		// Legacy wall clock: keep the digits, move them into the local zone
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+		node.Value = t.Format(time.RFC3339Nano)
+	} else if _, offset := t.Zone(); offset != localOffset(t, loc) {
+		// Scheduled in another zone; the explicit offset is all we know
+		return
+	}
+
+	if zone != "" {
+		item.Content = append(item.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "timezone"},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: zone})
+	}
+}
+
+// localOffset returns the UTC offset of loc at the instant t
+func localOffset(t time.Time, loc *time.Location) int {
+	_, offset := t.In(loc).Zone()
+	return offset
+}
+
+// migrateConfigV0 stamps an unversioned config and fills in the UI section
+// that very early builds did not write.
+func migrateConfigV0(data []byte) ([]byte, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		doc = make(map[string]interface{})
+	}
+
+	defaults := models.NewDefaultConfig()
+	ui, _ := doc["ui"].(map[string]interface{})
+	if ui == nil {
+		ui = make(map[string]interface{})
+	}
+	if _, ok := ui["theme"]; !ok {
+		ui["theme"] = defaults.UI.Theme
+	}
+	if _, ok := ui["viewMode"]; !ok {
+		ui["viewMode"] = defaults.UI.ViewMode
+	}
+	doc["ui"] = ui
+	doc["version"] = configVersionString(1)
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// stampConfigVersion returns a step that only stamps version. Settings added
+// since keep their layout, but builds that do not know them must not load the
+// config and drop them when saving it.
+func stampConfigVersion(version int) func([]byte) ([]byte, error) {
+	return func(data []byte) ([]byte, error) {
+		return setJSONField(data, "version", configVersionString(version))
+	}
+}
+
+// stampPomodoroVersion returns a step that only stamps version, so that
+// builds without the fields added since refuse the session instead of
+// dropping them
+func stampPomodoroVersion(version int) func([]byte) ([]byte, error) {
+	return func(data []byte) ([]byte, error) {
+		return setJSONField(data, "version", version)
+	}
+}
+
+// configVersionString formats a schema version as stored in config.json
+func configVersionString(version int) string {
+	return fmt.Sprintf("%d.0", version)
+}
+
+// setJSONField sets a top-level field of a JSON object, keeping the other
+// fields as they are
+func setJSONField(data []byte, key string, value interface{}) ([]byte, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		doc = make(map[string]json.RawMessage)
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	doc[key] = encoded
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// isYAMLMapping reports whether data is a YAML document holding a mapping,
+// such as the wrapper of a monthly file. Legacy TXT never parses as one.
+func isYAMLMapping(data []byte) bool {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil || len(root.Content) == 0 {
+		return false
+	}
+	return root.Content[0].Kind == yaml.MappingNode
+}
+
+// isYAMLList reports whether data is a YAML sequence rather than legacy TXT.
+// Blank lines, comments and a leading document marker are skipped; the
+// content itself need not parse, so a damaged list is reported as YAML.
+func isYAMLList(data []byte) bool {
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		trimmed := bytes.TrimSpace(line)
+		if len(trimmed) == 0 || trimmed[0] == '#' || bytes.Equal(trimmed, []byte("---")) {
+			continue
+		}
+		if bytes.HasPrefix(trimmed, []byte("--- ")) {
+			trimmed = bytes.TrimSpace(trimmed[len("--- "):])
+		}
+		return bytes.HasPrefix(trimmed, []byte("- ")) || bytes.Equal(trimmed, []byte("-")) || bytes.HasPrefix(trimmed, []byte("["))
+	}
+	return false
+}
+
+// detectMonthlyVersion reads the schema version of a YAML monthly file.
+// Bare lists and wrappers without a version predate versioning and count as
+// version 0.
+func detectMonthlyVersion(data []byte) (int, *CorruptFileError) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return 0, &CorruptFileError{Line: yamlErrorLine(err), Reason: "invalid YAML syntax", Err: err}
+	}
+	if len(root.Content) == 0 {
+		return CurrentMonthlyVersion, nil
+	}
+
+	doc := root.Content[0]
+	switch doc.Kind {
+	case yaml.SequenceNode:
+		return 0, nil
+	case yaml.MappingNode:
+		node := mappingValue(doc, "version")
+		if node == nil {
+			return 0, nil
+		}
+		version, err := strconv.Atoi(node.Value)
+		if err != nil || version < 0 {
+			return 0, &CorruptFileError{Line: node.Line, Reason: fmt.Sprintf("invalid version %q", node.Value)}
+		}
+		return version, nil
+	default:
+		return 0, &CorruptFileError{Line: doc.Line, Reason: "expected a todo list"}
+	}
+}
+
+// detectConfigVersion reads the schema version of config.json. The version is
+// stored as "<schema>.<minor>"; a missing version means version 0.
+func detectConfigVersion(data []byte) (int, error) {
+	var header struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return 0, err
+	}
+	if header.Version == "" {
+		return 0, nil
+	}
+	major := strings.SplitN(header.Version, ".", 2)[0]
+	version, err := strconv.Atoi(major)
+	if err != nil || version < 0 {
+		return 0, fmt.Errorf("invalid config version %q", header.Version)
+	}
+	return version, nil
+}
+
+// detectPomodoroVersion reads the schema version of pomodoro.json; a missing
+// version means version 0
+func detectPomodoroVersion(data []byte) (int, error) {
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return 0, err
+	}
+	if header.Version < 0 {
+		return 0, fmt.Errorf("invalid pomodoro version %d", header.Version)
+	}
+	return header.Version, nil
+}
+
+// writeFileAtomic writes data to a temporary file and renames it over path
+func writeFileAtomic(path string, data []byte) error {
+	return writeFileAtomicPerm(path, data, 0644)
+}
+
+// writeFileAtomicPerm is writeFileAtomic for a file created with perm
+func writeFileAtomicPerm(path string, data []byte, perm os.FileMode) error {
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, perm); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		// Clean up temp file on error
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
@@ -2,8 +2,8 @@ package persistence
 
 import (
 	"fmt"
-	"os"
 	"sort"
+	"strings"
 	"time"
 
 	"godo/src/models"
@@ -14,6 +14,7 @@ import (
 type MonthlyManager struct {
 	fileManager *FileIOManager
 	cache       map[string][]*models.TodoItem // Cache for loaded monthly data
+	events      *TodoEvents
 }
 
 // NewMonthlyManager creates a new monthly manager
@@ -21,9 +22,15 @@ func NewMonthlyManager(dataDir string) *MonthlyManager {
 	return &MonthlyManager{
 		fileManager: NewFileIOManager(dataDir),
 		cache:       make(map[string][]*models.TodoItem),
+		events:      NewTodoEvents(),
 	}
 }
 
+// Events returns the bus the manager publishes its changes on
+func (m *MonthlyManager) Events() *TodoEvents {
+	return m.events
+}
+
 // GetDataDir returns the data directory path
 func (m *MonthlyManager) GetDataDir() string {
 	return m.fileManager.dataDir
@@ -55,8 +62,44 @@ func (m *MonthlyManager) GetTodosForMonth(year, month int) ([]*models.TodoItem,
 	return todos, nil
 }
 
+// GetTodosForDay returns the todos that fall on the calendar day of day, as seen
+// in day's location. Items are filed by the zone they were scheduled in, so on
+// the first and last day of a month the neighbouring file is searched as well.
+func (m *MonthlyManager) GetTodosForDay(day time.Time) ([]*models.TodoItem, error) {
+	months := []time.Time{day}
+	if day.Day() == 1 {
+		months = append(months, day.AddDate(0, 0, -1))
+	}
+	if day.Day() == utils.DaysInMonth(int(day.Month()), day.Year()) {
+		months = append(months, day.AddDate(0, 0, 1))
+	}
+
+	var dayTodos []*models.TodoItem
+	for _, monthDay := range months {
+		todos, err := m.GetTodosForMonth(monthDay.Year(), int(monthDay.Month()))
+		if err != nil {
+			return nil, err
+		}
+		for _, todo := range todos {
+			if todo.OccursOn(day, day.Location()) {
+				dayTodos = append(dayTodos, todo)
+			}
+		}
+	}
+	return dayTodos, nil
+}
+
 // SaveTodosForMonth saves todos for a specific month
 func (m *MonthlyManager) SaveTodosForMonth(year, month int, todos []*models.TodoItem) error {
+	if err := m.saveMonth(year, month, todos); err != nil {
+		return err
+	}
+	m.events.Publish(newTodoEvent(TodosSaved, todos, utils.FormatDateKey(year, month)))
+	return nil
+}
+
+// saveMonth writes a month file and caches its todos without publishing
+func (m *MonthlyManager) saveMonth(year, month int, todos []*models.TodoItem) error {
 	dateKey := utils.FormatDateKey(year, month)
 
 	err := m.fileManager.SaveTodos(year, month, todos)
@@ -72,7 +115,16 @@ func (m *MonthlyManager) SaveTodosForMonth(year, month int, todos []*models.Todo
 
 // AddTodo adds a new todo item to the appropriate month
 func (m *MonthlyManager) AddTodo(todo *models.TodoItem) error {
-	year, month := todo.TodoTime.Year(), int(todo.TodoTime.Month())
+	if err := m.addTodo(todo); err != nil {
+		return err
+	}
+	m.events.Publish(newTodoEvent(TodosAdded, []*models.TodoItem{todo}))
+	return nil
+}
+
+// addTodo adds todo without publishing
+func (m *MonthlyManager) addTodo(todo *models.TodoItem) error {
+	year, month := todoMonth(todo)
 
 	// Get existing todos for the month
 	todos, err := m.GetTodosForMonth(year, month)
@@ -88,22 +140,28 @@ func (m *MonthlyManager) AddTodo(todo *models.TodoItem) error {
 		return todos[i].TodoTime.After(todos[j].TodoTime)
 	})
 
-	return m.SaveTodosForMonth(year, month, todos)
+	return m.saveMonth(year, month, todos)
 }
 
 // UpdateTodo updates an existing todo item
 func (m *MonthlyManager) UpdateTodo(todo *models.TodoItem, originalTime time.Time) error {
-	originalYear, originalMonth := originalTime.Year(), int(originalTime.Month())
-	newYear, newMonth := todo.TodoTime.Year(), int(todo.TodoTime.Month())
+	originalYear, originalMonth := m.findMonth(originalTime, func(existing *models.TodoItem) bool {
+		return existing.Name == todo.Name
+	})
+	newYear, newMonth := todoMonth(todo)
 
 	// If the month changed, we need to move the todo
 	if originalYear != newYear || originalMonth != newMonth {
 		// Remove from original month
-		if err := m.RemoveTodo(originalTime); err != nil {
+		if _, err := m.removeTodo(originalTime); err != nil {
 			return err
 		}
 		// Add to new month
-		return m.AddTodo(todo)
+		if err := m.addTodo(todo); err != nil {
+			return err
+		}
+		m.events.Publish(newTodoEvent(TodosUpdated, []*models.TodoItem{todo}, utils.FormatDateKey(originalYear, originalMonth)))
+		return nil
 	}
 
 	// Update within the same month
@@ -132,27 +190,46 @@ func (m *MonthlyManager) UpdateTodo(todo *models.TodoItem, originalTime time.Tim
 		return todos[i].TodoTime.After(todos[j].TodoTime)
 	})
 
-	return m.SaveTodosForMonth(originalYear, originalMonth, todos)
+	if err := m.saveMonth(originalYear, originalMonth, todos); err != nil {
+		return err
+	}
+	m.events.Publish(newTodoEvent(TodosUpdated, []*models.TodoItem{todo}))
+	return nil
 }
 
 // RemoveTodo removes a todo item by its time
 func (m *MonthlyManager) RemoveTodo(todoTime time.Time) error {
-	year, month := todoTime.Year(), int(todoTime.Month())
+	removed, err := m.removeTodo(todoTime)
+	if err != nil {
+		return err
+	}
+	if removed != nil {
+		m.events.Publish(newTodoEvent(TodosRemoved, []*models.TodoItem{removed}))
+	}
+	return nil
+}
+
+// removeTodo removes the todo at todoTime without publishing and returns it,
+// or nil when there was none
+func (m *MonthlyManager) removeTodo(todoTime time.Time) (*models.TodoItem, error) {
+	year, month := m.findMonth(todoTime, nil)
 
 	todos, err := m.GetTodosForMonth(year, month)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Find and remove the todo
+	var removed *models.TodoItem
 	for i, todo := range todos {
 		if todo.TodoTime.Equal(todoTime) {
+			removed = todo
 			todos = append(todos[:i], todos[i+1:]...)
 			break
 		}
 	}
 
-	return m.SaveTodosForMonth(year, month, todos)
+	return removed, m.saveMonth(year, month, todos)
 }
 
 // RemoveTodos removes multiple todos by their times
@@ -161,11 +238,19 @@ func (m *MonthlyManager) RemoveTodos(todoTimes []time.Time) error {
 	monthGroups := make(map[string][]time.Time)
 
 	for _, todoTime := range todoTimes {
-		dateKey := utils.FormatDateKey(todoTime.Year(), int(todoTime.Month()))
+		year, month := m.findMonth(todoTime, nil)
+		dateKey := utils.FormatDateKey(year, month)
 		monthGroups[dateKey] = append(monthGroups[dateKey], todoTime)
 	}
 
 	// Remove from each month
+	var removed []*models.TodoItem
+	defer func() {
+		// Months removed from before a failure are reported too
+		if len(removed) > 0 {
+			m.events.Publish(newTodoEvent(TodosRemoved, removed))
+		}
+	}()
 	for dateKey, times := range monthGroups {
 		year, month := utils.ParseDateKey(dateKey)
 
@@ -176,6 +261,7 @@ func (m *MonthlyManager) RemoveTodos(todoTimes []time.Time) error {
 
 		// Remove todos
 		newTodos := make([]*models.TodoItem, 0, len(todos))
+		var monthRemoved []*models.TodoItem
 		for _, todo := range todos {
 			shouldRemove := false
 			for _, removeTime := range times {
@@ -184,14 +270,17 @@ func (m *MonthlyManager) RemoveTodos(todoTimes []time.Time) error {
 					break
 				}
 			}
-			if !shouldRemove {
+			if shouldRemove {
+				monthRemoved = append(monthRemoved, todo)
+			} else {
 				newTodos = append(newTodos, todo)
 			}
 		}
 
-		if err := m.SaveTodosForMonth(year, month, newTodos); err != nil {
+		if err := m.saveMonth(year, month, newTodos); err != nil {
 			return err
 		}
+		removed = append(removed, monthRemoved...)
 	}
 
 	return nil
@@ -199,7 +288,7 @@ func (m *MonthlyManager) RemoveTodos(todoTimes []time.Time) error {
 
 // GetTodoByTime finds a todo item by its time (for editing)
 func (m *MonthlyManager) GetTodoByTime(todoTime time.Time) (*models.TodoItem, error) {
-	year, month := todoTime.Year(), int(todoTime.Month())
+	year, month := m.findMonth(todoTime, nil)
 
 	todos, err := m.GetTodosForMonth(year, month)
 	if err != nil {
@@ -215,6 +304,44 @@ func (m *MonthlyManager) GetTodoByTime(todoTime time.Time) (*models.TodoItem, er
 	return nil, fmt.Errorf("todo item not found")
 }
 
+// todoMonth returns the month file a todo belongs to. Items are bucketed by the
+// calendar of the zone they were scheduled in, so viewing them from another
+// zone or after a DST change never moves them between files.
+func todoMonth(todo *models.TodoItem) (int, int) {
+	zoned := todo.ZonedTime()
+	return zoned.Year(), int(zoned.Month())
+}
+
+// findMonth returns the month file holding the todo stored at todoTime.
+// Callers may pass the time converted to another zone, so the neighbouring
+// month is checked when the instant falls on a month boundary. The month of
+// todoTime is returned when no file contains the todo.
+func (m *MonthlyManager) findMonth(todoTime time.Time, match func(*models.TodoItem) bool) (int, int) {
+	year, month := todoTime.Year(), int(todoTime.Month())
+
+	// UTC offsets range from -12h to +14h
+	checked := make(map[string]bool)
+	for _, candidate := range []time.Time{todoTime, todoTime.Add(-14 * time.Hour), todoTime.Add(14 * time.Hour)} {
+		cYear, cMonth := candidate.Year(), int(candidate.Month())
+		dateKey := utils.FormatDateKey(cYear, cMonth)
+		if checked[dateKey] {
+			continue
+		}
+		checked[dateKey] = true
+
+		todos, err := m.GetTodosForMonth(cYear, cMonth)
+		if err != nil {
+			continue
+		}
+		for _, todo := range todos {
+			if todo.TodoTime.Equal(todoTime) && (match == nil || match(todo)) {
+				return cYear, cMonth
+			}
+		}
+	}
+	return year, month
+}
+
 // GetAllMonths returns all months that have data files
 func (m *MonthlyManager) GetAllMonths() ([]string, error) {
 	return m.fileManager.GetAllMonthlyFiles()
@@ -230,45 +357,50 @@ func (m *MonthlyManager) GetCacheSize() int {
 	return len(m.cache)
 }
 
-// MigrateAllToYAML converts existing legacy TXT monthly files to YAML format.
-// If a YAML file already exists for a month, it will be left untouched.
-func (m *MonthlyManager) MigrateAllToYAML() error {
+// RepairMonth salvages a damaged monthly file and refreshes the cached copy.
+// The original file is kept in the quarantine folder whenever it is rewritten.
+func (m *MonthlyManager) RepairMonth(year, month int) (*RepairReport, error) {
+	dateKey := utils.FormatDateKey(year, month)
+
+	report, err := m.fileManager.RepairTodos(year, month)
+	if err != nil {
+		return nil, fmt.Errorf("failed to repair todos for %s: %w", dateKey, err)
+	}
+
+	// Force a fresh load of the repaired data
+	delete(m.cache, dateKey)
+	m.events.Publish(newTodoEvent(TodosReloaded, nil, dateKey))
+	return report, nil
+}
+
+// MigrateAll upgrades every monthly file to the current schema version by
+// running it through the migration registry (legacy TXT files included).
+// Damaged files or files from a newer release are skipped and reported together.
+func (m *MonthlyManager) MigrateAll() error {
 	months, err := m.GetAllMonths()
 	if err != nil {
 		return err
 	}
 
+	var failed []string
 	for _, dateKey := range months {
 		year, month := utils.ParseDateKey(dateKey)
 		if year == 0 {
 			continue
 		}
 
-		yamlPath := m.fileManager.getYamlFilePath(year, month)
-		if _, err := os.Stat(yamlPath); err == nil {
-			// Already migrated
-			continue
-		}
-
-		// Try loading from legacy TXT directly
-		todos, err := m.fileManager.loadTodosTxt(year, month)
-		if err != nil {
-			// Skip problematic month but continue others
-			continue
-		}
-
-		if len(todos) == 0 {
-			// Nothing to migrate
-			continue
-		}
-
-		// Save in YAML using current saver
-		if err := m.fileManager.SaveTodos(year, month, todos); err != nil {
-			return fmt.Errorf("failed to migrate %s to YAML: %w", dateKey, err)
+		// Loading performs the upgrade and writes the result back
+		if _, err := m.fileManager.LoadTodos(year, month); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", dateKey, err))
 		}
 	}
 
-	// Clear cache to ensure fresh loads from YAML
+	// Clear cache to ensure fresh loads of the upgraded files
 	m.ClearCache()
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return fmt.Errorf("failed to migrate %d month(s): %s", len(failed), strings.Join(failed, "; "))
+	}
 	return nil
 }
@@ -0,0 +1,79 @@
+package persistence
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"godo/src/models"
+)
+
+// pomodoroFile is the on-disk layout of pomodoro.json
+type pomodoroFile struct {
+	Version int `json:"version"`
+	models.PomodoroSession
+}
+
+// PomodoroStore persists the Pomodoro timer state and completed intervals
+type PomodoroStore struct {
+	path string
+}
+
+// NewPomodoroStore creates a store keeping pomodoro.json in dataDir
+func NewPomodoroStore(dataDir string) *PomodoroStore {
+	return &PomodoroStore{
+		path: filepath.Join(dataDir, "pomodoro.json"),
+	}
+}
+
+// LoadSession loads the saved session. A missing file yields an idle session.
+// Older versions are upgraded; a file from a newer release is refused with
+// *NewerVersionError.
+func (ps *PomodoroStore) LoadSession() (*models.PomodoroSession, error) {
+	data, err := os.ReadFile(ps.path)
+	if os.IsNotExist(err) {
+		return &models.PomodoroSession{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pomodoro file: %w", err)
+	}
+
+	// Upgrade older schema versions
+	version, err := detectPomodoroVersion(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pomodoro file: %w", err)
+	}
+	upgraded, err := pomodoroMigrations.Migrate(data, version)
+	if err != nil {
+		var newer *NewerVersionError
+		if errors.As(err, &newer) {
+			newer.Path = ps.path
+			return nil, newer
+		}
+		return nil, fmt.Errorf("failed to migrate pomodoro file: %w", err)
+	}
+
+	var file pomodoroFile
+	if err := json.Unmarshal(upgraded, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse pomodoro file: %w", err)
+	}
+	return &file.PomodoroSession, nil
+}
+
+// SaveSession writes the session to disk
+func (ps *PomodoroStore) SaveSession(session *models.PomodoroSession) error {
+	if err := os.MkdirAll(filepath.Dir(ps.path), 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(pomodoroFile{Version: CurrentPomodoroVersion, PomodoroSession: *session}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal pomodoro session: %w", err)
+	}
+	if err := writeFileAtomic(ps.path, data); err != nil {
+		return fmt.Errorf("failed to write pomodoro file: %w", err)
+	}
+	return nil
+}
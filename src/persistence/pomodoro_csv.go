@@ -0,0 +1,70 @@
+package persistence
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"godo/src/models"
+)
+
+// pomodoroCSVHeader names the columns written by WritePomodoroCSV
+var pomodoroCSVHeader = []string{"kind", "start", "end", "minutes", "status", "task", "task_time", "label", "interruptions"}
+
+// WritePomodoroCSV writes one row per recorded interval, oldest first, with
+// the number of interruptions logged during it. labelOf returns the label of
+// a task's todo; it may be nil.
+func WritePomodoroCSV(w io.Writer, records []models.PomodoroRecord, interruptions []models.Interruption, labelOf func(*models.PomodoroTask) string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(pomodoroCSVHeader); err != nil {
+		return fmt.Errorf("failed to write pomodoro CSV: %w", err)
+	}
+
+	for _, record := range records {
+		kind, err := record.Kind.MarshalText()
+		if err != nil {
+			return fmt.Errorf("failed to write pomodoro CSV: %w", err)
+		}
+		status := "completed"
+		if record.Voided {
+			status = "voided"
+		}
+		var task, taskTime, label string
+		if record.Task != nil {
+			task = record.Task.Name
+			taskTime = record.Task.Time.Format(time.RFC3339)
+			if labelOf != nil {
+				label = labelOf(record.Task)
+			}
+		}
+		count := 0
+		for _, interruption := range interruptions {
+			if !interruption.At.Before(record.Start) && !interruption.At.After(record.End) {
+				count++
+			}
+		}
+
+		row := []string{
+			string(kind),
+			record.Start.Format(time.RFC3339),
+			record.End.Format(time.RFC3339),
+			strconv.FormatFloat(record.End.Sub(record.Start).Minutes(), 'f', 1, 64),
+			status,
+			task,
+			taskTime,
+			label,
+			strconv.Itoa(count),
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write pomodoro CSV: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write pomodoro CSV: %w", err)
+	}
+	return nil
+}
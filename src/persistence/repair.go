@@ -0,0 +1,340 @@
+package persistence
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"godo/src/models"
+	"godo/src/utils"
+)
+
+// versionLinePattern finds the version key of a monthly file that does not parse
+var versionLinePattern = regexp.MustCompile(`(?m)^version:\s*(\d+)\s*$`)
+
+// quarantineDirName is the folder inside the data directory holding copies of damaged files
+const quarantineDirName = "quarantine"
+
+// RepairIssue describes a single problem found while repairing a monthly file
+type RepairIssue struct {
+	Line   int    // 1-based line in the source file (0 if unknown)
+	Reason string // What was wrong and what was done about it
+}
+
+// RepairReport summarizes the result of a repair run over one monthly file
+type RepairReport struct {
+	Path           string        // File that was checked
+	QuarantinePath string        // Copy of the original file (empty if the file was healthy)
+	Salvaged       int           // Records written back
+	Dropped        int           // Records that could not be recovered
+	Normalized     int           // Records whose Level/Kind were out of range
+	Duplicates     int           // Duplicate records removed
+	Issues         []RepairIssue // Details for every problem found
+}
+
+// Changed reports whether the repair altered anything
+func (r *RepairReport) Changed() bool {
+	return r.Dropped > 0 || r.Normalized > 0 || r.Duplicates > 0
+}
+
+// corruptFile quarantines a damaged file and completes the error with its path
+func (f *FileIOManager) corruptFile(path string, data []byte, perr *CorruptFileError) *CorruptFileError {
+	perr.Path = path
+	if qPath, err := f.quarantine(path, data); err == nil {
+		perr.QuarantinePath = qPath
+	}
+	return perr
+}
+
+// quarantine copies a damaged file into the quarantine folder. Copies are named
+// by content hash so the same damage is only stored once.
+func (f *FileIOManager) quarantine(path string, data []byte) (string, error) {
+	dir := filepath.Join(f.dataDir, quarantineDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create quarantine directory: %w", err)
+	}
+
+	sum := sha1.Sum(data)
+	base := filepath.Base(path)
+	ext := filepath.Ext(base)
+	name := fmt.Sprintf("%s-%s%s", strings.TrimSuffix(base, ext), hex.EncodeToString(sum[:4]), ext)
+	qPath := filepath.Join(dir, name)
+
+	if _, err := os.Stat(qPath); err == nil {
+		return qPath, nil
+	}
+	if err := os.WriteFile(qPath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to quarantine %s: %w", base, err)
+	}
+	return qPath, nil
+}
+
+// RepairTodos salvages every readable record of a monthly file, normalizes
+// out-of-range Level/Kind values, removes duplicates and writes the result back
+// as YAML. The original file is quarantined before anything is rewritten.
+func (f *FileIOManager) RepairTodos(year, month int) (*RepairReport, error) {
+	path := f.getYamlFilePath(year, month)
+	data, err := os.ReadFile(path)
+	legacy := false
+	if os.IsNotExist(err) {
+		path = f.getTxtFilePath(year, month)
+		data, err = os.ReadFile(path)
+		legacy = true
+	}
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &RepairReport{Path: f.getYamlFilePath(year, month)}, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+
+	report := &RepairReport{Path: path}
+	version := repairSourceVersion(data, legacy)
+	if version > CurrentMonthlyVersion {
+		return nil, &NewerVersionError{Path: path, Kind: "monthly", Version: version, Supported: CurrentMonthlyVersion}
+	}
+
+	var todos []*models.TodoItem
+	if legacy {
+		var perr *CorruptFileError
+		todos, perr = parseTodosTxt(data)
+		if perr != nil {
+			// Records after a broken one cannot be realigned in the TXT format
+			if count, ok := legacyRecordCount(data); ok {
+				report.Dropped += count - len(todos)
+				report.Issues = append(report.Issues, RepairIssue{Line: perr.Line, Reason: fmt.Sprintf("%s; %d remaining records dropped", perr.Reason, count-len(todos))})
+			} else {
+				// Without a count the records cannot be told apart at all
+				report.Dropped++
+				report.Issues = append(report.Issues, RepairIssue{Line: perr.Line, Reason: perr.Reason + "; file contents dropped"})
+			}
+		}
+	} else {
+		todos = salvageTodosYAML(data, report)
+	}
+
+	todos = normalizeTodos(todos, report)
+
+	if !report.Changed() {
+		report.Salvaged = len(todos)
+		return report, nil
+	}
+
+	qPath, err := f.quarantine(path, data)
+	if err != nil {
+		return nil, err
+	}
+	report.QuarantinePath = qPath
+
+	// Salvaged records keep the schema of the source file, so run the
+	// remaining migrations before writing them back
+	salvaged, err := yaml.Marshal(&monthlyYAML{Version: version, Todos: todos})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+	upgraded, err := monthlyMigrations.Migrate(salvaged, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate repaired %s: %w", filepath.Base(path), err)
+	}
+	if err := writeFileAtomic(f.getYamlFilePath(year, month), upgraded); err != nil {
+		return nil, err
+	}
+	if legacy {
+		// The YAML file now supersedes the legacy one
+		_ = os.Remove(path)
+	}
+	report.Salvaged = len(todos)
+	return report, nil
+}
+
+// repairSourceVersion guesses the schema version of a file that may not parse.
+// Salvaged records are at least version 1, the first versioned layout.
+func repairSourceVersion(data []byte, legacy bool) int {
+	if legacy {
+		return 1
+	}
+	version, perr := detectMonthlyVersion(data)
+	if perr != nil {
+		match := versionLinePattern.FindSubmatch(data)
+		if match == nil {
+			return 1
+		}
+		version, _ = strconv.Atoi(string(match[1]))
+	}
+	if version < 1 {
+		return 1
+	}
+	return version
+}
+
+// salvageTodosYAML decodes each todo record on its own so one bad record does
+// not take the rest of the month with it.
+func salvageTodosYAML(data []byte, report *RepairReport) []*models.TodoItem {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		// The document as a whole is unreadable: fall back to per-record chunks
+		return salvageTodoChunks(data, report)
+	}
+	if len(root.Content) == 0 {
+		return []*models.TodoItem{}
+	}
+
+	items := root.Content[0]
+	if items.Kind == yaml.MappingNode {
+		items = mappingValue(items, "todos")
+	}
+	if items == nil || items.Kind != yaml.SequenceNode {
+		report.Issues = append(report.Issues, RepairIssue{Line: root.Content[0].Line, Reason: "no todo list found"})
+		return []*models.TodoItem{}
+	}
+
+	todos := make([]*models.TodoItem, 0, len(items.Content))
+	for _, node := range items.Content {
+		todo := models.NewTodoItem()
+		if err := node.Decode(todo); err != nil {
+			report.Dropped++
+			report.Issues = append(report.Issues, RepairIssue{Line: node.Line, Reason: fmt.Sprintf("dropped unreadable record: %v", err)})
+			continue
+		}
+		todos = append(todos, todo)
+	}
+	return todos
+}
+
+// salvageTodoChunks splits a syntactically broken file at list item markers
+// and decodes every chunk separately.
+func salvageTodoChunks(data []byte, report *RepairReport) []*models.TodoItem {
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+
+	// Item indentation is taken from the first list marker in the file
+	indent := -1
+	var starts []int
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		if !strings.HasPrefix(trimmed, "- ") {
+			continue
+		}
+		lineIndent := len(line) - len(trimmed)
+		if indent == -1 {
+			indent = lineIndent
+		}
+		if lineIndent == indent {
+			starts = append(starts, i)
+		}
+	}
+
+	todos := make([]*models.TodoItem, 0, len(starts))
+	for n, start := range starts {
+		end := len(lines)
+		if n+1 < len(starts) {
+			end = starts[n+1]
+		}
+		chunk := strings.Join(lines[start:end], "\n")
+
+		var list []*models.TodoItem
+		if err := yaml.Unmarshal([]byte(chunk), &list); err != nil || len(list) != 1 || list[0] == nil {
+			report.Dropped++
+			reason := "dropped unreadable record"
+			if err != nil {
+				reason = fmt.Sprintf("%s: %v", reason, err)
+			}
+			report.Issues = append(report.Issues, RepairIssue{Line: start + 1, Reason: reason})
+			continue
+		}
+		todos = append(todos, list[0])
+	}
+	return todos
+}
+
+// legacyRecordCount returns the record count on the first line of a legacy
+// TXT file
+func legacyRecordCount(data []byte) (int, bool) {
+	line := string(data)
+	if i := strings.IndexAny(line, "\r\n"); i >= 0 {
+		line = line[:i]
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "\ufeff")))
+	if err != nil || count < 0 {
+		return 0, false
+	}
+	return count, true
+}
+
+// mappingValue returns the value node stored under key in a YAML mapping
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// normalizeTodos clamps out-of-range Level/Kind values and drops duplicate records
+// (same name and time), keeping the first occurrence.
+func normalizeTodos(todos []*models.TodoItem, report *RepairReport) []*models.TodoItem {
+	seen := make(map[string]struct{}, len(todos))
+	result := make([]*models.TodoItem, 0, len(todos))
+
+	for _, todo := range todos {
+		if todo == nil {
+			continue
+		}
+
+		normalized := false
+		if todo.Level < int(models.PriorityLow) {
+			todo.Level = int(models.PriorityLow)
+			normalized = true
+		} else if todo.Level > int(models.PriorityUrgent) {
+			todo.Level = int(models.PriorityUrgent)
+			normalized = true
+		}
+		if todo.Kind != 0 && todo.Kind != 1 {
+			todo.Kind = 0 // Default to Event like NewTodoItem
+			normalized = true
+		}
+		if normalized {
+			report.Normalized++
+			report.Issues = append(report.Issues, RepairIssue{Reason: fmt.Sprintf("normalized level/kind of %q", todo.Name)})
+		}
+
+		key := fmt.Sprintf("%d|%s", todo.TodoTime.UnixNano(), todo.Name)
+		if _, dup := seen[key]; dup {
+			report.Duplicates++
+			report.Issues = append(report.Issues, RepairIssue{Reason: fmt.Sprintf("removed duplicate of %q at %s", todo.Name, todo.TodoTime.Format("2006-01-02 15:04"))})
+			continue
+		}
+		seen[key] = struct{}{}
+		result = append(result, todo)
+	}
+	return result
+}
+
+// RepairAll runs RepairTodos over every monthly file in the data directory
+func (f *FileIOManager) RepairAll() ([]*RepairReport, error) {
+	months, err := f.GetAllMonthlyFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []*RepairReport
+	for _, dateKey := range months {
+		year, month := utils.ParseDateKey(dateKey)
+		if year == 0 {
+			continue
+		}
+		report, err := f.RepairTodos(year, month)
+		if err != nil {
+			return reports, fmt.Errorf("failed to repair %s: %w", dateKey, err)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
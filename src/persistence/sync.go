@@ -0,0 +1,364 @@
+package persistence
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"godo/src/models"
+	"godo/src/utils"
+)
+
+// syncDirName is the folder inside the data directory holding the base
+// snapshots of each sync remote
+const syncDirName = "sync"
+
+// SyncStore is a set of monthly files the sync engine reads and writes. A
+// FileIOManager on a mounted or synced folder is one; a WebDAV client would
+// be another.
+type SyncStore interface {
+	GetAllMonthlyFiles() ([]string, error)
+	LoadTodos(year, month int) ([]*models.TodoItem, error)
+	SaveTodos(year, month int, todos []*models.TodoItem) error
+}
+
+// SyncChoice is the user's decision on a conflict
+type SyncChoice int
+
+const (
+	SyncUndecided  SyncChoice = iota // Leave both sides as they are
+	SyncKeepLocal                    // Overwrite the remote version
+	SyncKeepRemote                   // Overwrite the local version
+)
+
+// SyncConflict is a todo changed differently on both sides since the last
+// sync. A nil version means the todo was deleted (or never existed) there.
+type SyncConflict struct {
+	Month  string // Date key of the month file
+	Base   *models.TodoItem
+	Local  *models.TodoItem
+	Remote *models.TodoItem
+	Choice SyncChoice
+}
+
+// Time returns the time identifying the conflicting todo
+func (c *SyncConflict) Time() time.Time {
+	if todo := c.todo(); todo != nil {
+		return todo.TodoTime
+	}
+	return time.Time{}
+}
+
+// todo returns one of the versions; all of them share time and name
+func (c *SyncConflict) todo() *models.TodoItem {
+	for _, todo := range []*models.TodoItem{c.Local, c.Remote, c.Base} {
+		if todo != nil {
+			return todo
+		}
+	}
+	return nil
+}
+
+// SyncReport summarizes one sync run
+type SyncReport struct {
+	Months    int             // Month files compared
+	Pulled    int             // Todos added, changed or deleted locally
+	Pushed    int             // Todos added, changed or deleted remotely
+	Conflicts []*SyncConflict // Undecided conflicts; those todos were left as they are on each side
+	Failed    []string        // Months that could not be synced, with the reason
+}
+
+// Changed reports whether the local data changed
+func (r *SyncReport) Changed() bool {
+	return r.Pulled > 0
+}
+
+// Syncer reconciles the local data directory with a remote copy. Each month
+// is merged per todo against the state both sides agreed on after the last
+// sync, so edits made on different devices are combined instead of one month
+// file overwriting the other. Todos are identified by their time and name,
+// like UpdateTodo does, so todos sharing a time are kept apart.
+type Syncer struct {
+	local   TodoRepository
+	dataDir string
+	remote  SyncStore
+	base    SyncStore // Snapshot of the last merge with remote
+}
+
+// NewSyncer creates a sync engine for the todos in dataDir. It has no remote
+// until SetRemote or SetRemoteDir is called.
+func NewSyncer(local TodoRepository, dataDir string) *Syncer {
+	return &Syncer{local: local, dataDir: dataDir}
+}
+
+// SetRemote sets the store to sync with. name identifies the remote, so each
+// remote keeps its own base snapshots: comparing a new remote against the
+// base of another would read every missing todo as deleted.
+func (s *Syncer) SetRemote(remote SyncStore, name string) {
+	if remote == nil {
+		s.remote, s.base = nil, nil
+		return
+	}
+	sum := sha256.Sum256([]byte(name))
+	s.remote = remote
+	s.base = NewFileIOManager(filepath.Join(s.dataDir, syncDirName, fmt.Sprintf("%x", sum[:4])))
+}
+
+// SetRemoteDir syncs with the monthly files in dir, typically a folder shared
+// by a file sync service or a mounted network drive. An empty dir turns
+// syncing off.
+func (s *Syncer) SetRemoteDir(dir string) error {
+	if dir == "" {
+		s.SetRemote(nil, "")
+		return nil
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve sync folder: %w", err)
+	}
+	if local, err := filepath.Abs(s.dataDir); err == nil && filepath.Clean(local) == filepath.Clean(abs) {
+		return errors.New("failed to set sync folder: it is the data directory itself")
+	}
+	s.SetRemote(NewFileIOManager(abs), filepath.Clean(abs))
+	return nil
+}
+
+// HasRemote reports whether a remote is set
+func (s *Syncer) HasRemote() bool {
+	return s.remote != nil
+}
+
+// Sync merges every month present locally, remotely or in the base snapshot.
+// Todos with undecided conflicts keep their version on each side while the
+// rest of their month is synced; the conflicts are returned in the report and
+// can be passed back with a Choice once the user decided. A decision only
+// applies while both versions are still the ones shown to the user.
+func (s *Syncer) Sync(resolved []*SyncConflict) (*SyncReport, error) {
+	if s.remote == nil {
+		return nil, errors.New("failed to sync: no sync folder set")
+	}
+
+	months, err := s.months()
+	if err != nil {
+		return nil, err
+	}
+
+	decisions := make(map[string]*SyncConflict)
+	for _, conflict := range resolved {
+		if conflict.Choice != SyncUndecided {
+			decisions[syncKey(conflict.Month, conflict.todo())] = conflict
+		}
+	}
+
+	// Re-read local files in case they changed outside the app
+	s.local.ClearCache()
+
+	report := &SyncReport{}
+	for _, dateKey := range months {
+		year, month := utils.ParseDateKey(dateKey)
+		if year == 0 {
+			continue
+		}
+		report.Months++
+		if err := s.syncMonth(dateKey, year, month, decisions, report); err != nil {
+			report.Failed = append(report.Failed, fmt.Sprintf("%s: %v", dateKey, err))
+		}
+	}
+	return report, nil
+}
+
+// months returns the date keys known to any of the three sides, oldest first
+func (s *Syncer) months() ([]string, error) {
+	seen := make(map[string]bool)
+	for _, list := range []func() ([]string, error){s.local.GetAllMonths, s.remote.GetAllMonthlyFiles, s.base.GetAllMonthlyFiles} {
+		keys, err := list()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list months: %w", err)
+		}
+		for _, key := range keys {
+			seen[key] = true
+		}
+	}
+
+	months := make([]string, 0, len(seen))
+	for key := range seen {
+		months = append(months, key)
+	}
+	sort.Strings(months)
+	return months, nil
+}
+
+// syncMonth merges one month and writes the result wherever it differs
+func (s *Syncer) syncMonth(dateKey string, year, month int, decisions map[string]*SyncConflict, report *SyncReport) error {
+	local, err := s.local.GetTodosForMonth(year, month)
+	if err != nil {
+		return err
+	}
+	remote, err := s.remote.LoadTodos(year, month)
+	if err != nil {
+		return fmt.Errorf("failed to load remote: %w", err)
+	}
+	base, err := s.base.LoadTodos(year, month)
+	if err != nil {
+		return fmt.Errorf("failed to load base snapshot: %w", err)
+	}
+
+	merged, conflicts := mergeTodos(dateKey, base, local, remote, decisions)
+	report.Conflicts = append(report.Conflicts, conflicts...)
+
+	// Conflicting todos stay as they are on each side until the user decides
+	newLocal := withConflicts(merged, conflicts, func(c *SyncConflict) *models.TodoItem { return c.Local })
+	newRemote := withConflicts(merged, conflicts, func(c *SyncConflict) *models.TodoItem { return c.Remote })
+	newBase := withConflicts(merged, conflicts, func(c *SyncConflict) *models.TodoItem { return c.Base })
+
+	pull := countChanges(local, newLocal)
+	push := countChanges(remote, newRemote)
+	if push > 0 {
+		// Another device may have synced meanwhile; its changes win the next run
+		current, err := s.remote.LoadTodos(year, month)
+		if err != nil {
+			return fmt.Errorf("failed to load remote: %w", err)
+		}
+		if countChanges(remote, current) > 0 {
+			return errors.New("remote changed during sync, retry later")
+		}
+		if err := s.remote.SaveTodos(year, month, newRemote); err != nil {
+			return fmt.Errorf("failed to save remote: %w", err)
+		}
+	}
+	if pull > 0 {
+		if err := s.local.SaveTodosForMonth(year, month, newLocal); err != nil {
+			return err
+		}
+	}
+	if countChanges(base, newBase) > 0 {
+		if err := s.base.SaveTodos(year, month, newBase); err != nil {
+			return fmt.Errorf("failed to save base snapshot: %w", err)
+		}
+	}
+
+	report.Pulled += pull
+	report.Pushed += push
+	return nil
+}
+
+// mergeTodos merges the todos of one month three ways. A todo changed on one
+// side only takes that side's version; a todo changed differently on both is
+// a conflict unless a matching decision exists. Conflicting todos are left
+// out of the result, which is sorted like the monthly files, latest first.
+func mergeTodos(dateKey string, base, local, remote []*models.TodoItem, decisions map[string]*SyncConflict) ([]*models.TodoItem, []*SyncConflict) {
+	baseByKey, localByKey, remoteByKey := indexTodos(base), indexTodos(local), indexTodos(remote)
+
+	keys := make(map[todoKey]*models.TodoItem)
+	for _, index := range []map[todoKey]*models.TodoItem{baseByKey, localByKey, remoteByKey} {
+		for key, todo := range index {
+			keys[key] = todo
+		}
+	}
+
+	var merged []*models.TodoItem
+	var conflicts []*SyncConflict
+	for key, todo := range keys {
+		b, l, r := baseByKey[key], localByKey[key], remoteByKey[key]
+
+		var result *models.TodoItem
+		switch {
+		case models.SameTodo(l, r), models.SameTodo(b, r):
+			result = l
+		case models.SameTodo(b, l):
+			result = r
+		default:
+			decision := decisions[syncKey(dateKey, todo)]
+			if decision == nil || !models.SameTodo(decision.Local, l) || !models.SameTodo(decision.Remote, r) {
+				conflicts = append(conflicts, &SyncConflict{Month: dateKey, Base: b, Local: l, Remote: r})
+				continue
+			}
+			result = l
+			if decision.Choice == SyncKeepRemote {
+				result = r
+			}
+		}
+		if result != nil {
+			merged = append(merged, result)
+		}
+	}
+
+	sortTodos(merged)
+	sort.Slice(conflicts, func(i, j int) bool {
+		if !conflicts[i].Time().Equal(conflicts[j].Time()) {
+			return conflicts[i].Time().Before(conflicts[j].Time())
+		}
+		return conflicts[i].todo().Name < conflicts[j].todo().Name
+	})
+	if merged == nil {
+		merged = []*models.TodoItem{}
+	}
+	return merged, conflicts
+}
+
+// withConflicts returns merged plus the version side picks of each conflict
+func withConflicts(merged []*models.TodoItem, conflicts []*SyncConflict, side func(*SyncConflict) *models.TodoItem) []*models.TodoItem {
+	todos := append([]*models.TodoItem{}, merged...)
+	for _, conflict := range conflicts {
+		if todo := side(conflict); todo != nil {
+			todos = append(todos, todo)
+		}
+	}
+	sortTodos(todos)
+	return todos
+}
+
+// sortTodos sorts todos like the monthly files, latest first. Todos sharing
+// a time are ordered by name so every side writes them alike.
+func sortTodos(todos []*models.TodoItem) {
+	sort.Slice(todos, func(i, j int) bool {
+		if !todos[i].TodoTime.Equal(todos[j].TodoTime) {
+			return todos[i].TodoTime.After(todos[j].TodoTime)
+		}
+		return todos[i].Name < todos[j].Name
+	})
+}
+
+// todoKey identifies a todo within a month the way UpdateTodo does
+type todoKey struct {
+	time int64
+	name string
+}
+
+// indexTodos maps todos by the instant of their time and their name
+func indexTodos(todos []*models.TodoItem) map[todoKey]*models.TodoItem {
+	index := make(map[todoKey]*models.TodoItem, len(todos))
+	for _, todo := range todos {
+		index[todoKey{time: todo.TodoTime.UnixNano(), name: todo.Name}] = todo
+	}
+	return index
+}
+
+// countChanges returns how many todos were added, changed or removed going
+// from before to after
+func countChanges(before, after []*models.TodoItem) int {
+	beforeByKey, afterByKey := indexTodos(before), indexTodos(after)
+	changes := 0
+	for key, todo := range afterByKey {
+		if !models.SameTodo(beforeByKey[key], todo) {
+			changes++
+		}
+	}
+	for key := range beforeByKey {
+		if afterByKey[key] == nil {
+			changes++
+		}
+	}
+	return changes
+}
+
+// syncKey identifies a todo across sync runs
+func syncKey(dateKey string, todo *models.TodoItem) string {
+	if todo == nil {
+		return dateKey
+	}
+	return fmt.Sprintf("%s/%d/%s", dateKey, todo.TodoTime.UnixNano(), todo.Name)
+}
@@ -0,0 +1,216 @@
+package persistence
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"godo/src/models"
+	"godo/src/utils"
+)
+
+// todoCSVHeader names one column per TodoItem field, as in its JSON form
+var todoCSVHeader = []string{
+	"name", "content", "place", "label", "kind", "level", "todoTime", "done", "warnTime", "starred",
+	"order", "timeZone", "floating", "duration", "allDay", "estimate", "pomodoros",
+}
+
+// WriteTodoCSV writes one row per todo, earliest first. Times are RFC 3339
+// with nanoseconds, so every todo keeps its exact instant.
+func WriteTodoCSV(w io.Writer, todos []*models.TodoItem) error {
+	sorted := append([]*models.TodoItem(nil), todos...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].TodoTime.Before(sorted[j].TodoTime)
+	})
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(todoCSVHeader); err != nil {
+		return fmt.Errorf("failed to write todo CSV: %w", err)
+	}
+	for _, todo := range sorted {
+		row := []string{
+			todo.Name,
+			todo.Content,
+			todo.Place,
+			todo.Label,
+			strconv.Itoa(todo.Kind),
+			strconv.Itoa(todo.Level),
+			todo.TodoTime.Format(time.RFC3339Nano),
+			strconv.FormatBool(todo.Done),
+			strconv.Itoa(todo.WarnTime),
+			strconv.FormatBool(todo.Starred),
+			strconv.Itoa(todo.Order),
+			todo.TimeZone,
+			strconv.FormatBool(todo.Floating),
+			strconv.Itoa(todo.Duration),
+			strconv.FormatBool(todo.AllDay),
+			strconv.Itoa(todo.Estimate),
+			strconv.Itoa(todo.Pomodoros),
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write todo CSV: %w", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write todo CSV: %w", err)
+	}
+	return nil
+}
+
+// ReadTodoCSV reads todos written by WriteTodoCSV or a spreadsheet. The
+// header names the columns in any order; name and todoTime are required,
+// other columns may be left out. Rows with invalid values are returned as
+// skipped. A header with unknown columns fails the whole file.
+func ReadTodoCSV(r io.Reader) ([]*models.TodoItem, []*LineError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, nil, errors.New("failed to read todo CSV: the file is empty")
+		}
+		return nil, nil, fmt.Errorf("failed to read todo CSV: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// Spreadsheets may start the file with a byte order mark
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		known := false
+		for _, column := range todoCSVHeader {
+			known = known || column == name
+		}
+		if !known {
+			return nil, nil, fmt.Errorf("failed to read todo CSV: unknown column %q", name)
+		}
+		if _, dup := columns[name]; dup {
+			return nil, nil, fmt.Errorf("failed to read todo CSV: column %q appears twice", name)
+		}
+		columns[name] = i
+	}
+	for _, required := range []string{"name", "todoTime"} {
+		if _, ok := columns[required]; !ok {
+			return nil, nil, fmt.Errorf("failed to read todo CSV: column %q is required", required)
+		}
+	}
+
+	var todos []*models.TodoItem
+	var skipped []*LineError
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				skipped = append(skipped, &LineError{Line: parseErr.Line, Err: parseErr.Err})
+				continue
+			}
+			return nil, nil, fmt.Errorf("failed to read todo CSV: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		todo, err := parseTodoCSVRow(record, columns)
+		if err != nil {
+			skipped = append(skipped, &LineError{Line: line, Text: strings.Join(record, ","), Err: err})
+			continue
+		}
+		todos = append(todos, todo)
+	}
+	return todos, skipped, nil
+}
+
+// parseTodoCSVRow reads and validates one row
+func parseTodoCSVRow(record []string, columns map[string]int) (*models.TodoItem, error) {
+	if len(record) != len(columns) {
+		return nil, fmt.Errorf("%d fields, want %d", len(record), len(columns))
+	}
+	value := func(column string) string {
+		if i, ok := columns[column]; ok {
+			return record[i]
+		}
+		return ""
+	}
+	var problems []string
+	number := func(column string, max int) int {
+		text := strings.TrimSpace(value(column))
+		if text == "" {
+			return 0
+		}
+		n, err := strconv.Atoi(text)
+		if err != nil || n < 0 || (max > 0 && n > max) {
+			problems = append(problems, fmt.Sprintf("invalid %s %q", column, text))
+		}
+		return n
+	}
+	flag := func(column string) bool {
+		text := strings.TrimSpace(value(column))
+		if text == "" {
+			return false
+		}
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("invalid %s %q", column, text))
+		}
+		return b
+	}
+
+	todo := &models.TodoItem{
+		Name:      strings.TrimSpace(value("name")),
+		Content:   value("content"),
+		Place:     value("place"),
+		Label:     value("label"),
+		Kind:      number("kind", 1),
+		Level:     number("level", 3),
+		Done:      flag("done"),
+		WarnTime:  number("warnTime", 0),
+		Starred:   flag("starred"),
+		Order:     number("order", 0),
+		TimeZone:  strings.TrimSpace(value("timeZone")),
+		Floating:  flag("floating"),
+		Duration:  number("duration", 0),
+		AllDay:    flag("allDay"),
+		Estimate:  number("estimate", 0),
+		Pomodoros: number("pomodoros", 0),
+	}
+	if todo.Name == "" {
+		problems = append(problems, "name is required")
+	}
+	loc := time.Local
+	if todo.TimeZone != "" {
+		if loc = utils.LoadZone(todo.TimeZone); loc == nil {
+			problems = append(problems, fmt.Sprintf("unknown timeZone %q", todo.TimeZone))
+			loc = time.Local
+		}
+	}
+	todoTime, err := parseCSVTime(strings.TrimSpace(value("todoTime")), loc)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("invalid todoTime %q: want an RFC 3339 time or YYYY-MM-DD HH:MM", value("todoTime")))
+	}
+	todo.TodoTime = todoTime
+	if len(problems) > 0 {
+		return nil, errors.New(strings.Join(problems, "; "))
+	}
+	return todo, nil
+}
+
+// parseCSVTime reads an RFC 3339 time, or a date and time without an offset
+// as spreadsheets write them, which is read in loc
+func parseCSVTime(text string, loc *time.Location) (time.Time, error) {
+	if at, err := time.Parse(time.RFC3339Nano, text); err == nil {
+		return at, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02 15:04:05"} {
+		if at, err := time.ParseInLocation(layout, text, loc); err == nil {
+			return at, nil
+		}
+	}
+	return time.Time{}, errors.New("invalid time")
+}
@@ -0,0 +1,316 @@
+package persistence
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"godo/src/utils"
+)
+
+// Todoist exports a project as a CSV template with one row per task, section
+// or comment:
+//
+//	TYPE,CONTENT,DESCRIPTION,PRIORITY,INDENT,AUTHOR,RESPONSIBLE,DATE,DATE_LANG,TIMEZONE
+//	task,Buy milk @errands,,1,1,,,2025-11-07 18:00,en,Europe/Berlin
+//
+// PRIORITY 1 is the highest. Labels are @words in the content. The project
+// is not part of the file, so the file name is used. Backups made through
+// the Todoist API are JSON, with priority 4 the highest.
+
+// todoistDateLayouts are the dates Todoist writes besides ISO dates
+var todoistDateLayouts = []string{"Jan 2 2006", "2 Jan 2006", "Jan 2 2006 15:04", "2 Jan 2006 15:04", "Jan 2 2006 3:04 PM", "2 Jan 2006 3:04 PM"}
+
+// ReadTodoist reads a Todoist CSV template or JSON backup. project names the
+// project of a CSV template, usually its file name.
+func ReadTodoist(r io.Reader, project string) ([]*ForeignTask, []*LineError, error) {
+	reader := bufio.NewReader(r)
+	for {
+		b, err := reader.Peek(1)
+		if err != nil {
+			return nil, nil, errors.New("failed to read Todoist export: the file is empty")
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			reader.ReadByte()
+			continue
+		case '{', '[':
+			return readTodoistJSON(reader)
+		}
+		return readTodoistCSV(reader, project)
+	}
+}
+
+// readTodoistCSV reads a project exported as a CSV template
+func readTodoistCSV(r io.Reader, project string) ([]*ForeignTask, []*LineError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read Todoist CSV: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"TYPE", "CONTENT"} {
+		if _, ok := columns[required]; !ok {
+			return nil, nil, fmt.Errorf("failed to read Todoist CSV: column %s is required", required)
+		}
+	}
+
+	var tasks []*ForeignTask
+	var skipped []*LineError
+	var last *ForeignTask
+	section := ""
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				skipped = append(skipped, &LineError{Line: parseErr.Line, Err: parseErr.Err})
+				continue
+			}
+			return nil, nil, fmt.Errorf("failed to read Todoist CSV: %w", err)
+		}
+		value := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		line, _ := reader.FieldPos(0)
+		switch strings.ToLower(value("TYPE")) {
+		case "section":
+			section = value("CONTENT")
+		case "note":
+			// Comments belong to the task above
+			if last != nil {
+				last.addContent(value("CONTENT"))
+			}
+		case "task":
+			task, err := parseTodoistRow(value, project, section)
+			if err != nil {
+				skipped = append(skipped, &LineError{Line: line, Text: strings.Join(record, ","), Err: err})
+				last = nil
+				continue
+			}
+			tasks = append(tasks, task)
+			last = task
+		}
+	}
+	return tasks, skipped, nil
+}
+
+// parseTodoistRow reads the task row of a CSV template
+func parseTodoistRow(value func(string) string, project, section string) (*ForeignTask, error) {
+	var name []string
+	var labels []string
+	for _, word := range strings.Fields(value("CONTENT")) {
+		if len(word) > 1 && word[0] == '@' {
+			labels = append(labels, word[1:])
+		} else {
+			name = append(name, word)
+		}
+	}
+	task := newForeignTask(strings.Join(name, " "), project)
+	if task.Todo.Name == "" {
+		return nil, errors.New("the task has no name")
+	}
+
+	if text := value("PRIORITY"); text != "" {
+		priority, err := strconv.Atoi(text)
+		if err != nil || priority < 1 || priority > 4 {
+			return nil, fmt.Errorf("invalid priority %q: want 1 to 4", text)
+		}
+		task.Todo.Level = 4 - priority
+	}
+	if section != "" {
+		task.addContent("Section: " + section)
+	}
+	task.addContent(value("DESCRIPTION"))
+	task.setLabels(labels, false)
+
+	loc := time.Local
+	zone := value("TIMEZONE")
+	if zone != "" {
+		if loc = utils.LoadZone(zone); loc == nil {
+			return nil, fmt.Errorf("unknown time zone %q", zone)
+		}
+	}
+	if date := value("DATE"); date != "" {
+		if !setTodoistDate(task, date, loc) {
+			// Recurring and other natural language dates cannot be kept
+			task.addContent("Todoist date: " + date)
+		}
+	}
+	if task.Dated && !task.Todo.AllDay && strings.EqualFold(value("DURATION_UNIT"), "minute") {
+		if minutes, err := strconv.Atoi(value("DURATION")); err == nil && minutes > 0 {
+			task.Todo.SetDuration(minutes)
+		}
+	}
+	return task, nil
+}
+
+// setTodoistDate sets the due date of a CSV row and reports whether it could
+// be read
+func setTodoistDate(task *ForeignTask, date string, loc *time.Location) bool {
+	if at, allDay, err := parseForeignTime(date, loc); err == nil {
+		if allDay {
+			task.setDay(at)
+		} else {
+			task.setDue(at, loc)
+		}
+		return true
+	}
+	for _, layout := range todoistDateLayouts {
+		if at, err := time.ParseInLocation(layout, date, loc); err == nil {
+			if strings.Contains(layout, "15") || strings.Contains(layout, "3:04") {
+				task.setDue(at, loc)
+			} else {
+				task.setDay(at)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// todoistBackup is a JSON backup of the Todoist sync API. The REST API
+// returns just the list of tasks.
+type todoistBackup struct {
+	Items    []todoistItem `json:"items"`
+	Projects []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"projects"`
+	Reminders []struct {
+		ItemID       string `json:"item_id"`
+		Type         string `json:"type"`
+		MinuteOffset int    `json:"minute_offset"`
+		IsDeleted    bool   `json:"is_deleted"`
+	} `json:"reminders"`
+}
+
+type todoistItem struct {
+	ID          string   `json:"id"`
+	Content     string   `json:"content"`
+	Description string   `json:"description"`
+	ProjectID   string   `json:"project_id"`
+	Labels      []string `json:"labels"`
+	Priority    int      `json:"priority"`
+	Checked     bool     `json:"checked"`
+	IsCompleted bool     `json:"is_completed"`
+	IsDeleted   bool     `json:"is_deleted"`
+	Due         *struct {
+		Date     string `json:"date"`
+		Datetime string `json:"datetime"`
+		Timezone string `json:"timezone"`
+		String   string `json:"string"`
+	} `json:"due"`
+	Duration *struct {
+		Amount int    `json:"amount"`
+		Unit   string `json:"unit"`
+	} `json:"duration"`
+}
+
+// readTodoistJSON reads a backup of the sync API or the task list of the
+// REST API
+func readTodoistJSON(r io.Reader) ([]*ForeignTask, []*LineError, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read Todoist export: %w", err)
+	}
+	var backup todoistBackup
+	if len(data) > 0 && data[0] == '[' {
+		err = json.Unmarshal(data, &backup.Items)
+	} else {
+		err = json.Unmarshal(data, &backup)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read Todoist export: %w", err)
+	}
+
+	projects := make(map[string]string, len(backup.Projects))
+	for _, project := range backup.Projects {
+		projects[project.ID] = project.Name
+	}
+	warnTimes := make(map[string]int)
+	for _, reminder := range backup.Reminders {
+		if reminder.Type == "relative" && !reminder.IsDeleted {
+			if _, ok := warnTimes[reminder.ItemID]; !ok {
+				warnTimes[reminder.ItemID] = reminder.MinuteOffset
+			}
+		}
+	}
+
+	var tasks []*ForeignTask
+	var skipped []*LineError
+	for i, item := range backup.Items {
+		if item.IsDeleted {
+			continue
+		}
+		task := newForeignTask(item.Content, projects[item.ProjectID])
+		if task.Todo.Name == "" {
+			skipped = append(skipped, entryError(i, item.Content, errors.New("the task has no name")))
+			continue
+		}
+		if item.Priority >= 1 && item.Priority <= 4 {
+			task.Todo.Level = item.Priority - 1
+		}
+		task.Todo.Done = item.Checked || item.IsCompleted
+		task.Todo.WarnTime = warnTimes[item.ID]
+		task.addContent(item.Description)
+		task.setLabels(item.Labels, false)
+
+		if item.Due != nil {
+			if err := setTodoistDue(task, item.Due.Datetime, item.Due.Date, item.Due.Timezone); err != nil {
+				skipped = append(skipped, entryError(i, item.Content, err))
+				continue
+			}
+		}
+		if task.Dated && !task.Todo.AllDay && item.Duration != nil && item.Duration.Unit == "minute" && item.Duration.Amount > 0 {
+			task.Todo.SetDuration(item.Duration.Amount)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, skipped, nil
+}
+
+// setTodoistDue sets the due date of a JSON task. Times without an offset
+// are floating, so they are read as local time.
+func setTodoistDue(task *ForeignTask, datetime, date, zone string) error {
+	text := datetime
+	if text == "" {
+		text = date
+	}
+	if text == "" {
+		return nil
+	}
+	loc := time.Local
+	if zone != "" {
+		if loc = utils.LoadZone(zone); loc == nil {
+			return fmt.Errorf("unknown time zone %q", zone)
+		}
+	}
+	at, allDay, err := parseForeignTime(text, time.Local)
+	if err != nil {
+		return err
+	}
+	if allDay {
+		task.setDay(at)
+	} else {
+		task.setDue(at, loc)
+	}
+	return nil
+}
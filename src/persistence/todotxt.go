@@ -0,0 +1,277 @@
+package persistence
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"godo/src/models"
+)
+
+// todo.txt (http://todotxt.org) keeps one task per line:
+//
+//	x 2025-11-04 Write report +work @office due:2025-11-04T17:00 rem:15m pri:A
+//	(B) Call dentist @phone due:2025-11-05
+//
+// Level maps to the priorities A (urgent) to D (low). todo.txt drops the
+// priority of a completed task, so it moves to a pri: value as other todo.txt
+// tools do. Label and Place become the first +project and @context. The time
+// is a due: value, with the time of day unless the todo is all-day, and the
+// reminder a rem: value. Go Do fields todo.txt has no notion of are written as
+// extra values (kind:, star:, dur:, est:, pomo:, tz:) only when set; notes and
+// the order within a day are not kept.
+
+// ErrNoDueDate reports a todo.txt line without a due: value. Go Do files
+// todos by their time, so such lines cannot be read as todos.
+var ErrNoDueDate = errors.New("todo.txt line has no due date")
+
+const (
+	todoTxtDate     = "2006-01-02"
+	todoTxtTime     = "2006-01-02T15:04"
+	todoTxtSeconds  = "2006-01-02T15:04:05"
+	todoTxtPriority = "ABCD" // Index is 3 - Level
+)
+
+var (
+	todoTxtPriorityPattern = regexp.MustCompile(`^\(([A-Z])\)$`)
+	todoTxtDatePattern     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+)
+
+// FormatTodoTxt writes todo as a todo.txt line. A done todo is marked
+// completed today.
+func FormatTodoTxt(todo *models.TodoItem) string {
+	return formatTodoTxt(todo, time.Now())
+}
+
+func formatTodoTxt(todo *models.TodoItem, completed time.Time) string {
+	var parts []string
+	priority := ""
+	if todo.Level >= 0 && todo.Level <= 3 {
+		priority = string(todoTxtPriority[3-todo.Level])
+	}
+	if todo.Done {
+		parts = append(parts, "x", completed.Format(todoTxtDate))
+	} else if priority != "" {
+		parts = append(parts, "("+priority+")")
+	}
+
+	// A line break would start a new task
+	parts = append(parts, strings.Join(strings.Fields(todo.Name), " "))
+	if tag := todoTxtTag(todo.Label); tag != "" {
+		parts = append(parts, "+"+tag)
+	}
+	if tag := todoTxtTag(todo.Place); tag != "" {
+		parts = append(parts, "@"+tag)
+	}
+
+	parts = append(parts, "due:"+formatTodoTxtDue(todo))
+	if todo.WarnTime > 0 {
+		parts = append(parts, "rem:"+formatMinutes(todo.WarnTime))
+	}
+	if todo.Done && priority != "" {
+		parts = append(parts, "pri:"+priority)
+	}
+	if todo.Kind == 0 {
+		parts = append(parts, "kind:event")
+	}
+	if todo.Starred {
+		parts = append(parts, "star:yes")
+	}
+	if todo.Duration > 0 && !todo.AllDay {
+		parts = append(parts, "dur:"+formatMinutes(todo.Duration))
+	}
+	if todo.Estimate > 0 {
+		parts = append(parts, "est:"+strconv.Itoa(todo.Estimate))
+	}
+	if todo.Pomodoros > 0 {
+		parts = append(parts, "pomo:"+strconv.Itoa(todo.Pomodoros))
+	}
+	if todo.TimeZone != "" && !todo.Floating {
+		parts = append(parts, "tz:"+todo.TimeZone)
+	}
+	return strings.Join(parts, " ")
+}
+
+// todoTxtTag turns a label or place into a +project or @context name, which
+// cannot contain spaces
+func todoTxtTag(text string) string {
+	return strings.Join(strings.Fields(text), "-")
+}
+
+// formatTodoTxtDue writes the time as seen where the todo was scheduled
+func formatTodoTxtDue(todo *models.TodoItem) string {
+	due := wallClock(todo)
+	switch {
+	case todo.AllDay:
+		return due.Format(todoTxtDate)
+	case due.Second() != 0:
+		return due.Format(todoTxtSeconds)
+	default:
+		return due.Format(todoTxtTime)
+	}
+}
+
+// ParseTodoTxt reads a todo.txt line. Lines from other tools become tasks;
+// key:value pairs Go Do does not know stay part of the name. It returns
+// ErrNoDueDate for a line without a due: value.
+func ParseTodoTxt(line string) (*models.TodoItem, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil, errors.New("empty todo.txt line")
+	}
+
+	todo := models.NewTodoItem()
+	todo.Kind = 1
+	if fields[0] == "x" {
+		todo.Done = true
+		fields = fields[1:]
+		// Completion date, then the creation date
+		for i := 0; i < 2 && len(fields) > 0 && todoTxtDatePattern.MatchString(fields[0]); i++ {
+			fields = fields[1:]
+		}
+	} else {
+		if len(fields) > 0 {
+			if match := todoTxtPriorityPattern.FindStringSubmatch(fields[0]); match != nil {
+				todo.Level = todoTxtLevel(match[1])
+				fields = fields[1:]
+			}
+		}
+		if len(fields) > 0 && todoTxtDatePattern.MatchString(fields[0]) {
+			fields = fields[1:]
+		}
+	}
+
+	var name []string
+	var due, zone string
+	for _, field := range fields {
+		if strings.HasPrefix(field, "+") && len(field) > 1 && todo.Label == "" {
+			todo.Label = field[1:]
+			continue
+		}
+		if strings.HasPrefix(field, "@") && len(field) > 1 && todo.Place == "" {
+			todo.Place = field[1:]
+			continue
+		}
+
+		key, value, found := strings.Cut(field, ":")
+		if !found || value == "" {
+			name = append(name, field)
+			continue
+		}
+		var err error
+		switch key {
+		case "due":
+			due = value
+		case "rem":
+			todo.WarnTime, err = parseMinutes(value)
+		case "pri":
+			if len(value) != 1 || value[0] < 'A' || value[0] > 'Z' {
+				err = errors.New("not a priority")
+			}
+			todo.Level = todoTxtLevel(value)
+		case "kind":
+			if value == "event" {
+				todo.Kind = 0
+			}
+		case "star":
+			todo.Starred = value == "yes"
+		case "dur":
+			todo.Duration, err = parseMinutes(value)
+		case "est":
+			todo.Estimate, err = strconv.Atoi(value)
+		case "pomo":
+			todo.Pomodoros, err = strconv.Atoi(value)
+		case "tz":
+			zone = value
+		default:
+			name = append(name, field)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %q", key, value)
+		}
+	}
+
+	if due == "" {
+		return nil, ErrNoDueDate
+	}
+	if err := setTodoTxtDue(todo, due, zone); err != nil {
+		return nil, err
+	}
+	todo.Name = strings.Join(name, " ")
+	if todo.Name == "" {
+		return nil, errors.New("todo.txt line has no description")
+	}
+	return todo, nil
+}
+
+// todoTxtLevel maps a priority letter to a Level; E to Z count as low
+func todoTxtLevel(priority string) int {
+	if index := strings.Index(todoTxtPriority, priority); index >= 0 {
+		return 3 - index
+	}
+	return 0
+}
+
+// setTodoTxtDue sets the time of todo from a due: value, read in zone when
+// given and in the local zone otherwise. A date alone makes an all-day todo.
+func setTodoTxtDue(todo *models.TodoItem, due, zone string) error {
+	if day, err := time.Parse(todoTxtDate, due); err == nil {
+		return setWallClock(todo, day, true, zone)
+	}
+	for _, layout := range []string{todoTxtTime, todoTxtSeconds} {
+		if at, err := time.Parse(layout, due); err == nil {
+			return setWallClock(todo, at, false, zone)
+		}
+	}
+	return fmt.Errorf("invalid due date %q", due)
+}
+
+// ReadTodoTxt reads every todo in a todo.txt file. Lines that cannot be read
+// as todos are returned as skipped; blank lines are ignored.
+func ReadTodoTxt(r io.Reader) ([]*models.TodoItem, []*LineError, error) {
+	var todos []*models.TodoItem
+	var skipped []*LineError
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for number := 1; scanner.Scan(); number++ {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		todo, err := ParseTodoTxt(line)
+		if err != nil {
+			skipped = append(skipped, &LineError{Line: number, Text: line, Err: err})
+			continue
+		}
+		todos = append(todos, todo)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read todo.txt: %w", err)
+	}
+	return todos, skipped, nil
+}
+
+// WriteTodoTxt writes todos as a todo.txt file, earliest first
+func WriteTodoTxt(w io.Writer, todos []*models.TodoItem) error {
+	sorted := append([]*models.TodoItem(nil), todos...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].TodoTime.Before(sorted[j].TodoTime)
+	})
+
+	writer := bufio.NewWriter(w)
+	for _, todo := range sorted {
+		if _, err := writer.WriteString(FormatTodoTxt(todo) + "\n"); err != nil {
+			return fmt.Errorf("failed to write todo.txt: %w", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write todo.txt: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,393 @@
+package persistence
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"godo/src/models"
+	"godo/src/utils"
+)
+
+// todoTxtEntry is one line of the backing todo.txt file
+type todoTxtEntry struct {
+	line string           // Text as last read or written
+	todo *models.TodoItem // Todo on the line; nil for lines kept as they are
+	read *models.TodoItem // Copy of todo as parsed from line
+}
+
+// fileStamp identifies a version of a file on disk
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// TodoTxtRepository stores todos in a single todo.txt file instead of month
+// files, so other todo.txt tools can edit them too. The file is read again
+// whenever it changed on disk. Lines that are not Go Do todos, such as tasks
+// without a due date, are kept as they are, and lines of todos that did not
+// change are written back untouched so their creation dates and unknown
+// values survive. Like MonthlyManager it is not safe for concurrent use,
+// except for Watch.
+type TodoTxtRepository struct {
+	path    string
+	entries []*todoTxtEntry
+	loaded  bool
+
+	mu     sync.Mutex
+	stamp  fileStamp // Version of the file the entries match
+	events *TodoEvents
+}
+
+// NewTodoTxtRepository creates a repository backed by the todo.txt file at
+// path, which is created on the first save
+func NewTodoTxtRepository(path string) *TodoTxtRepository {
+	return &TodoTxtRepository{path: path, events: NewTodoEvents()}
+}
+
+// Events returns the bus the repository publishes its changes on
+func (r *TodoTxtRepository) Events() *TodoEvents {
+	return r.events
+}
+
+// GetPath returns the path of the backing file
+func (r *TodoTxtRepository) GetPath() string {
+	return r.path
+}
+
+// statFile returns the current version of the file; a missing file has the
+// zero stamp
+func (r *TodoTxtRepository) statFile() (fileStamp, error) {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fileStamp{}, nil
+		}
+		return fileStamp{}, fmt.Errorf("failed to read %s: %w", r.path, err)
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}, nil
+}
+
+// load reads the file unless the entries already match it
+func (r *TodoTxtRepository) load() error {
+	stamp, err := r.statFile()
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	current := r.loaded && stamp == r.stamp
+	r.mu.Unlock()
+	if current {
+		return nil
+	}
+
+	data, err := os.ReadFile(r.path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", r.path, err)
+	}
+	var entries []*todoTxtEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		entry := &todoTxtEntry{line: scanner.Text()}
+		if todo, err := ParseTodoTxt(entry.line); err == nil {
+			entry.todo = todo
+			copied := *todo
+			entry.read = &copied
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", r.path, err)
+	}
+
+	r.entries = entries
+	r.loaded = true
+	r.setStamp(stamp)
+	return nil
+}
+
+// save writes the entries back, formatting only the todos that changed
+func (r *TodoTxtRepository) save() error {
+	var b strings.Builder
+	for _, entry := range r.entries {
+		if entry.todo != nil && !models.SameTodo(entry.todo, entry.read) {
+			entry.line = FormatTodoTxt(entry.todo)
+			copied := *entry.todo
+			entry.read = &copied
+		}
+		b.WriteString(entry.line)
+		b.WriteString("\n")
+	}
+
+	if err := writeFileAtomic(r.path, []byte(b.String())); err != nil {
+		return fmt.Errorf("failed to save todos: %w", err)
+	}
+	stamp, err := r.statFile()
+	if err != nil {
+		return err
+	}
+	r.setStamp(stamp)
+	return nil
+}
+
+func (r *TodoTxtRepository) setStamp(stamp fileStamp) {
+	r.mu.Lock()
+	r.stamp = stamp
+	r.mu.Unlock()
+}
+
+// Watch publishes a TodosReloaded event and calls onChanged, unless nil, on
+// its own goroutine whenever another program changed the file, checking every
+// interval. The next read picks up the change. Call the returned function to
+// stop watching.
+func (r *TodoTxtRepository) Watch(interval time.Duration, onChanged func()) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var reported fileStamp
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			stamp, err := r.statFile()
+			if err != nil {
+				continue
+			}
+			r.mu.Lock()
+			changed := stamp != r.stamp
+			r.mu.Unlock()
+			// Report each change once, even before it is read
+			if changed && stamp != reported {
+				reported = stamp
+				r.events.Publish(newTodoEvent(TodosReloaded, nil))
+				if onChanged != nil {
+					onChanged()
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+// todos returns the todos matching keep, latest first like MonthlyManager
+func (r *TodoTxtRepository) todos(keep func(*models.TodoItem) bool) ([]*models.TodoItem, error) {
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	var todos []*models.TodoItem
+	for _, entry := range r.entries {
+		if entry.todo != nil && keep(entry.todo) {
+			todos = append(todos, entry.todo)
+		}
+	}
+	sort.SliceStable(todos, func(i, j int) bool {
+		return todos[i].TodoTime.After(todos[j].TodoTime)
+	})
+	return todos, nil
+}
+
+// GetTodosForMonth returns the todos scheduled in a month
+func (r *TodoTxtRepository) GetTodosForMonth(year, month int) ([]*models.TodoItem, error) {
+	return r.todos(func(todo *models.TodoItem) bool {
+		y, m := todoMonth(todo)
+		return y == year && m == month
+	})
+}
+
+// GetTodosForDay returns the todos that fall on the calendar day of day, as
+// seen in day's location
+func (r *TodoTxtRepository) GetTodosForDay(day time.Time) ([]*models.TodoItem, error) {
+	return r.todos(func(todo *models.TodoItem) bool {
+		return todo.OccursOn(day, day.Location())
+	})
+}
+
+// SaveTodosForMonth replaces the todos of a month with todos. Todos that keep
+// their time keep their line in the file; new ones are added at the end.
+func (r *TodoTxtRepository) SaveTodosForMonth(year, month int, todos []*models.TodoItem) error {
+	if err := r.load(); err != nil {
+		return err
+	}
+
+	pending := make(map[int64]*models.TodoItem, len(todos))
+	for _, todo := range todos {
+		pending[todo.TodoTime.UnixNano()] = todo
+	}
+	entries := r.entries[:0]
+	for _, entry := range r.entries {
+		if entry.todo != nil {
+			if y, m := todoMonth(entry.todo); y == year && m == month {
+				todo, ok := pending[entry.todo.TodoTime.UnixNano()]
+				if !ok {
+					continue
+				}
+				delete(pending, entry.todo.TodoTime.UnixNano())
+				entry.todo = todo
+			}
+		}
+		entries = append(entries, entry)
+	}
+	for _, todo := range todos {
+		if _, ok := pending[todo.TodoTime.UnixNano()]; ok {
+			entries = append(entries, &todoTxtEntry{todo: todo})
+		}
+	}
+	r.entries = entries
+	if err := r.save(); err != nil {
+		return err
+	}
+	r.events.Publish(newTodoEvent(TodosSaved, todos, utils.FormatDateKey(year, month)))
+	return nil
+}
+
+// AddTodo adds a todo at the end of the file
+func (r *TodoTxtRepository) AddTodo(todo *models.TodoItem) error {
+	if err := r.load(); err != nil {
+		return err
+	}
+	r.entries = append(r.entries, &todoTxtEntry{todo: todo})
+	if err := r.save(); err != nil {
+		return err
+	}
+	r.events.Publish(newTodoEvent(TodosAdded, []*models.TodoItem{todo}))
+	return nil
+}
+
+// UpdateTodo replaces the todo of the same name stored at originalTime
+func (r *TodoTxtRepository) UpdateTodo(todo *models.TodoItem, originalTime time.Time) error {
+	if err := r.load(); err != nil {
+		return err
+	}
+	for _, entry := range r.entries {
+		if entry.todo != nil && entry.todo.TodoTime.Equal(originalTime) && entry.todo.Name == todo.Name {
+			original := utils.FormatDateKey(todoMonth(entry.todo))
+			entry.todo = todo
+			if err := r.save(); err != nil {
+				return err
+			}
+			r.events.Publish(newTodoEvent(TodosUpdated, []*models.TodoItem{todo}, original))
+			return nil
+		}
+	}
+	return fmt.Errorf("todo item not found for update")
+}
+
+// RemoveTodo removes the todo stored at todoTime
+func (r *TodoTxtRepository) RemoveTodo(todoTime time.Time) error {
+	return r.RemoveTodos([]time.Time{todoTime})
+}
+
+// RemoveTodos removes the todos stored at todoTimes
+func (r *TodoTxtRepository) RemoveTodos(todoTimes []time.Time) error {
+	if err := r.load(); err != nil {
+		return err
+	}
+	remove := make(map[int64]bool, len(todoTimes))
+	for _, todoTime := range todoTimes {
+		remove[todoTime.UnixNano()] = true
+	}
+	var removed []*models.TodoItem
+	entries := r.entries[:0]
+	for _, entry := range r.entries {
+		if entry.todo != nil && remove[entry.todo.TodoTime.UnixNano()] {
+			// Only the first todo at a time is removed, as in MonthlyManager
+			delete(remove, entry.todo.TodoTime.UnixNano())
+			removed = append(removed, entry.todo)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	r.entries = entries
+	if err := r.save(); err != nil {
+		return err
+	}
+	if len(removed) > 0 {
+		r.events.Publish(newTodoEvent(TodosRemoved, removed))
+	}
+	return nil
+}
+
+// GetTodoByTime finds the todo stored at todoTime
+func (r *TodoTxtRepository) GetTodoByTime(todoTime time.Time) (*models.TodoItem, error) {
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	for _, entry := range r.entries {
+		if entry.todo != nil && entry.todo.TodoTime.Equal(todoTime) {
+			return entry.todo, nil
+		}
+	}
+	return nil, fmt.Errorf("todo item not found")
+}
+
+// GetAllMonths returns the months that have todos, as YYYYMM keys
+func (r *TodoTxtRepository) GetAllMonths() ([]string, error) {
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	months := []string{}
+	for _, entry := range r.entries {
+		if entry.todo == nil {
+			continue
+		}
+		dateKey := utils.FormatDateKey(todoMonth(entry.todo))
+		if !seen[dateKey] {
+			seen[dateKey] = true
+			months = append(months, dateKey)
+		}
+	}
+	sort.Strings(months)
+	return months, nil
+}
+
+// ClearCache makes the next read load the file again
+func (r *TodoTxtRepository) ClearCache() {
+	r.loaded = false
+}
+
+// RepairMonth has nothing to repair: lines that cannot be read are kept as
+// they are rather than failing the whole file
+func (r *TodoTxtRepository) RepairMonth(year, month int) (*RepairReport, error) {
+	return &RepairReport{Path: r.path}, nil
+}
+
+// MigrateAll has nothing to migrate; the todo.txt format has no versions
+func (r *TodoTxtRepository) MigrateAll() error {
+	return nil
+}
+
+// errTodoTxtIsDirectory is returned by CheckTodoTxtFile for a folder
+var errTodoTxtIsDirectory = errors.New("the todo.txt path is a folder")
+
+// CheckTodoTxtFile reports whether path can serve as a todo.txt file: it is
+// a file, or does not exist yet in an existing folder
+func CheckTodoTxtFile(path string) error {
+	info, err := os.Stat(path)
+	if err == nil {
+		if info.IsDir() {
+			return errTodoTxtIsDirectory
+		}
+		return nil
+	}
+	if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to check %s: %w", path, err)
+	}
+	if info, err := os.Stat(filepath.Dir(path)); err != nil || !info.IsDir() {
+		return fmt.Errorf("the folder of %s does not exist", path)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package persistence
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Trello exports a board as JSON with its lists, labels, cards and
+// checklists. Cards become tasks of the board; their list, description and
+// checklists go into the content. Trello has no priorities, so they are taken
+// from labels such as "High priority". Archived cards and lists are left out.
+
+type trelloBoard struct {
+	Name  string `json:"name"`
+	Lists []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Closed bool   `json:"closed"`
+	} `json:"lists"`
+	Labels []struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	} `json:"labels"`
+	Cards []struct {
+		ID          string   `json:"id"`
+		Name        string   `json:"name"`
+		Desc        string   `json:"desc"`
+		IDList      string   `json:"idList"`
+		IDLabels    []string `json:"idLabels"`
+		Due         string   `json:"due"`
+		DueComplete bool     `json:"dueComplete"`
+		DueReminder *int     `json:"dueReminder"` // Minutes before due, -1 for none
+		Closed      bool     `json:"closed"`
+	} `json:"cards"`
+	Checklists []struct {
+		IDCard     string `json:"idCard"`
+		Name       string `json:"name"`
+		CheckItems []struct {
+			Name  string `json:"name"`
+			State string `json:"state"`
+		} `json:"checkItems"`
+	} `json:"checklists"`
+}
+
+// ReadTrello reads the JSON export of a Trello board
+func ReadTrello(r io.Reader) ([]*ForeignTask, []*LineError, error) {
+	var board trelloBoard
+	if err := json.NewDecoder(r).Decode(&board); err != nil {
+		return nil, nil, fmt.Errorf("failed to read Trello export: %w", err)
+	}
+	if board.Cards == nil {
+		return nil, nil, errors.New("failed to read Trello export: not a board export")
+	}
+
+	lists := make(map[string]string, len(board.Lists))
+	closedLists := make(map[string]bool)
+	for _, list := range board.Lists {
+		lists[list.ID] = list.Name
+		closedLists[list.ID] = list.Closed
+	}
+	labels := make(map[string]string, len(board.Labels))
+	for _, label := range board.Labels {
+		if label.Name != "" {
+			labels[label.ID] = label.Name
+		} else {
+			labels[label.ID] = label.Color
+		}
+	}
+	checklists := make(map[string][]string)
+	for _, checklist := range board.Checklists {
+		lines := []string{checklist.Name + ":"}
+		for _, item := range checklist.CheckItems {
+			box := "[ ]"
+			if item.State == "complete" {
+				box = "[x]"
+			}
+			lines = append(lines, "- "+box+" "+item.Name)
+		}
+		checklists[checklist.IDCard] = append(checklists[checklist.IDCard], strings.Join(lines, "\n"))
+	}
+
+	var tasks []*ForeignTask
+	var skipped []*LineError
+	for i, card := range board.Cards {
+		if card.Closed || closedLists[card.IDList] {
+			continue
+		}
+		task := newForeignTask(card.Name, board.Name)
+		if task.Todo.Name == "" {
+			skipped = append(skipped, entryError(i, card.Name, errors.New("the card has no name")))
+			continue
+		}
+		task.Todo.Done = card.DueComplete
+		if list := lists[card.IDList]; list != "" {
+			task.addContent("List: " + list)
+		}
+		task.addContent(card.Desc)
+		for _, checklist := range checklists[card.ID] {
+			task.addContent(checklist)
+		}
+		var cardLabels []string
+		for _, id := range card.IDLabels {
+			cardLabels = append(cardLabels, labels[id])
+		}
+		task.setLabels(cardLabels, true)
+
+		if card.Due != "" {
+			at, err := time.Parse(time.RFC3339Nano, card.Due)
+			if err != nil {
+				skipped = append(skipped, entryError(i, card.Name, fmt.Errorf("invalid due date %q", card.Due)))
+				continue
+			}
+			task.setDue(at, nil)
+			if card.DueReminder != nil && *card.DueReminder > 0 {
+				task.Todo.WarnTime = *card.DueReminder
+			}
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, skipped, nil
+}
@@ -0,0 +1,766 @@
+package services
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"godo/src/models"
+	"godo/src/persistence"
+)
+
+// apiSpec is the OpenAPI description served at /api/openapi.yaml
+//
+//go:embed openapi.yaml
+var apiSpec []byte
+
+const (
+	apiMaxBody   = 1 << 20 // Largest request body accepted
+	apiMaxRange  = 366     // Most days one list request may span
+	apiDayFormat = "2006-01-02"
+)
+
+// APIServer serves the todo repository and the Pomodoro timer as JSON on
+// localhost, so editors, launchers and scripts can drive the running app.
+// Every request runs through the dispatch function, which the UI sets to run
+// on its thread: the repository cache is shared with the windows.
+type APIServer struct {
+	repo     persistence.TodoRepository
+	pomodoro *PomodoroService
+	exporter *ExportService
+
+	mu        sync.Mutex
+	token     string
+	server    *http.Server
+	addr      string
+	dispatch  func(func())
+	onChanged func()
+}
+
+// NewAPIServer creates a stopped server; see Configure
+func NewAPIServer(repo persistence.TodoRepository, pomodoro *PomodoroService) *APIServer {
+	return &APIServer{
+		repo:     repo,
+		pomodoro: pomodoro,
+		exporter: NewExportService(repo),
+		dispatch: func(fn func()) { fn() },
+	}
+}
+
+// NewAPIToken creates a random bearer token for the API
+func NewAPIToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate API token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// SetDispatch sets how requests reach the repository; fn must run its
+// argument before returning
+func (s *APIServer) SetDispatch(fn func(func())) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dispatch = fn
+}
+
+// SetOnChanged registers fn to be called, through dispatch, after a request
+// changed todos or the timer
+func (s *APIServer) SetOnChanged(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChanged = fn
+}
+
+// Configure stops a running server and starts it again on 127.0.0.1 if the
+// API is enabled
+func (s *APIServer) Configure(config models.APIConfig) error {
+	if err := s.Stop(); err != nil {
+		return err
+	}
+	if !config.Enabled {
+		return nil
+	}
+	if config.Token == "" {
+		return fmt.Errorf("failed to start API: no token configured")
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", config.GetPort()))
+	if err != nil {
+		return fmt.Errorf("failed to start API: %w", err)
+	}
+	server := &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 5 * time.Second}
+
+	s.mu.Lock()
+	s.token = config.Token
+	s.server = server
+	s.addr = listener.Addr().String()
+	s.mu.Unlock()
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Printf("Failed to serve API: %v\n", err)
+		}
+	}()
+	return nil
+}
+
+// Stop shuts the server down if it is running
+func (s *APIServer) Stop() error {
+	s.mu.Lock()
+	server := s.server
+	s.server = nil
+	s.addr = ""
+	s.mu.Unlock()
+
+	if server == nil {
+		return nil
+	}
+	if err := server.Close(); err != nil {
+		return fmt.Errorf("failed to stop API: %w", err)
+	}
+	return nil
+}
+
+// Addr returns the address the server listens on, or "" when stopped
+func (s *APIServer) Addr() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addr
+}
+
+// SetToken sets the bearer token; Configure sets it from the config
+func (s *APIServer) SetToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+}
+
+// Handler returns the API's routes
+func (s *APIServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/openapi.yaml", s.handleSpec)
+	mux.HandleFunc("/api/todos", s.authorized(s.handleTodos))
+	mux.HandleFunc("/api/todos/", s.authorized(s.handleTodo))
+	mux.HandleFunc("/api/export", s.authorized(s.handleExport))
+	mux.HandleFunc("/api/import", s.authorized(s.handleImport))
+	mux.HandleFunc("/api/pomodoro", s.authorized(s.handlePomodoro))
+	mux.HandleFunc("/api/pomodoro/", s.authorized(s.handlePomodoroAction))
+	return mux
+}
+
+// apiError is an error with the HTTP status to report it with
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func errorf(status int, format string, args ...interface{}) error {
+	return &apiError{status: status, message: fmt.Sprintf(format, args...)}
+}
+
+// apiTodo is a todo as the API shows it, with its id
+type apiTodo struct {
+	ID string `json:"id"`
+	*models.TodoItem
+}
+
+// todoID returns the id of a todo: its time, which the repository keys on
+func todoID(todo *models.TodoItem) string {
+	return todo.TodoTime.Format(time.RFC3339Nano)
+}
+
+func newAPITodo(todo *models.TodoItem) apiTodo {
+	return apiTodo{ID: todoID(todo), TodoItem: todo}
+}
+
+// apiPomodoroStatus is the timer as the API shows it
+type apiPomodoroStatus struct {
+	State     models.PomodoroState `json:"state"`
+	Interval  models.PomodoroState `json:"interval"`        // Running, paused or next interval
+	Remaining float64              `json:"remaining"`       // Seconds left; 0 when open-ended
+	Elapsed   float64              `json:"elapsed"`         // Seconds into the interval
+	OpenEnded bool                 `json:"openEnded"`       // Flowtime work without a set end
+	Sessions  int                  `json:"sessions"`        // Work sessions finished this cycle
+	Task      *models.PomodoroTask `json:"task,omitempty"`  // Todo being worked on
+	Step      int                  `json:"step,omitempty"`  // Position in a custom sequence
+	Steps     int                  `json:"steps,omitempty"` // Length of a custom sequence
+}
+
+// authorized requires the bearer token, then reads the body and calls handle.
+// The body is read here so no request waits on the network inside dispatch.
+func (s *APIServer) authorized(handle func(http.ResponseWriter, *http.Request, []byte) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		token := s.token
+		s.mu.Unlock()
+
+		given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="godo"`)
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "missing or wrong bearer token"})
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, apiMaxBody))
+		if err == nil {
+			err = handle(w, r, body)
+		} else {
+			err = errorf(http.StatusRequestEntityTooLarge, "failed to read request body: %v", err)
+		}
+		if err != nil {
+			status := http.StatusInternalServerError
+			var apiErr *apiError
+			if errors.As(err, &apiErr) {
+				status = apiErr.status
+			}
+			writeJSON(w, status, map[string]string{"error": err.Error()})
+		}
+	}
+}
+
+// run calls fn through dispatch and reports changes made by it
+func (s *APIServer) run(fn func() (changed bool, err error)) error {
+	s.mu.Lock()
+	dispatch, onChanged := s.dispatch, s.onChanged
+	s.mu.Unlock()
+
+	var err error
+	dispatch(func() {
+		var changed bool
+		changed, err = fn()
+		if changed && onChanged != nil {
+			onChanged()
+		}
+	})
+	return err
+}
+
+func (s *APIServer) handleSpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Write(apiSpec)
+}
+
+// handleTodos lists todos (GET) or adds one (POST)
+func (s *APIServer) handleTodos(w http.ResponseWriter, r *http.Request, body []byte) error {
+	switch r.Method {
+	case http.MethodGet:
+		days, err := listDays(r)
+		if err != nil {
+			return err
+		}
+		todos := []apiTodo{}
+		err = s.run(func() (bool, error) {
+			for _, day := range days {
+				dayTodos, err := s.repo.GetTodosForDay(day)
+				if err != nil {
+					return false, err
+				}
+				models.SortTodosByOrder(dayTodos)
+				for _, todo := range dayTodos {
+					copied := *todo
+					todos = append(todos, newAPITodo(&copied))
+				}
+			}
+			return false, nil
+		})
+		if err != nil {
+			return err
+		}
+		return writeJSON(w, http.StatusOK, todos)
+
+	case http.MethodPost:
+		todo := models.NewTodoItem()
+		if err := decodeBody(body, todo, false); err != nil {
+			return err
+		}
+		if err := validateTodo(todo); err != nil {
+			return err
+		}
+		err := s.run(func() (bool, error) {
+			if _, err := s.repo.GetTodoByTime(todo.TodoTime); err == nil {
+				return false, errorf(http.StatusConflict, "a todo already exists at %s", todoID(todo))
+			}
+			return true, s.repo.AddTodo(todo)
+		})
+		if err != nil {
+			return err
+		}
+		return writeJSON(w, http.StatusCreated, newAPITodo(todo))
+
+	default:
+		return errorf(http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	}
+}
+
+// handleTodo serves /api/todos/{id} and its actions done, star and reorder
+func (s *APIServer) handleTodo(w http.ResponseWriter, r *http.Request, body []byte) error {
+	id, action := strings.TrimPrefix(r.URL.Path, "/api/todos/"), ""
+	if slash := strings.IndexByte(id, '/'); slash >= 0 {
+		id, action = id[:slash], id[slash+1:]
+	}
+	at, err := time.Parse(time.RFC3339Nano, id)
+	if err != nil {
+		return errorf(http.StatusBadRequest, "invalid todo id %q: want an RFC 3339 time", id)
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		var todo models.TodoItem
+		err := s.run(func() (bool, error) {
+			found, err := s.findTodo(at)
+			if err == nil {
+				todo = *found
+			}
+			return false, err
+		})
+		if err != nil {
+			return err
+		}
+		return writeJSON(w, http.StatusOK, newAPITodo(&todo))
+
+	case action == "" && (r.Method == http.MethodPut || r.Method == http.MethodPatch):
+		// PUT replaces the todo, PATCH changes only the fields sent
+		return s.updateTodo(w, at, func(todo *models.TodoItem) error {
+			if r.Method == http.MethodPut {
+				*todo = *models.NewTodoItem()
+			}
+			if err := decodeBody(body, todo, false); err != nil {
+				return err
+			}
+			return validateTodo(todo)
+		})
+
+	case action == "" && r.Method == http.MethodDelete:
+		err := s.run(func() (bool, error) {
+			if _, err := s.findTodo(at); err != nil {
+				return false, err
+			}
+			return true, s.repo.RemoveTodo(at)
+		})
+		if err != nil {
+			return err
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+
+	case action == "done" && r.Method == http.MethodPost:
+		request := struct {
+			Done *bool `json:"done"`
+		}{}
+		if err := decodeBody(body, &request, true); err != nil {
+			return err
+		}
+		return s.updateTodo(w, at, func(todo *models.TodoItem) error {
+			todo.MarkAsDone(request.Done == nil || *request.Done)
+			return nil
+		})
+
+	case action == "star" && r.Method == http.MethodPost:
+		request := struct {
+			Starred *bool `json:"starred"`
+		}{}
+		if err := decodeBody(body, &request, true); err != nil {
+			return err
+		}
+		return s.updateTodo(w, at, func(todo *models.TodoItem) error {
+			todo.Starred = request.Starred == nil || *request.Starred
+			return nil
+		})
+
+	case action == "reorder" && r.Method == http.MethodPost:
+		return s.reorderTodo(w, at, body)
+
+	case action == "" || action == "done" || action == "star" || action == "reorder":
+		return errorf(http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+
+	default:
+		return errorf(http.StatusNotFound, "unknown action %q", action)
+	}
+}
+
+// findTodo returns the stored todo at the given time
+func (s *APIServer) findTodo(at time.Time) (*models.TodoItem, error) {
+	todo, err := s.repo.GetTodoByTime(at)
+	if err != nil {
+		var corrupt *persistence.CorruptFileError
+		if errors.As(err, &corrupt) {
+			return nil, err
+		}
+		return nil, errorf(http.StatusNotFound, "no todo at %s", at.Format(time.RFC3339Nano))
+	}
+	return todo, nil
+}
+
+// updateTodo applies change to a copy of the todo at the given time and
+// stores it
+func (s *APIServer) updateTodo(w http.ResponseWriter, at time.Time, change func(*models.TodoItem) error) error {
+	var updated models.TodoItem
+	err := s.run(func() (bool, error) {
+		original, err := s.findTodo(at)
+		if err != nil {
+			return false, err
+		}
+		updated = *original
+		if err := change(&updated); err != nil {
+			return false, err
+		}
+
+		if !updated.TodoTime.Equal(original.TodoTime) {
+			if _, err := s.repo.GetTodoByTime(updated.TodoTime); err == nil {
+				return false, errorf(http.StatusConflict, "a todo already exists at %s", todoID(&updated))
+			}
+		}
+		stored := updated
+		if updated.Name == original.Name {
+			return true, s.repo.UpdateTodo(&stored, original.TodoTime)
+		}
+		// The repository finds the todo to update by time and name, so a
+		// renamed todo is replaced instead
+		if err := s.repo.RemoveTodo(original.TodoTime); err != nil {
+			return false, err
+		}
+		return true, s.repo.AddTodo(&stored)
+	})
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, newAPITodo(&updated))
+}
+
+// reorderTodo moves a todo to another position among the todos of its day
+func (s *APIServer) reorderTodo(w http.ResponseWriter, at time.Time, body []byte) error {
+	request := struct {
+		Position *int `json:"position"`
+	}{}
+	if err := decodeBody(body, &request, false); err != nil {
+		return err
+	}
+	if request.Position == nil {
+		return errorf(http.StatusBadRequest, "position is required")
+	}
+
+	todos := []apiTodo{}
+	err := s.run(func() (bool, error) {
+		todo, err := s.findTodo(at)
+		if err != nil {
+			return false, err
+		}
+		shown := todo.DisplayTime(time.Local)
+		day := time.Date(shown.Year(), shown.Month(), shown.Day(), 0, 0, 0, 0, time.Local)
+		dayTodos, err := s.repo.GetTodosForDay(day)
+		if err != nil {
+			return false, err
+		}
+		models.SortTodosByOrder(dayTodos)
+		for i, t := range dayTodos {
+			if t == todo {
+				models.MoveTodo(dayTodos, i, *request.Position)
+				break
+			}
+		}
+
+		// Save every month file the day's todos are stored in
+		saved := make(map[int]bool)
+		for _, t := range dayTodos {
+			zoned := t.ZonedTime()
+			year, month := zoned.Year(), int(zoned.Month())
+			if saved[year*100+month] {
+				continue
+			}
+			saved[year*100+month] = true
+			monthTodos, err := s.repo.GetTodosForMonth(year, month)
+			if err != nil {
+				return true, err
+			}
+			if err := s.repo.SaveTodosForMonth(year, month, monthTodos); err != nil {
+				return true, err
+			}
+		}
+
+		for _, t := range dayTodos {
+			copied := *t
+			todos = append(todos, newAPITodo(&copied))
+		}
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, todos)
+}
+
+// apiImportReport is the result of an import as the API shows it
+type apiImportReport struct {
+	Added    int              `json:"added"`
+	Existing int              `json:"existing"` // Left out because their time was taken
+	Skipped  []apiImportError `json:"skipped"`  // Entries that could not be read
+}
+
+// apiImportError is an entry an import could not read
+type apiImportError struct {
+	Line    int    `json:"line"`
+	Text    string `json:"text,omitempty"`
+	Message string `json:"message"`
+}
+
+// handleExport writes the todos of ?day=, ?month= or ?from=&to=, or every
+// todo without a range, in ?format=
+func (s *APIServer) handleExport(w http.ResponseWriter, r *http.Request, _ []byte) error {
+	if r.Method != http.MethodGet {
+		return errorf(http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	}
+	format, err := ParseExportFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		return errorf(http.StatusBadRequest, "%v", err)
+	}
+	from, to, err := requestRange(r)
+	if err != nil {
+		return err
+	}
+	exportRange := ExportRange{From: from, To: to}
+
+	var todos []*models.TodoItem
+	err = s.run(func() (bool, error) {
+		var err error
+		todos, err = s.exporter.Todos(exportRange)
+		return false, err
+	})
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := WriteTodos(&buf, format, todos); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Write(buf.Bytes())
+	return nil
+}
+
+// handleImport adds the todos in the body, read in ?format=, and reports the
+// entries that could not be read
+func (s *APIServer) handleImport(w http.ResponseWriter, r *http.Request, body []byte) error {
+	if r.Method != http.MethodPost {
+		return errorf(http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	}
+	format, err := ParseExportFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		return errorf(http.StatusBadRequest, "%v", err)
+	}
+	todos, skipped, err := ReadTodos(bytes.NewReader(body), format)
+	if err != nil {
+		return errorf(http.StatusBadRequest, "%v", err)
+	}
+
+	report := apiImportReport{Skipped: []apiImportError{}}
+	for _, entry := range skipped {
+		report.Skipped = append(report.Skipped, apiImportError{Line: entry.Line, Text: entry.Text, Message: entry.Err.Error()})
+	}
+	err = s.run(func() (bool, error) {
+		var err error
+		report.Added, report.Existing, err = s.exporter.Add(todos)
+		return report.Added > 0, err
+	})
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, report)
+}
+
+// handlePomodoro returns the timer status
+func (s *APIServer) handlePomodoro(w http.ResponseWriter, r *http.Request, _ []byte) error {
+	if r.Method != http.MethodGet {
+		return errorf(http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	}
+	return writeJSON(w, http.StatusOK, s.pomodoroStatus())
+}
+
+// handlePomodoroAction serves POST /api/pomodoro/{start,pause,resume,skip,reset}
+func (s *APIServer) handlePomodoroAction(w http.ResponseWriter, r *http.Request, body []byte) error {
+	if r.Method != http.MethodPost {
+		return errorf(http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	}
+	timer := s.pomodoro.Timer()
+
+	var action func() error
+	switch strings.TrimPrefix(r.URL.Path, "/api/pomodoro/") {
+	case "start":
+		// Starts the timer, on the given todo if there is one
+		request := struct {
+			Todo string `json:"todo"`
+		}{}
+		if err := decodeBody(body, &request, true); err != nil {
+			return err
+		}
+		action = func() error {
+			if request.Todo == "" {
+				timer.Start()
+				return nil
+			}
+			at, err := time.Parse(time.RFC3339Nano, request.Todo)
+			if err != nil {
+				return errorf(http.StatusBadRequest, "invalid todo id %q: want an RFC 3339 time", request.Todo)
+			}
+			todo, err := s.findTodo(at)
+			if err != nil {
+				return err
+			}
+			s.pomodoro.StartTask(todo)
+			return nil
+		}
+	case "pause":
+		action = func() error { timer.Pause(); return nil }
+	case "resume":
+		action = func() error { timer.Resume(); return nil }
+	case "skip":
+		// Ends a break, or open-ended work, early
+		action = func() error {
+			if timer.Snapshot().OpenEnded {
+				timer.FinishWork()
+			} else {
+				timer.SkipBreak()
+			}
+			return nil
+		}
+	case "reset":
+		action = func() error { timer.Reset(); return nil }
+	default:
+		return errorf(http.StatusNotFound, "unknown Pomodoro action %q", strings.TrimPrefix(r.URL.Path, "/api/pomodoro/"))
+	}
+
+	if err := s.run(func() (bool, error) { return true, action() }); err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, s.pomodoroStatus())
+}
+
+// pomodoroStatus describes the timer and its task
+func (s *APIServer) pomodoroStatus() apiPomodoroStatus {
+	status := s.pomodoro.Timer().Snapshot()
+	result := apiPomodoroStatus{
+		State:     status.State,
+		Interval:  status.Interval(),
+		Elapsed:   status.Elapsed.Seconds(),
+		OpenEnded: status.OpenEnded,
+		Sessions:  status.Sessions,
+		Task:      s.pomodoro.Task(),
+		Step:      status.Step,
+		Steps:     status.Steps,
+	}
+	if !status.OpenEnded {
+		result.Remaining = status.Remaining.Seconds()
+	}
+	return result
+}
+
+// listDays returns the days a list request asks for: ?day=, ?month=, or
+// ?from=&to= (inclusive); today without parameters
+func listDays(r *http.Request) ([]time.Time, error) {
+	from, to, err := requestRange(r)
+	if err != nil {
+		return nil, err
+	}
+	if from.IsZero() {
+		now := time.Now()
+		from = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+		to = from
+	}
+
+	var days []time.Time
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if len(days) == apiMaxRange {
+			return nil, errorf(http.StatusBadRequest, "range too long: at most %d days", apiMaxRange)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// requestRange returns the first and last day given by ?day=, ?month=, or
+// ?from=&to=; zero times without parameters
+func requestRange(r *http.Request) (time.Time, time.Time, error) {
+	query := r.URL.Query()
+	parseDay := func(name string) (time.Time, error) {
+		day, err := time.ParseInLocation(apiDayFormat, query.Get(name), time.Local)
+		if err != nil {
+			return time.Time{}, errorf(http.StatusBadRequest, "invalid %s %q: want YYYY-MM-DD", name, query.Get(name))
+		}
+		return day, nil
+	}
+
+	var from, to time.Time
+	var err error
+	switch {
+	case query.Get("day") != "":
+		if from, err = parseDay("day"); err != nil {
+			return from, to, err
+		}
+		to = from
+	case query.Get("month") != "":
+		month, err := time.ParseInLocation("2006-01", query.Get("month"), time.Local)
+		if err != nil {
+			return from, to, errorf(http.StatusBadRequest, "invalid month %q: want YYYY-MM", query.Get("month"))
+		}
+		from, to = month, month.AddDate(0, 1, -1)
+	case query.Get("from") != "" || query.Get("to") != "":
+		if from, err = parseDay("from"); err != nil {
+			return from, to, err
+		}
+		if to, err = parseDay("to"); err != nil {
+			return from, to, err
+		}
+		if from.After(to) {
+			return from, to, errorf(http.StatusBadRequest, "from is after to")
+		}
+	}
+	return from, to, nil
+}
+
+// validateTodo rejects todos the repository cannot store
+func validateTodo(todo *models.TodoItem) error {
+	if strings.TrimSpace(todo.Name) == "" {
+		return errorf(http.StatusBadRequest, "name is required")
+	}
+	if todo.TodoTime.IsZero() {
+		return errorf(http.StatusBadRequest, "todoTime is required")
+	}
+	return nil
+}
+
+// decodeBody reads a JSON body into v; an empty body is accepted if optional
+func decodeBody(body []byte, v interface{}, optional bool) error {
+	if optional && len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return errorf(http.StatusBadRequest, "invalid request body: %v", err)
+	}
+	return nil
+}
+
+// writeJSON writes v with the given status
+func writeJSON(w http.ResponseWriter, status int, v interface{}) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
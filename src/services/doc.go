@@ -0,0 +1,33 @@
+/*
+Package services contains long-lived application services that outlive any
+single window of the Go Do application.
+
+Pomodoro (pomodoro.go):
+  - PomodoroService: Owns the Pomodoro timer, keeps it running while its
+    window is closed and persists its state so a session survives restarts
+
+Local API (api.go, openapi.yaml):
+  - APIServer: Opt-in REST API on 127.0.0.1 for scripts and other apps,
+    authenticated with a bearer token and described by an OpenAPI document
+
+Export (export.go):
+  - ExportService: Exports a day, a month, a date range or every todo as
+    Markdown, CSV or todo.txt and imports such files, reporting the lines
+    that could not be read
+
+Import from other tools (foreign.go):
+  - Reads Todoist, Trello and Google Tasks exports and previews the todos they
+    map to, leaving out tasks imported before
+
+Hooks (hooks.go, hooked_repository.go, reminders.go):
+  - Hooks: Runs the executables of the hooks folder in the data directory on
+    todo, reminder and Pomodoro events, passing a JSON payload on stdin
+  - HookedRepository: Wraps the todo repository so every change fires hooks
+  - ReminderService: Notices when the reminder of an open todo comes due
+
+Priority matrix (matrix.go):
+  - MatrixService: Sorts the open todos of a day, its week or all of them into
+    the Eisenhower matrix, moves them between quadrants and quick-adds
+    unscheduled tasks
+*/
+package services
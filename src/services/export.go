@@ -0,0 +1,233 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"godo/src/models"
+	"godo/src/persistence"
+	"godo/src/utils"
+)
+
+// ExportFormat names a file format todos are exported to and imported from
+type ExportFormat string
+
+const (
+	FormatMarkdown ExportFormat = "markdown" // GitHub task lists under a heading per day
+	FormatCSV      ExportFormat = "csv"      // One column per TodoItem field
+	FormatTodoTxt  ExportFormat = "todotxt"  // One todo.txt line per todo
+)
+
+// ExportFormats lists the supported formats
+var ExportFormats = []ExportFormat{FormatMarkdown, FormatCSV, FormatTodoTxt}
+
+// ParseExportFormat returns the format with the given name
+func ParseExportFormat(name string) (ExportFormat, error) {
+	for _, format := range ExportFormats {
+		if string(format) == strings.ToLower(name) {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unknown format %q: want markdown, csv or todotxt", name)
+}
+
+// Extension returns the usual file extension of the format
+func (f ExportFormat) Extension() string {
+	switch f {
+	case FormatMarkdown:
+		return ".md"
+	case FormatCSV:
+		return ".csv"
+	default:
+		return ".txt"
+	}
+}
+
+// ContentType returns the MIME type of the format
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case FormatMarkdown:
+		return "text/markdown; charset=utf-8"
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// ExportRange selects todos by the local calendar days they fall on, From to
+// To inclusive. The zero range selects every todo.
+type ExportRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// DayRange selects the todos of one day
+func DayRange(day time.Time) ExportRange {
+	day = localDay(day)
+	return ExportRange{From: day, To: day}
+}
+
+// MonthRange selects the todos of one month
+func MonthRange(year, month int) ExportRange {
+	first := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+	return ExportRange{From: first, To: first.AddDate(0, 1, -1)}
+}
+
+// DateRange selects the todos from one day to another, both included
+func DateRange(from, to time.Time) (ExportRange, error) {
+	from, to = localDay(from), localDay(to)
+	if from.After(to) {
+		return ExportRange{}, fmt.Errorf("invalid range: %s is after %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	}
+	return ExportRange{From: from, To: to}, nil
+}
+
+// IsAll reports whether the range selects every todo
+func (r ExportRange) IsAll() bool {
+	return r.From.IsZero() && r.To.IsZero()
+}
+
+// Contains reports whether todo falls on a day of the range
+func (r ExportRange) Contains(todo *models.TodoItem) bool {
+	if r.IsAll() {
+		return true
+	}
+	day := localDay(todo.DisplayTime(time.Local))
+	return !day.Before(r.From) && !day.After(r.To)
+}
+
+// localDay returns midnight of the local calendar day of t
+func localDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// WriteTodos writes todos in the given format
+func WriteTodos(w io.Writer, format ExportFormat, todos []*models.TodoItem) error {
+	switch format {
+	case FormatMarkdown:
+		return persistence.WriteTodoMarkdown(w, todos)
+	case FormatCSV:
+		return persistence.WriteTodoCSV(w, todos)
+	case FormatTodoTxt:
+		return persistence.WriteTodoTxt(w, todos)
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
+
+// ReadTodos reads todos in the given format. Entries that cannot be read are
+// returned as skipped with their line; an error means the input as a whole
+// could not be read.
+func ReadTodos(r io.Reader, format ExportFormat) ([]*models.TodoItem, []*persistence.LineError, error) {
+	switch format {
+	case FormatMarkdown:
+		return persistence.ReadTodoMarkdown(r)
+	case FormatCSV:
+		return persistence.ReadTodoCSV(r)
+	case FormatTodoTxt:
+		return persistence.ReadTodoTxt(r)
+	}
+	return nil, nil, fmt.Errorf("unknown format %q", format)
+}
+
+// ImportReport describes the result of an import
+type ImportReport struct {
+	Added    int                      // Todos added
+	Existing int                      // Todos left out because their time was taken
+	Skipped  []*persistence.LineError // Entries that could not be read
+}
+
+// ExportService exports the todos of a repository and imports todos into it.
+// Like the repository it is not safe for concurrent use; Todos returns copies
+// that may be written anywhere.
+type ExportService struct {
+	repo persistence.TodoRepository
+}
+
+// NewExportService creates a service over repo
+func NewExportService(repo persistence.TodoRepository) *ExportService {
+	return &ExportService{repo: repo}
+}
+
+// Todos returns copies of the todos in r, earliest first
+func (s *ExportService) Todos(r ExportRange) ([]*models.TodoItem, error) {
+	var months []string
+	if r.IsAll() {
+		var err error
+		if months, err = s.repo.GetAllMonths(); err != nil {
+			return nil, err
+		}
+	} else {
+		// Todos are filed by the zone they were scheduled in, which can be a
+		// month off the local day at either end
+		first := time.Date(r.From.Year(), r.From.Month(), 1, 0, 0, 0, 0, time.Local).AddDate(0, -1, 0)
+		for month := first; !month.After(r.To.AddDate(0, 1, 0)); month = month.AddDate(0, 1, 0) {
+			months = append(months, utils.FormatDateKey(month.Year(), int(month.Month())))
+		}
+	}
+
+	var todos []*models.TodoItem
+	for _, dateKey := range months {
+		year, month := utils.ParseDateKey(dateKey)
+		if year == 0 {
+			continue
+		}
+		monthTodos, err := s.repo.GetTodosForMonth(year, month)
+		if err != nil {
+			return nil, err
+		}
+		for _, todo := range monthTodos {
+			if r.Contains(todo) {
+				copied := *todo
+				todos = append(todos, &copied)
+			}
+		}
+	}
+	sort.SliceStable(todos, func(i, j int) bool {
+		return todos[i].TodoTime.Before(todos[j].TodoTime)
+	})
+	return todos, nil
+}
+
+// Export writes the todos in r in the given format and returns their number
+func (s *ExportService) Export(w io.Writer, format ExportFormat, r ExportRange) (int, error) {
+	todos, err := s.Todos(r)
+	if err != nil {
+		return 0, err
+	}
+	if err := WriteTodos(w, format, todos); err != nil {
+		return 0, err
+	}
+	return len(todos), nil
+}
+
+// Add adds todos, leaving out those at a time already taken, so importing a
+// file twice adds nothing
+func (s *ExportService) Add(todos []*models.TodoItem) (added, existing int, err error) {
+	for _, todo := range todos {
+		if _, err := s.repo.GetTodoByTime(todo.TodoTime); err == nil {
+			existing++
+			continue
+		}
+		if err := s.repo.AddTodo(todo); err != nil {
+			return added, existing, fmt.Errorf("failed to import %q: %w", todo.Name, err)
+		}
+		added++
+	}
+	return added, existing, nil
+}
+
+// Import reads todos in the given format and adds them. The readable todos
+// are added even when some entries are skipped.
+func (s *ExportService) Import(r io.Reader, format ExportFormat) (*ImportReport, error) {
+	todos, skipped, err := ReadTodos(r, format)
+	if err != nil {
+		return nil, err
+	}
+	report := &ImportReport{Skipped: skipped}
+	report.Added, report.Existing, err = s.Add(todos)
+	return report, err
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"godo/src/models"
+	"godo/src/persistence"
+)
+
+// ForeignFormat names the export format of another task tool todos are
+// imported from
+type ForeignFormat string
+
+const (
+	FormatTodoist     ForeignFormat = "todoist"     // Todoist CSV template or JSON backup
+	FormatTrello      ForeignFormat = "trello"      // Trello board JSON
+	FormatGoogleTasks ForeignFormat = "googletasks" // Google Takeout Tasks JSON
+)
+
+// ForeignFormats lists the supported tools
+var ForeignFormats = []ForeignFormat{FormatTodoist, FormatTrello, FormatGoogleTasks}
+
+// ParseForeignFormat returns the format with the given name
+func ParseForeignFormat(name string) (ForeignFormat, error) {
+	for _, format := range ForeignFormats {
+		if string(format) == strings.ToLower(name) {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unknown format %q: want todoist, trello or googletasks", name)
+}
+
+// Extensions returns the file extensions the tool exports to
+func (f ForeignFormat) Extensions() []string {
+	if f == FormatTodoist {
+		return []string{".csv", ".json"}
+	}
+	return []string{".json"}
+}
+
+// ReadForeign reads the export of another tool. fileName is used as the
+// project of exports that do not name it.
+func ReadForeign(r io.Reader, format ForeignFormat, fileName string) ([]*persistence.ForeignTask, []*persistence.LineError, error) {
+	project := strings.TrimSuffix(filepath.Base(fileName), filepath.Ext(fileName))
+	if fileName == "" {
+		project = ""
+	}
+	switch format {
+	case FormatTodoist:
+		return persistence.ReadTodoist(r, project)
+	case FormatTrello:
+		return persistence.ReadTrello(r)
+	case FormatGoogleTasks:
+		return persistence.ReadGoogleTasks(r, project)
+	}
+	return nil, nil, fmt.Errorf("unknown format %q", format)
+}
+
+// PreviewTask is a task as it will be imported
+type PreviewTask struct {
+	*persistence.ForeignTask
+	Duplicate bool // Imported before, so it is left out
+}
+
+// ImportPreview shows how the tasks of another tool map onto todos before
+// they are added
+type ImportPreview struct {
+	Tasks   []*PreviewTask
+	Skipped []*persistence.LineError // Entries that could not be read
+}
+
+// New returns the number of tasks that will be added
+func (p *ImportPreview) New() int {
+	n := 0
+	for _, task := range p.Tasks {
+		if !task.Duplicate {
+			n++
+		}
+	}
+	return n
+}
+
+// Preview places tasks without a due date on undatedDay as all-day todos and
+// marks the tasks imported before. A task counts as imported when a todo of
+// the same name and label exists on its day, or on any day for an undated
+// task, so importing a newer export of the same tool adds only new tasks.
+// Todos are keyed by time, so tasks at a time already taken move on by a
+// second.
+func (s *ExportService) Preview(tasks []*persistence.ForeignTask, skipped []*persistence.LineError, undatedDay time.Time) (*ImportPreview, error) {
+	existing, err := s.Todos(ExportRange{})
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	taken := make(map[time.Time]bool)
+	for _, todo := range existing {
+		seen[foreignKey(todo, true)] = true
+		seen[foreignKey(todo, false)] = true
+		taken[todo.TodoTime.UTC()] = true
+	}
+
+	preview := &ImportPreview{Skipped: skipped}
+	for _, task := range tasks {
+		if !task.Dated {
+			task.Todo.SetAllDay(undatedDay)
+		}
+		key := foreignKey(task.Todo, task.Dated)
+		item := &PreviewTask{ForeignTask: task, Duplicate: seen[key]}
+		preview.Tasks = append(preview.Tasks, item)
+		if item.Duplicate {
+			continue
+		}
+		// A task listed twice in the export is imported once
+		seen[key] = true
+		for taken[task.Todo.TodoTime.UTC()] {
+			task.Todo.TodoTime = task.Todo.TodoTime.Add(time.Second)
+		}
+		taken[task.Todo.TodoTime.UTC()] = true
+	}
+	return preview, nil
+}
+
+// foreignKey identifies a todo by name and label, and by its day when dated
+func foreignKey(todo *models.TodoItem, dated bool) string {
+	key := strings.ToLower(strings.TrimSpace(todo.Name)) + "\x00" + strings.ToLower(todo.Label)
+	if dated {
+		key += "\x00" + todo.DisplayTime(time.Local).Format("2006-01-02")
+	}
+	return key
+}
+
+// AddPreview adds the tasks of preview that were not imported before
+func (s *ExportService) AddPreview(preview *ImportPreview) (*ImportReport, error) {
+	report := &ImportReport{Skipped: preview.Skipped}
+	var todos []*models.TodoItem
+	for _, task := range preview.Tasks {
+		if task.Duplicate {
+			report.Existing++
+		} else {
+			todos = append(todos, task.Todo)
+		}
+	}
+	added, existing, err := s.Add(todos)
+	report.Added = added
+	report.Existing += existing
+	return report, err
+}
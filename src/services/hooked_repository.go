@@ -0,0 +1,149 @@
+package services
+
+import (
+	"time"
+
+	"godo/src/models"
+	"godo/src/persistence"
+)
+
+// HookedRepository runs the todo hooks after each successful change to the
+// repository it wraps, whoever makes it: the windows, the local API or a sync
+type HookedRepository struct {
+	persistence.TodoRepository
+	hooks *Hooks
+}
+
+// NewHookedRepository wraps repo so its changes fire hooks
+func NewHookedRepository(repo persistence.TodoRepository, hooks *Hooks) *HookedRepository {
+	return &HookedRepository{TodoRepository: repo, hooks: hooks}
+}
+
+// AddTodo adds todo and fires todo-added
+func (r *HookedRepository) AddTodo(todo *models.TodoItem) error {
+	if err := r.TodoRepository.AddTodo(todo); err != nil {
+		return err
+	}
+	r.hooks.FireTodo(HookTodoAdded, todo, nil)
+	return nil
+}
+
+// UpdateTodo updates todo and fires todo-updated, and todo-completed when it
+// was marked done
+func (r *HookedRepository) UpdateTodo(todo *models.TodoItem, originalTime time.Time) error {
+	previous := r.find(originalTime)
+	if err := r.TodoRepository.UpdateTodo(todo, originalTime); err != nil {
+		return err
+	}
+	r.fireUpdated(previous, todo)
+	return nil
+}
+
+// RemoveTodo removes the todo at todoTime and fires todo-deleted
+func (r *HookedRepository) RemoveTodo(todoTime time.Time) error {
+	previous := r.find(todoTime)
+	if err := r.TodoRepository.RemoveTodo(todoTime); err != nil {
+		return err
+	}
+	if previous != nil {
+		r.hooks.FireTodo(HookTodoDeleted, previous, nil)
+	}
+	return nil
+}
+
+// RemoveTodos removes the todos at todoTimes and fires todo-deleted for each
+func (r *HookedRepository) RemoveTodos(todoTimes []time.Time) error {
+	var removed []*models.TodoItem
+	for _, todoTime := range todoTimes {
+		if previous := r.find(todoTime); previous != nil {
+			removed = append(removed, previous)
+		}
+	}
+	if err := r.TodoRepository.RemoveTodos(todoTimes); err != nil {
+		return err
+	}
+	for _, previous := range removed {
+		r.hooks.FireTodo(HookTodoDeleted, previous, nil)
+	}
+	return nil
+}
+
+// SaveTodosForMonth replaces a month and fires the hooks of the todos that
+// were added, changed or removed. Todos changed in place in the repository's
+// cache before the call are not seen as changed.
+func (r *HookedRepository) SaveTodosForMonth(year, month int, todos []*models.TodoItem) error {
+	before, err := r.TodoRepository.GetTodosForMonth(year, month)
+	if err != nil {
+		before = nil
+	}
+	old := make(map[hookKey]*models.TodoItem, len(before))
+	for _, todo := range before {
+		old[keyOf(todo)] = copyTodo(todo)
+	}
+
+	if err := r.TodoRepository.SaveTodosForMonth(year, month, todos); err != nil {
+		return err
+	}
+
+	for _, todo := range todos {
+		key := keyOf(todo)
+		previous, ok := old[key]
+		delete(old, key)
+		switch {
+		case !ok:
+			r.hooks.FireTodo(HookTodoAdded, todo, nil)
+		case !models.SameTodo(previous, todo):
+			r.fireUpdated(previous, todo)
+		}
+	}
+	for _, todo := range before {
+		if previous, ok := old[keyOf(todo)]; ok {
+			r.hooks.FireTodo(HookTodoDeleted, previous, nil)
+		}
+	}
+	return nil
+}
+
+// Watch forwards to the wrapped repository when other programs may edit its
+// files; otherwise there is nothing to watch
+func (r *HookedRepository) Watch(interval time.Duration, onChanged func()) (stop func()) {
+	if watched, ok := r.TodoRepository.(persistence.WatchedRepository); ok {
+		return watched.Watch(interval, onChanged)
+	}
+	return func() {}
+}
+
+func (r *HookedRepository) fireUpdated(previous, todo *models.TodoItem) {
+	r.hooks.FireTodo(HookTodoUpdated, todo, previous)
+	if todo.Done && (previous == nil || !previous.Done) {
+		r.hooks.FireTodo(HookTodoCompleted, todo, previous)
+	}
+}
+
+// find returns a copy of the todo at todoTime, or nil
+func (r *HookedRepository) find(todoTime time.Time) *models.TodoItem {
+	todo, err := r.TodoRepository.GetTodoByTime(todoTime)
+	if err != nil || todo == nil {
+		return nil
+	}
+	return copyTodo(todo)
+}
+
+// hookKey identifies a todo within a month the way UpdateTodo does
+type hookKey struct {
+	time int64
+	name string
+}
+
+func keyOf(todo *models.TodoItem) hookKey {
+	return hookKey{time: todo.TodoTime.UnixNano(), name: todo.Name}
+}
+
+// copyTodo keeps a todo as it was before a change, as the repository may
+// update its cached items in place
+func copyTodo(todo *models.TodoItem) *models.TodoItem {
+	c := *todo
+	return &c
+}
+
+var _ persistence.WatchedRepository = (*HookedRepository)(nil)
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"godo/src/models"
+)
+
+// HookEvent names an event hooks can run on. A hook runs on an event when
+// its file name up to the first dot is the event name, such as
+// todo-added.sh or todo-completed.py.
+type HookEvent string
+
+const (
+	HookTodoAdded            HookEvent = "todo-added"
+	HookTodoUpdated          HookEvent = "todo-updated"
+	HookTodoCompleted        HookEvent = "todo-completed"
+	HookTodoDeleted          HookEvent = "todo-deleted"
+	HookReminder             HookEvent = "reminder"
+	HookPomodoroWorkStarted  HookEvent = "pomodoro-work-started"
+	HookPomodoroWorkFinished HookEvent = "pomodoro-work-finished"
+)
+
+const (
+	// DefaultHookTimeout is how long a hook may run before it is killed
+	DefaultHookTimeout = 10 * time.Second
+	// hookQueueSize is how many events may wait for hooks before new ones
+	// are dropped
+	hookQueueSize = 64
+	// hookOutputShown limits the output of a failed hook written to the log
+	hookOutputShown = 500
+)
+
+// HookPayload is the JSON document a hook reads from stdin
+type HookPayload struct {
+	Event    HookEvent        `json:"event"`
+	Time     time.Time        `json:"time"`
+	Todo     *models.TodoItem `json:"todo,omitempty"`
+	Previous *models.TodoItem `json:"previous,omitempty"` // The todo before an update
+	Pomodoro *HookPomodoro    `json:"pomodoro,omitempty"`
+}
+
+// HookPomodoro describes the work session of a Pomodoro event
+type HookPomodoro struct {
+	Sessions int                  `json:"sessions"` // Completed work sessions
+	Minutes  int                  `json:"minutes"`  // Planned length of the session
+	Task     *models.PomodoroTask `json:"task,omitempty"`
+}
+
+// Hooks runs the executables of a folder on application events. Hooks run
+// one after another on a background goroutine so a slow script never blocks
+// the caller; each receives its payload as JSON on stdin and is killed after
+// the timeout. Failures are logged.
+type Hooks struct {
+	dir   string
+	clock models.Clock
+
+	mu      sync.Mutex
+	timeout time.Duration
+	stopped bool
+	queue   chan hookJob
+	done    chan struct{}
+}
+
+type hookJob struct {
+	event   HookEvent
+	payload []byte
+}
+
+// NewHooks creates a runner for the hooks in dir. The folder does not have
+// to exist; hooks added later are found on the next event.
+func NewHooks(dir string) *Hooks {
+	h := &Hooks{
+		dir:     dir,
+		clock:   models.SystemClock,
+		timeout: DefaultHookTimeout,
+		queue:   make(chan hookJob, hookQueueSize),
+		done:    make(chan struct{}),
+	}
+	go h.work()
+	return h
+}
+
+// Dir returns the folder hooks are read from
+func (h *Hooks) Dir() string {
+	return h.dir
+}
+
+// SetTimeout changes how long a hook may run
+func (h *Hooks) SetTimeout(timeout time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.timeout = timeout
+}
+
+// Fire queues the hooks of payload.Event. It returns at once; when too many
+// events are waiting the event is dropped and logged.
+func (h *Hooks) Fire(payload HookPayload) {
+	if payload.Time.IsZero() {
+		payload.Time = h.clock.Now()
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		fmt.Printf("Failed to encode %s hook payload: %v\n", payload.Event, err)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.stopped {
+		return
+	}
+	select {
+	case h.queue <- hookJob{event: payload.Event, payload: data}:
+	default:
+		fmt.Printf("Failed to run %s hooks: too many events waiting\n", payload.Event)
+	}
+}
+
+// FireTodo queues the hooks of event for todo
+func (h *Hooks) FireTodo(event HookEvent, todo, previous *models.TodoItem) {
+	h.Fire(HookPayload{Event: event, Todo: todo, Previous: previous})
+}
+
+// WatchPomodoro fires the Pomodoro hooks on transitions of the service's
+// timer and returns a function that stops doing so
+func (h *Hooks) WatchPomodoro(pomodoro *PomodoroService) func() {
+	return pomodoro.Timer().Subscribe(func(event models.PomodoroEvent) {
+		var hook HookEvent
+		switch event.Type {
+		case models.EventWorkStarted:
+			hook = HookPomodoroWorkStarted
+		case models.EventWorkFinished:
+			hook = HookPomodoroWorkFinished
+		default:
+			return
+		}
+		h.Fire(HookPayload{
+			Event: hook,
+			Time:  event.At,
+			Pomodoro: &HookPomodoro{
+				Sessions: event.Sessions,
+				Minutes:  int(event.Duration / time.Minute),
+				Task:     pomodoro.Task(),
+			},
+		})
+	})
+}
+
+// Stop waits for the queued hooks to finish. Events fired afterwards are
+// ignored.
+func (h *Hooks) Stop() {
+	h.mu.Lock()
+	if !h.stopped {
+		h.stopped = true
+		close(h.queue)
+	}
+	h.mu.Unlock()
+	<-h.done
+}
+
+func (h *Hooks) work() {
+	defer close(h.done)
+	for job := range h.queue {
+		for _, path := range h.find(job.event) {
+			h.run(path, job)
+		}
+	}
+}
+
+// find returns the executables of the hooks folder named after event
+func (h *Hooks) find(event HookEvent) []string {
+	entries, err := os.ReadDir(h.dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			fmt.Printf("Failed to read hooks folder: %v\n", err)
+		}
+		return nil
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.SplitN(name, ".", 2)[0] != string(event) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !isExecutable(name, info.Mode()) {
+			continue
+		}
+		paths = append(paths, filepath.Join(h.dir, name))
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// run executes one hook and logs its failure
+func (h *Hooks) run(path string, job hookJob) {
+	h.mu.Lock()
+	timeout := h.timeout
+	h.mu.Unlock()
+
+	cmd := exec.Command(path)
+	cmd.Dir = h.dir
+	cmd.Env = append(os.Environ(), "GODO_EVENT="+string(job.event))
+	cmd.Stdin = bytes.NewReader(job.payload)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	startHookGroup(cmd)
+
+	if err := cmd.Start(); err != nil {
+		fmt.Printf("Failed to run hook %s: %v\n", filepath.Base(path), err)
+		return
+	}
+	finished := make(chan error, 1)
+	go func() { finished <- cmd.Wait() }()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case err := <-finished:
+		if err != nil {
+			text := strings.TrimSpace(output.String())
+			if len(text) > hookOutputShown {
+				text = text[:hookOutputShown] + "..."
+			}
+			fmt.Printf("Failed to run hook %s: %v\n%s\n", filepath.Base(path), err, text)
+		}
+	case <-timer.C:
+		if err := killHook(cmd); err != nil {
+			fmt.Printf("Failed to stop hook %s: %v\n", filepath.Base(path), err)
+		}
+		<-finished
+		fmt.Printf("Failed to run hook %s: timed out after %v\n", filepath.Base(path), timeout)
+	}
+}
+
+// isExecutable reports whether a file of the hooks folder can be run
+func isExecutable(name string, mode os.FileMode) bool {
+	if runtime.GOOS == "windows" {
+		switch strings.ToLower(filepath.Ext(name)) {
+		case ".exe", ".bat", ".cmd", ".com":
+			return true
+		}
+		return false
+	}
+	return mode&0111 != 0
+}
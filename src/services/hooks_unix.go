@@ -0,0 +1,20 @@
+//go:build !windows
+// +build !windows
+
+package services
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// startHookGroup runs the hook in its own process group so a timeout also
+// ends the programs it started, which would otherwise keep its output open
+func startHookGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killHook ends the hook and the programs it started
+func killHook(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
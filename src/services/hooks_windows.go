@@ -0,0 +1,14 @@
+//go:build windows
+// +build windows
+
+package services
+
+import "os/exec"
+
+// startHookGroup leaves the hook in the application's process group
+func startHookGroup(cmd *exec.Cmd) {}
+
+// killHook ends the hook
+func killHook(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
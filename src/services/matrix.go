@@ -0,0 +1,129 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"godo/src/models"
+	"godo/src/persistence"
+)
+
+// MatrixScope selects the open todos the Eisenhower matrix shows
+type MatrixScope int
+
+const (
+	MatrixDay  MatrixScope = iota // The chosen day
+	MatrixWeek                    // Monday to Sunday of the chosen day's week
+	MatrixAll                     // Every open todo
+)
+
+// MatrixScopes lists the scopes in the order they are offered
+var MatrixScopes = []MatrixScope{MatrixDay, MatrixWeek, MatrixAll}
+
+// Range returns the days of the scope around day
+func (s MatrixScope) Range(day time.Time) ExportRange {
+	switch s {
+	case MatrixDay:
+		return DayRange(day)
+	case MatrixWeek:
+		monday := localDay(day).AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		return ExportRange{From: monday, To: monday.AddDate(0, 0, 6)}
+	default:
+		return ExportRange{}
+	}
+}
+
+// Matrix holds the open todos of a scope sorted into the four quadrants of
+// the Eisenhower matrix, earliest first
+type Matrix struct {
+	quadrants map[models.PriorityLevel][]*models.TodoItem
+}
+
+// Quadrant returns the todos of level. With unscheduledOnly it returns only
+// the all-day todos, which have no time of their own yet.
+func (m *Matrix) Quadrant(level models.PriorityLevel, unscheduledOnly bool) []*models.TodoItem {
+	if !unscheduledOnly {
+		return m.quadrants[level]
+	}
+	var todos []*models.TodoItem
+	for _, todo := range m.quadrants[level] {
+		if todo.AllDay {
+			todos = append(todos, todo)
+		}
+	}
+	return todos
+}
+
+// MatrixService sorts the open todos of a repository into the Eisenhower
+// matrix and changes their priority. Like the repository it is not safe for
+// concurrent use.
+type MatrixService struct {
+	repo     persistence.TodoRepository
+	exporter *ExportService
+}
+
+// NewMatrixService creates a service over repo
+func NewMatrixService(repo persistence.TodoRepository) *MatrixService {
+	return &MatrixService{repo: repo, exporter: NewExportService(repo)}
+}
+
+// Load returns the matrix of the open todos in scope around day. The todos
+// are copies.
+func (s *MatrixService) Load(scope MatrixScope, day time.Time) (*Matrix, error) {
+	todos, err := s.exporter.Todos(scope.Range(day))
+	if err != nil {
+		return nil, err
+	}
+	matrix := &Matrix{quadrants: make(map[models.PriorityLevel][]*models.TodoItem)}
+	for _, todo := range todos {
+		if todo.Done {
+			continue
+		}
+		level := models.PriorityOf(todo)
+		matrix.quadrants[level] = append(matrix.quadrants[level], todo)
+	}
+	return matrix, nil
+}
+
+// Move puts todo, as returned by Load, into the quadrant of level
+func (s *MatrixService) Move(todo *models.TodoItem, level models.PriorityLevel) error {
+	if todo.Level == int(level) {
+		return nil
+	}
+	updated := *todo
+	updated.SetLevel(int(level))
+	if err := s.repo.UpdateTodo(&updated, todo.TodoTime); err != nil {
+		return fmt.Errorf("failed to move %q: %w", todo.Name, err)
+	}
+	return nil
+}
+
+// QuickAdd adds an unscheduled task named name to the quadrant of level, as
+// an all-day todo on day. Todos are found by their time, so the task takes
+// the first minute of the day no other todo is stored at.
+func (s *MatrixService) QuickAdd(level models.PriorityLevel, name string, day time.Time) (*models.TodoItem, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("a todo needs a name")
+	}
+	todo := models.NewTodoItem()
+	todo.SetName(name)
+	todo.SetKind(1)
+	todo.SetLevel(int(level))
+	todo.SetAllDay(day)
+	midnight := todo.TodoTime
+	for {
+		if _, err := s.repo.GetTodoByTime(todo.TodoTime); err != nil {
+			break
+		}
+		todo.TodoTime = todo.TodoTime.Add(time.Minute)
+		if todo.TodoTime.Day() != midnight.Day() {
+			return nil, fmt.Errorf("%s has no free minute left for %q", day.Format("Mon 02.01.2006"), name)
+		}
+	}
+	if err := s.repo.AddTodo(todo); err != nil {
+		return nil, fmt.Errorf("failed to add %q: %w", name, err)
+	}
+	return todo, nil
+}
@@ -0,0 +1,271 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"godo/src/models"
+	"godo/src/persistence"
+)
+
+// PomodoroService owns the application's Pomodoro timer. It advances the
+// timer in the background, records completed intervals and interruptions and
+// saves the session after every change so it can be resumed after a restart.
+type PomodoroService struct {
+	timer *models.PomodoroTimer
+	store persistence.PomodoroRepository
+	clock models.Clock
+
+	mu             sync.Mutex
+	task           *models.PomodoroTask
+	completed      []models.PomodoroRecord
+	interruptions  []models.Interruption
+	stop           chan struct{}
+	locked         bool // The saved session could not be read and must not be overwritten
+	onTaskPomodoro func(models.PomodoroTask)
+}
+
+// NewPomodoroService creates a service using the system clock
+func NewPomodoroService(store persistence.PomodoroRepository) *PomodoroService {
+	return NewPomodoroServiceWithClock(store, models.SystemClock)
+}
+
+// NewPomodoroServiceWithClock creates a service whose timer is driven by clock
+func NewPomodoroServiceWithClock(store persistence.PomodoroRepository, clock models.Clock) *PomodoroService {
+	s := &PomodoroService{
+		timer: models.NewPomodoroTimerWithClock(models.NewDefaultPomodoroConfig(), clock),
+		store: store,
+		clock: clock,
+	}
+	s.timer.Subscribe(s.onTimerEvent)
+	return s
+}
+
+// Timer returns the shared timer
+func (s *PomodoroService) Timer() *models.PomodoroTimer {
+	return s.timer
+}
+
+// Restore continues the saved session. Intervals that finished while the app
+// was closed are recorded as completed. If the file cannot be read the timer
+// still works, but nothing is saved so the file is left as it was.
+func (s *PomodoroService) Restore() error {
+	session, err := s.store.LoadSession()
+	if err != nil {
+		s.mu.Lock()
+		s.locked = true
+		s.mu.Unlock()
+		return fmt.Errorf("failed to load pomodoro session: %w", err)
+	}
+
+	s.mu.Lock()
+	s.task = session.Task
+	s.completed = session.Completed
+	s.interruptions = session.Interruptions
+	s.mu.Unlock()
+
+	s.timer.RestoreState(session.Timer)
+	return nil
+}
+
+// Start advances the timer every interval until Stop is called
+func (s *PomodoroService) Start(interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		return
+	}
+
+	stop := make(chan struct{})
+	s.stop = stop
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.timer.Update()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the background updates and saves the session
+func (s *PomodoroService) Stop() error {
+	s.mu.Lock()
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+	s.mu.Unlock()
+	return s.save()
+}
+
+// SetOnTaskPomodoro registers fn to be called when a work session spent on a
+// task finishes. It runs on the goroutine that advanced the timer, including
+// during Restore for sessions that finished while the app was closed.
+func (s *PomodoroService) SetOnTaskPomodoro(fn func(models.PomodoroTask)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onTaskPomodoro = fn
+}
+
+// Task returns the todo the timer is working on, or nil
+func (s *PomodoroService) Task() *models.PomodoroTask {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.task == nil {
+		return nil
+	}
+	task := *s.task
+	return &task
+}
+
+// StartTask links the timer to todo and makes sure a work session is running.
+// A running or paused work session is credited to the new task; a break is
+// skipped.
+func (s *PomodoroService) StartTask(todo *models.TodoItem) {
+	// Finish intervals that already ended so they are not credited to todo
+	s.timer.Update()
+
+	s.mu.Lock()
+	s.task = models.NewPomodoroTask(todo)
+	s.mu.Unlock()
+
+	status := s.timer.Snapshot()
+	if status.Interval().IsBreak() {
+		s.timer.SkipBreak()
+		status = s.timer.Snapshot()
+	}
+	if status.State != models.PomodoroWork {
+		s.timer.Start()
+	}
+	s.saveOrLog()
+}
+
+// ClearTask unlinks the timer from its todo
+func (s *PomodoroService) ClearTask() {
+	s.mu.Lock()
+	s.task = nil
+	s.mu.Unlock()
+	s.saveOrLog()
+}
+
+// Completed returns the intervals completed so far, oldest first
+func (s *PomodoroService) Completed() []models.PomodoroRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]models.PomodoroRecord(nil), s.completed...)
+}
+
+// LogInterruption records an interruption of the current work session with an
+// optional note. If void is set the session is abandoned, otherwise it goes on.
+// It returns false when no work session is running or paused.
+func (s *PomodoroService) LogInterruption(kind models.InterruptionKind, note string, void bool) bool {
+	if !s.timer.Snapshot().InWork() {
+		return false
+	}
+
+	s.mu.Lock()
+	interruption := models.Interruption{Kind: kind, At: s.clock.Now(), Note: strings.TrimSpace(note), Voided: void}
+	if s.task != nil {
+		task := *s.task
+		interruption.Task = &task
+	}
+	s.interruptions = append(s.interruptions, interruption)
+	s.mu.Unlock()
+
+	if void {
+		s.timer.Void() // saves through onTimerEvent
+	} else {
+		s.saveOrLog()
+	}
+	return true
+}
+
+// Interruptions returns the interruptions logged on the calendar day of day
+func (s *PomodoroService) Interruptions(day time.Time) []models.Interruption {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return models.InterruptionsOn(s.interruptions, day)
+}
+
+// MarkInterruptionConverted remembers that the note of the interruption logged
+// at the given time was turned into a todo
+func (s *PomodoroService) MarkInterruptionConverted(at time.Time) {
+	s.mu.Lock()
+	for i := range s.interruptions {
+		if s.interruptions[i].At.Equal(at) {
+			s.interruptions[i].Converted = true
+		}
+	}
+	s.mu.Unlock()
+	s.saveOrLog()
+}
+
+// Stats summarizes the recorded sessions between the midnights of from and
+// to; labelOf returns the label of a task's todo and may be nil
+func (s *PomodoroService) Stats(from, to time.Time, labelOf func(*models.PomodoroTask) string) models.PomodoroStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return models.ComputePomodoroStats(s.completed, s.interruptions, from, to, labelOf)
+}
+
+// ExportCSV writes all recorded intervals to w as CSV
+func (s *PomodoroService) ExportCSV(w io.Writer, labelOf func(*models.PomodoroTask) string) error {
+	s.mu.Lock()
+	records := append([]models.PomodoroRecord(nil), s.completed...)
+	interruptions := append([]models.Interruption(nil), s.interruptions...)
+	s.mu.Unlock()
+	return persistence.WritePomodoroCSV(w, records, interruptions, labelOf)
+}
+
+// onTimerEvent records finished intervals, credits the task and saves the new state
+func (s *PomodoroService) onTimerEvent(event models.PomodoroEvent) {
+	var credited *models.PomodoroTask
+	var notify func(models.PomodoroTask)
+	if record, ok := models.NewPomodoroRecord(event); ok {
+		s.mu.Lock()
+		if record.Kind == models.PomodoroWork && s.task != nil {
+			task := *s.task
+			record.Task = &task
+			// A voided Pomodoro does not count towards the task
+			if !record.Voided {
+				credited = &task
+				notify = s.onTaskPomodoro
+			}
+		}
+		s.completed = append(s.completed, record)
+		s.mu.Unlock()
+	}
+	s.saveOrLog()
+
+	if credited != nil && notify != nil {
+		notify(*credited)
+	}
+}
+
+// saveOrLog saves the session, logging failures
+func (s *PomodoroService) saveOrLog() {
+	if err := s.save(); err != nil {
+		fmt.Printf("Failed to save pomodoro session: %v\n", err)
+	}
+}
+
+// save writes the current timer state and history
+func (s *PomodoroService) save() error {
+	state := s.timer.ExportState()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.locked {
+		return nil
+	}
+	session := &models.PomodoroSession{Timer: state, Task: s.task, Completed: s.completed, Interruptions: s.interruptions}
+	return s.store.SaveSession(session)
+}
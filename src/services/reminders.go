@@ -0,0 +1,156 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"godo/src/models"
+	"godo/src/persistence"
+	"godo/src/utils"
+)
+
+// ReminderService notices when the reminder of an open todo comes due, that
+// is WarnTime minutes before the todo. Reminders due while the app was
+// closed are not caught up on.
+type ReminderService struct {
+	repo  persistence.TodoRepository
+	clock models.Clock
+
+	mu         sync.Mutex
+	dispatch   func(func())
+	onReminder func(*models.TodoItem)
+	last       time.Time
+	stop       chan struct{}
+}
+
+// NewReminderService creates a service using the system clock
+func NewReminderService(repo persistence.TodoRepository) *ReminderService {
+	return NewReminderServiceWithClock(repo, models.SystemClock)
+}
+
+// NewReminderServiceWithClock creates a service that reads the time from clock
+func NewReminderServiceWithClock(repo persistence.TodoRepository, clock models.Clock) *ReminderService {
+	return &ReminderService{
+		repo:     repo,
+		clock:    clock,
+		dispatch: func(fn func()) { fn() },
+	}
+}
+
+// SetDispatch sets how checks reach the repository; fn must run its
+// argument before returning
+func (s *ReminderService) SetDispatch(fn func(func())) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dispatch = fn
+}
+
+// SetOnReminder registers fn to be called with a copy of each todo whose
+// reminder came due. It runs on the goroutine that checked.
+func (s *ReminderService) SetOnReminder(fn func(*models.TodoItem)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onReminder = fn
+}
+
+// Start checks for reminders every interval until Stop is called. Only
+// reminders coming due after Start are reported.
+func (s *ReminderService) Start(interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		return
+	}
+	if s.last.IsZero() {
+		s.last = s.clock.Now()
+	}
+
+	stop := make(chan struct{})
+	s.stop = stop
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.Check()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the background checks
+func (s *ReminderService) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+}
+
+// Check reports the reminders that came due since the previous check, or
+// since the first check when there was none
+func (s *ReminderService) Check() {
+	now := s.clock.Now()
+	s.mu.Lock()
+	from := s.last
+	s.last = now
+	dispatch, onReminder := s.dispatch, s.onReminder
+	s.mu.Unlock()
+	if from.IsZero() || onReminder == nil {
+		return
+	}
+
+	var due []*models.TodoItem
+	dispatch(func() {
+		due = s.due(from, now)
+	})
+	for _, todo := range due {
+		onReminder(todo)
+	}
+}
+
+// due returns copies of the open todos whose reminder falls in (from, now].
+// A reminder comes before its todo, so only months from the one before from
+// onwards are read; the extra month covers todos filed by a zone a day off
+// the local one. How far ahead they lie depends on WarnTime, which imports
+// leave unbounded, so every later month with todos is checked.
+func (s *ReminderService) due(from, now time.Time) []*models.TodoItem {
+	months, err := s.repo.GetAllMonths()
+	if err != nil {
+		fmt.Printf("Failed to check reminders: %v\n", err)
+		return nil
+	}
+	first := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, from.Location()).AddDate(0, -1, 0)
+	firstKey := utils.FormatDateKey(first.Year(), int(first.Month()))
+
+	var due []*models.TodoItem
+	for _, dateKey := range months {
+		if dateKey < firstKey {
+			continue
+		}
+		year, month := utils.ParseDateKey(dateKey)
+		if year == 0 {
+			continue
+		}
+		todos, err := s.repo.GetTodosForMonth(year, month)
+		if err != nil {
+			fmt.Printf("Failed to check reminders: %v\n", err)
+			continue
+		}
+		for _, todo := range todos {
+			if todo.Done || todo.WarnTime <= 0 {
+				continue
+			}
+			at := todo.RemindTime(now.Location())
+			if at.After(from) && !at.After(now) {
+				due = append(due, copyTodo(todo))
+			}
+		}
+	}
+	return due
+}
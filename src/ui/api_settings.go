@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"godo/src/localization"
+	"godo/src/models"
+	"godo/src/services"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showAPISettings lets the user switch the local REST API on or off, pick its
+// port and see or replace its token. Saving restarts the server.
+func (mw *MainWindow) showAPISettings() {
+	settings := mw.config.API
+
+	enableCheck := widget.NewCheck(localization.GetString("api_enable"), nil)
+	enableCheck.SetChecked(settings.Enabled)
+	portEntry := widget.NewEntry()
+	portEntry.SetText(strconv.Itoa(settings.GetPort()))
+	tokenEntry := widget.NewEntry()
+	tokenEntry.SetText(settings.Token)
+	tokenEntry.Disable()
+	addressLabel := widget.NewLabel("")
+	updateAddress := func(string) {
+		addressLabel.SetText(localization.GetStringWithArgs("api_address", strings.TrimSpace(portEntry.Text)))
+	}
+	portEntry.OnChanged = updateAddress
+	updateAddress("")
+
+	copyBtn := widget.NewButton(localization.GetString("api_button_copy"), func() {
+		mw.window.Clipboard().SetContent(tokenEntry.Text)
+	})
+	newTokenBtn := widget.NewButton(localization.GetString("api_button_new_token"), func() {
+		token, err := services.NewAPIToken()
+		if err != nil {
+			dialog.ShowError(err, mw.window)
+			return
+		}
+		tokenEntry.SetText(token)
+	})
+
+	form := container.NewVBox(
+		enableCheck,
+		widget.NewForm(
+			widget.NewFormItem(localization.GetString("api_port"), portEntry),
+			widget.NewFormItem(localization.GetString("api_token"),
+				container.NewBorder(nil, nil, nil, container.NewHBox(copyBtn, newTokenBtn), tokenEntry)),
+		),
+		addressLabel,
+	)
+
+	d := dialog.NewCustomWithoutButtons(localization.GetString("api_title"), form, mw.window)
+	saveBtn := widget.NewButton(localization.GetString("api_button_save"), func() {
+		port, err := strconv.Atoi(strings.TrimSpace(portEntry.Text))
+		if err != nil || port < 1 || port > 65535 {
+			dialog.ShowError(errors.New(localization.GetString("api_invalid_port")), mw.window)
+			return
+		}
+		updated := models.APIConfig{Enabled: enableCheck.Checked, Port: port, Token: tokenEntry.Text}
+		if updated.Port == models.DefaultAPIPort {
+			updated.Port = 0
+		}
+		if updated.Enabled && updated.Token == "" {
+			if updated.Token, err = services.NewAPIToken(); err != nil {
+				dialog.ShowError(err, mw.window)
+				return
+			}
+		}
+
+		d.Hide()
+		mw.config.API = updated
+		mw.saveConfig()
+		if err := mw.api.Configure(updated); err != nil {
+			dialog.ShowError(err, mw.window)
+		}
+	})
+	saveBtn.Importance = widget.HighImportance
+	cancelBtn := widget.NewButton(localization.GetString("api_button_cancel"), func() { d.Hide() })
+
+	d.SetButtons([]fyne.CanvasObject{cancelBtn, saveBtn})
+	d.Resize(fyne.NewSize(480, 280))
+	d.Show()
+}
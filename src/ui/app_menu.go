@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"godo/src/localization"
+	"godo/src/services"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// toolsMenuItems returns the items opening sync, the local API, import,
+// export and the storage settings. The main window's menu button and the
+// system tray both show them, so the features stay reachable where the
+// desktop has no tray. before runs ahead of every action; the tray uses it
+// to bring back the main window the dialogs open over.
+func (mw *MainWindow) toolsMenuItems(before func()) []*fyne.MenuItem {
+	run := func(action func()) func() {
+		return func() {
+			if before != nil {
+				before()
+			}
+			action()
+		}
+	}
+
+	importItems := mw.formatItems(run, mw.showImport)
+	importItems = append(importItems, fyne.NewMenuItemSeparator())
+	for _, format := range services.ForeignFormats {
+		format := format
+		importItems = append(importItems, fyne.NewMenuItem(foreignFormatName(format)+"…", run(func() {
+			mw.showForeignImport(format)
+		})))
+	}
+	importItem := fyne.NewMenuItem(localization.GetString("tray_import"), nil)
+	importItem.ChildMenu = fyne.NewMenu("", importItems...)
+	exportItem := fyne.NewMenuItem(localization.GetString("tray_export"), nil)
+	exportItem.ChildMenu = fyne.NewMenu("", mw.formatItems(run, mw.showExport)...)
+
+	return []*fyne.MenuItem{
+		fyne.NewMenuItem(localization.GetString("tray_sync_now"), run(mw.syncNow)),
+		fyne.NewMenuItem(localization.GetString("tray_sync_settings"), run(mw.showSyncSettings)),
+		fyne.NewMenuItem(localization.GetString("tray_calendar_settings"), run(mw.showCalendarSettings)),
+		fyne.NewMenuItem(localization.GetString("tray_local_api"), run(mw.showAPISettings)),
+		fyne.NewMenuItemSeparator(),
+		importItem,
+		exportItem,
+		fyne.NewMenuItem(localization.GetString("tray_todotxt_file"), run(mw.showTodoTxtSettings)),
+		fyne.NewMenuItem(localization.GetString("tray_markdown_folder"), run(mw.showMarkdownFolderSettings)),
+	}
+}
+
+// formatItems returns an item per export format calling show with it
+func (mw *MainWindow) formatItems(run func(func()) func(), show func(services.ExportFormat)) []*fyne.MenuItem {
+	var items []*fyne.MenuItem
+	for _, format := range services.ExportFormats {
+		format := format
+		items = append(items, fyne.NewMenuItem(formatName(format)+"…", run(func() {
+			show(format)
+		})))
+	}
+	return items
+}
+
+// showToolsMenu opens the tools menu below the menu button of the header
+func (mw *MainWindow) showToolsMenu() {
+	popup := widget.NewPopUpMenu(fyne.NewMenu("", mw.toolsMenuItems(nil)...), mw.window.Canvas())
+	pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(mw.menuButton)
+	size := mw.menuButton.Size()
+
+	// Right-aligned with the button so the menu stays inside the window
+	x := pos.X + size.Width - popup.MinSize().Width
+	if x < 0 {
+		x = 0
+	}
+	popup.ShowAtPosition(fyne.NewPos(x, pos.Y+size.Height))
+}
@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"godo/src/localization"
+	"godo/src/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showCalendarSettings lets the user connect Go Do to a calendar collection
+// on a CalDAV server. Saving syncs right away.
+func (mw *MainWindow) showCalendarSettings() {
+	settings := mw.config.CalDAV
+
+	urlEntry := widget.NewEntry()
+	urlEntry.SetPlaceHolder(localization.GetString("caldav_url_placeholder"))
+	urlEntry.SetText(settings.URL)
+	userEntry := widget.NewEntry()
+	userEntry.SetText(settings.Username)
+	passwordEntry := widget.NewPasswordEntry()
+	passwordEntry.SetText(settings.Password)
+	intervalEntry := widget.NewEntry()
+	intervalEntry.SetText(strconv.Itoa(settings.Interval))
+	hint := widget.NewLabel(localization.GetString("caldav_hint"))
+	hint.Wrapping = fyne.TextWrapWord
+
+	form := container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem(localization.GetString("caldav_url"), urlEntry),
+			widget.NewFormItem(localization.GetString("caldav_username"), userEntry),
+			widget.NewFormItem(localization.GetString("caldav_password"), passwordEntry),
+			widget.NewFormItem(localization.GetString("sync_interval"), intervalEntry),
+		),
+		hint,
+	)
+
+	d := dialog.NewCustomWithoutButtons(localization.GetString("caldav_title"), form, mw.window)
+	saveBtn := widget.NewButton(localization.GetString("sync_button_save"), func() {
+		interval, err := strconv.Atoi(strings.TrimSpace(intervalEntry.Text))
+		if err != nil || interval < 0 {
+			dialog.ShowError(errors.New(localization.GetString("sync_invalid_interval")), mw.window)
+			return
+		}
+		updated := models.CalDAVConfig{
+			URL:      strings.TrimSpace(urlEntry.Text),
+			Username: strings.TrimSpace(userEntry.Text),
+			Password: passwordEntry.Text,
+			Interval: interval,
+		}
+		if err := mw.calendar.Configure(updated); err != nil {
+			dialog.ShowError(err, mw.window)
+			return
+		}
+
+		d.Hide()
+		mw.config.CalDAV = updated
+		mw.saveConfig()
+		if mw.calendar.HasServer() {
+			mw.runCalendarSync(nil, true)
+		}
+	})
+	saveBtn.Importance = widget.HighImportance
+	cancelBtn := widget.NewButton(localization.GetString("sync_button_cancel"), func() { d.Hide() })
+
+	d.SetButtons([]fyne.CanvasObject{cancelBtn, saveBtn})
+	d.Resize(fyne.NewSize(520, 320))
+	d.Show()
+}
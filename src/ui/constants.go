@@ -5,12 +5,15 @@ const (
 	MainWindowHeight     = 800
 	PomodoroWindowWidth  = 400
 	PomodoroWindowHeight = 500
+	PomodoroMiniWidth    = 150
+	PomodoroMiniHeight   = 170
 	NotesWindowWidth     = 400
 	NotesWindowHeight    = 300
 )
 
 const (
 	TimelineItemHeight = 80
+	PlannerHourHeight  = 48
 	ButtonHeight       = 44
 	ButtonPadding      = 24
 	BorderRadius       = 8
@@ -0,0 +1,256 @@
+package ui
+
+import (
+	"image/color"
+	"time"
+
+	"godo/src/models"
+	"godo/src/ui/helpers"
+	"godo/src/utils"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	plannerLabelWidth  = 44 // Space for the hour labels left of the grid
+	plannerAllDayRow   = 26 // Height of one all-day item above the grid
+	plannerMinBlockH   = 18 // Smallest drawn block so short items stay tappable
+	plannerBlockMargin = 2
+)
+
+// DayPlanner draws the todos of one day as blocks on an hourly grid.
+// Block heights are proportional to the item's length and overlapping items
+// are placed side by side.
+type DayPlanner struct {
+	widget.BaseWidget
+
+	day        time.Time
+	allDay     []*models.TodoItem
+	blocks     []models.TimeBlock
+	onSelected func(*models.TodoItem, time.Time)
+}
+
+// NewDayPlanner creates a planner for the local calendar day of day
+func NewDayPlanner(day time.Time, todos []*models.TodoItem, onSelected func(*models.TodoItem, time.Time)) *DayPlanner {
+	dayStart := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local)
+	allDay, blocks := models.PlanDay(todos, dayStart, time.Local)
+	p := &DayPlanner{
+		day:        dayStart,
+		allDay:     allDay,
+		blocks:     blocks,
+		onSelected: onSelected,
+	}
+	p.ExtendBaseWidget(p)
+	return p
+}
+
+// CreateRenderer creates the planner renderer
+func (p *DayPlanner) CreateRenderer() fyne.WidgetRenderer {
+	r := &dayPlannerRenderer{planner: p}
+
+	isLightTheme := helpers.IsLightTheme()
+	var lineColor, labelColor color.Color
+	if isLightTheme {
+		lineColor = color.NRGBA{R: 0xD0, G: 0xD0, B: 0xD0, A: 0xFF}
+		labelColor = color.NRGBA{R: 0x66, G: 0x66, B: 0x66, A: 0xFF}
+	} else {
+		lineColor = color.NRGBA{R: 0x3c, G: 0x38, B: 0x36, A: 0xFF}
+		labelColor = color.NRGBA{R: 0xA8, G: 0x99, B: 0x84, A: 0xFF}
+	}
+
+	for hour := 0; hour < 24; hour++ {
+		line := canvas.NewRectangle(lineColor)
+		label := canvas.NewText(time.Date(2000, 1, 1, hour, 0, 0, 0, time.UTC).Format("15:04"), labelColor)
+		label.TextSize = 12
+		r.hourLines = append(r.hourLines, line)
+		r.hourLabels = append(r.hourLabels, label)
+		r.objects = append(r.objects, line, label)
+	}
+
+	for _, todo := range p.allDay {
+		block := newPlannerBlock(todo, helpers.FormatTimeRange(todo, time.Local), p.onSelected)
+		r.allDayBlocks = append(r.allDayBlocks, block)
+		r.objects = append(r.objects, block)
+	}
+	for _, tb := range p.blocks {
+		block := newPlannerBlock(tb.Todo, helpers.FormatTimeRange(tb.Todo, time.Local), p.onSelected)
+		r.blocks = append(r.blocks, block)
+		r.objects = append(r.objects, block)
+	}
+
+	// Current time marker when showing today
+	if utils.SameDay(time.Now(), p.day) {
+		r.nowLine = canvas.NewRectangle(color.NRGBA{R: 0xFB, G: 0x49, B: 0x34, A: 0xFF})
+		r.objects = append(r.objects, r.nowLine)
+	}
+	return r
+}
+
+// dayPlannerRenderer positions the hour grid and blocks
+type dayPlannerRenderer struct {
+	planner      *DayPlanner
+	hourLines    []*canvas.Rectangle
+	hourLabels   []*canvas.Text
+	allDayBlocks []*plannerBlock
+	blocks       []*plannerBlock
+	nowLine      *canvas.Rectangle
+	objects      []fyne.CanvasObject
+}
+
+func (r *dayPlannerRenderer) gridTop() float32 {
+	return float32(len(r.allDayBlocks)) * plannerAllDayRow
+}
+
+// yFor returns the vertical position of a wall-clock time on the grid
+func (r *dayPlannerRenderer) yFor(t time.Time) float32 {
+	minutes := t.Hour()*60 + t.Minute()
+	if !utils.SameDay(t, r.planner.day) {
+		// Blocks clipped to the end of the day end at the next midnight
+		minutes = 24 * 60
+	}
+	return r.gridTop() + float32(minutes)/60*PlannerHourHeight
+}
+
+func (r *dayPlannerRenderer) Layout(size fyne.Size) {
+	gridX := float32(plannerLabelWidth)
+	gridW := size.Width - gridX - plannerBlockMargin
+	top := r.gridTop()
+
+	for i, block := range r.allDayBlocks {
+		block.Move(fyne.NewPos(gridX, float32(i)*plannerAllDayRow+plannerBlockMargin))
+		block.Resize(fyne.NewSize(gridW, plannerAllDayRow-2*plannerBlockMargin))
+	}
+
+	for hour := range r.hourLines {
+		y := top + float32(hour)*PlannerHourHeight
+		r.hourLines[hour].Move(fyne.NewPos(gridX, y))
+		r.hourLines[hour].Resize(fyne.NewSize(gridW, 1))
+		r.hourLabels[hour].Move(fyne.NewPos(4, y+2))
+	}
+
+	for i, tb := range r.planner.blocks {
+		laneW := gridW / float32(tb.Lanes)
+		y := r.yFor(tb.Start) + 1
+		h := r.yFor(tb.End) - y - 1
+		if h < plannerMinBlockH {
+			h = plannerMinBlockH
+		}
+		r.blocks[i].Move(fyne.NewPos(gridX+float32(tb.Lane)*laneW+1, y))
+		r.blocks[i].Resize(fyne.NewSize(laneW-plannerBlockMargin, h))
+	}
+
+	if r.nowLine != nil {
+		r.nowLine.Move(fyne.NewPos(gridX, r.yFor(time.Now())))
+		r.nowLine.Resize(fyne.NewSize(gridW, 2))
+	}
+}
+
+func (r *dayPlannerRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(200, r.gridTop()+24*PlannerHourHeight)
+}
+
+func (r *dayPlannerRenderer) Refresh() {
+	r.Layout(r.planner.Size())
+	for _, obj := range r.objects {
+		obj.Refresh()
+	}
+}
+
+func (r *dayPlannerRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+func (r *dayPlannerRenderer) Destroy() {}
+
+// plannerBlock is a tappable block showing one todo in the planner
+type plannerBlock struct {
+	widget.BaseWidget
+	todo       *models.TodoItem
+	timeText   string
+	onSelected func(*models.TodoItem, time.Time)
+}
+
+func newPlannerBlock(todo *models.TodoItem, timeText string, onSelected func(*models.TodoItem, time.Time)) *plannerBlock {
+	b := &plannerBlock{todo: todo, timeText: timeText, onSelected: onSelected}
+	b.ExtendBaseWidget(b)
+	return b
+}
+
+func (b *plannerBlock) CreateRenderer() fyne.WidgetRenderer {
+	fill := color.NRGBAModel.Convert(b.todo.GetLevelColor()).(color.NRGBA)
+	if b.todo.Done {
+		fill.A = 0x70 // Fade finished items
+	}
+	rect := canvas.NewRectangle(fill)
+	rect.CornerRadius = 4
+
+	text := canvas.NewText("", color.NRGBA{R: 0x28, G: 0x28, B: 0x28, A: 0xFF})
+	text.TextSize = 13
+	text.TextStyle = fyne.TextStyle{Bold: true}
+	return &plannerBlockRenderer{block: b, rect: rect, text: text}
+}
+
+// Tapped opens the todo like a tap on a timeline row
+func (b *plannerBlock) Tapped(*fyne.PointEvent) {
+	if b.onSelected != nil {
+		b.onSelected(b.todo, b.todo.TodoTime)
+	}
+}
+
+// Cursor shows a pointer over blocks
+func (b *plannerBlock) Cursor() desktop.Cursor {
+	return desktop.PointerCursor
+}
+
+type plannerBlockRenderer struct {
+	block *plannerBlock
+	rect  *canvas.Rectangle
+	text  *canvas.Text
+}
+
+func (r *plannerBlockRenderer) Layout(size fyne.Size) {
+	r.rect.Resize(size)
+
+	// Truncate the label so it stays inside narrow blocks
+	full := r.block.timeText + "  " + r.block.todo.Name
+	r.text.Text = fitText(full, r.text.TextSize, r.text.TextStyle, size.Width-8)
+	r.text.Move(fyne.NewPos(4, 2))
+}
+
+func (r *plannerBlockRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(20, plannerMinBlockH)
+}
+
+func (r *plannerBlockRenderer) Refresh() {
+	r.Layout(r.block.Size())
+	r.rect.Refresh()
+	r.text.Refresh()
+}
+
+func (r *plannerBlockRenderer) Objects() []fyne.CanvasObject {
+	return []fyne.CanvasObject{r.rect, r.text}
+}
+
+func (r *plannerBlockRenderer) Destroy() {}
+
+// fitText shortens text with an ellipsis until it fits into width
+func fitText(text string, size float32, style fyne.TextStyle, width float32) string {
+	if width <= 0 {
+		return ""
+	}
+	if fyne.MeasureText(text, size, style).Width <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		candidate := string(runes) + "…"
+		if fyne.MeasureText(candidate, size, style).Width <= width {
+			return candidate
+		}
+	}
+	return ""
+}
@@ -0,0 +1,157 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"godo/src/localization"
+	"godo/src/services"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// importSkippedShown is how many unreadable entries an import lists
+const importSkippedShown = 8
+
+// formatName returns the name of an export format as shown to the user
+func formatName(format services.ExportFormat) string {
+	return localization.GetString("format_" + string(format))
+}
+
+// showExport asks which todos to export in format, then for the file to
+// write them to
+func (mw *MainWindow) showExport(format services.ExportFormat) {
+	day := mw.displayDay()
+	options := []string{
+		localization.GetStringWithArgs("export_range_day", day.Format("Mon 2 Jan 2006")),
+		localization.GetStringWithArgs("export_range_month", day.Format("January 2006")),
+		localization.GetString("export_range_dates"),
+		localization.GetString("export_range_all"),
+	}
+	fromEntry := widget.NewEntry()
+	fromEntry.SetPlaceHolder("DD.MM.YYYY")
+	fromEntry.SetText(day.Format("02.01.2006"))
+	toEntry := widget.NewEntry()
+	toEntry.SetPlaceHolder("DD.MM.YYYY")
+	toEntry.SetText(day.Format("02.01.2006"))
+	dates := widget.NewForm(
+		widget.NewFormItem(localization.GetString("export_from"), fromEntry),
+		widget.NewFormItem(localization.GetString("export_to"), toEntry),
+	)
+	dates.Hide()
+	choice := widget.NewRadioGroup(options, func(selected string) {
+		if selected == options[2] {
+			dates.Show()
+		} else {
+			dates.Hide()
+		}
+	})
+	choice.SetSelected(options[0])
+
+	title := localization.GetStringWithArgs("export_title", formatName(format))
+	d := dialog.NewCustomWithoutButtons(title, container.NewVBox(choice, dates), mw.window)
+	exportBtn := widget.NewButton(localization.GetString("export_button"), func() {
+		var exportRange services.ExportRange
+		name := "godo"
+		switch choice.Selected {
+		case options[0]:
+			exportRange = services.DayRange(day)
+			name += "-" + day.Format("2006-01-02")
+		case options[1]:
+			exportRange = services.MonthRange(day.Year(), int(day.Month()))
+			name += "-" + day.Format("2006-01")
+		case options[2]:
+			from, errFrom := time.ParseInLocation("02.01.2006", strings.TrimSpace(fromEntry.Text), time.Local)
+			to, errTo := time.ParseInLocation("02.01.2006", strings.TrimSpace(toEntry.Text), time.Local)
+			if errFrom != nil || errTo != nil {
+				dialog.ShowError(errors.New(localization.GetString("export_invalid_date")), mw.window)
+				return
+			}
+			var err error
+			if exportRange, err = services.DateRange(from, to); err != nil {
+				dialog.ShowError(err, mw.window)
+				return
+			}
+			name += "-" + from.Format("2006-01-02") + "-" + to.Format("2006-01-02")
+		}
+		d.Hide()
+		mw.exportTo(format, exportRange, name+format.Extension())
+	})
+	exportBtn.Importance = widget.HighImportance
+	cancelBtn := widget.NewButton(localization.GetString("sync_button_cancel"), func() { d.Hide() })
+
+	d.SetButtons([]fyne.CanvasObject{cancelBtn, exportBtn})
+	d.Resize(fyne.NewSize(360, 280))
+	d.Show()
+}
+
+// exportTo writes the todos in exportRange to a file chosen by the user
+func (mw *MainWindow) exportTo(format services.ExportFormat, exportRange services.ExportRange, fileName string) {
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, mw.window)
+			return
+		}
+		if writer == nil {
+			return // cancelled
+		}
+		defer writer.Close()
+		if _, err := mw.exporter.Export(writer, format, exportRange); err != nil {
+			dialog.ShowError(err, mw.window)
+		}
+	}, mw.window)
+	save.SetFileName(fileName)
+	save.Show()
+}
+
+// showImport adds the todos of a file in format chosen by the user. Todos at
+// a time already taken are left out, so importing twice adds nothing.
+func (mw *MainWindow) showImport(format services.ExportFormat) {
+	open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, mw.window)
+			return
+		}
+		if reader == nil {
+			return // cancelled
+		}
+		defer reader.Close()
+
+		report, err := mw.exporter.Import(reader, format)
+		if err != nil {
+			dialog.ShowError(err, mw.window)
+			return
+		}
+		mw.showImportReport(report)
+	}, mw.window)
+	open.SetFilter(storage.NewExtensionFileFilter([]string{format.Extension()}))
+	open.Show()
+}
+
+// showImportReport tells what an import added and lists the first entries
+// that could not be read
+func (mw *MainWindow) showImportReport(report *services.ImportReport) {
+	lines := []string{localization.GetStringWithArgs("import_done_message", report.Added, report.Existing, len(report.Skipped))}
+	for i, skipped := range report.Skipped {
+		if i == importSkippedShown {
+			lines = append(lines, localization.GetStringWithArgs("import_more_skipped", len(report.Skipped)-i))
+			break
+		}
+		lines = append(lines, localization.GetStringWithArgs("import_skipped_line", skipped.Line, skipped.Err))
+	}
+	for _, skipped := range report.Skipped {
+		fmt.Printf("Skipped imported %v\n", skipped)
+	}
+
+	message := widget.NewLabel(strings.Join(lines, "\n"))
+	message.Wrapping = fyne.TextWrapWord
+	d := dialog.NewCustom(localization.GetString("import_done_title"), localization.GetString("import_button_close"), message, mw.window)
+	d.Resize(fyne.NewSize(480, 0))
+	d.Show()
+}
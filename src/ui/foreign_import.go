@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"godo/src/localization"
+	"godo/src/services"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// foreignFormatName returns the name of another task tool
+func foreignFormatName(format services.ForeignFormat) string {
+	return localization.GetString("format_" + string(format))
+}
+
+// showForeignImport reads the export of another task tool chosen by the user
+// and previews the todos it maps to before adding them
+func (mw *MainWindow) showForeignImport(format services.ForeignFormat) {
+	open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, mw.window)
+			return
+		}
+		if reader == nil {
+			return // cancelled
+		}
+		defer reader.Close()
+
+		tasks, skipped, err := services.ReadForeign(reader, format, reader.URI().Name())
+		if err != nil {
+			dialog.ShowError(err, mw.window)
+			return
+		}
+		preview, err := mw.exporter.Preview(tasks, skipped, mw.displayDay())
+		if err != nil {
+			dialog.ShowError(err, mw.window)
+			return
+		}
+		mw.showImportPreview(format, preview)
+	}, mw.window)
+	open.SetFilter(storage.NewExtensionFileFilter(format.Extensions()))
+	open.Show()
+}
+
+// showImportPreview lists the todos an import will add and adds them when
+// the user agrees
+func (mw *MainWindow) showImportPreview(format services.ForeignFormat, preview *services.ImportPreview) {
+	message := widget.NewLabel(localization.GetStringWithArgs("import_preview_message",
+		preview.New(), len(preview.Tasks)-preview.New(), len(preview.Skipped), mw.displayDay().Format("Mon 2 Jan 2006")))
+	message.Wrapping = fyne.TextWrapWord
+
+	rows := container.NewVBox()
+	for _, task := range preview.Tasks {
+		heading := widget.NewLabelWithStyle(task.Todo.Name, fyne.TextAlignLeading, fyne.TextStyle{Bold: !task.Duplicate})
+		details := widget.NewLabel(describeImportTask(task))
+		details.Wrapping = fyne.TextWrapWord
+		rows.Add(container.NewVBox(heading, details, widget.NewSeparator()))
+	}
+	scroll := container.NewVScroll(rows)
+	scroll.SetMinSize(fyne.NewSize(480, 320))
+
+	title := localization.GetStringWithArgs("import_preview_title", foreignFormatName(format))
+	d := dialog.NewCustomWithoutButtons(title, container.NewBorder(message, nil, nil, nil, scroll), mw.window)
+	importBtn := widget.NewButton(localization.GetString("import_button"), func() {
+		d.Hide()
+		report, err := mw.exporter.AddPreview(preview)
+		if err != nil {
+			dialog.ShowError(err, mw.window)
+			return
+		}
+		mw.showImportReport(report)
+	})
+	importBtn.Importance = widget.HighImportance
+	if preview.New() == 0 {
+		importBtn.Disable()
+	}
+	cancelBtn := widget.NewButton(localization.GetString("sync_button_cancel"), func() { d.Hide() })
+
+	d.SetButtons([]fyne.CanvasObject{cancelBtn, importBtn})
+	d.Show()
+}
+
+// describeImportTask summarizes how a task maps onto a todo on a single line
+func describeImportTask(task *services.PreviewTask) string {
+	var parts []string
+	switch {
+	case !task.Dated:
+		parts = append(parts, localization.GetString("import_preview_undated"))
+	case task.Todo.AllDay:
+		parts = append(parts, task.Todo.DisplayTime(time.Local).Format("Mon 2 Jan 2006"))
+	default:
+		parts = append(parts, task.Todo.DisplayTime(time.Local).Format("Mon 2 Jan 2006 15:04"))
+	}
+	if task.Project != "" {
+		parts = append(parts, localization.GetStringWithArgs("import_preview_label", task.Project))
+	}
+	if len(task.Labels) > 0 {
+		parts = append(parts, "#"+strings.Join(task.Labels, " #"))
+	}
+	parts = append(parts, localization.GetString(fmt.Sprintf("priority_%d", task.Todo.Level)))
+	if task.Todo.WarnTime > 0 {
+		parts = append(parts, localization.GetStringWithArgs("import_preview_reminder", task.Todo.WarnTime))
+	}
+	if task.Todo.Done {
+		parts = append(parts, localization.GetString("sync_version_done"))
+	}
+	if task.Duplicate {
+		parts = append(parts, localization.GetString("import_preview_duplicate"))
+	}
+	return strings.Join(parts, " · ")
+}
@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"image/color"
 	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	"godo/src/localization"
 	"godo/src/models"
 	"godo/src/persistence"
 	"godo/src/ui/helpers"
+	"godo/src/utils"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
@@ -21,6 +24,9 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
+// maxEstimate is the largest Pomodoro estimate offered in the form
+const maxEstimate = 12
+
 // TodoForm represents a form for creating/editing todo items
 type TodoForm struct {
 	parentWindow fyne.Window // Main application window (for dialogs)
@@ -34,8 +40,11 @@ type TodoForm struct {
 	labelEntry     *widget.Entry
 	dateTimeEntry  *widget.Entry
 	dateTimeButton *widget.Button
+	endTimeEntry   *widget.Entry
+	allDayCheck    *widget.Check
 	prioritySelect *widget.Select
 	kindSelect     *widget.Select
+	estimateSelect *widget.Select
 	warnTimeSlider *ReminderSlider
 	warnTimeLabel  *canvas.Text
 
@@ -84,10 +93,12 @@ func (tf *TodoForm) ShowCreateDialog(onSave func()) {
 	formItems := []*widget.FormItem{
 		{Text: "Name:", Widget: tf.nameEntry},
 		{Text: "Date/Time:", Widget: container.NewBorder(nil, nil, nil, tf.dateTimeButton, tf.dateTimeEntry)},
+		{Text: "Until:", Widget: container.NewBorder(nil, nil, nil, tf.allDayCheck, tf.endTimeEntry)},
 		{Text: "Location:", Widget: tf.placeEntry},
 		{Text: "Label:", Widget: tf.labelEntry},
 		{Text: "Type:", Widget: tf.kindSelect},
 		{Text: "Priority:", Widget: tf.prioritySelect},
+		{Text: "Pomodoros:", Widget: tf.estimateSelect},
 		{Text: "Reminder:", Widget: container.NewVBox(tf.warnTimeSlider, tf.warnTimeLabel)},
 	}
 
@@ -123,10 +134,12 @@ func (tf *TodoForm) ShowEditDialog(todo *models.TodoItem, originalTime time.Time
 	formItems := []*widget.FormItem{
 		{Text: "Name:", Widget: tf.nameEntry},
 		{Text: "Date/Time:", Widget: container.NewBorder(nil, nil, nil, tf.dateTimeButton, tf.dateTimeEntry)},
+		{Text: "Until:", Widget: container.NewBorder(nil, nil, nil, tf.allDayCheck, tf.endTimeEntry)},
 		{Text: "Location:", Widget: tf.placeEntry},
 		{Text: "Label:", Widget: tf.labelEntry},
 		{Text: "Type:", Widget: tf.kindSelect},
 		{Text: "Priority:", Widget: tf.prioritySelect},
+		{Text: "Pomodoros:", Widget: tf.estimateSelect},
 		{Text: "Reminder:", Widget: container.NewVBox(tf.warnTimeSlider, tf.warnTimeLabel)},
 	}
 
@@ -185,10 +198,12 @@ func (tf *TodoForm) ShowCreateWindow(onSave func(), onWindowCreated func(fyne.Wi
 	rows := []fyne.CanvasObject{
 		tf.makeRowLabel("Name:", tf.nameEntry),
 		tf.makeRowLabel("Date/Time:", container.NewBorder(nil, nil, nil, tf.dateTimeButton, tf.dateTimeEntry)),
+		tf.makeRowLabel("Until:", container.NewBorder(nil, nil, nil, tf.allDayCheck, tf.endTimeEntry)),
 		tf.makeRowLabel("Location:", tf.placeEntry),
 		tf.makeRowLabel("Label:", tf.labelEntry),
 		tf.makeRowLabel("Type:", tf.kindSelect),
 		tf.makeRowLabel("Priority:", tf.prioritySelect),
+		tf.makeRowLabel("Pomodoros:", tf.estimateSelect),
 		tf.makeRowLabel("Content:", container.NewScroll(tf.contentEntry)),
 		tf.makeRowLabel("Reminder:", container.NewVBox(tf.warnTimeSlider, tf.warnTimeLabel)),
 	}
@@ -204,14 +219,12 @@ func (tf *TodoForm) ShowCreateWindow(onSave func(), onWindowCreated func(fyne.Wi
 
 	// Buttons
 	addBtn := tf.makePrimaryButton(localization.GetString("form_button_add"), func() {
-		if err := tf.trySubmit(); err != nil {
-			dialog.ShowError(err, tf.formWindow)
-			return
-		}
-		if tf.onSaveCallback != nil {
-			tf.onSaveCallback()
-		}
-		win.Close()
+		tf.submit(win, func() {
+			if tf.onSaveCallback != nil {
+				tf.onSaveCallback()
+			}
+			win.Close()
+		})
 	})
 	cancelBtn := tf.makeCancelButton(localization.GetString("form_button_cancel"), func() { win.Close() })
 
@@ -228,6 +241,12 @@ func (tf *TodoForm) ShowCreateWindow(onSave func(), onWindowCreated func(fyne.Wi
 	win.Show()
 }
 
+// ShowCreateWindowFrom opens the create window with the fields filled in from draft
+func (tf *TodoForm) ShowCreateWindowFrom(draft *models.TodoItem, onSave func(), onWindowCreated func(fyne.Window), onWindowClosed func()) {
+	tf.ShowCreateWindow(onSave, onWindowCreated, onWindowClosed)
+	tf.populateForm(draft)
+}
+
 // ShowEditWindow opens the form in a standalone window for editing an existing todo
 func (tf *TodoForm) ShowEditWindow(todo *models.TodoItem, originalTime time.Time, onSave func(), onWindowCreated func(fyne.Window), onWindowClosed func()) {
 	tf.isEditMode = true
@@ -267,10 +286,12 @@ func (tf *TodoForm) ShowEditWindow(todo *models.TodoItem, originalTime time.Time
 	rows := []fyne.CanvasObject{
 		tf.makeRowLabel("Name:", tf.nameEntry),
 		tf.makeRowLabel("Date/Time:", container.NewBorder(nil, nil, nil, tf.dateTimeButton, tf.dateTimeEntry)),
+		tf.makeRowLabel("Until:", container.NewBorder(nil, nil, nil, tf.allDayCheck, tf.endTimeEntry)),
 		tf.makeRowLabel("Location:", tf.placeEntry),
 		tf.makeRowLabel("Label:", tf.labelEntry),
 		tf.makeRowLabel("Type:", tf.kindSelect),
 		tf.makeRowLabel("Priority:", tf.prioritySelect),
+		tf.makeRowLabel("Pomodoros:", tf.estimateSelect),
 		tf.makeRowLabel("Content:", container.NewScroll(tf.contentEntry)),
 		tf.makeRowLabel("Reminder:", container.NewVBox(tf.warnTimeSlider, tf.warnTimeLabel)),
 	}
@@ -286,14 +307,12 @@ func (tf *TodoForm) ShowEditWindow(todo *models.TodoItem, originalTime time.Time
 
 	// Buttons
 	saveBtn := tf.makePrimaryButton(localization.GetString("form_button_save"), func() {
-		if err := tf.trySubmit(); err != nil {
-			dialog.ShowError(err, tf.formWindow)
-			return
-		}
-		if tf.onSaveCallback != nil {
-			tf.onSaveCallback()
-		}
-		win.Close()
+		tf.submit(win, func() {
+			if tf.onSaveCallback != nil {
+				tf.onSaveCallback()
+			}
+			win.Close()
+		})
 	})
 	cancelBtn := tf.makeCancelButton(localization.GetString("form_button_cancel"), func() { win.Close() })
 
@@ -340,6 +359,17 @@ func (tf *TodoForm) setupForm() {
 		tf.showDateTimePicker()
 	})
 
+	// Optional end time and all-day toggle
+	tf.endTimeEntry = widget.NewEntry()
+	tf.endTimeEntry.SetPlaceHolder(localization.GetString("field_end_time_placeholder"))
+	tf.allDayCheck = widget.NewCheck(localization.GetString("field_all_day"), func(checked bool) {
+		if checked {
+			tf.endTimeEntry.Disable()
+		} else {
+			tf.endTimeEntry.Enable()
+		}
+	})
+
 	// Initialize selected date/time
 	tf.selectedDateTime = time.Now()
 
@@ -358,6 +388,14 @@ func (tf *TodoForm) setupForm() {
 	tf.kindSelect = widget.NewSelect(kindOptions, nil)
 	tf.kindSelect.SetSelectedIndex(0)
 
+	// Estimated Pomodoros
+	estimateOptions := []string{localization.GetString("estimate_none")}
+	for n := 1; n <= maxEstimate; n++ {
+		estimateOptions = append(estimateOptions, strconv.Itoa(n))
+	}
+	tf.estimateSelect = widget.NewSelect(estimateOptions, nil)
+	tf.estimateSelect.SetSelectedIndex(0)
+
 	// Warning time slider (0-864 minutes = 0-14.4 hours)
 	tf.warnTimeSlider = NewReminderSlider(0, 864)
 	tf.warnTimeSlider.Step = 5
@@ -384,9 +422,12 @@ func (tf *TodoForm) resetForm() {
 	tf.selectedDateTime = now
 	currentDateTime := now.Format("02.01.2006 15:04")
 	tf.dateTimeEntry.SetText(currentDateTime)
+	tf.endTimeEntry.SetText("")
+	tf.allDayCheck.SetChecked(false)
 
 	tf.prioritySelect.SetSelectedIndex(0)
 	tf.kindSelect.SetSelectedIndex(0)
+	tf.estimateSelect.SetSelectedIndex(0)
 	tf.warnTimeSlider.SetValue(0)
 	tf.onWarnTimeChanged(0)
 }
@@ -399,12 +440,22 @@ func (tf *TodoForm) populateForm(todo *models.TodoItem) {
 	tf.labelEntry.SetText(todo.Label)
 
 	// Format date/time for display in DD.MM.YYYY HH:MM format
-	tf.selectedDateTime = todo.TodoTime
-	dateTimeStr := todo.TodoTime.Format("02.01.2006 15:04")
+	tf.selectedDateTime = todo.DisplayTime(time.Local)
+	dateTimeStr := tf.selectedDateTime.Format("02.01.2006 15:04")
 	tf.dateTimeEntry.SetText(dateTimeStr)
+	tf.endTimeEntry.SetText("")
+	if todo.Duration > 0 {
+		tf.endTimeEntry.SetText(todo.EndTime(time.Local).Format("15:04"))
+	}
+	tf.allDayCheck.SetChecked(todo.AllDay)
 
 	tf.prioritySelect.SetSelectedIndex(todo.Level)
 	tf.kindSelect.SetSelectedIndex(todo.Kind)
+	estimate := todo.Estimate
+	if estimate > maxEstimate {
+		estimate = maxEstimate
+	}
+	tf.estimateSelect.SetSelectedIndex(estimate)
 	tf.warnTimeSlider.SetValue(float64(todo.WarnTime))
 	tf.onWarnTimeChanged(float64(todo.WarnTime))
 }
@@ -460,19 +511,87 @@ func (tf *TodoForm) onWarnTimeChanged(value float64) {
 
 // onSubmit handles form submission
 func (tf *TodoForm) onSubmit() {
-	if err := tf.trySubmit(); err != nil {
-		dialog.ShowError(err, tf.parentWindow)
+	tf.submit(tf.parentWindow, tf.onSaveCallback)
+}
+
+// submit validates and saves the todo, asking first when an event overlaps
+// other events of the day. onSaved runs after a successful save.
+func (tf *TodoForm) submit(win fyne.Window, onSaved func()) {
+	todo, err := tf.buildTodo()
+	if err != nil {
+		dialog.ShowError(err, win)
 		return
 	}
-	if tf.onSaveCallback != nil {
-		tf.onSaveCallback()
+
+	save := func() {
+		if err := tf.saveTodo(todo); err != nil {
+			dialog.ShowError(err, win)
+			return
+		}
+		if onSaved != nil {
+			onSaved()
+		}
+	}
+
+	conflicts := tf.findConflicts(todo)
+	if len(conflicts) == 0 {
+		save()
+		return
 	}
+
+	lines := make([]string, 0, len(conflicts))
+	for _, other := range conflicts {
+		lines = append(lines, fmt.Sprintf("• %s (%s)", other.Name, helpers.FormatTimeRange(other, time.Local)))
+	}
+	message := localization.GetStringWithArgs("conflict_message", todo.Name, joinStrings(lines, "\n"))
+	confirm := dialog.NewConfirm(localization.GetString("conflict_title"), message, func(ok bool) {
+		if ok {
+			save()
+		}
+	}, win)
+	confirm.SetConfirmText(localization.GetString("conflict_button_save"))
+	confirm.SetDismissText(localization.GetString("form_button_cancel"))
+	confirm.Show()
+}
+
+// findConflicts returns the saved events that overlap todo, ignoring the
+// todo being edited
+func (tf *TodoForm) findConflicts(todo *models.TodoItem) []*models.TodoItem {
+	start, end := todo.TimeRange(time.Local)
+	days := []time.Time{start}
+	if end.After(start) && !utils.SameDay(start, end.Add(-time.Nanosecond)) {
+		days = append(days, end.Add(-time.Nanosecond))
+	}
+
+	var others []*models.TodoItem
+	for _, day := range days {
+		dayTodos, err := tf.dataManager.GetTodosForDay(day)
+		if err != nil {
+			continue
+		}
+		for _, other := range dayTodos {
+			if tf.isEditMode && tf.originalTodo != nil &&
+				other.TodoTime.Equal(tf.originalTime) && other.Name == tf.originalTodo.Name {
+				continue
+			}
+			others = append(others, other)
+		}
+	}
+	return models.FindConflicts(todo, others, time.Local)
 }
 
-// trySubmit validates and saves the todo, returning error on failure
-func (tf *TodoForm) trySubmit() error {
+// saveTodo adds the todo, or replaces the edited one
+func (tf *TodoForm) saveTodo(todo *models.TodoItem) error {
+	if tf.isEditMode {
+		return tf.dataManager.UpdateTodo(todo, tf.originalTime)
+	}
+	return tf.dataManager.AddTodo(todo)
+}
+
+// buildTodo validates the form and creates the todo it describes
+func (tf *TodoForm) buildTodo() (*models.TodoItem, error) {
 	if tf.nameEntry.Text == "" {
-		return errors.New(localization.GetString("error_name_required"))
+		return nil, errors.New(localization.GetString("error_name_required"))
 	}
 
 	// Use the selected date/time
@@ -489,20 +608,54 @@ func (tf *TodoForm) trySubmit() error {
 	todo.Label = tf.labelEntry.Text
 	todo.Kind = tf.kindSelect.SelectedIndex()
 	todo.Level = tf.prioritySelect.SelectedIndex()
-	todo.TodoTime = todoTime
+	if tf.allDayCheck.Checked {
+		todo.SetAllDay(todoTime)
+	} else {
+		duration, err := parseDuration(tf.endTimeEntry.Text, todoTime)
+		if err != nil {
+			return nil, err
+		}
+		tf.applyTime(todo, todoTime)
+		todo.SetDuration(duration)
+	}
 	todo.WarnTime = int(tf.warnTimeSlider.Value)
+	todo.SetEstimate(tf.estimateSelect.SelectedIndex())
+	if tf.isEditMode && tf.originalTodo != nil {
+		// Finished Pomodoros are tracked by the timer, not edited in the form
+		todo.Pomodoros = tf.originalTodo.Pomodoros
+	}
+	return todo, nil
+}
 
-	// Save todo
-	var err error
-	if tf.isEditMode {
-		err = tf.dataManager.UpdateTodo(todo, tf.originalTime)
-	} else {
-		err = tf.dataManager.AddTodo(todo)
+// parseDuration turns an optional "HH:MM" end time into minutes after start
+func parseDuration(endText string, start time.Time) (int, error) {
+	endText = strings.TrimSpace(endText)
+	if endText == "" {
+		return 0, nil
 	}
+	clock, err := time.Parse("15:04", endText)
 	if err != nil {
-		return err
+		return 0, errors.New(localization.GetString("error_invalid_end_time"))
+	}
+	end := time.Date(start.Year(), start.Month(), start.Day(), clock.Hour(), clock.Minute(), 0, 0, start.Location())
+	if !end.After(start) {
+		return 0, errors.New(localization.GetString("error_invalid_end_time"))
+	}
+	return int(end.Sub(start) / time.Minute), nil
+}
+
+// applyTime stores the picked local time together with the local zone name.
+// An unchanged time keeps the zone (or floating flag) of the edited todo.
+func (tf *TodoForm) applyTime(todo *models.TodoItem, todoTime time.Time) {
+	if tf.isEditMode && tf.originalTodo != nil && !tf.originalTodo.AllDay &&
+		todoTime.Equal(tf.originalTodo.DisplayTime(time.Local)) {
+		todo.TodoTime = tf.originalTodo.TodoTime
+		todo.TimeZone = tf.originalTodo.TimeZone
+		todo.Floating = tf.originalTodo.Floating
+		return
 	}
-	return nil
+	_, zone := utils.LocalZone()
+	todo.SetZonedTime(todoTime, zone)
 }
 
 // makeRowLabel creates a two-column row with a styled label and a widget
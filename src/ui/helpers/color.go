@@ -38,6 +38,21 @@ func Lighten(c color.NRGBA, amount float32) color.NRGBA {
 	return color.NRGBA{R: mix(c.R), G: mix(c.G), B: mix(c.B), A: c.A}
 }
 
+// Blend mixes from and to, returning from at t=0 and to at t=1.
+func Blend(from, to color.Color, t float32) color.NRGBA {
+	if t < 0 {
+		t = 0
+	}
+	if t > 1 {
+		t = 1
+	}
+	a, b := ToNRGBA(from), ToNRGBA(to)
+	mix := func(x, y uint8) uint8 {
+		return uint8(float32(x)*(1-t) + float32(y)*t)
+	}
+	return color.NRGBA{R: mix(a.R, b.R), G: mix(a.G, b.G), B: mix(a.B, b.B), A: mix(a.A, b.A)}
+}
+
 // Hex parses a #RRGGBB hex color string into color.NRGBA.
 func Hex(h string) color.NRGBA {
 	var r, g, b uint8
@@ -8,6 +8,7 @@ Color Utilities (color.go):
   - Lighten: Lightens a color by a given factor
   - Darken: Darkens a color by a given factor
   - Hex: Converts hex color string to color.Color
+  - Blend: Mixes two colors
 
 Theme Utilities (theme.go):
   - IsLightTheme: Checks if current theme is light
@@ -21,5 +22,13 @@ Layout Utilities (layout.go):
 
 Window Utilities (window.go):
   - FlashWindow: Creates a visual flash effect on a window
+
+Time Utilities (timefmt.go):
+  - FormatTimeRange: Formats the start/end or all-day label of a todo
+
+Pomodoro Utilities (pomodorofmt.go):
+  - FormatPomodoroProgress: Draws finished vs. estimated Pomodoros as dots
+  - FormatFocus: Formats focused time as hours and minutes
+  - FormatPomodoroStatus: Describes the timer state in whole minutes
 */
 package helpers
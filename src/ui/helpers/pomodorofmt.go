@@ -0,0 +1,70 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"godo/src/models"
+)
+
+// maxPomodoroDots caps the dots drawn for one todo so long estimates stay short
+const maxPomodoroDots = 8
+
+// FormatPomodoroProgress draws finished against estimated Pomodoros:
+// "●●○○" for 2 of 4, "●●●●+1" when the estimate was exceeded and "" when
+// the todo has neither an estimate nor finished Pomodoros
+func FormatPomodoroProgress(todo *models.TodoItem) string {
+	done, planned := todo.Pomodoros, todo.Estimate
+	if done == 0 && planned == 0 {
+		return ""
+	}
+	if planned == 0 && done > maxPomodoroDots {
+		return fmt.Sprintf("●%d", done)
+	}
+	if planned > maxPomodoroDots {
+		return fmt.Sprintf("●%d/%d", done, planned)
+	}
+
+	filled, empty := done, planned-done
+	if empty < 0 {
+		filled, empty = planned, 0
+	}
+	if planned == 0 {
+		filled = done
+	}
+	text := strings.Repeat("●", filled) + strings.Repeat("○", empty)
+	if planned > 0 && done > planned {
+		text += fmt.Sprintf("+%d", done-planned)
+	}
+	return text
+}
+
+// FormatFocus writes a focus duration as "2h 05m", or "25m" under an hour
+func FormatFocus(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+}
+
+// FormatPomodoroStatus describes the timer in whole minutes for places that
+// cannot redraw every second: "Work · 13 min left", "Paused (Work) · 13 min
+// left", "Work · 12 min" for open-ended work and "Ready" when nothing runs
+func FormatPomodoroStatus(status models.PomodoroStatus) string {
+	if status.State == models.PomodoroIdle {
+		return status.State.String()
+	}
+
+	name := status.State.String()
+	if status.State == models.PomodoroPaused {
+		name = fmt.Sprintf("%s (%s)", name, status.PausedFrom)
+	}
+	if status.OpenEnded {
+		return fmt.Sprintf("%s · %d min", name, int(status.Elapsed.Minutes()))
+	}
+	// Round up so the last minute reads "1 min left" rather than "0"
+	left := (status.Remaining + time.Minute - 1) / time.Minute
+	return fmt.Sprintf("%s · %d min left", name, left)
+}
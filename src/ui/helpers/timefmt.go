@@ -0,0 +1,21 @@
+package helpers
+
+import (
+	"time"
+
+	"godo/src/localization"
+	"godo/src/models"
+)
+
+// FormatTimeRange formats the time of a todo as seen in loc:
+// "All day", "10:00–11:30" or "10:00" for items without a duration
+func FormatTimeRange(todo *models.TodoItem, loc *time.Location) string {
+	if todo.AllDay {
+		return localization.GetString("time_all_day")
+	}
+	start, end := todo.TimeRange(loc)
+	if !end.After(start) {
+		return start.Format("15:04")
+	}
+	return start.Format("15:04") + "–" + end.Format("15:04")
+}
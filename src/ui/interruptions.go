@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"godo/src/localization"
+	"godo/src/models"
+	"godo/src/services"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// interruptionShortcut opens the interruption dialog (Ctrl+I, Cmd+I on macOS)
+var interruptionShortcut = &desktop.CustomShortcut{KeyName: fyne.KeyI, Modifier: fyne.KeyModifierShortcutDefault}
+
+// addInterruptionShortcut registers the interruption hotkey on win
+func addInterruptionShortcut(win fyne.Window, pomodoro *services.PomodoroService, onLogged func()) {
+	win.Canvas().AddShortcut(interruptionShortcut, func(fyne.Shortcut) {
+		showInterruptionDialog(win, pomodoro, onLogged)
+	})
+}
+
+// showInterruptionDialog asks what interrupted the running work session. As
+// the Pomodoro Technique prescribes, the user either notes it and continues,
+// or voids the Pomodoro and starts over later.
+func showInterruptionDialog(win fyne.Window, pomodoro *services.PomodoroService, onLogged func()) {
+	if !pomodoro.Timer().Snapshot().InWork() {
+		dialog.ShowInformation(localization.GetString("interruption_title"),
+			localization.GetString("interruption_not_working"), win)
+		return
+	}
+
+	internal := localization.GetString("interruption_kind_internal")
+	external := localization.GetString("interruption_kind_external")
+	kindRadio := widget.NewRadioGroup([]string{internal, external}, nil)
+	kindRadio.Required = true
+	kindRadio.SetSelected(internal)
+	noteEntry := widget.NewMultiLineEntry()
+	noteEntry.SetPlaceHolder(localization.GetString("interruption_note_placeholder"))
+	noteEntry.SetMinRowsVisible(3)
+
+	d := dialog.NewCustomWithoutButtons(localization.GetString("interruption_title"),
+		container.NewVBox(kindRadio, noteEntry), win)
+	logAs := func(void bool) {
+		d.Hide()
+		kind := models.InterruptionInternal
+		if kindRadio.Selected == external {
+			kind = models.InterruptionExternal
+		}
+		if pomodoro.LogInterruption(kind, noteEntry.Text, void) && onLogged != nil {
+			onLogged()
+		}
+	}
+
+	continueBtn := widget.NewButton(localization.GetString("interruption_button_continue"), func() { logAs(false) })
+	continueBtn.Importance = widget.HighImportance
+	voidBtn := widget.NewButton(localization.GetString("interruption_button_void"), func() { logAs(true) })
+	voidBtn.Importance = widget.DangerImportance
+	cancelBtn := widget.NewButton(localization.GetString("interruption_button_cancel"), d.Hide)
+
+	d.SetButtons([]fyne.CanvasObject{cancelBtn, voidBtn, continueBtn})
+	d.Resize(fyne.NewSize(420, 260))
+	d.Show()
+	win.Canvas().Focus(noteEntry)
+}
+
+// showInterruptionLog lists the interruptions of a day, starting with day.
+// Notes can be turned into todos with onConvert; nil hides that button.
+func showInterruptionLog(win fyne.Window, pomodoro *services.PomodoroService, day time.Time, onConvert func(models.Interruption)) {
+	var d dialog.Dialog
+	dayLabel := widget.NewLabel("")
+	dayLabel.TextStyle = fyne.TextStyle{Bold: true}
+	summary := widget.NewLabel("")
+	rows := container.NewVBox()
+
+	var render func()
+	render = func() {
+		dayLabel.SetText(day.Format("Mon 02.01.2006"))
+		interruptions := pomodoro.Interruptions(day)
+
+		var internal, external, voided int
+		rows.RemoveAll()
+		for _, interruption := range interruptions {
+			interruption := interruption
+			if interruption.Kind == models.InterruptionExternal {
+				external++
+			} else {
+				internal++
+			}
+			if interruption.Voided {
+				voided++
+			}
+			rows.Add(interruptionRow(interruption, func() {
+				d.Hide()
+				onConvert(interruption)
+			}, onConvert != nil))
+		}
+		if len(interruptions) == 0 {
+			rows.Add(widget.NewLabel(localization.GetString("interruption_log_empty")))
+		}
+		summary.SetText(localization.GetStringWithArgs("interruption_log_summary", internal, external, voided))
+	}
+
+	prevBtn := widget.NewButton("◀", func() {
+		day = day.AddDate(0, 0, -1)
+		render()
+	})
+	nextBtn := widget.NewButton("▶", func() {
+		day = day.AddDate(0, 0, 1)
+		render()
+	})
+	header := container.NewBorder(nil, nil, prevBtn, nextBtn, container.NewCenter(dayLabel))
+	content := container.NewBorder(header, summary, nil, nil, container.NewVScroll(rows))
+
+	render()
+	d = dialog.NewCustom(localization.GetString("interruption_log_title"),
+		localization.GetString("interruption_button_close"), content, win)
+	d.Resize(fyne.NewSize(440, 420))
+	d.Show()
+}
+
+// interruptionRow shows one interruption as "10:42 ' note (task)"
+func interruptionRow(interruption models.Interruption, convert func(), canConvert bool) fyne.CanvasObject {
+	text := fmt.Sprintf("%s  %s  %s", interruption.At.Local().Format("15:04"), interruption.Kind.Symbol(), interruption.Note)
+	if interruption.Task != nil {
+		text += fmt.Sprintf(" (%s)", interruption.Task.Name)
+	}
+	if interruption.Voided {
+		text += " · " + localization.GetString("interruption_voided")
+	}
+	label := widget.NewLabel(text)
+	label.Wrapping = fyne.TextWrapWord
+
+	if !canConvert || interruption.Note == "" || interruption.Converted {
+		return label
+	}
+	todoBtn := widget.NewButton(localization.GetString("interruption_button_todo"), convert)
+	return container.NewBorder(nil, nil, nil, todoBtn, label)
+}
@@ -1,22 +1,29 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"image/color"
+	"path/filepath"
+	"strings"
 	"time"
 
 	assets "godo/resources"
+	"godo/src/caldav"
 	"godo/src/localization"
 	"godo/src/models"
 	"godo/src/persistence"
+	"godo/src/services"
 	"godo/src/ui/forms"
 	"godo/src/ui/helpers"
+	"godo/src/ui/threading"
 	"godo/src/ui/widgets"
 	"godo/src/utils"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 )
@@ -25,8 +32,14 @@ import (
 type MainWindow struct {
 	window        fyne.Window
 	dataManager   persistence.TodoRepository
+	exporter      *services.ExportService
 	configManager persistence.ConfigRepository
 	config        *models.Config
+	configLocked  bool                      // Config comes from a newer release and must not be overwritten
+	pomodoro      *services.PomodoroService // App-wide timer that keeps running while its window is closed
+	api           *services.APIServer       // Local REST API, running while enabled in the config
+	syncer        *persistence.Syncer       // Syncs the data directory with the configured folder
+	calendar      *caldav.Syncer            // Syncs todos with the configured CalDAV collection
 	todoForm      *forms.TodoForm
 	timeline      *Timeline
 
@@ -43,29 +56,54 @@ type MainWindow struct {
 	nextRectBtn     *widgets.SimpleRectButton
 	pomodoroRectBtn *widgets.SimpleRectButton
 	themeRectBtn    *widgets.SimpleRectButton
+	layoutRectBtn   *widgets.SimpleRectButton
+	matrixRectBtn   *widgets.SimpleRectButton
+	menuButton      fyne.CanvasObject // Opens sync, import/export and storage settings
 
 	// State
 	currentDate    time.Time // Changed to time.Time for daily view
 	viewMode       models.ViewMode
 	todos          []*models.TodoItem
 	isGruvbox      bool
+	plannerMode    bool            // Day shown as hourly planner instead of list
 	pomodoroWindow *PomodoroWindow // Reference to open pomodoro window
+	statsWindow    *PomodoroStatsWindow
+	matrixWindow   *MatrixWindow   // Reference to open priority matrix
+	tray           *systemTray     // nil where the platform has no system tray
 	todoFormWindow fyne.Window     // Reference to open todo form window
+	loadErr        error           // Error from the last month load, shown in the timeline
+	corruptNotice  map[string]bool // Damaged files the user was already told about
+
+	lastSync          time.Time // Start of the last folder sync
+	lastCalendarSync  time.Time // Start of the last calendar sync
+	syncConflictsOpen bool      // Conflict dialog is showing
 }
 
 // NewMainWindow creates a new main window
-func NewMainWindow(window fyne.Window, dataManager persistence.TodoRepository, configManager persistence.ConfigRepository) *MainWindow {
+func NewMainWindow(window fyne.Window, dataManager persistence.TodoRepository, configManager persistence.ConfigRepository, pomodoro *services.PomodoroService, api *services.APIServer, syncer *persistence.Syncer, calendar *caldav.Syncer) *MainWindow {
 	mw := &MainWindow{
 		window:        window,
 		dataManager:   dataManager,
+		exporter:      services.NewExportService(dataManager),
 		configManager: configManager,
+		pomodoro:      pomodoro,
+		api:           api,
+		syncer:        syncer,
+		calendar:      calendar,
 		currentDate:   time.Now(), // Start with today
 		viewMode:      models.ViewIncomplete,
 		isGruvbox:     false,
+		corruptNotice: make(map[string]bool),
 	}
 
 	// Load configuration
 	mw.loadConfig()
+	mw.pomodoro.Timer().SetConfig(mw.config.GetActivePomodoroPreset().Config())
+	mw.pomodoro.SetOnTaskPomodoro(func(task models.PomodoroTask) {
+		runOnMainThread(func() {
+			mw.creditPomodoro(task)
+		})
+	})
 
 	// Find and set to latest day with data (if config has no saved date)
 	if mw.config.GetCurrentDate().IsZero() {
@@ -82,12 +120,32 @@ func NewMainWindow(window fyne.Window, dataManager persistence.TodoRepository, c
 	// Reorder callback from timeline (manual up/down or DnD)
 	mw.timeline.SetOnTodoReorder(mw.onTodoReorder)
 	mw.timeline.SetOnReorderFinished(mw.onReorderFinished)
-	mw.timeline.SetOnTodosChanged(func() {
-		mw.loadTodos()
-		mw.refreshView()
+	mw.timeline.SetOnStartPomodoro(mw.onStartPomodoro)
+
+	// Changes to todos from any window, the API, a sync, a Pomodoro or another
+	// program arrive as events; the main window reloads the shown day and
+	// hands it to the timeline
+	mw.dataManager.Events().Subscribe(func(event persistence.TodoEvent) {
+		threading.RunOnMainThread(func() {
+			mw.onTodosChanged(event)
+		})
 	})
 
 	mw.setupUI()
+	addInterruptionShortcut(window, mw.pomodoro, mw.refreshPomodoroWindow)
+	mw.tray = newSystemTray(mw)
+	if mw.tray != nil {
+		window.SetCloseIntercept(mw.onCloseRequested)
+	}
+
+	// API requests share the todo cache with the UI, so they run on its thread
+	mw.api.SetDispatch(threading.RunOnMainThreadAndWait)
+	mw.api.SetOnChanged(mw.refreshPomodoroWindow)
+	if err := mw.api.Configure(mw.config.API); err != nil {
+		fmt.Printf("Failed to configure API: %v\n", err)
+	}
+	mw.startSync()
+	mw.watchTodoFile()
 	mw.loadTodos()
 	mw.refreshView()
 
@@ -100,7 +158,7 @@ func (mw *MainWindow) findAndSetCurrentDateFromDataFile() {
 	if err != nil {
 		return
 	}
-	var latestTime time.Time
+	var latestTime, latestDisplay time.Time
 	for _, dateKey := range months {
 		year, month := utils.ParseDateKey(dateKey)
 		if year == 0 {
@@ -115,12 +173,13 @@ func (mw *MainWindow) findAndSetCurrentDateFromDataFile() {
 		for _, todo := range todos {
 			if todo.TodoTime.After(latestTime) {
 				latestTime = todo.TodoTime
+				latestDisplay = todo.DisplayTime(time.Local)
 			}
 		}
 	}
 
 	if !latestTime.IsZero() {
-		mw.currentDate = latestTime
+		mw.currentDate = latestDisplay
 	}
 }
 
@@ -197,7 +256,9 @@ func (mw *MainWindow) setupUI() {
 		container.NewMax(logoImg),
 	)
 
-	header := container.NewHBox(logoAligned, titleTxt)
+	// Menu button on the right; the tools it opens must not depend on a system tray
+	mw.menuButton = container.NewGridWrap(fyne.NewSize(ButtonHeight, ButtonHeight), RoundedIconButton(theme.MenuIcon(), mw.showToolsMenu))
+	header := container.NewBorder(nil, nil, nil, container.NewCenter(mw.menuButton), container.NewHBox(logoAligned, titleTxt))
 
 	// --- Controls row: [Select] [←] [→] [Add] ---
 	var navBg, navFg color.Color
@@ -259,6 +320,7 @@ func (mw *MainWindow) setupUI() {
 	// Set up timeline with current date and view mode
 	mw.timeline.SetDate(mw.currentDate)
 	mw.timeline.SetViewMode(mw.viewMode)
+	mw.timeline.SetPlannerMode(mw.plannerMode)
 
 	// Create main content tasks container strictly per mockup
 	timelineCard := CreateTasksContainer(mw.timeline)
@@ -327,43 +389,117 @@ func (mw *MainWindow) onThemeToggleClicked() {
 
 // loadTodos loads todos for the current day
 func (mw *MainWindow) loadTodos() {
-	year, month := mw.currentDate.Year(), int(mw.currentDate.Month())
-
-	// Load all todos for the month
-	monthlyTodos, err := mw.dataManager.GetTodosForMonth(year, month)
+	// Load all todos falling on the current local calendar day
+	dailyTodos, err := mw.dataManager.GetTodosForDay(mw.displayDay())
+	mw.loadErr = err
 	if err != nil {
 		fmt.Println(localization.GetStringWithArgs("error_load_failed", err.Error()))
 		mw.todos = []*models.TodoItem{}
+		mw.notifyCorruptFile(err)
 		return
 	}
 
-	// Filter for the current day and view mode
+	// Filter for the view mode
 	currentTime := time.Now()
-	var dailyTodos []*models.TodoItem
-	startOfDay := time.Date(mw.currentDate.Year(), mw.currentDate.Month(), mw.currentDate.Day(), 0, 0, 0, 0, mw.currentDate.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour)
-	for _, todo := range monthlyTodos {
-		if todo.TodoTime.After(startOfDay) && todo.TodoTime.Before(endOfDay) {
-			dailyTodos = append(dailyTodos, todo)
-		}
-	}
 	mw.todos = mw.viewMode.FilterItems(dailyTodos, currentTime)
 
 	// Sort daily todos by implicit Order (if set), then by time (newest first)
 	models.SortTodosByOrder(mw.todos)
 }
 
+// onTodosChanged keeps the todos of the shown day and the tray in step with a
+// change to the todos, wherever it was made
+func (mw *MainWindow) onTodosChanged(event persistence.TodoEvent) {
+	if event.AffectsDay(mw.displayDay()) {
+		mw.loadTodos()
+		mw.timeline.SetLoadError(mw.loadErr)
+		mw.timeline.SetTodos(mw.todos)
+		mw.timeline.Refresh()
+	}
+	if mw.tray != nil && event.AffectsDay(today()) {
+		mw.tray.recount()
+		mw.tray.refresh()
+	}
+}
+
+// displayDay returns the current date as a local calendar day
+func (mw *MainWindow) displayDay() time.Time {
+	return time.Date(mw.currentDate.Year(), mw.currentDate.Month(), mw.currentDate.Day(), 0, 0, 0, 0, time.Local)
+}
+
 // refreshView updates the UI display
 func (mw *MainWindow) refreshView() {
 	// Update timeline data
 	mw.timeline.SetDate(mw.currentDate) // Now passes full time.Time
 	mw.timeline.SetViewMode(mw.viewMode)
+	mw.timeline.SetLoadError(mw.loadErr)
 	mw.timeline.SetTodos(mw.todos)
 	mw.timeline.Refresh()
+
+	// The matrix follows the shown day
+	if mw.matrixWindow != nil {
+		mw.matrixWindow.Refresh()
+	}
+
+	if mw.tray != nil {
+		mw.tray.recount()
+		mw.tray.refresh()
+	}
+}
+
+// notifyCorruptFile offers to repair a damaged monthly file, once per file
+func (mw *MainWindow) notifyCorruptFile(err error) {
+	var corrupt *persistence.CorruptFileError
+	if !errors.As(err, &corrupt) || mw.corruptNotice[corrupt.Path] {
+		return
+	}
+	mw.corruptNotice[corrupt.Path] = true
+
+	// The day view may span two month files; repair the one that failed
+	base := filepath.Base(corrupt.Path)
+	year, month := utils.ParseDateKey(strings.TrimSuffix(base, filepath.Ext(base)))
+	if year == 0 {
+		return
+	}
+
+	message := localization.GetStringWithArgs("corrupt_message",
+		filepath.Base(corrupt.Path), corrupt.Line, corrupt.Reason, corrupt.QuarantinePath)
+	confirm := dialog.NewConfirm(localization.GetString("corrupt_title"), message, func(repair bool) {
+		if repair {
+			mw.repairMonth(year, month)
+		}
+	}, mw.window)
+	confirm.SetConfirmText(localization.GetString("corrupt_button_repair"))
+	confirm.SetDismissText(localization.GetString("corrupt_button_later"))
+	confirm.Show()
+}
+
+// repairMonth salvages the given month and reloads the view
+func (mw *MainWindow) repairMonth(year, month int) {
+	report, err := mw.dataManager.RepairMonth(year, month)
+	if err != nil {
+		dialog.ShowError(err, mw.window)
+		return
+	}
+	delete(mw.corruptNotice, report.Path)
+
+	dialog.ShowInformation(localization.GetString("repair_done_title"),
+		localization.GetStringWithArgs("repair_done_message", report.Salvaged, report.Dropped, report.Normalized, report.Duplicates),
+		mw.window)
 }
 
 // Event handlers
 
+// onCloseRequested hides the window to the tray if the user chose so, and
+// quits otherwise
+func (mw *MainWindow) onCloseRequested() {
+	if mw.config.GetMinimizeToTray() {
+		mw.tray.hideWindow()
+		return
+	}
+	mw.window.Close()
+}
+
 func (mw *MainWindow) onAddButtonClicked() {
 	// If todo form window already exists, flash it instead of opening a new one
 	if mw.todoFormWindow != nil {
@@ -373,11 +509,7 @@ func (mw *MainWindow) onAddButtonClicked() {
 
 	// Pass callback to track when window is created and closed
 	mw.todoForm.ShowCreateWindow(
-		func() {
-			// Refresh the todo list after saving
-			mw.loadTodos()
-			mw.refreshView()
-		},
+		nil,
 		func(win fyne.Window) {
 			// Window created - store reference
 			mw.todoFormWindow = win
@@ -429,11 +561,7 @@ func (mw *MainWindow) onTodoSelected(todo *models.TodoItem, todoTime time.Time)
 	mw.todoForm.ShowEditWindow(
 		todo,
 		todoTime,
-		func() {
-			// Refresh the todo list after saving
-			mw.loadTodos()
-			mw.refreshView()
-		},
+		nil,
 		func(win fyne.Window) {
 			// Window created - store reference
 			mw.todoFormWindow = win
@@ -451,22 +579,12 @@ func (mw *MainWindow) onTodoReorder(todo *models.TodoItem, delta int) {
 		return
 	}
 
-	year, month := mw.currentDate.Year(), int(mw.currentDate.Month())
-	monthlyTodos, err := mw.dataManager.GetTodosForMonth(year, month)
+	// Build full list for current day (includes hidden by filter)
+	dayTodos, err := mw.dataManager.GetTodosForDay(mw.displayDay())
 	if err != nil {
 		return
 	}
 
-	// Build full list for current day (includes hidden by filter)
-	startOfDay := time.Date(mw.currentDate.Year(), mw.currentDate.Month(), mw.currentDate.Day(), 0, 0, 0, 0, mw.currentDate.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour)
-	dayTodos := make([]*models.TodoItem, 0)
-	for _, t := range monthlyTodos {
-		if t.TodoTime.After(startOfDay) && t.TodoTime.Before(endOfDay) {
-			dayTodos = append(dayTodos, t)
-		}
-	}
-
 	// Sort by current visible rule (Order then time desc)
 	models.SortTodosByOrder(dayTodos)
 
@@ -495,19 +613,8 @@ func (mw *MainWindow) onTodoReorder(todo *models.TodoItem, delta int) {
 		return
 	}
 
-	// Move within slice
-	item := dayTodos[idx]
-	if newIdx > idx {
-		copy(dayTodos[idx:], dayTodos[idx+1:newIdx+1])
-	} else {
-		copy(dayTodos[newIdx+1:], dayTodos[newIdx:idx])
-	}
-	dayTodos[newIdx] = item
-
-	// Reassign Order sequentially starting at 1
-	for i, t := range dayTodos {
-		t.Order = i + 1
-	}
+	// Move within slice and reassign Order sequentially starting at 1
+	models.MoveTodo(dayTodos, idx, newIdx)
 
 	// Synchronize Order values to visible todos list
 	// This ensures mw.todos reflects the new order even if it's a filtered subset
@@ -531,12 +638,41 @@ func (mw *MainWindow) onTodoReorder(todo *models.TodoItem, delta int) {
 
 // onReorderFinished persists the updated order once at the end of drag
 func (mw *MainWindow) onReorderFinished() {
-	year, month := mw.currentDate.Year(), int(mw.currentDate.Month())
-	monthlyTodos, err := mw.dataManager.GetTodosForMonth(year, month)
+	dayTodos, err := mw.dataManager.GetTodosForDay(mw.displayDay())
 	if err != nil {
 		return
 	}
-	_ = mw.dataManager.SaveTodosForMonth(year, month, monthlyTodos)
+
+	// Save every month file the day's todos are stored in
+	saved := make(map[string]bool)
+	for _, t := range dayTodos {
+		zoned := t.ZonedTime()
+		year, month := zoned.Year(), int(zoned.Month())
+		dateKey := utils.FormatDateKey(year, month)
+		if saved[dateKey] {
+			continue
+		}
+		saved[dateKey] = true
+
+		monthlyTodos, err := mw.dataManager.GetTodosForMonth(year, month)
+		if err != nil {
+			continue
+		}
+		_ = mw.dataManager.SaveTodosForMonth(year, month, monthlyTodos)
+	}
+}
+
+// onLayoutToggleClicked switches between the todo list and the day planner
+func (mw *MainWindow) onLayoutToggleClicked() {
+	mw.plannerMode = !mw.plannerMode
+	if mw.plannerMode {
+		mw.layoutRectBtn.SetText("List")
+	} else {
+		mw.layoutRectBtn.SetText("Planner")
+	}
+	mw.timeline.SetPlannerMode(mw.plannerMode)
+	mw.refreshView()
+	mw.saveConfig()
 }
 
 // setupBottomButtons creates the bottom button layout with Pomodoro and theme buttons
@@ -569,12 +705,20 @@ func (mw *MainWindow) setupBottomButtons() fyne.CanvasObject {
 	// Create theme button as SimpleRectButton
 	mw.themeRectBtn = NewSimpleRectButton(themeLabel, themeBg, themeFg, fyne.NewSize(100, ButtonHeight), BorderRadius, mw.onThemeToggleClicked)
 
+	// Layout toggle in the middle: label names the layout it switches to
+	layoutLabel := "Planner"
+	if mw.plannerMode {
+		layoutLabel = "List"
+	}
+	mw.layoutRectBtn = NewSimpleRectButton(layoutLabel, themeBg, themeFg, fyne.NewSize(100, ButtonHeight), BorderRadius, mw.onLayoutToggleClicked)
+	mw.matrixRectBtn = NewSimpleRectButton(localization.GetString("matrix_button"), themeBg, themeFg, fyne.NewSize(100, ButtonHeight), BorderRadius, mw.showMatrix)
+
 	// Create bottom button layout: theme on left, pomodoro on right with padding
 	bottomButtons := container.NewBorder(
 		nil, nil,
 		container.NewBorder(nil, nil, helpers.CreateSpacer(25, 1), nil, mw.themeRectBtn),    // 25px left margin
 		container.NewBorder(nil, nil, nil, helpers.CreateSpacer(25, 1), mw.pomodoroRectBtn), // 25px right margin
-		canvas.NewRectangle(color.Transparent),                                      // center placeholder
+		container.NewCenter(container.NewHBox(mw.layoutRectBtn, mw.matrixRectBtn)), // layout toggle and matrix
 	)
 
 	// Place spacer BELOW the buttons to lift them up from the bottom edge
@@ -593,7 +737,10 @@ func (mw *MainWindow) onPomodoroTopClicked() {
 	}
 
 	// Create and show pomodoro window
-	mw.pomodoroWindow = NewPomodoroWindow(fyne.CurrentApp(), mw.isGruvbox)
+	mw.pomodoroWindow = NewPomodoroWindow(fyne.CurrentApp(), mw.pomodoro, mw.isGruvbox, mw.config, mw.saveConfig)
+
+	mw.pomodoroWindow.SetOnConvertInterruption(mw.convertInterruption)
+	mw.pomodoroWindow.SetOnShowStats(mw.showPomodoroStats)
 
 	// Set callback to clear reference when window closes
 	mw.pomodoroWindow.SetOnClosed(func() {
@@ -603,12 +750,142 @@ func (mw *MainWindow) onPomodoroTopClicked() {
 	mw.pomodoroWindow.Show()
 }
 
+// onStartPomodoro starts the timer on todo and shows the Pomodoro window
+func (mw *MainWindow) onStartPomodoro(todo *models.TodoItem) {
+	mw.pomodoro.StartTask(todo)
+	if mw.pomodoroWindow != nil {
+		mw.pomodoroWindow.tick()
+		mw.pomodoroWindow.Show()
+		return
+	}
+	mw.onPomodoroTopClicked()
+}
+
+// showPomodoroStats opens the Pomodoro statistics, or flashes them if open
+func (mw *MainWindow) showPomodoroStats() {
+	if mw.statsWindow != nil {
+		FlashWindow(mw.statsWindow.window)
+		return
+	}
+	mw.statsWindow = NewPomodoroStatsWindow(fyne.CurrentApp(), mw.pomodoro, mw.pomodoroLabel)
+	mw.statsWindow.window.SetOnClosed(func() {
+		mw.statsWindow = nil
+	})
+}
+
+// showMatrix opens the priority matrix of the shown day, or flashes it if open
+func (mw *MainWindow) showMatrix() {
+	if mw.matrixWindow != nil {
+		FlashWindow(mw.matrixWindow.window)
+		return
+	}
+	mw.matrixWindow = NewMatrixWindow(fyne.CurrentApp(), mw.dataManager, mw.displayDay, mw.onTodoSelected)
+	mw.matrixWindow.SetOnClosed(func() {
+		mw.matrixWindow = nil
+	})
+}
+
+// pomodoroLabel returns the label of the todo a session was spent on
+func (mw *MainWindow) pomodoroLabel(task *models.PomodoroTask) string {
+	todo, err := mw.dataManager.GetTodoByTime(task.Time)
+	if err != nil || !task.Matches(todo) {
+		return ""
+	}
+	return todo.Label
+}
+
+// refreshPomodoroWindow redraws the Pomodoro window if it is open
+func (mw *MainWindow) refreshPomodoroWindow() {
+	if mw.pomodoroWindow != nil {
+		mw.pomodoroWindow.tick()
+	}
+}
+
+// convertInterruption opens the add form with the note of an interruption,
+// due at the next full hour
+func (mw *MainWindow) convertInterruption(interruption models.Interruption) {
+	if mw.todoFormWindow != nil {
+		FlashWindow(mw.todoFormWindow)
+		return
+	}
+
+	due := time.Now().Truncate(time.Hour).Add(time.Hour)
+	mw.todoForm.ShowCreateWindowFrom(
+		interruption.Todo(due),
+		func() {
+			mw.pomodoro.MarkInterruptionConverted(interruption.At)
+		},
+		func(win fyne.Window) {
+			mw.todoFormWindow = win
+		},
+		func() {
+			mw.todoFormWindow = nil
+		},
+	)
+}
+
+// creditPomodoro counts a finished work session on its todo and asks what to
+// do once the estimate is used up
+func (mw *MainWindow) creditPomodoro(task models.PomodoroTask) {
+	todo, err := mw.dataManager.GetTodoByTime(task.Time)
+	if err != nil || !task.Matches(todo) {
+		// The todo was deleted or renamed in the meantime
+		return
+	}
+
+	updated := *todo
+	updated.Pomodoros++
+	if err := mw.dataManager.UpdateTodo(&updated, todo.TodoTime); err != nil {
+		dialog.ShowError(err, mw.window)
+		return
+	}
+
+	if updated.EstimateReached() {
+		mw.promptEstimateReached(&updated)
+	}
+}
+
+// promptEstimateReached offers to finish the todo or plan another Pomodoro
+func (mw *MainWindow) promptEstimateReached(todo *models.TodoItem) {
+	message := localization.GetStringWithArgs("estimate_reached_message", todo.Name, todo.Pomodoros, todo.Estimate)
+	content := widget.NewLabel(message)
+	content.Wrapping = fyne.TextWrapWord
+
+	d := dialog.NewCustomWithoutButtons(localization.GetString("estimate_reached_title"), content, mw.window)
+	update := func(change func(*models.TodoItem)) {
+		d.Hide()
+		updated := *todo
+		change(&updated)
+		if err := mw.dataManager.UpdateTodo(&updated, todo.TodoTime); err != nil {
+			dialog.ShowError(err, mw.window)
+		}
+	}
+
+	doneBtn := widget.NewButton(localization.GetString("estimate_button_done"), func() {
+		update(func(t *models.TodoItem) { t.MarkAsDone(true) })
+		if mw.pomodoro.Task().Matches(todo) {
+			mw.pomodoro.ClearTask()
+		}
+	})
+	doneBtn.Importance = widget.HighImportance
+	extendBtn := widget.NewButton(localization.GetString("estimate_button_extend"), func() {
+		update(func(t *models.TodoItem) { t.SetEstimate(t.Estimate + 1) })
+	})
+	laterBtn := widget.NewButton(localization.GetString("estimate_button_later"), d.Hide)
+
+	d.SetButtons([]fyne.CanvasObject{laterBtn, extendBtn, doneBtn})
+	d.Resize(fyne.NewSize(420, 220))
+	d.Show()
+}
+
 // loadConfig loads the application configuration and applies UI state
 func (mw *MainWindow) loadConfig() {
 	config, err := mw.configManager.LoadConfig()
 	if err != nil {
 		fmt.Printf("Failed to load config: %v, using defaults\n", err)
 		config = models.NewDefaultConfig()
+		var newer *persistence.NewerVersionError
+		mw.configLocked = errors.As(err, &newer)
 	}
 	mw.config = config
 
@@ -623,6 +900,7 @@ func (mw *MainWindow) loadConfig() {
 
 	// Apply view mode
 	mw.viewMode = models.ViewModeFromString(config.GetViewMode())
+	mw.plannerMode = config.GetDayLayout() == "planner"
 
 	// Apply current date
 	if !config.GetCurrentDate().IsZero() {
@@ -632,6 +910,10 @@ func (mw *MainWindow) loadConfig() {
 
 // saveConfig saves the current UI state to configuration
 func (mw *MainWindow) saveConfig() {
+	if mw.configLocked {
+		return
+	}
+
 	// Update config with current UI state
 	if mw.isGruvbox {
 		mw.config.SetTheme("dark")
@@ -640,6 +922,11 @@ func (mw *MainWindow) saveConfig() {
 	}
 
 	mw.config.SetViewMode(mw.viewMode.String())
+	if mw.plannerMode {
+		mw.config.SetDayLayout("planner")
+	} else {
+		mw.config.SetDayLayout("list")
+	}
 
 	mw.config.SetCurrentDate(mw.currentDate)
 
@@ -648,3 +935,49 @@ func (mw *MainWindow) saveConfig() {
 		fmt.Printf("Failed to save config: %v\n", err)
 	}
 }
+
+// Raise shows the window, also when it was hidden to the tray, and brings it
+// to the front
+func (mw *MainWindow) Raise() {
+	if mw.tray != nil {
+		mw.tray.showWindow()
+		return
+	}
+	mw.window.Show()
+	mw.window.RequestFocus()
+}
+
+// ShowDay switches the view to the calendar day of day
+func (mw *MainWindow) ShowDay(day time.Time) {
+	mw.currentDate = day
+	mw.loadTodos()
+	mw.refreshView()
+	mw.saveConfig()
+}
+
+// QuickAdd opens the add form with name filled in, due at the next full hour
+// of the shown day
+func (mw *MainWindow) QuickAdd(name string) {
+	if mw.todoFormWindow != nil {
+		FlashWindow(mw.todoFormWindow)
+		return
+	}
+
+	due := time.Now().Truncate(time.Hour).Add(time.Hour)
+	if day := mw.displayDay(); !day.Equal(today()) {
+		due = time.Date(day.Year(), day.Month(), day.Day(), due.Hour(), 0, 0, 0, time.Local)
+	}
+	draft := models.NewTodoItem()
+	draft.SetName(strings.TrimSpace(name))
+	draft.SetTime(due)
+	mw.todoForm.ShowCreateWindowFrom(
+		draft,
+		nil,
+		func(win fyne.Window) {
+			mw.todoFormWindow = win
+		},
+		func() {
+			mw.todoFormWindow = nil
+		},
+	)
+}
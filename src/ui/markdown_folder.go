@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"strings"
+
+	"godo/src/localization"
+	"godo/src/persistence"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showMarkdownFolderSettings lets the user keep each todo as a Markdown note
+// in a folder, such as a notes vault, instead of the month files. The choice
+// replaces a todo.txt file and takes effect at the next start.
+func (mw *MainWindow) showMarkdownFolderSettings() {
+	folderEntry := widget.NewEntry()
+	folderEntry.SetPlaceHolder(localization.GetString("markdown_folder_placeholder"))
+	folderEntry.SetText(mw.config.Markdown.Folder)
+	browseBtn := widget.NewButton(localization.GetString("sync_button_browse"), func() {
+		dialog.ShowFolderOpen(func(uri fyne.ListableURI, err error) {
+			if err == nil && uri != nil {
+				folderEntry.SetText(uri.Path())
+			}
+		}, mw.window)
+	})
+	hint := widget.NewLabel(localization.GetString("markdown_folder_hint"))
+	hint.Wrapping = fyne.TextWrapWord
+
+	form := container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem(localization.GetString("markdown_folder"),
+				container.NewBorder(nil, nil, nil, browseBtn, folderEntry)),
+		),
+		hint,
+	)
+
+	d := dialog.NewCustomWithoutButtons(localization.GetString("markdown_folder_title"), form, mw.window)
+	saveBtn := widget.NewButton(localization.GetString("sync_button_save"), func() {
+		folder := strings.TrimSpace(folderEntry.Text)
+		if folder != "" {
+			if err := persistence.CheckMarkdownFolder(folder); err != nil {
+				dialog.ShowError(err, mw.window)
+				return
+			}
+		}
+
+		d.Hide()
+		if folder == mw.config.Markdown.Folder {
+			return
+		}
+		mw.config.Markdown.Folder = folder
+		if folder != "" {
+			// Todos are kept in one place at a time
+			mw.config.TodoTxt.File = ""
+		}
+		mw.saveConfig()
+		dialog.ShowInformation(localization.GetString("markdown_folder_title"),
+			localization.GetString("todotxt_restart"), mw.window)
+	})
+	saveBtn.Importance = widget.HighImportance
+	cancelBtn := widget.NewButton(localization.GetString("sync_button_cancel"), func() { d.Hide() })
+
+	d.SetButtons([]fyne.CanvasObject{cancelBtn, saveBtn})
+	d.Resize(fyne.NewSize(520, 260))
+	d.Show()
+}
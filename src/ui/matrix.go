@@ -0,0 +1,292 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"godo/src/localization"
+	"godo/src/models"
+	"godo/src/persistence"
+	"godo/src/services"
+	"godo/src/ui/threading"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// matrixDropColor highlights the quadrant a dragged card would land in
+var matrixDropColor = color.NRGBA{R: 0x3C, G: 0x82, B: 0xFF, A: 40}
+
+// MatrixWindow shows the open todos of the chosen day, its week or all of
+// them as an Eisenhower matrix. Dragging a card to another quadrant changes
+// its priority; every quadrant counts its cards and has a quick add entry.
+type MatrixWindow struct {
+	window      fyne.Window
+	matrix      *services.MatrixService
+	day         func() time.Time // Day shown in the main window
+	onSelected  func(*models.TodoItem, time.Time)
+	unsubscribe func()
+
+	scope       services.MatrixScope
+	unscheduled map[models.PriorityLevel]bool
+	quadrants   map[models.PriorityLevel]*canvas.Rectangle // Backgrounds, also the drop targets
+	dropLevel   models.PriorityLevel
+	dropping    bool
+}
+
+// NewMatrixWindow opens the matrix of the todos in dataManager. day returns
+// the day the main window shows; onSelected opens a todo for editing.
+func NewMatrixWindow(app fyne.App, dataManager persistence.TodoRepository, day func() time.Time, onSelected func(*models.TodoItem, time.Time)) *MatrixWindow {
+	mx := &MatrixWindow{
+		window:      app.NewWindow(localization.GetString("matrix_title")),
+		matrix:      services.NewMatrixService(dataManager),
+		day:         day,
+		onSelected:  onSelected,
+		scope:       services.MatrixDay,
+		unscheduled: make(map[models.PriorityLevel]bool),
+	}
+	// Redraw after every change, whether made here or anywhere else
+	mx.unsubscribe = dataManager.Events().Subscribe(func(persistence.TodoEvent) {
+		threading.RunOnMainThread(mx.render)
+	})
+	mx.render()
+	mx.window.Resize(fyne.NewSize(900, 680))
+	mx.window.CenterOnScreen()
+	mx.window.Show()
+	return mx
+}
+
+// SetOnClosed sets the callback run after the window closed
+func (mx *MatrixWindow) SetOnClosed(callback func()) {
+	mx.window.SetOnClosed(func() {
+		if mx.unsubscribe != nil {
+			mx.unsubscribe()
+			mx.unsubscribe = nil
+		}
+		if callback != nil {
+			callback()
+		}
+	})
+}
+
+// Refresh redraws the matrix, for instance after the main window moved to
+// another day
+func (mx *MatrixWindow) Refresh() {
+	mx.render()
+}
+
+// scopeName returns the label of a scope in the scope select
+func scopeName(scope services.MatrixScope) string {
+	switch scope {
+	case services.MatrixWeek:
+		return localization.GetString("matrix_scope_week")
+	case services.MatrixAll:
+		return localization.GetString("matrix_scope_all")
+	default:
+		return localization.GetString("matrix_scope_day")
+	}
+}
+
+// render rebuilds the window for the selected scope
+func (mx *MatrixWindow) render() {
+	board, err := mx.matrix.Load(mx.scope, mx.day())
+	if err != nil {
+		mx.window.SetContent(widget.NewLabel(localization.GetStringWithArgs("status_loading_error", err.Error())))
+		return
+	}
+
+	names := make([]string, len(services.MatrixScopes))
+	for i, scope := range services.MatrixScopes {
+		names[i] = scopeName(scope)
+	}
+	scopeSelect := NewCustomSelect(names, func(name string) {
+		for _, scope := range services.MatrixScopes {
+			if scopeName(scope) == name && scope != mx.scope {
+				mx.scope = scope
+				mx.render()
+			}
+		}
+	})
+	scopeSelect.SetSelected(scopeName(mx.scope))
+	hint := widget.NewLabel(localization.GetStringWithArgs("matrix_hint", mx.day().Format("Mon 02.01.2006")))
+	hint.Wrapping = fyne.TextWrapWord
+
+	mx.quadrants = make(map[models.PriorityLevel]*canvas.Rectangle)
+	cells := make([]fyne.CanvasObject, 0, len(models.MatrixLevels))
+	for _, level := range models.MatrixLevels {
+		cells = append(cells, mx.quadrant(board, level))
+	}
+
+	mx.window.SetContent(container.NewBorder(
+		container.NewVBox(container.NewBorder(nil, nil, nil, scopeSelect, hint)),
+		nil, nil, nil,
+		container.NewGridWithColumns(2, cells...),
+	))
+}
+
+// quadrant builds one quadrant: a heading with the count and the
+// unscheduled toggle, the cards and a quick add entry
+func (mx *MatrixWindow) quadrant(board *services.Matrix, level models.PriorityLevel) fyne.CanvasObject {
+	todos := board.Quadrant(level, mx.unscheduled[level])
+	name := localization.GetString(fmt.Sprintf("matrix_quadrant_%d", level))
+
+	stripe := canvas.NewRectangle(level.GetColor())
+	stripe.SetMinSize(fyne.NewSize(6, 1))
+	title := widget.NewLabelWithStyle(localization.GetStringWithArgs("matrix_heading", name, len(todos)), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	subtitle := widget.NewLabel(localization.GetString(fmt.Sprintf("priority_%d", level)))
+	subtitle.Importance = widget.LowImportance
+	toggle := widget.NewCheck(localization.GetString("matrix_unscheduled_only"), func(checked bool) {
+		mx.unscheduled[level] = checked
+		mx.render()
+	})
+	toggle.SetChecked(mx.unscheduled[level])
+	heading := container.NewBorder(nil, nil, stripe, toggle, container.NewVBox(title, subtitle))
+
+	cards := container.NewVBox()
+	for _, todo := range todos {
+		cards.Add(newMatrixCard(mx, todo))
+	}
+	if len(todos) == 0 {
+		empty := widget.NewLabel(localization.GetString("matrix_empty"))
+		empty.Importance = widget.LowImportance
+		cards.Add(empty)
+	}
+
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder(localization.GetStringWithArgs("matrix_add_placeholder", name))
+	entry.OnSubmitted = func(text string) {
+		if _, err := mx.matrix.QuickAdd(level, text, mx.day()); err != nil {
+			dialog.ShowError(err, mx.window)
+		}
+		// The change event redraws the window with the new card
+	}
+
+	background := canvas.NewRectangle(theme.Color(theme.ColorNameInputBackground))
+	background.CornerRadius = 8
+	mx.quadrants[level] = background
+	content := container.NewBorder(heading, entry, nil, nil, container.NewVScroll(cards))
+	return container.NewPadded(container.NewStack(background, container.NewPadded(content)))
+}
+
+// levelAt returns the quadrant under an absolute position
+func (mx *MatrixWindow) levelAt(pos fyne.Position) (models.PriorityLevel, bool) {
+	driver := fyne.CurrentApp().Driver()
+	for level, background := range mx.quadrants {
+		origin := driver.AbsolutePositionForObject(background)
+		size := background.Size()
+		if pos.X >= origin.X && pos.X < origin.X+size.Width && pos.Y >= origin.Y && pos.Y < origin.Y+size.Height {
+			return level, true
+		}
+	}
+	return 0, false
+}
+
+// highlightDrop marks the quadrant a card dragged to pos would land in
+func (mx *MatrixWindow) highlightDrop(pos fyne.Position) {
+	level, ok := mx.levelAt(pos)
+	if ok == mx.dropping && level == mx.dropLevel {
+		return
+	}
+	mx.dropLevel, mx.dropping = level, ok
+	for l, background := range mx.quadrants {
+		if ok && l == level {
+			background.FillColor = matrixDropColor
+		} else {
+			background.FillColor = theme.Color(theme.ColorNameInputBackground)
+		}
+		background.Refresh()
+	}
+}
+
+// drop moves todo to the quadrant it was dragged to
+func (mx *MatrixWindow) drop(todo *models.TodoItem) {
+	level, ok := mx.dropLevel, mx.dropping
+	mx.dropping = false
+	if !ok {
+		mx.render()
+		return
+	}
+	if err := mx.matrix.Move(todo, level); err != nil {
+		mx.render()
+		dialog.ShowError(err, mx.window)
+		return
+	}
+	if todo.Level == int(level) {
+		// Nothing changed, so no event clears the highlight
+		mx.render()
+	}
+}
+
+// matrixCard is a todo in a quadrant. Tapping opens it; dragging moves it to
+// another quadrant.
+type matrixCard struct {
+	widget.BaseWidget
+	board  *MatrixWindow
+	todo   *models.TodoItem
+	border *canvas.Rectangle
+	body   fyne.CanvasObject
+}
+
+func newMatrixCard(board *MatrixWindow, todo *models.TodoItem) *matrixCard {
+	name := widget.NewLabel(todo.Name)
+	name.Wrapping = fyne.TextWrapWord
+	when := todo.DisplayTime(time.Local)
+	format := "Mon 02.01 15:04"
+	if todo.AllDay {
+		format = "Mon 02.01"
+	}
+	timeText := canvas.NewText(when.Format(format), theme.Color(theme.ColorNamePlaceHolder))
+	timeText.TextSize = 12
+
+	border := canvas.NewRectangle(theme.Color(theme.ColorNameBackground))
+	border.CornerRadius = 6
+	border.StrokeColor = theme.Color(theme.ColorNameSeparator)
+	border.StrokeWidth = 1
+	stripe := canvas.NewRectangle(todo.GetLevelColor())
+	stripe.SetMinSize(fyne.NewSize(4, 1))
+
+	c := &matrixCard{
+		board:  board,
+		todo:   todo,
+		border: border,
+		body:   container.NewStack(border, container.NewBorder(nil, nil, stripe, nil, container.NewVBox(name, container.NewPadded(timeText)))),
+	}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+func (c *matrixCard) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(c.body)
+}
+
+// Cursor shows the card can be picked up
+func (c *matrixCard) Cursor() desktop.Cursor {
+	return desktop.PointerCursor
+}
+
+func (c *matrixCard) Tapped(*fyne.PointEvent) {
+	if c.board.onSelected != nil {
+		c.board.onSelected(c.todo, c.todo.TodoTime)
+	}
+}
+
+// Dragged highlights the card and the quadrant under the pointer
+func (c *matrixCard) Dragged(e *fyne.DragEvent) {
+	if c.border.StrokeWidth != 2 {
+		c.border.StrokeColor = color.NRGBA{R: 0x3C, G: 0x82, B: 0xFF, A: 200}
+		c.border.StrokeWidth = 2
+		c.border.Refresh()
+	}
+	c.board.highlightDrop(e.AbsolutePosition)
+}
+
+// DragEnd moves the todo to the quadrant it was dropped on
+func (c *matrixCard) DragEnd() {
+	c.board.drop(c.todo)
+}
@@ -0,0 +1,104 @@
+//go:build !ci && !android && !ios && !mobile && !wasm && !test_web_driver
+// +build !ci,!android,!ios,!mobile,!wasm,!test_web_driver
+
+package ui
+
+import (
+	"runtime/debug"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// Fyne has no API for window position or stacking, so the compact Pomodoro
+// window reaches through to the GLFW window of the desktop driver. Windows of
+// other drivers are left alone. All functions must run on the main thread.
+//
+// RunWithContext is not part of Fyne's public API. It has been checked against
+// the Fyne release in nativeFyneVersion; with any other release the compact
+// window falls back to a normal window at the position the system picks.
+
+// nativeFyneVersion is the Fyne release whose desktop driver was checked
+const nativeFyneVersion = "v2.6."
+
+var (
+	nativeOnce      sync.Once
+	nativeSupported bool
+)
+
+// nativeWindowsSupported reports whether the app was built with the checked
+// Fyne release
+func nativeWindowsSupported() bool {
+	nativeOnce.Do(func() {
+		info, ok := debug.ReadBuildInfo()
+		if !ok {
+			return
+		}
+		for _, dep := range info.Deps {
+			if dep.Path != "fyne.io/fyne/v2" {
+				continue
+			}
+			version := dep.Version
+			if dep.Replace != nil {
+				version = dep.Replace.Version
+			}
+			nativeSupported = strings.HasPrefix(version, nativeFyneVersion)
+		}
+	})
+	return nativeSupported
+}
+
+// contextRunner is implemented by windows of the desktop GLFW driver
+type contextRunner interface {
+	RunWithContext(f func())
+}
+
+// withNativeWindow calls f with the GLFW window behind w, if there is one
+func withNativeWindow(w fyne.Window, f func(*glfw.Window)) {
+	if !nativeWindowsSupported() {
+		return
+	}
+	runner, ok := w.(contextRunner)
+	if !ok {
+		return
+	}
+	runner.RunWithContext(func() {
+		if native := glfw.GetCurrentContext(); native != nil {
+			f(native)
+		}
+	})
+}
+
+// setWindowFloating keeps w above other windows without decorations, or
+// restores a normal window
+func setWindowFloating(w fyne.Window, floating bool) {
+	withNativeWindow(w, func(native *glfw.Window) {
+		native.SetAttrib(glfw.Floating, glfwBool(floating))
+		native.SetAttrib(glfw.Decorated, glfwBool(!floating))
+	})
+}
+
+// windowPosition returns the screen position of w
+func windowPosition(w fyne.Window) (x, y int, ok bool) {
+	withNativeWindow(w, func(native *glfw.Window) {
+		x, y = native.GetPos()
+		ok = true
+	})
+	return x, y, ok
+}
+
+// moveWindow places w at the given screen position
+func moveWindow(w fyne.Window, x, y int) {
+	withNativeWindow(w, func(native *glfw.Window) {
+		native.SetPos(x, y)
+	})
+}
+
+func glfwBool(b bool) int {
+	if b {
+		return glfw.True
+	}
+	return glfw.False
+}
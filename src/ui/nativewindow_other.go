@@ -0,0 +1,17 @@
+//go:build ci || android || ios || mobile || wasm || test_web_driver
+// +build ci android ios mobile wasm test_web_driver
+
+package ui
+
+import "fyne.io/fyne/v2"
+
+// The software driver used by the ci build, the mobile drivers and the web
+// driver have no GLFW windows to float or move
+
+func setWindowFloating(w fyne.Window, floating bool) {}
+
+func windowPosition(w fyne.Window) (x, y int, ok bool) {
+	return 0, 0, false
+}
+
+func moveWindow(w fyne.Window, x, y int) {}
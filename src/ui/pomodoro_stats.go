@@ -0,0 +1,406 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"godo/src/localization"
+	"godo/src/models"
+	"godo/src/services"
+	"godo/src/ui/helpers"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// focusColor fills bars and hot heatmap cells; it is the "complete" green of the ProgressRing
+var focusColor = helpers.Hex("#a4d868")
+
+// statsRange is a period offered by the statistics window
+type statsRange struct {
+	name string
+	days int
+}
+
+var statsRanges = []statsRange{
+	{"Last 7 days", 7},
+	{"Last 4 weeks", 28},
+	{"Last 12 weeks", 84},
+}
+
+// PomodoroStatsWindow shows analytics over the recorded Pomodoro sessions
+type PomodoroStatsWindow struct {
+	window   fyne.Window
+	pomodoro *services.PomodoroService
+	labelOf  func(*models.PomodoroTask) string
+	days     int
+}
+
+// NewPomodoroStatsWindow opens the statistics of pomodoro. labelOf returns the
+// label of the todo a session was spent on.
+func NewPomodoroStatsWindow(app fyne.App, pomodoro *services.PomodoroService, labelOf func(*models.PomodoroTask) string) *PomodoroStatsWindow {
+	sw := &PomodoroStatsWindow{
+		window:   app.NewWindow(localization.GetString("stats_title")),
+		pomodoro: pomodoro,
+		labelOf:  labelOf,
+		days:     statsRanges[0].days,
+	}
+	sw.render()
+	sw.window.Resize(fyne.NewSize(640, 720))
+	sw.window.CenterOnScreen()
+	sw.window.Show()
+	return sw
+}
+
+// render rebuilds the window for the selected range
+func (sw *PomodoroStatsWindow) render() {
+	palette := currentPomodoroPalette()
+	to := time.Now().AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -sw.days)
+	stats := sw.pomodoro.Stats(from, to, sw.labelOf)
+
+	names := make([]string, len(statsRanges))
+	selected := statsRanges[0].name
+	for i, r := range statsRanges {
+		names[i] = r.name
+		if r.days == sw.days {
+			selected = r.name
+		}
+	}
+	rangeSelect := NewCustomSelect(names, func(name string) {
+		for _, r := range statsRanges {
+			if r.name == name {
+				sw.days = r.days
+			}
+		}
+		sw.render()
+	})
+	rangeSelect.SetSelected(selected)
+	exportBtn := widget.NewButton(localization.GetString("stats_export_csv"), sw.onExportClicked)
+	toolbar := container.NewBorder(nil, nil, nil, exportBtn, rangeSelect)
+
+	summary := widget.NewLabel(localization.GetStringWithArgs("stats_summary",
+		helpers.FormatFocus(stats.Total),
+		stats.Completed, stats.Abandoned, stats.CompletionRate()*100,
+		stats.InterruptionsPerPomodoro()))
+	summary.Wrapping = fyne.TextWrapWord
+
+	// Daily bars for short ranges, weekly bars for long ones
+	periods, format, chartTitle := stats.Days, "02", localization.GetString("stats_per_day")
+	if sw.days > 28 {
+		periods, format, chartTitle = stats.Weeks, "02.01", localization.GetString("stats_per_week")
+	}
+	values := make([]time.Duration, len(periods))
+	labels := make([]string, len(periods))
+	for i, p := range periods {
+		values[i] = p.Focus
+		labels[i] = p.Start.Format(format)
+	}
+	chart := NewFocusBarChart(values, labels, palette.tickBg)
+	heatmap := NewFocusHeatmap(stats.Heatmap, palette.tickBg)
+
+	content := container.NewVBox(
+		toolbar,
+		summary,
+		sectionTitle(chartTitle),
+		chart,
+		sectionTitle(localization.GetString("stats_heatmap")),
+		heatmap,
+		sectionTitle(localization.GetString("stats_by_label")),
+		labelBreakdown(stats.Labels, palette.tickBg),
+	)
+	sw.window.SetContent(container.NewVScroll(container.NewPadded(content)))
+}
+
+// onExportClicked writes all recorded sessions to a CSV file chosen by the user
+func (sw *PomodoroStatsWindow) onExportClicked() {
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, sw.window)
+			return
+		}
+		if writer == nil {
+			return // cancelled
+		}
+		defer writer.Close()
+		if err := sw.pomodoro.ExportCSV(writer, sw.labelOf); err != nil {
+			dialog.ShowError(err, sw.window)
+		}
+	}, sw.window)
+	save.SetFileName("pomodoro-sessions.csv")
+	save.Show()
+}
+
+// sectionTitle is a bold heading above a chart
+func sectionTitle(text string) fyne.CanvasObject {
+	label := widget.NewLabel(text)
+	label.TextStyle = fyne.TextStyle{Bold: true}
+	return label
+}
+
+// labelBreakdown draws one horizontal bar per label, longest first
+func labelBreakdown(labels []models.LabelFocus, bg color.Color) fyne.CanvasObject {
+	if len(labels) == 0 {
+		return widget.NewLabel(localization.GetString("stats_no_sessions"))
+	}
+	max := labels[0].Focus
+	rows := container.NewVBox()
+	for _, l := range labels {
+		name := l.Label
+		if name == "" {
+			name = localization.GetString("stats_no_label")
+		}
+		text := canvas.NewText(fmt.Sprintf("%s · %s", name, helpers.FormatFocus(l.Focus)), helpers.GetForegroundColor())
+		text.TextSize = 13
+		rows.Add(text)
+		rows.Add(NewFocusBarChart([]time.Duration{l.Focus, max}, nil, bg).Horizontal())
+	}
+	return rows
+}
+
+// FocusBarChart draws focused time as bars from the chart's baseline
+type FocusBarChart struct {
+	widget.BaseWidget
+	Values     []time.Duration
+	Labels     []string // one per value, drawn under vertical bars; may be nil
+	BgColor    color.Color
+	horizontal bool // a single bar of Values[0] against Values[1]
+}
+
+// NewFocusBarChart creates a vertical bar chart of values
+func NewFocusBarChart(values []time.Duration, labels []string, bg color.Color) *FocusBarChart {
+	c := &FocusBarChart{Values: values, Labels: labels, BgColor: bg}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+// Horizontal turns the chart into a single progress-style bar showing
+// Values[0] out of Values[1]
+func (c *FocusBarChart) Horizontal() *FocusBarChart {
+	c.horizontal = true
+	return c
+}
+
+func (c *FocusBarChart) MinSize() fyne.Size {
+	if c.horizontal {
+		return fyne.NewSize(200, 10)
+	}
+	return fyne.NewSize(300, 140)
+}
+
+func (c *FocusBarChart) CreateRenderer() fyne.WidgetRenderer {
+	r := &focusBarChartRenderer{chart: c}
+	for i := range c.Values {
+		if c.horizontal && i > 0 {
+			break
+		}
+		track := canvas.NewRectangle(c.BgColor)
+		track.CornerRadius = 2
+		bar := canvas.NewRectangle(focusColor)
+		bar.CornerRadius = 2
+		r.tracks = append(r.tracks, track)
+		r.bars = append(r.bars, bar)
+		r.objs = append(r.objs, track, bar)
+		if !c.horizontal && i < len(c.Labels) {
+			label := canvas.NewText(c.Labels[i], helpers.GetForegroundColor())
+			label.TextSize = 10
+			label.Alignment = fyne.TextAlignCenter
+			r.labels = append(r.labels, label)
+			r.objs = append(r.objs, label)
+		}
+	}
+	return r
+}
+
+type focusBarChartRenderer struct {
+	chart  *FocusBarChart
+	tracks []*canvas.Rectangle
+	bars   []*canvas.Rectangle
+	labels []*canvas.Text
+	objs   []fyne.CanvasObject
+}
+
+// scale returns the value drawn at full length
+func (r *focusBarChartRenderer) scale() time.Duration {
+	var max time.Duration
+	for _, v := range r.chart.Values {
+		if v > max {
+			max = v
+		}
+	}
+	return max
+}
+
+func (r *focusBarChartRenderer) Layout(size fyne.Size) {
+	max := r.scale()
+	fraction := func(v time.Duration) float32 {
+		if max <= 0 {
+			return 0
+		}
+		return float32(float64(v) / float64(max))
+	}
+
+	if r.chart.horizontal {
+		r.tracks[0].Move(fyne.NewPos(0, 0))
+		r.tracks[0].Resize(size)
+		r.bars[0].Move(fyne.NewPos(0, 0))
+		r.bars[0].Resize(fyne.NewSize(size.Width*fraction(r.chart.Values[0]), size.Height))
+		return
+	}
+
+	n := len(r.bars)
+	if n == 0 {
+		return
+	}
+	labelHeight := float32(0)
+	if len(r.labels) > 0 {
+		labelHeight = 16
+	}
+	slot := size.Width / float32(n)
+	gap := slot * 0.2
+	plot := size.Height - labelHeight
+	for i := range r.bars {
+		x := float32(i)*slot + gap/2
+		r.tracks[i].Move(fyne.NewPos(x, 0))
+		r.tracks[i].Resize(fyne.NewSize(slot-gap, plot))
+		h := plot * fraction(r.chart.Values[i])
+		r.bars[i].Move(fyne.NewPos(x, plot-h))
+		r.bars[i].Resize(fyne.NewSize(slot-gap, h))
+		if i < len(r.labels) {
+			// Thin out labels so they do not overlap
+			every := int(28/slot) + 1
+			if i%every != 0 {
+				r.labels[i].Hide()
+			} else {
+				r.labels[i].Show()
+			}
+			r.labels[i].Move(fyne.NewPos(float32(i)*slot, plot+2))
+			r.labels[i].Resize(fyne.NewSize(slot, labelHeight-2))
+		}
+	}
+}
+
+func (r *focusBarChartRenderer) MinSize() fyne.Size {
+	return r.chart.MinSize()
+}
+
+func (r *focusBarChartRenderer) Refresh() {
+	for i := range r.bars {
+		r.tracks[i].FillColor = r.chart.BgColor
+		r.tracks[i].Refresh()
+		r.bars[i].Refresh()
+	}
+	r.Layout(r.chart.Size())
+}
+
+func (r *focusBarChartRenderer) Objects() []fyne.CanvasObject { return r.objs }
+func (r *focusBarChartRenderer) Destroy()                     {}
+
+// FocusHeatmap draws focused time by weekday (rows, Monday first) and hour
+// of day (columns). Cells blend from the background to the focus color.
+type FocusHeatmap struct {
+	widget.BaseWidget
+	Cells   [7][24]time.Duration // indexed by time.Weekday
+	BgColor color.Color
+}
+
+// NewFocusHeatmap creates a heatmap of cells
+func NewFocusHeatmap(cells [7][24]time.Duration, bg color.Color) *FocusHeatmap {
+	h := &FocusHeatmap{Cells: cells, BgColor: bg}
+	h.ExtendBaseWidget(h)
+	return h
+}
+
+func (h *FocusHeatmap) MinSize() fyne.Size {
+	return fyne.NewSize(heatmapLabelWidth+24*12, heatmapHourHeight+7*14)
+}
+
+const (
+	heatmapLabelWidth = 36 // room for the weekday names
+	heatmapHourHeight = 16 // room for the hour marks
+)
+
+// heatmapRows lists the weekdays from top to bottom
+var heatmapRows = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday}
+
+func (h *FocusHeatmap) CreateRenderer() fyne.WidgetRenderer {
+	r := &focusHeatmapRenderer{heatmap: h}
+	for row, day := range heatmapRows {
+		label := canvas.NewText(day.String()[:3], helpers.GetForegroundColor())
+		label.TextSize = 11
+		r.dayLabels = append(r.dayLabels, label)
+		r.objs = append(r.objs, label)
+		for hour := 0; hour < 24; hour++ {
+			cell := canvas.NewRectangle(h.BgColor)
+			cell.CornerRadius = 2
+			r.cells[row][hour] = cell
+			r.objs = append(r.objs, cell)
+		}
+	}
+	for hour := 0; hour < 24; hour += 6 {
+		mark := canvas.NewText(fmt.Sprintf("%02d", hour), helpers.GetForegroundColor())
+		mark.TextSize = 10
+		r.hourMarks = append(r.hourMarks, mark)
+		r.objs = append(r.objs, mark)
+	}
+	r.Refresh()
+	return r
+}
+
+type focusHeatmapRenderer struct {
+	heatmap   *FocusHeatmap
+	cells     [7][24]*canvas.Rectangle
+	dayLabels []*canvas.Text
+	hourMarks []*canvas.Text
+	objs      []fyne.CanvasObject
+}
+
+func (r *focusHeatmapRenderer) Layout(size fyne.Size) {
+	cellW := (size.Width - heatmapLabelWidth) / 24
+	cellH := (size.Height - heatmapHourHeight) / 7
+	for i, mark := range r.hourMarks {
+		mark.Move(fyne.NewPos(heatmapLabelWidth+float32(i*6)*cellW, 0))
+	}
+	for row := range heatmapRows {
+		y := heatmapHourHeight + float32(row)*cellH
+		r.dayLabels[row].Move(fyne.NewPos(0, y))
+		for hour := 0; hour < 24; hour++ {
+			cell := r.cells[row][hour]
+			cell.Move(fyne.NewPos(heatmapLabelWidth+float32(hour)*cellW+1, y+1))
+			cell.Resize(fyne.NewSize(cellW-2, cellH-2))
+		}
+	}
+}
+
+func (r *focusHeatmapRenderer) MinSize() fyne.Size {
+	return r.heatmap.MinSize()
+}
+
+func (r *focusHeatmapRenderer) Refresh() {
+	var peak time.Duration
+	for _, hours := range r.heatmap.Cells {
+		for _, focus := range hours {
+			if focus > peak {
+				peak = focus
+			}
+		}
+	}
+	for row, day := range heatmapRows {
+		for hour := 0; hour < 24; hour++ {
+			t := float32(0)
+			if peak > 0 {
+				t = float32(float64(r.heatmap.Cells[day][hour]) / float64(peak))
+			}
+			r.cells[row][hour].FillColor = helpers.Blend(r.heatmap.BgColor, focusColor, t)
+			r.cells[row][hour].Refresh()
+		}
+	}
+	r.Layout(r.heatmap.Size())
+}
+
+func (r *focusHeatmapRenderer) Objects() []fyne.CanvasObject { return r.objs }
+func (r *focusHeatmapRenderer) Destroy()                     {}
@@ -6,61 +6,96 @@ import (
 	"math"
 	"time"
 
+	"godo/src/localization"
 	"godo/src/models"
+	"godo/src/services"
 	"godo/src/ui/helpers"
 	"godo/src/ui/widgets"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/widget"
 )
 
 // PomodoroWindow represents the Pomodoro timer window
 type PomodoroWindow struct {
 	window    fyne.Window
+	pomodoro  *services.PomodoroService
 	timer     *models.PomodoroTimer
 	config    *models.PomodoroConfig
 	isGruvbox bool
 
+	// Persisted presets; onSettingsChanged saves them
+	settings          *models.Config
+	onSettingsChanged func()
+
+	// Turns the note of a logged interruption into a todo
+	onConvertInterruption func(models.Interruption)
+	// Opens the Pomodoro statistics
+	onShowStats func()
+
 	// UI components
 	timerCanvas    *canvas.Text
 	stateCanvas    *canvas.Text
 	startBtn       *widgets.SimpleRectButton
 	pauseBtn       *widgets.SimpleRectButton
+	skipBtn        *widgets.SimpleRectButton
 	resetBtn       *widgets.SimpleRectButton
+	interruptBtn   *widgets.SimpleRectButton
 	sessionsCanvas *canvas.Text
 	progressRing   *ProgressRing
 
+	// Compact always-on-top layout; the timer keeps running across toggles
+	compact bool
+	miniBtn *widgets.SimpleRectButton // start/pause/resume in the compact layout
+
 	// Configuration inputs
+	presetSelect      *widgets.CustomSelect
+	modeSelect        *widgets.CustomSelect
 	workSpinner       *widgets.NumberSpinner
 	shortBreakSpinner *widgets.NumberSpinner
 	longBreakSpinner  *widgets.NumberSpinner
+	cadenceSpinner    *widgets.NumberSpinner
+	ratioSpinner      *widgets.NumberSpinner
+	sequenceEntry     *widget.Entry
+	classicRows       *fyne.Container // rows shown in the classic mode
+	flowtimeRows      *fyne.Container // rows shown in the Flowtime mode
+	sequenceRows      *fyne.Container // rows shown in the sequence mode
+	applyingPreset    bool            // ignore input callbacks while a preset is loaded
 
 	// Timer animation
 	anim           *fyne.Animation
 	lastUpdate     time.Time
-	isInitializing bool                 // prevent animation on startup
-	timerContainer *fyne.Container      // for hiding/showing digits during animation
-	lastState      models.PomodoroState // track previous state to detect transitions
+	isInitializing bool            // prevent animation on startup
+	timerContainer *fyne.Container // for hiding/showing digits during animation
+	unsubscribe    func()          // detaches from timer events when the window closes
 }
 
-// NewPomodoroWindow creates a new Pomodoro timer window
-func NewPomodoroWindow(app fyne.App, isGruvbox bool) *PomodoroWindow {
-	config := models.NewDefaultPomodoroConfig()
-	timer := models.NewPomodoroTimer(config)
+// NewPomodoroWindow creates a window showing the timer of pomodoro, which keeps
+// running after the window is closed. The last used preset from settings is
+// applied and onSettingsChanged is called after presets change.
+func NewPomodoroWindow(app fyne.App, pomodoro *services.PomodoroService, isGruvbox bool, settings *models.Config, onSettingsChanged func()) *PomodoroWindow {
+	config := settings.GetActivePomodoroPreset().Config()
+	timer := pomodoro.Timer()
+	timer.SetConfig(config)
 
 	pw := &PomodoroWindow{
-		window:         app.NewWindow("Pomodoro Timer"),
-		timer:          timer,
-		config:         config,
-		isGruvbox:      isGruvbox,
-		lastUpdate:     time.Now().Add(-time.Second),
-		isInitializing: true,                // prevent animation on first tick
-		lastState:      models.PomodoroIdle, // track previous state
+		window:            app.NewWindow("Pomodoro Timer"),
+		pomodoro:          pomodoro,
+		timer:             timer,
+		config:            config,
+		isGruvbox:         isGruvbox,
+		settings:          settings,
+		onSettingsChanged: onSettingsChanged,
+		lastUpdate:        time.Now().Add(-time.Second),
+		isInitializing:    true, // prevent animation on first tick
 	}
 
 	pw.setupUI()
+	addInterruptionShortcut(pw.window, pomodoro, pw.tick)
+	pw.unsubscribe = timer.Subscribe(pw.onTimerEvent)
 	pw.startTicker()
 	pw.tick() // initial update
 
@@ -72,28 +107,55 @@ func NewPomodoroWindow(app fyne.App, isGruvbox bool) *PomodoroWindow {
 	return pw
 }
 
-// setupUI initializes the user interface
-func (pw *PomodoroWindow) setupUI() {
-	// Window properties will be set after animation
-	// pw.window.Resize(fyne.NewSize(PomodoroWindowWidth, PomodoroWindowHeight))
-	// pw.window.SetFixedSize(true)
-	// pw.window.CenterOnScreen()
-
-	// Get colors based on theme (match main window)
-	var bgStart, bgEnd color.Color
-	var titleColor color.Color
+// pomodoroPalette holds the theme colors shared by the full and compact layouts
+type pomodoroPalette struct {
+	bgStart, bgEnd color.Color
+	title          color.Color
+	btnBg, btnFg   color.Color
+	tickBg         color.Color
+}
+
+// currentPomodoroPalette returns the colors of the current theme (match main window)
+func currentPomodoroPalette() pomodoroPalette {
+	var p pomodoroPalette
 	currentTheme := fyne.CurrentApp().Settings().Theme()
 	isLightTheme := helpers.IsLightTheme()
-	if gradientTheme, ok := currentTheme.(interface{ GetHeaderGradientColors() (color.Color, color.Color) }); ok {
-		bgStart, bgEnd = gradientTheme.GetHeaderGradientColors()
+	if gradientTheme, ok := currentTheme.(interface {
+		GetHeaderGradientColors() (color.Color, color.Color)
+	}); ok {
+		p.bgStart, p.bgEnd = gradientTheme.GetHeaderGradientColors()
 	} else {
-		bgStart = helpers.GetBackgroundColor()
-		bgEnd = bgStart
+		p.bgStart = helpers.GetBackgroundColor()
+		p.bgEnd = p.bgStart
 	}
-	titleColor = color.White
-	if !isLightTheme {
-		titleColor = helpers.Hex("#fabd2f")
+	if isLightTheme {
+		p.title = color.White
+		p.btnBg = helpers.Hex("#ff8c42")
+		p.btnFg = color.White
+		p.tickBg = color.White
+	} else {
+		p.title = helpers.Hex("#fabd2f")
+		p.btnBg = helpers.Hex("#504945")
+		p.btnFg = helpers.Hex("#fabd2f")
+		p.tickBg = helpers.Hex("#504945")
+	}
+	return p
+}
+
+// buildUI lays out the window in its current mode
+func (pw *PomodoroWindow) buildUI() {
+	if pw.compact {
+		pw.setupCompactUI()
+	} else {
+		pw.setupUI()
 	}
+}
+
+// setupUI initializes the user interface
+func (pw *PomodoroWindow) setupUI() {
+	palette := currentPomodoroPalette()
+	bgStart, bgEnd := palette.bgStart, palette.bgEnd
+	titleColor := palette.title
 
 	// Timer display - big digits, themed color
 	pw.timerCanvas = canvas.NewText("25:00", titleColor)
@@ -109,22 +171,18 @@ func (pw *PomodoroWindow) setupUI() {
 	pw.sessionsCanvas.TextSize = 16
 
 	// Control buttons (match theme button styles from main window)
-	var btnBg, btnFg color.Color
-	if isLightTheme {
-		btnBg = helpers.Hex("#ff8c42")
-		btnFg = color.White
-	} else {
-		btnBg = helpers.Hex("#504945")
-		btnFg = helpers.Hex("#fabd2f")
-	}
+	btnBg, btnFg := palette.btnBg, palette.btnFg
 	pw.startBtn = NewSimpleRectButton("Start", btnBg, btnFg, fyne.NewSize(90, 36), 8, pw.onStartClicked)
 	pw.pauseBtn = NewSimpleRectButton("Pause", btnBg, btnFg, fyne.NewSize(90, 36), 8, pw.onPauseClicked)
 	pw.pauseBtn.Disable()
+	pw.skipBtn = NewSimpleRectButton("Skip", btnBg, btnFg, fyne.NewSize(70, 36), 8, pw.onSkipClicked)
+	pw.skipBtn.Disable()
 	pw.resetBtn = NewSimpleRectButton("Reset", btnBg, btnFg, fyne.NewSize(90, 36), 8, pw.onResetClicked)
 
 	buttonRow := container.NewHBox(
 		pw.startBtn,
 		pw.pauseBtn,
+		pw.skipBtn,
 		pw.resetBtn,
 	)
 
@@ -142,22 +200,56 @@ func (pw *PomodoroWindow) setupUI() {
 	labelLong := canvas.NewText("Long break (min):", titleColor)
 	labelLong.TextSize = 16
 	labelLong.TextStyle = fyne.TextStyle{Bold: true}
+	labelCadence := canvas.NewText("Long break every:", titleColor)
+	labelCadence.TextSize = 16
+	labelCadence.TextStyle = fyne.TextStyle{Bold: true}
+	labelPreset := canvas.NewText("Preset:", titleColor)
+	labelPreset.TextSize = 16
+	labelPreset.TextStyle = fyne.TextStyle{Bold: true}
+	labelMode := canvas.NewText("Mode:", titleColor)
+	labelMode.TextSize = 16
+	labelMode.TextStyle = fyne.TextStyle{Bold: true}
+	labelRatio := canvas.NewText("Focus min per break min:", titleColor)
+	labelRatio.TextSize = 16
+	labelRatio.TextStyle = fyne.TextStyle{Bold: true}
+	labelSequence := canvas.NewText("Sequence (min):", titleColor)
+	labelSequence.TextSize = 16
+	labelSequence.TextStyle = fyne.TextStyle{Bold: true}
 
 	darkText := helpers.Hex("#3c3836")
 	whiteBg := color.White
 
-	pw.workSpinner = NewNumberSpinner(pw.window, pw.config.WorkDuration, 1, 120, 1, darkText, whiteBg, func(v int) {
-		pw.config.WorkDuration = v
-		pw.tick()
+	pw.presetSelect = NewCustomSelect(presetNames(pw.settings), pw.onPresetSelected)
+	pw.presetSelect.SetSelected(pw.settings.GetActivePomodoroPreset().Name)
+	pw.modeSelect = NewCustomSelect(pomodoroModeNames, func(string) {
+		pw.onInputChanged()
 	})
-	pw.shortBreakSpinner = NewNumberSpinner(pw.window, pw.config.ShortBreakDuration, 1, 60, 1, darkText, whiteBg, func(v int) {
-		pw.config.ShortBreakDuration = v
-		pw.tick()
+	pw.modeSelect.SetSelected(pomodoroModeName(pw.config.Mode))
+	pw.workSpinner = NewNumberSpinner(pw.window, pw.config.WorkDuration, 1, 120, 1, darkText, whiteBg, func(int) {
+		pw.onInputChanged()
 	})
-	pw.longBreakSpinner = NewNumberSpinner(pw.window, pw.config.LongBreakDuration, 1, 120, 1, darkText, whiteBg, func(v int) {
-		pw.config.LongBreakDuration = v
-		pw.tick()
+	pw.shortBreakSpinner = NewNumberSpinner(pw.window, pw.config.ShortBreakDuration, 1, 60, 1, darkText, whiteBg, func(int) {
+		pw.onInputChanged()
 	})
+	pw.longBreakSpinner = NewNumberSpinner(pw.window, pw.config.LongBreakDuration, 1, 120, 1, darkText, whiteBg, func(int) {
+		pw.onInputChanged()
+	})
+	pw.cadenceSpinner = NewNumberSpinner(pw.window, pw.config.SessionsUntilLongBreak, 1, 12, 1, darkText, whiteBg, func(int) {
+		pw.onInputChanged()
+	})
+	ratio := pw.config.FlowtimeRatio
+	if ratio <= 0 {
+		ratio = models.DefaultFlowtimeRatio
+	}
+	pw.ratioSpinner = NewNumberSpinner(pw.window, ratio, 1, 12, 1, darkText, whiteBg, func(int) {
+		pw.onInputChanged()
+	})
+	pw.sequenceEntry = widget.NewEntry()
+	pw.sequenceEntry.SetPlaceHolder("90, 20 x3")
+	pw.sequenceEntry.SetText(pw.settings.GetActivePomodoroPreset().Sequence)
+	pw.sequenceEntry.OnChanged = func(string) {
+		pw.onInputChanged()
+	}
 	spinnerVerticalOffset := pw.workSpinner.MinSize().Height * 0.2
 	wrapSpinner := func(spinner *widgets.NumberSpinner) fyne.CanvasObject {
 		return container.NewVBox(
@@ -165,9 +257,7 @@ func (pw *PomodoroWindow) setupUI() {
 			spinner,
 		)
 	}
-	configForm := container.NewVBox(
-		container.NewCenter(cfgHeader),
-		helpers.CreateSpacer(1, 7),
+	pw.classicRows = container.NewVBox(
 		container.NewGridWithColumns(2,
 			labelWork,
 			wrapSpinner(pw.workSpinner),
@@ -180,16 +270,41 @@ func (pw *PomodoroWindow) setupUI() {
 			labelLong,
 			wrapSpinner(pw.longBreakSpinner),
 		),
+		container.NewGridWithColumns(2,
+			labelCadence,
+			wrapSpinner(pw.cadenceSpinner),
+		),
+	)
+	pw.flowtimeRows = container.NewVBox(
+		container.NewGridWithColumns(2,
+			labelRatio,
+			wrapSpinner(pw.ratioSpinner),
+		),
+	)
+	pw.sequenceRows = container.NewVBox(
+		container.NewGridWithColumns(2,
+			labelSequence,
+			pw.sequenceEntry,
+		),
+	)
+	configForm := container.NewVBox(
+		container.NewCenter(cfgHeader),
+		helpers.CreateSpacer(1, 7),
+		container.NewGridWithColumns(2,
+			labelPreset,
+			pw.presetSelect,
+		),
+		container.NewGridWithColumns(2,
+			labelMode,
+			pw.modeSelect,
+		),
+		pw.classicRows,
+		pw.flowtimeRows,
+		pw.sequenceRows,
 	)
+	pw.showModeRows(pw.config.Mode)
 
-	// Progress ring colors
-	var tickBg color.Color
-	if isLightTheme {
-		tickBg = color.White
-	} else {
-		tickBg = helpers.Hex("#504945")
-	}
-	pw.progressRing = NewProgressRing(tickBg)
+	pw.progressRing = NewProgressRing(palette.tickBg)
 
 	// Layout
 	// Wrap timer canvas so we can hide it during animation
@@ -237,9 +352,20 @@ func (pw *PomodoroWindow) setupUI() {
 		helpers.CreateSpacer(1, 20),
 	)
 
+	// Interruption log on the left, compact toggle in the top right corner
+	pw.interruptBtn = NewSimpleRectButton("Interrupt", btnBg, btnFg, fyne.NewSize(90, 28), 8, pw.onInterruptClicked)
+	logBtn := NewSimpleRectButton("Log", btnBg, btnFg, fyne.NewSize(50, 28), 8, pw.onInterruptionLogClicked)
+	statsBtn := NewSimpleRectButton("Stats", btnBg, btnFg, fyne.NewSize(60, 28), 8, pw.onStatsClicked)
+	compactBtn := NewSimpleRectButton("Mini", btnBg, btnFg, fyne.NewSize(60, 28), 8, pw.ToggleCompact)
+	header := container.NewVBox(
+		helpers.CreateSpacer(1, 10),
+		container.NewHBox(pw.interruptBtn, logBtn, statsBtn, layout.NewSpacer(), compactBtn),
+		helpers.CreateSpacer(1, 12),
+	)
+
 	// Wrap in padding (24px left/right, 10px top)
 	paddedContent := container.NewBorder(
-		helpers.CreateSpacer(1, 50), nil,
+		header, nil,
 		helpers.CreateSpacer(ButtonPadding, 1),
 		helpers.CreateSpacer(ButtonPadding, 1),
 		content,
@@ -256,6 +382,130 @@ func (pw *PomodoroWindow) setupUI() {
 	pw.window.Show()
 }
 
+// setupCompactUI shows only the ring, the remaining time and a start/pause
+// button in a small undecorated window that stays above other windows
+func (pw *PomodoroWindow) setupCompactUI() {
+	palette := currentPomodoroPalette()
+
+	pw.timerCanvas = canvas.NewText("25:00", palette.title)
+	pw.timerCanvas.TextStyle = fyne.TextStyle{Bold: true}
+	pw.timerCanvas.TextSize = 20
+
+	pw.progressRing = NewProgressRing(palette.tickBg)
+	pw.progressRing.Diameter = 56
+	pw.progressRing.SegLength = 10
+	pw.progressRing.StrokeWidth = 3
+
+	// Borderless windows have no title bar, so the ring doubles as a drag handle
+	pw.timerContainer = container.NewCenter(pw.timerCanvas)
+	ring := container.NewGridWrap(fyne.NewSize(110, 110), container.NewMax(
+		container.NewCenter(pw.progressRing),
+		pw.timerContainer,
+		newWindowDragHandle(pw.window, pw.rememberPosition),
+	))
+
+	pw.miniBtn = NewSimpleRectButton("Start", palette.btnBg, palette.btnFg, fyne.NewSize(70, 28), 8, pw.onMiniClicked)
+	fullBtn := NewSimpleRectButton("Full", palette.btnBg, palette.btnFg, fyne.NewSize(50, 28), 8, pw.ToggleCompact)
+
+	content := container.NewVBox(
+		helpers.CreateSpacer(1, 8),
+		container.NewCenter(ring),
+		helpers.CreateSpacer(1, 6),
+		container.NewCenter(container.NewHBox(pw.miniBtn, fullBtn)),
+	)
+	background := NewGradientRect(palette.bgStart, palette.bgEnd, 0)
+
+	pw.window.SetContent(container.NewMax(background, content))
+	pw.window.SetFixedSize(true)
+	pw.window.Resize(fyne.NewSize(PomodoroMiniWidth, PomodoroMiniHeight))
+	pw.window.Show()
+	setWindowFloating(pw.window, true)
+	if pos := pw.settings.Pomodoro.MiniPosition; pos != nil {
+		moveWindow(pw.window, pos.X, pos.Y)
+	}
+}
+
+// ToggleCompact switches between the full and the compact layout. The timer
+// is not touched; only the widgets showing it are rebuilt.
+func (pw *PomodoroWindow) ToggleCompact() {
+	if pw.compact {
+		pw.rememberPosition()
+		setWindowFloating(pw.window, false)
+	}
+	pw.compact = !pw.compact
+	pw.buildUI()
+	pw.tick()
+}
+
+// rememberPosition stores where the compact window is, so it opens there again
+func (pw *PomodoroWindow) rememberPosition() {
+	x, y, ok := windowPosition(pw.window)
+	if !ok {
+		return
+	}
+	pos := &models.WindowPosition{X: x, Y: y}
+	if old := pw.settings.Pomodoro.MiniPosition; old != nil && *old == *pos {
+		return
+	}
+	pw.settings.Pomodoro.MiniPosition = pos
+	if pw.onSettingsChanged != nil {
+		pw.onSettingsChanged()
+	}
+}
+
+// onMiniClicked starts, pauses or resumes the timer with one click
+func (pw *PomodoroWindow) onMiniClicked() {
+	switch pw.timer.Snapshot().State {
+	case models.PomodoroIdle:
+		pw.timer.Start()
+	case models.PomodoroPaused:
+		pw.timer.Resume()
+	default:
+		pw.timer.Pause()
+	}
+	pw.tick()
+}
+
+// windowDragHandle moves its window when dragged. It keeps the point that
+// was grabbed under the pointer, so the window follows the pointer exactly.
+type windowDragHandle struct {
+	widget.BaseWidget
+	window  fyne.Window
+	grab    *fyne.Position
+	onMoved func()
+}
+
+func newWindowDragHandle(window fyne.Window, onMoved func()) *windowDragHandle {
+	h := &windowDragHandle{window: window, onMoved: onMoved}
+	h.ExtendBaseWidget(h)
+	return h
+}
+
+func (h *windowDragHandle) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(canvas.NewRectangle(color.Transparent))
+}
+
+func (h *windowDragHandle) Dragged(e *fyne.DragEvent) {
+	if h.grab == nil {
+		start := e.AbsolutePosition.Subtract(e.Dragged)
+		h.grab = &start
+	}
+	x, y, ok := windowPosition(h.window)
+	if !ok {
+		return
+	}
+	offset := e.AbsolutePosition.Subtract(*h.grab)
+	scale := h.window.Canvas().Scale()
+	moveWindow(h.window, x+int(offset.X*scale), y+int(offset.Y*scale))
+}
+
+func (h *windowDragHandle) DragEnd() {
+	h.grab = nil
+	if h.onMoved != nil {
+		h.onMoved()
+	}
+}
+
 // startTicker starts the timer update ticker
 func (pw *PomodoroWindow) startTicker() {
 	pw.anim = fyne.NewAnimation(time.Second, func(_ float32) {
@@ -277,96 +527,60 @@ func (pw *PomodoroWindow) stopTicker() {
 	}
 }
 
+// tick renders the current timer status; transitions are handled in onTimerEvent
 func (pw *PomodoroWindow) tick() {
-	pw.timer.Update()
+	status := pw.timer.Snapshot()
 
-	minutes := int(pw.timer.TimeRemaining.Minutes())
-	seconds := int(pw.timer.TimeRemaining.Seconds()) % 60
-
-	timerText := ""
-	if pw.timer.State == models.PomodoroIdle {
-		timerText = fmt.Sprintf("%02d:00", pw.config.WorkDuration)
-	} else {
-		timerText = fmt.Sprintf("%02d:%02d", minutes, seconds)
+	// Flowtime work counts up; everything else counts down
+	shown := status.Remaining
+	if status.OpenEnded {
+		shown = status.Elapsed
 	}
-
-	stateText := pw.timer.GetStateString()
-	sessionsText := fmt.Sprintf("Sessions: %d", pw.timer.SessionsCompleted)
-
-	pw.timerCanvas.Text = timerText
+	minutes := int(shown.Minutes())
+	seconds := int(shown.Seconds()) % 60
+	pw.timerCanvas.Text = fmt.Sprintf("%02d:%02d", minutes, seconds)
 	pw.timerCanvas.Refresh()
+
+	stateText := status.State.String()
+	if status.State == models.PomodoroPaused {
+		stateText = fmt.Sprintf("%s (%s)", stateText, status.PausedFrom)
+	} else if status.State == models.PomodoroIdle && status.Next.IsBreak() {
+		stateText = fmt.Sprintf("Next: %s", status.Next)
+	}
+	if status.Steps > 0 {
+		stateText = fmt.Sprintf("%s · %d/%d", stateText, status.Step+1, status.Steps)
+	}
 	pw.stateCanvas.Text = stateText
 	pw.stateCanvas.Refresh()
+	sessionsText := fmt.Sprintf("Sessions: %d", status.Sessions)
+	if task := pw.pomodoro.Task(); task != nil {
+		sessionsText += " · " + localization.GetStringWithArgs("pomodoro_working_on", task.Name)
+	}
 	pw.sessionsCanvas.Text = sessionsText
 	pw.sessionsCanvas.Refresh()
 
-	// Update progress ring (elapsed fraction, fills clockwise from left)
-	var prog float32 = 0
-	total := pw.timer.GetCurrentDuration()
-	// Handle paused/unknown state by estimating total from config
-	if (pw.timer.State == models.PomodoroPaused || total == 0) && pw.timer.TimeRemaining > 0 {
-		wrk := time.Duration(pw.config.WorkDuration) * time.Minute
-		sbr := time.Duration(pw.config.ShortBreakDuration) * time.Minute
-		lbr := time.Duration(pw.config.LongBreakDuration) * time.Minute
-		// choose the smallest duration that is >= remaining as a plausible total
-		candidates := []time.Duration{wrk, sbr, lbr}
-		var best time.Duration
-		for _, d := range candidates {
-			if d >= pw.timer.TimeRemaining && (best == 0 || d < best) {
-				best = d
-			}
-		}
-		if best == 0 {
-			best = wrk
-		}
-		total = best
-	}
-	if pw.timer.State != models.PomodoroIdle && total > 0 {
-		// Calculate elapsed fraction instead of remaining
-		elapsed := total - pw.timer.TimeRemaining
-		prog = float32(elapsed.Seconds() / total.Seconds())
-		if prog < 0 {
-			prog = 0
-		}
-		if prog > 1 {
-			prog = 1
-		}
-	}
+	// Progress ring shows the elapsed fraction, filling clockwise from left
 	if pw.progressRing != nil {
-		pw.progressRing.SetProgress(prog)
-
-		// Detect transitions that mean a period just finished:
-		// - Work -> Short/Long break
-		// - Short/Long break -> Idle
-		isWorkTransition := pw.lastState == models.PomodoroWork &&
-			(pw.timer.State == models.PomodoroShortBreak || pw.timer.State == models.PomodoroLongBreak)
-		isBreakTransition := (pw.lastState == models.PomodoroShortBreak || pw.lastState == models.PomodoroLongBreak) &&
-			pw.timer.State == models.PomodoroIdle
-		periodCompleted := (isWorkTransition || isBreakTransition)
-
-		// Update lastState for next tick
-		pw.lastState = pw.timer.State
-
-		// Trigger animation when period completes
-		if periodCompleted && !pw.isInitializing {
-			// Play completion animation (on main thread)
-			// Hide timer digits during animation
-			pw.timerContainer.Hide()
-			pw.progressRing.PlayCompletionAnimation()
-			// Show digits again after animation (now 2.5 seconds for longer animation)
-			time.AfterFunc(2500*time.Millisecond, func() {
-				pw.timerContainer.Show()
-				pw.timerContainer.Refresh()
-			})
-		}
+		pw.progressRing.SetCountUp(status.OpenEnded)
+		pw.progressRing.SetProgress(status.Progress())
 	}
 
-	// Update button states
-	switch pw.timer.State {
+	// Update button states; during Flowtime work Skip becomes Break
+	breakSkippable := status.Interval().IsBreak() || status.OpenEnded
+	if status.OpenEnded {
+		pw.skipBtn.SetText("Break")
+	} else {
+		pw.skipBtn.SetText("Skip")
+	}
+	switch status.State {
 	case models.PomodoroIdle:
 		pw.startBtn.Enable()
 		pw.pauseBtn.Disable()
-		pw.resetBtn.Disable()
+		if status.Sessions > 0 {
+			pw.resetBtn.Enable()
+		} else {
+			pw.resetBtn.Disable()
+		}
 		pw.startBtn.SetText("Start")
 	case models.PomodoroWork, models.PomodoroShortBreak, models.PomodoroLongBreak:
 		pw.startBtn.Disable()
@@ -380,6 +594,49 @@ func (pw *PomodoroWindow) tick() {
 		pw.resetBtn.Enable()
 		pw.pauseBtn.SetText("Resume")
 	}
+	if breakSkippable {
+		pw.skipBtn.Enable()
+	} else {
+		pw.skipBtn.Disable()
+	}
+	if status.InWork() {
+		pw.interruptBtn.Enable()
+	} else {
+		pw.interruptBtn.Disable()
+	}
+
+	if pw.compact && pw.miniBtn != nil {
+		switch status.State {
+		case models.PomodoroIdle:
+			pw.miniBtn.SetText("Start")
+		case models.PomodoroPaused:
+			pw.miniBtn.SetText("Resume")
+		default:
+			pw.miniBtn.SetText("Pause")
+		}
+	}
+}
+
+// onTimerEvent plays the completion animation when an interval finishes
+func (pw *PomodoroWindow) onTimerEvent(event models.PomodoroEvent) {
+	if event.Type != models.EventWorkFinished && event.Type != models.EventBreakFinished {
+		return
+	}
+	if pw.isInitializing || pw.progressRing == nil {
+		return
+	}
+	runOnMainThread(func() {
+		// Hide timer digits during the animation
+		pw.timerContainer.Hide()
+		pw.progressRing.PlayCompletionAnimation()
+		// Show digits again after the 2.5 second animation
+		time.AfterFunc(2500*time.Millisecond, func() {
+			runOnMainThread(func() {
+				pw.timerContainer.Show()
+				pw.timerContainer.Refresh()
+			})
+		})
+	})
 }
 
 // ProgressRing renders a circular segmented progress indicator.
@@ -394,6 +651,8 @@ type ProgressRing struct {
 	InnerRatio     float32     // inner radius ratio relative to half of min(size)
 	SegLength      float32     // length of each radial segment in px
 	StrokeWidth    float32     // thickness of each segment
+	CountUp        bool        // open-ended interval: laps drawn in a single color
+	Diameter       float32     // minimum size; 0 uses ProgressRingSize
 	IsCompleting   bool        // true when showing completion animation
 	CompletionAnim float32     // 0..1 animation progress for completion
 }
@@ -402,7 +661,7 @@ func NewProgressRing(bg color.Color) *ProgressRing {
 	pr := &ProgressRing{
 		Progress:    0,
 		Segments:    60,
-		StartAngle:  math.Pi,        // start from left side
+		StartAngle:  math.Pi,                // start from left side
 		StartColor:  helpers.Hex("#d65c5c"), // Red-orange - start (0% progress, warning state)
 		EndColor:    helpers.Hex("#a4d868"), // Bright vibrant green - end (100% progress, complete)
 		BgColor:     bg,
@@ -428,6 +687,16 @@ func (pr *ProgressRing) SetProgress(p float32) {
 	pr.Refresh()
 }
 
+// SetCountUp switches between the countdown gradient and the single-color
+// laps used for open-ended intervals
+func (pr *ProgressRing) SetCountUp(countUp bool) {
+	if pr.CountUp == countUp {
+		return
+	}
+	pr.CountUp = countUp
+	pr.Refresh()
+}
+
 // PlayCompletionAnimation starts the green flash + checkmark animation
 func (pr *ProgressRing) PlayCompletionAnimation() {
 	pr.IsCompleting = true
@@ -456,7 +725,11 @@ func (pr *ProgressRing) PlayCompletionAnimation() {
 }
 
 func (pr *ProgressRing) MinSize() fyne.Size {
-	return fyne.NewSize(200, 200)
+	size := pr.Diameter
+	if size <= 0 {
+		size = ProgressRingSize
+	}
+	return fyne.NewSize(size, size)
 }
 
 func (pr *ProgressRing) CreateRenderer() fyne.WidgetRenderer {
@@ -599,7 +872,10 @@ func (r *progressRingRenderer) Refresh() {
 		er, eg, eb, _ := r.ring.EndColor.RGBA()
 
 		for i := 0; i < r.ring.Segments; i++ {
-			if i < filled {
+			if i < filled && r.ring.CountUp {
+				// Laps have no start or end, so no gradient
+				r.lines[i].StrokeColor = r.ring.EndColor
+			} else if i < filled {
 				// Calculate gradient color for this segment
 				t := float32(i) / float32(r.ring.Segments)
 				nr := uint8((float32(sr>>8)*(1-t) + float32(er>>8)*t))
@@ -631,16 +907,12 @@ func (r *progressRingRenderer) Destroy()                             {}
 
 // Event handlers
 func (pw *PomodoroWindow) onStartClicked() {
-	if pw.timer.State == models.PomodoroPaused {
-		pw.timer.Resume()
-	} else {
-		pw.timer.Start()
-	}
+	pw.timer.Start()
 	pw.tick()
 }
 
 func (pw *PomodoroWindow) onPauseClicked() {
-	if pw.timer.State == models.PomodoroPaused {
+	if pw.timer.Snapshot().State == models.PomodoroPaused {
 		pw.timer.Resume()
 	} else {
 		pw.timer.Pause()
@@ -648,22 +920,182 @@ func (pw *PomodoroWindow) onPauseClicked() {
 	pw.tick()
 }
 
+func (pw *PomodoroWindow) onSkipClicked() {
+	if pw.timer.Snapshot().OpenEnded {
+		pw.timer.FinishWork()
+	} else {
+		pw.timer.SkipBreak()
+	}
+	pw.tick()
+}
+
+func (pw *PomodoroWindow) onInterruptClicked() {
+	showInterruptionDialog(pw.window, pw.pomodoro, pw.tick)
+}
+
+func (pw *PomodoroWindow) onInterruptionLogClicked() {
+	showInterruptionLog(pw.window, pw.pomodoro, time.Now(), pw.onConvertInterruption)
+}
+
+func (pw *PomodoroWindow) onStatsClicked() {
+	if pw.onShowStats != nil {
+		pw.onShowStats()
+	}
+}
+
 func (pw *PomodoroWindow) onResetClicked() {
 	pw.timer.Reset()
 	pw.tick()
 }
 
-// (config change handled by spinners' callbacks)
+// presetNames returns the names shown in the preset picker
+func presetNames(settings *models.Config) []string {
+	presets := settings.GetPomodoroPresets()
+	names := make([]string, len(presets))
+	for i, p := range presets {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// onPresetSelected switches the timer to the chosen preset
+func (pw *PomodoroWindow) onPresetSelected(name string) {
+	for _, preset := range pw.settings.GetPomodoroPresets() {
+		if preset.Name == name {
+			pw.applyPreset(preset)
+			return
+		}
+	}
+}
+
+// pomodoroModeNames are the entries of the mode picker, in PomodoroMode order
+var pomodoroModeNames = []string{"Classic", "Flowtime", "Sequence"}
+
+var pomodoroModes = []models.PomodoroMode{models.ModeClassic, models.ModeFlowtime, models.ModeSequence}
+
+// pomodoroModeName returns the picker entry for mode
+func pomodoroModeName(mode models.PomodoroMode) string {
+	for i, m := range pomodoroModes {
+		if m == mode {
+			return pomodoroModeNames[i]
+		}
+	}
+	return pomodoroModeNames[0]
+}
+
+// selectedMode returns the mode chosen in the picker
+func (pw *PomodoroWindow) selectedMode() models.PomodoroMode {
+	for i, name := range pomodoroModeNames {
+		if name == pw.modeSelect.Selected {
+			return pomodoroModes[i]
+		}
+	}
+	return models.ModeClassic
+}
+
+// showModeRows shows only the inputs used by mode
+func (pw *PomodoroWindow) showModeRows(mode models.PomodoroMode) {
+	rows := map[models.PomodoroMode]*fyne.Container{
+		models.ModeClassic:  pw.classicRows,
+		models.ModeFlowtime: pw.flowtimeRows,
+		models.ModeSequence: pw.sequenceRows,
+	}
+	if _, ok := rows[mode]; !ok {
+		mode = models.ModeClassic
+	}
+	for m, row := range rows {
+		if m == mode {
+			row.Show()
+		} else {
+			row.Hide()
+		}
+	}
+}
+
+// onInputChanged stores edited settings. Built-in presets stay fixed, so
+// editing one saves the values as the custom preset instead. A sequence that
+// does not parse yet is left alone until the user finishes typing it.
+func (pw *PomodoroWindow) onInputChanged() {
+	if pw.applyingPreset {
+		return
+	}
+	preset := models.PomodoroPreset{
+		Name:                   pw.settings.GetActivePomodoroPreset().Name,
+		WorkDuration:           pw.workSpinner.Value,
+		ShortBreakDuration:     pw.shortBreakSpinner.Value,
+		LongBreakDuration:      pw.longBreakSpinner.Value,
+		SessionsUntilLongBreak: pw.cadenceSpinner.Value,
+		Mode:                   pw.selectedMode(),
+		FlowtimeRatio:          pw.ratioSpinner.Value,
+		Sequence:               pw.sequenceEntry.Text,
+	}
+	pw.showModeRows(preset.Mode)
+	if preset.Mode == models.ModeSequence {
+		if _, _, err := models.ParsePomodoroSequence(preset.Sequence); err != nil {
+			return
+		}
+	}
+	if models.IsBuiltinPreset(preset.Name) {
+		preset.Name = models.CustomPresetName
+	}
+	pw.settings.SavePomodoroPreset(preset)
+	pw.applyPreset(preset)
+}
+
+// applyPreset makes preset the active one, updates the inputs and saves the
+// settings. The running interval keeps its length; the next one uses the preset.
+func (pw *PomodoroWindow) applyPreset(preset models.PomodoroPreset) {
+	pw.settings.SetActivePomodoroPreset(preset.Name)
+	pw.config = preset.Config()
+	pw.timer.SetConfig(pw.config)
+
+	pw.applyingPreset = true
+	pw.workSpinner.SetValue(preset.WorkDuration)
+	pw.shortBreakSpinner.SetValue(preset.ShortBreakDuration)
+	pw.longBreakSpinner.SetValue(preset.LongBreakDuration)
+	pw.cadenceSpinner.SetValue(preset.SessionsUntilLongBreak)
+	pw.modeSelect.SetSelected(pomodoroModeName(preset.Mode))
+	if preset.FlowtimeRatio > 0 {
+		pw.ratioSpinner.SetValue(preset.FlowtimeRatio)
+	}
+	if pw.sequenceEntry.Text != preset.Sequence {
+		pw.sequenceEntry.SetText(preset.Sequence)
+	}
+	pw.applyingPreset = false
+	pw.showModeRows(preset.Mode)
+
+	pw.presetSelect.Options = presetNames(pw.settings)
+	pw.presetSelect.SetSelected(preset.Name)
+
+	if pw.onSettingsChanged != nil {
+		pw.onSettingsChanged()
+	}
+	pw.tick()
+}
 
 // Show displays the window
 func (pw *PomodoroWindow) Show() {
 	pw.window.Show()
 }
 
+// SetOnConvertInterruption sets the callback that turns an interruption note into a todo
+func (pw *PomodoroWindow) SetOnConvertInterruption(callback func(models.Interruption)) {
+	pw.onConvertInterruption = callback
+}
+
+// SetOnShowStats sets the callback that opens the Pomodoro statistics
+func (pw *PomodoroWindow) SetOnShowStats(callback func()) {
+	pw.onShowStats = callback
+}
+
 // SetOnClosed sets the callback for when the window is closed
 func (pw *PomodoroWindow) SetOnClosed(callback func()) {
 	pw.window.SetOnClosed(func() {
 		pw.stopTicker()
+		if pw.unsubscribe != nil {
+			pw.unsubscribe()
+			pw.unsubscribe = nil
+		}
 		if callback != nil {
 			callback()
 		}
@@ -676,7 +1108,7 @@ func (pw *PomodoroWindow) UpdateTheme(isGruvbox bool) {
 
 	// Update the app theme first (should already be done by MainWindow)
 	// Then recreate the UI with new colors
-	pw.setupUI()
+	pw.buildUI()
 
 	// Preserve timer state and refresh display
 	pw.tick()
@@ -0,0 +1,265 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"godo/src/localization"
+	"godo/src/models"
+	"godo/src/persistence"
+	"godo/src/ui/threading"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// startSync points the syncers at the configured folder and calendar server,
+// and syncs at start and then every configured interval
+func (mw *MainWindow) startSync() {
+	if err := mw.syncer.SetRemoteDir(mw.config.Sync.RemoteDir); err != nil {
+		fmt.Printf("Failed to configure sync: %v\n", err)
+	}
+	// Calendar syncs run on their own goroutine and share the todo cache with the UI
+	mw.calendar.SetDispatch(threading.RunOnMainThreadAndWait)
+	if err := mw.calendar.Configure(mw.config.CalDAV); err != nil {
+		fmt.Printf("Failed to configure calendar sync: %v\n", err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for {
+			runOnMainThread(mw.autoSync)
+			<-ticker.C
+		}
+	}()
+}
+
+// autoSync runs each configured sync once at start and then whenever its
+// interval has passed
+func (mw *MainWindow) autoSync() {
+	if mw.syncer.HasRemote() && syncDue(mw.lastSync, mw.config.Sync.Interval) {
+		mw.runSync(nil, false)
+	}
+	if mw.calendar.HasServer() && syncDue(mw.lastCalendarSync, mw.config.CalDAV.Interval) {
+		mw.runCalendarSync(nil, false)
+	}
+}
+
+// syncDue reports whether a sync last run at last is due again; an interval
+// of 0 minutes syncs only once
+func syncDue(last time.Time, minutes int) bool {
+	if last.IsZero() {
+		return true
+	}
+	return minutes > 0 && time.Since(last) >= time.Duration(minutes)*time.Minute
+}
+
+// syncNow runs every configured sync, or opens the sync settings if there is
+// none
+func (mw *MainWindow) syncNow() {
+	if !mw.syncer.HasRemote() && !mw.calendar.HasServer() {
+		mw.showSyncSettings()
+		return
+	}
+	if mw.syncer.HasRemote() {
+		mw.runSync(nil, true)
+	}
+	if mw.calendar.HasServer() {
+		mw.runCalendarSync(nil, true)
+	}
+}
+
+// runSync syncs with the sync folder, applying the decisions in resolved
+func (mw *MainWindow) runSync(resolved []*persistence.SyncConflict, interactive bool) {
+	mw.lastSync = time.Now()
+	report, err := mw.syncer.Sync(resolved)
+	mw.finishSync(report, err, interactive, localization.GetString("sync_conflict_remote"), mw.runSync)
+}
+
+// runCalendarSync syncs with the calendar server, applying the decisions in
+// resolved. It runs on a goroutine so a slow server does not block the UI.
+func (mw *MainWindow) runCalendarSync(resolved []*persistence.SyncConflict, interactive bool) {
+	mw.lastCalendarSync = time.Now()
+	go func() {
+		report, err := mw.calendar.Sync(resolved)
+		runOnMainThread(func() {
+			mw.finishSync(report, err, interactive, localization.GetString("sync_conflict_calendar"), mw.runCalendarSync)
+		})
+	}()
+}
+
+// finishSync asks about any conflicts left after a sync; retry runs the same
+// sync again with the user's decisions. Changed todos reach the view as
+// events. Only interactive runs report success and errors in a dialog.
+func (mw *MainWindow) finishSync(report *persistence.SyncReport, err error, interactive bool, remoteLabel string,
+	retry func([]*persistence.SyncConflict, bool)) {
+	if err != nil {
+		if interactive {
+			dialog.ShowError(err, mw.window)
+		} else {
+			fmt.Printf("Failed to sync: %v\n", err)
+		}
+		return
+	}
+	for _, failure := range report.Failed {
+		fmt.Printf("Failed to sync %s\n", failure)
+	}
+
+	if len(report.Conflicts) > 0 {
+		mw.showSyncConflicts(report.Conflicts, remoteLabel, func(decided []*persistence.SyncConflict) {
+			retry(decided, true)
+		})
+		return
+	}
+	if interactive {
+		dialog.ShowInformation(localization.GetString("sync_done_title"),
+			localization.GetStringWithArgs("sync_done_message", report.Pulled, report.Pushed, len(report.Failed)),
+			mw.window)
+	}
+}
+
+// showSyncConflicts lets the user pick a version of each todo changed both
+// here and on the remote named remoteLabel, then passes the decisions to
+// apply. Undecided todos stay unsynced and are asked about again.
+func (mw *MainWindow) showSyncConflicts(conflicts []*persistence.SyncConflict, remoteLabel string, apply func([]*persistence.SyncConflict)) {
+	if mw.syncConflictsOpen {
+		return
+	}
+	mw.syncConflictsOpen = true
+
+	local := localization.GetString("sync_conflict_local")
+	choices := make([]*widget.RadioGroup, len(conflicts))
+	rows := container.NewVBox()
+	for i, conflict := range conflicts {
+		heading := widget.NewLabelWithStyle(conflict.Time().In(time.Local).Format("Mon 2 Jan 2006 15:04"),
+			fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+		base := widget.NewLabel(localization.GetStringWithArgs("sync_conflict_base", describeSyncVersion(conflict.Base)))
+		base.Wrapping = fyne.TextWrapWord
+		choices[i] = widget.NewRadioGroup([]string{
+			fmt.Sprintf("%s: %s", local, describeSyncVersion(conflict.Local)),
+			fmt.Sprintf("%s: %s", remoteLabel, describeSyncVersion(conflict.Remote)),
+		}, nil)
+		rows.Add(container.NewVBox(heading, base, choices[i], widget.NewSeparator()))
+	}
+
+	message := widget.NewLabel(localization.GetStringWithArgs("sync_conflicts_message", len(conflicts)))
+	message.Wrapping = fyne.TextWrapWord
+	scroll := container.NewVScroll(rows)
+	scroll.SetMinSize(fyne.NewSize(460, 320))
+
+	d := dialog.NewCustomWithoutButtons(localization.GetString("sync_conflicts_title"),
+		container.NewBorder(message, nil, nil, nil, scroll), mw.window)
+	d.SetOnClosed(func() { mw.syncConflictsOpen = false })
+
+	applyBtn := widget.NewButton(localization.GetString("sync_button_apply"), func() {
+		for i, conflict := range conflicts {
+			switch choices[i].Selected {
+			case choices[i].Options[0]:
+				conflict.Choice = persistence.SyncKeepLocal
+			case choices[i].Options[1]:
+				conflict.Choice = persistence.SyncKeepRemote
+			}
+		}
+		d.Hide()
+		apply(conflicts)
+	})
+	applyBtn.Importance = widget.HighImportance
+	laterBtn := widget.NewButton(localization.GetString("sync_button_later"), func() { d.Hide() })
+
+	d.SetButtons([]fyne.CanvasObject{laterBtn, applyBtn})
+	d.Show()
+}
+
+// describeSyncVersion summarizes one side of a conflict on a single line
+func describeSyncVersion(todo *models.TodoItem) string {
+	if todo == nil {
+		return localization.GetString("sync_version_deleted")
+	}
+
+	parts := []string{todo.Name}
+	if todo.Done {
+		parts = append(parts, localization.GetString("sync_version_done"))
+	}
+	if todo.Starred {
+		parts = append(parts, "★")
+	}
+	if todo.Place != "" {
+		parts = append(parts, todo.Place)
+	}
+	if content := strings.TrimSpace(todo.Content); content != "" {
+		if runes := []rune(content); len(runes) > 40 {
+			content = string(runes[:40]) + "…"
+		}
+		parts = append(parts, strings.ReplaceAll(content, "\n", " "))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// showSyncSettings lets the user choose the folder to sync with and how often
+func (mw *MainWindow) showSyncSettings() {
+	settings := mw.config.Sync
+
+	folderEntry := widget.NewEntry()
+	folderEntry.SetPlaceHolder(localization.GetString("sync_folder_placeholder"))
+	folderEntry.SetText(settings.RemoteDir)
+	browseBtn := widget.NewButton(localization.GetString("sync_button_browse"), func() {
+		dialog.ShowFolderOpen(func(folder fyne.ListableURI, err error) {
+			if err == nil && folder != nil {
+				folderEntry.SetText(folder.Path())
+			}
+		}, mw.window)
+	})
+	intervalEntry := widget.NewEntry()
+	intervalEntry.SetText(strconv.Itoa(settings.Interval))
+	hint := widget.NewLabel(localization.GetString("sync_hint"))
+	hint.Wrapping = fyne.TextWrapWord
+
+	form := container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem(localization.GetString("sync_folder"),
+				container.NewBorder(nil, nil, nil, browseBtn, folderEntry)),
+			widget.NewFormItem(localization.GetString("sync_interval"), intervalEntry),
+		),
+		hint,
+	)
+
+	d := dialog.NewCustomWithoutButtons(localization.GetString("sync_title"), form, mw.window)
+	saveBtn := widget.NewButton(localization.GetString("sync_button_save"), func() {
+		interval, err := strconv.Atoi(strings.TrimSpace(intervalEntry.Text))
+		if err != nil || interval < 0 {
+			dialog.ShowError(errors.New(localization.GetString("sync_invalid_interval")), mw.window)
+			return
+		}
+		updated := models.SyncConfig{RemoteDir: strings.TrimSpace(folderEntry.Text), Interval: interval}
+		if updated.RemoteDir != "" {
+			if info, err := os.Stat(updated.RemoteDir); err != nil || !info.IsDir() {
+				dialog.ShowError(errors.New(localization.GetString("sync_invalid_folder")), mw.window)
+				return
+			}
+		}
+		if err := mw.syncer.SetRemoteDir(updated.RemoteDir); err != nil {
+			dialog.ShowError(err, mw.window)
+			return
+		}
+
+		d.Hide()
+		mw.config.Sync = updated
+		mw.saveConfig()
+		if mw.syncer.HasRemote() {
+			mw.runSync(nil, true)
+		}
+	})
+	saveBtn.Importance = widget.HighImportance
+	cancelBtn := widget.NewButton(localization.GetString("sync_button_cancel"), func() { d.Hide() })
+
+	d.SetButtons([]fyne.CanvasObject{cancelBtn, saveBtn})
+	d.Resize(fyne.NewSize(520, 260))
+	d.Show()
+}
@@ -26,6 +26,20 @@ func RunOnMainThread(fn func()) {
 	fyne.Do(fn)
 }
 
+// RunOnMainThreadAndWait runs fn on the Fyne UI thread and returns once it
+// has finished. Use it from goroutines that need the result of UI-owned
+// state, such as the todo cache.
+func RunOnMainThreadAndWait(fn func()) {
+	if fn == nil {
+		return
+	}
+	if isMainThread() {
+		fn()
+		return
+	}
+	fyne.DoAndWait(fn)
+}
+
 // isMainThread checks if we're currently on the main UI thread
 func isMainThread() bool {
 	// Get stack trace
@@ -1,8 +1,10 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"image/color"
+	"path/filepath"
 	"time"
 
 	"godo/src/localization"
@@ -35,12 +37,14 @@ type Timeline struct {
 	itemHeight     float32
 	dateGroups     map[string][]*models.TodoItem
 	visibleItems   []*models.TodoItem
+	loadErr        error // Set when the current month could not be loaded
+	plannerMode    bool  // Show an hourly day planner instead of the list
 
 	// Event callbacks
 	onTodoSelected    func(*models.TodoItem, time.Time)
 	onTodoReorder     func(*models.TodoItem, int) // delta: -1 up, +1 down
 	onReorderFinished func()
-	onTodosChanged    func()
+	onStartPomodoro   func(*models.TodoItem)
 
 	// drag state
 	draggingTodo *models.TodoItem
@@ -81,6 +85,18 @@ func (t *Timeline) SetTodos(todos []*models.TodoItem) {
 	// Don't auto-refresh - let caller control when to refresh
 }
 
+// SetLoadError shows a warning instead of the todo list when loading failed (nil clears it)
+func (t *Timeline) SetLoadError(err error) {
+	t.loadErr = err
+	// Don't auto-refresh - let caller control when to refresh
+}
+
+// SetPlannerMode switches between the todo list and the hourly day planner
+func (t *Timeline) SetPlannerMode(planner bool) {
+	t.plannerMode = planner
+	// Don't auto-refresh - let caller control when to refresh
+}
+
 // SetWindow sets the parent window reference for dialogs.
 func (t *Timeline) SetWindow(win fyne.Window) {
 	t.window = win
@@ -91,9 +107,9 @@ func (t *Timeline) SetOnTodoReorder(callback func(*models.TodoItem, int)) {
 	t.onTodoReorder = callback
 }
 
-// SetOnTodosChanged registers callback invoked when timeline mutates todo data.
-func (t *Timeline) SetOnTodosChanged(callback func()) {
-	t.onTodosChanged = callback
+// SetOnStartPomodoro sets the callback for starting the Pomodoro timer on a todo
+func (t *Timeline) SetOnStartPomodoro(callback func(*models.TodoItem)) {
+	t.onStartPomodoro = callback
 }
 
 // organizeByDate groups todos by date for display
@@ -187,6 +203,11 @@ func (r *timelineRenderer) buildTimelineObjects() []fyne.CanvasObject {
 	dateHeader := r.createDateHeader(dateKey)
 	objects = append(objects, dateHeader)
 
+	if r.timeline.loadErr != nil {
+		objects = append(objects, r.createLoadErrorLabel(r.timeline.loadErr))
+		return objects
+	}
+
 	if len(r.timeline.visibleItems) == 0 {
 		emptyLabel := widget.NewLabel(localization.GetString("status_empty_list"))
 		emptyLabel.Alignment = fyne.TextAlignCenter
@@ -194,6 +215,12 @@ func (r *timelineRenderer) buildTimelineObjects() []fyne.CanvasObject {
 		return objects
 	}
 
+	if r.timeline.plannerMode {
+		planner := NewDayPlanner(r.timeline.currentDate, r.timeline.todos, r.timeline.onTodoSelected)
+		objects = append(objects, helpers.CreateSpacer(1, 6), planner)
+		return objects
+	}
+
 	// Add todo items
 	for _, todo := range r.timeline.todos {
 		todoItem := r.createTodoItem(todo)
@@ -202,6 +229,20 @@ func (r *timelineRenderer) buildTimelineObjects() []fyne.CanvasObject {
 	return objects
 }
 
+// createLoadErrorLabel explains why the day is shown without todos
+func (r *timelineRenderer) createLoadErrorLabel(err error) fyne.CanvasObject {
+	text := localization.GetStringWithArgs("status_loading_error", err.Error())
+	var corrupt *persistence.CorruptFileError
+	if errors.As(err, &corrupt) {
+		text = localization.GetStringWithArgs("status_corrupt_file", filepath.Base(corrupt.Path), corrupt.Line, corrupt.Reason)
+	}
+	label := widget.NewLabel(text)
+	label.Alignment = fyne.TextAlignCenter
+	label.Wrapping = fyne.TextWrapWord
+	label.Importance = widget.DangerImportance
+	return label
+}
+
 func (r *timelineRenderer) getSortedDateKeys() []string {
 	return []string{r.timeline.currentDate.Format("2006-01-02")}
 }
@@ -265,11 +306,8 @@ func (r *timelineRenderer) createTodoItem(todo *models.TodoItem) fyne.CanvasObje
 	doneCheck := newSquareCheckbox(todo.Done, func(checked bool) {
 		updated := *todo
 		updated.Done = checked
-		_ = r.timeline.dataManager.UpdateTodo(&updated, todo.TodoTime)
-		if todos, err := r.timeline.dataManager.GetTodosForMonth(r.timeline.currentDate.Year(), int(r.timeline.currentDate.Month())); err == nil {
-			filtered := r.timeline.viewMode.FilterItems(todos, time.Now())
-			r.timeline.SetTodos(filtered)
-			r.timeline.Refresh()
+		if err := r.timeline.dataManager.UpdateTodo(&updated, todo.TodoTime); err != nil {
+			r.timeline.showError(err)
 		}
 	})
 	// Wrap checkbox in container for vertical centering
@@ -287,10 +325,15 @@ func (r *timelineRenderer) createTodoItem(todo *models.TodoItem) fyne.CanvasObje
 	} else {
 		timeColor = color.NRGBA{R: 0xA8, G: 0x99, B: 0x84, A: 0xFF} // #a89984
 	}
-	timeText := canvas.NewText(fmt.Sprintf("%02d:%02d", todo.TodoTime.Hour(), todo.TodoTime.Minute()), timeColor)
+	timeText := canvas.NewText(helpers.FormatTimeRange(todo, time.Local), timeColor)
 	timeText.TextSize = 18
 	timeLabel := verticallyCenterCompact(timeText)
 
+	// Finished vs. estimated Pomodoros, e.g. "●●○○"
+	pomodoroText := canvas.NewText(helpers.FormatPomodoroProgress(todo), timeColor)
+	pomodoroText.TextSize = 14
+	pomodoroLabel := verticallyCenterCompact(pomodoroText)
+
 	//Status indicator
 	status := newStatusIndicator(todo, func(toggleStar bool) {
 		if toggleStar {
@@ -298,9 +341,7 @@ func (r *timelineRenderer) createTodoItem(todo *models.TodoItem) fyne.CanvasObje
 			updated.Starred = !todo.Starred
 			if err := r.timeline.dataManager.UpdateTodo(&updated, todo.TodoTime); err != nil {
 				r.timeline.showError(err)
-				return
 			}
-			r.timeline.notifyTodosChanged()
 		}
 	})
 	// Keep status aligned with the schedule time for a cleaner row
@@ -313,10 +354,23 @@ func (r *timelineRenderer) createTodoItem(todo *models.TodoItem) fyne.CanvasObje
 	deleteBtn.Importance = widget.LowImportance
 	deleteBtnCentered := verticallyCenterCompact(deleteBtn)
 
-	// Layout: [ColorSquare] [Spacer] [Checkbox] [Name................] [Time] [Star] [Delete]
+	// Pomodoro action: start the timer for this todo
+	pomodoroBtn := widget.NewButtonWithIcon("", theme.MediaPlayIcon(), func() {
+		if r.timeline.onStartPomodoro != nil {
+			r.timeline.onStartPomodoro(todo)
+		}
+	})
+	pomodoroBtn.Importance = widget.LowImportance
+	if todo.Done || r.timeline.onStartPomodoro == nil {
+		pomodoroBtn.Hide()
+	}
+	pomodoroBtnCentered := verticallyCenterCompact(pomodoroBtn)
+
+	// Layout: [ColorSquare] [Spacer] [Checkbox] [Name................] [Pomodoros] [Time] [Star] [Start] [Delete]
 	// Add spacer between color and checkbox (doubled spacing)
 	leftSection := container.NewHBox(colorSquareAligned, helpers.CreateSpacer(8, 1), doneCheckCentered)
-	rightSection := container.NewHBox(timeLabel, helpers.CreateSpacer(8, 1), statusCentered, helpers.CreateSpacer(4, 1), deleteBtnCentered)
+	rightSection := container.NewHBox(pomodoroLabel, helpers.CreateSpacer(8, 1), timeLabel, helpers.CreateSpacer(8, 1), statusCentered,
+		helpers.CreateSpacer(4, 1), pomodoroBtnCentered, deleteBtnCentered)
 	content := container.NewBorder(nil, nil, leftSection, rightSection, verticallyCenterWide(nameLabel))
 
 	// Row with bottom border only (no card)
@@ -564,29 +618,7 @@ func (t *Timeline) deleteTodo(todo *models.TodoItem) {
 	}
 	if err := t.dataManager.RemoveTodo(todo.TodoTime); err != nil {
 		t.showError(err)
-		return
-	}
-	t.notifyTodosChanged()
-}
-
-func (t *Timeline) notifyTodosChanged() {
-	if t.onTodosChanged != nil {
-		t.onTodosChanged()
-		return
-	}
-	t.reloadVisibleTodos()
-}
-
-func (t *Timeline) reloadVisibleTodos() {
-	year, month := t.currentDate.Year(), int(t.currentDate.Month())
-	todos, err := t.dataManager.GetTodosForMonth(year, month)
-	if err != nil {
-		t.showError(err)
-		return
 	}
-	filtered := t.viewMode.FilterItems(todos, time.Now())
-	t.SetTodos(filtered)
-	t.Refresh()
 }
 
 func (t *Timeline) showError(err error) {
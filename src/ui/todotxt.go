@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"godo/src/localization"
+	"godo/src/persistence"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// watchTodoFile watches the files todos are stored in, such as a todo.txt
+// file or a notes vault shared with other tools, for edits by other programs.
+// Such edits reach the view as TodosReloaded events.
+func (mw *MainWindow) watchTodoFile() {
+	watched, ok := mw.dataManager.(persistence.WatchedRepository)
+	if !ok {
+		return
+	}
+	watched.Watch(2*time.Second, nil)
+}
+
+// showTodoTxtSettings lets the user keep todos in a todo.txt file instead of
+// the month files. The choice takes effect at the next start.
+func (mw *MainWindow) showTodoTxtSettings() {
+	fileEntry := widget.NewEntry()
+	fileEntry.SetPlaceHolder(localization.GetString("todotxt_file_placeholder"))
+	fileEntry.SetText(mw.config.TodoTxt.File)
+	browseBtn := widget.NewButton(localization.GetString("sync_button_browse"), func() {
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err == nil && reader != nil {
+				fileEntry.SetText(reader.URI().Path())
+				reader.Close()
+			}
+		}, mw.window)
+	})
+	hint := widget.NewLabel(localization.GetString("todotxt_hint"))
+	hint.Wrapping = fyne.TextWrapWord
+
+	form := container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem(localization.GetString("todotxt_file"),
+				container.NewBorder(nil, nil, nil, browseBtn, fileEntry)),
+		),
+		hint,
+	)
+
+	d := dialog.NewCustomWithoutButtons(localization.GetString("todotxt_title"), form, mw.window)
+	saveBtn := widget.NewButton(localization.GetString("sync_button_save"), func() {
+		file := strings.TrimSpace(fileEntry.Text)
+		if file != "" {
+			if err := persistence.CheckTodoTxtFile(file); err != nil {
+				dialog.ShowError(err, mw.window)
+				return
+			}
+		}
+
+		d.Hide()
+		if file == mw.config.TodoTxt.File {
+			return
+		}
+		mw.config.TodoTxt.File = file
+		if file != "" {
+			// Todos are kept in one place at a time
+			mw.config.Markdown.Folder = ""
+		}
+		mw.saveConfig()
+		dialog.ShowInformation(localization.GetString("todotxt_title"),
+			localization.GetString("todotxt_restart"), mw.window)
+	})
+	saveBtn.Importance = widget.HighImportance
+	cancelBtn := widget.NewButton(localization.GetString("sync_button_cancel"), func() { d.Hide() })
+
+	d.SetButtons([]fyne.CanvasObject{cancelBtn, saveBtn})
+	d.Resize(fyne.NewSize(520, 240))
+	d.Show()
+}
@@ -0,0 +1,197 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"godo/src/localization"
+	"godo/src/models"
+	"godo/src/ui/helpers"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+)
+
+// systemTray is the app's entry in the system tray. Its menu shows today's
+// open todos and the running Pomodoro and offers the common actions, so the
+// main window can stay hidden.
+type systemTray struct {
+	mw   *MainWindow
+	menu *fyne.Menu
+
+	todayItem    *fyne.MenuItem
+	timerItem    *fyne.MenuItem
+	pomodoroItem *fyne.MenuItem
+	windowItem   *fyne.MenuItem
+	minimizeItem *fyne.MenuItem
+
+	hidden     bool      // Main window was hidden to the tray
+	openTodos  int       // Open todos of countedDay
+	countedDay time.Time // Day openTodos was counted for; recounted after midnight
+}
+
+// newSystemTray adds the tray menu, or returns nil where the driver has no
+// system tray (mobile, CI builds)
+func newSystemTray(mw *MainWindow) *systemTray {
+	desk, ok := fyne.CurrentApp().(desktop.App)
+	if !ok {
+		return nil
+	}
+
+	t := &systemTray{mw: mw}
+	t.todayItem = fyne.NewMenuItem("", nil)
+	t.todayItem.Disabled = true
+	t.timerItem = fyne.NewMenuItem("", nil)
+	t.timerItem.Disabled = true
+	t.pomodoroItem = fyne.NewMenuItem("", t.onPomodoroClicked)
+	t.windowItem = fyne.NewMenuItem("", t.onWindowClicked)
+	t.minimizeItem = fyne.NewMenuItem(localization.GetString("tray_minimize_to_tray"), t.onMinimizeClicked)
+	t.minimizeItem.Checked = mw.config.GetMinimizeToTray()
+	// The driver quits the app when an item marked IsQuit has no action
+	quitItem := fyne.NewMenuItem(localization.GetString("tray_quit"), nil)
+	quitItem.IsQuit = true
+
+	items := []*fyne.MenuItem{
+		t.todayItem,
+		t.timerItem,
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(localization.GetString("tray_quick_add"), t.onQuickAddClicked),
+		t.pomodoroItem,
+		t.windowItem,
+		fyne.NewMenuItemSeparator(),
+	}
+	// Dialogs, conflicts and file pickers open over the main window
+	items = append(items, mw.toolsMenuItems(t.showWindow)...)
+	items = append(items,
+		fyne.NewMenuItemSeparator(),
+		t.minimizeItem,
+		quitItem,
+	)
+	t.menu = fyne.NewMenu("Go Do", items...)
+	t.recount()
+	t.updateLabels()
+	desk.SetSystemTrayMenu(t.menu)
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			runOnMainThread(t.refresh)
+		}
+	}()
+	return t
+}
+
+// refresh updates the menu, redrawing it only when a label changed. Labels
+// use whole minutes because redrawing a tray menu closes it on some desktops.
+func (t *systemTray) refresh() {
+	if !t.countedDay.Equal(today()) {
+		t.recount()
+	}
+	if t.updateLabels() {
+		t.menu.Refresh()
+	}
+}
+
+// recount counts the open todos of today; call it after todos changed
+func (t *systemTray) recount() {
+	day := today()
+	todos, err := t.mw.dataManager.GetTodosForDay(day)
+	if err != nil {
+		fmt.Printf("Failed to count open todos for the tray: %v\n", err)
+		return
+	}
+
+	open := 0
+	for _, todo := range todos {
+		if !todo.IsDone() {
+			open++
+		}
+	}
+	t.openTodos = open
+	t.countedDay = day
+}
+
+// updateLabels sets the item labels from the current state and reports
+// whether any of them changed
+func (t *systemTray) updateLabels() bool {
+	changed := false
+	set := func(item *fyne.MenuItem, label string) {
+		if item.Label != label {
+			item.Label = label
+			changed = true
+		}
+	}
+
+	status := t.mw.pomodoro.Timer().Snapshot()
+	set(t.todayItem, localization.GetStringWithArgs("tray_open_todos", t.openTodos))
+	set(t.timerItem, localization.GetStringWithArgs("tray_pomodoro", helpers.FormatPomodoroStatus(status)))
+	switch status.State {
+	case models.PomodoroIdle:
+		set(t.pomodoroItem, localization.GetString("tray_pomodoro_start"))
+	case models.PomodoroPaused:
+		set(t.pomodoroItem, localization.GetString("tray_pomodoro_resume"))
+	default:
+		set(t.pomodoroItem, localization.GetString("tray_pomodoro_pause"))
+	}
+	if t.hidden {
+		set(t.windowItem, localization.GetString("tray_show_window"))
+	} else {
+		set(t.windowItem, localization.GetString("tray_hide_window"))
+	}
+	return changed
+}
+
+// hideWindow hides the main window; the app keeps running in the tray
+func (t *systemTray) hideWindow() {
+	t.mw.window.Hide()
+	t.hidden = true
+	t.refresh()
+}
+
+// showWindow brings the main window back
+func (t *systemTray) showWindow() {
+	t.mw.window.Show()
+	t.mw.window.RequestFocus()
+	t.hidden = false
+	t.refresh()
+}
+
+func (t *systemTray) onQuickAddClicked() {
+	t.mw.onAddButtonClicked()
+}
+
+func (t *systemTray) onPomodoroClicked() {
+	timer := t.mw.pomodoro.Timer()
+	switch timer.Snapshot().State {
+	case models.PomodoroIdle:
+		timer.Start()
+	case models.PomodoroPaused:
+		timer.Resume()
+	default:
+		timer.Pause()
+	}
+	t.mw.refreshPomodoroWindow()
+	t.refresh()
+}
+
+func (t *systemTray) onWindowClicked() {
+	if t.hidden {
+		t.showWindow()
+	} else {
+		t.hideWindow()
+	}
+}
+
+func (t *systemTray) onMinimizeClicked() {
+	t.minimizeItem.Checked = !t.minimizeItem.Checked
+	t.mw.config.SetMinimizeToTray(t.minimizeItem.Checked)
+	t.mw.saveConfig()
+	t.menu.Refresh()
+}
+
+// today returns midnight of the current local day
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+// ipcTimeout bounds how long forwarding arguments may take, so a hung
+// instance cannot block a new launch forever
+const ipcTimeout = 3 * time.Second
+
+// Listen accepts the arguments of later launches on a local socket and passes
+// each set to handle on its own goroutine. It must be called after the lock
+// was acquired; the socket is removed again by Unlock.
+func (si *SingleInstance) Listen(handle func(args []string)) error {
+	if si.lockFile == nil {
+		return fmt.Errorf("failed to listen for instances: lock not held")
+	}
+
+	// Holding the lock means any socket left behind belongs to a dead instance
+	os.Remove(si.socketPath)
+	listener, err := net.Listen("unix", si.socketPath)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", si.socketPath, err)
+	}
+	if err := os.Chmod(si.socketPath, 0600); err != nil {
+		listener.Close()
+		return fmt.Errorf("failed to restrict socket permissions: %w", err)
+	}
+	si.listener = listener
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return // Closed by Unlock
+			}
+			go si.serve(conn, handle)
+		}
+	}()
+	return nil
+}
+
+// serve reads one set of arguments from conn and acknowledges it
+func (si *SingleInstance) serve(conn net.Conn, handle func(args []string)) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(ipcTimeout))
+
+	var args []string
+	if err := json.NewDecoder(conn).Decode(&args); err != nil {
+		fmt.Printf("Failed to read forwarded arguments: %v\n", err)
+		return
+	}
+	if _, err := conn.Write([]byte("ok\n")); err != nil {
+		fmt.Printf("Failed to acknowledge forwarded arguments: %v\n", err)
+	}
+	handle(args)
+}
+
+// Forward sends args to the running instance, which acts on them as if it had
+// been started with them
+func (si *SingleInstance) Forward(args []string) error {
+	conn, err := net.DialTimeout("unix", si.socketPath, ipcTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to reach running instance: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(ipcTimeout))
+
+	if args == nil {
+		args = []string{}
+	}
+	if err := json.NewEncoder(conn).Encode(args); err != nil {
+		return fmt.Errorf("failed to forward arguments: %w", err)
+	}
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil || reply != "ok\n" {
+		return fmt.Errorf("failed to forward arguments: no acknowledgement from running instance")
+	}
+	return nil
+}
+
+// GetSocketPath returns the path of the instance socket (for debugging)
+func (si *SingleInstance) GetSocketPath() string {
+	return si.socketPath
+}
@@ -1,57 +1,73 @@
 package utils
 
 import (
+	"crypto/sha256"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
-// SingleInstance manages application instance locking
+// SingleInstance manages application instance locking. The lock is scoped to
+// the user and optionally to a data directory, and is held by the operating
+// system, so it goes away with a crashed process. The instance holding the
+// lock can receive the arguments of later launches (see Listen/Forward).
 type SingleInstance struct {
-	lockFile *os.File
-	lockPath string
+	lockFile   *os.File
+	lockPath   string
+	socketPath string
+	listener   net.Listener
 }
 
-// NewSingleInstance creates a new single instance manager
+// NewSingleInstance creates a single instance manager for the current user
 func NewSingleInstance(appName string) *SingleInstance {
-	// Use system temp directory for lock file
-	tempDir := os.TempDir()
-	lockPath := filepath.Join(tempDir, fmt.Sprintf("%s.lock", appName))
+	return NewSingleInstanceForDir(appName, "")
+}
+
+// NewSingleInstanceForDir creates a single instance manager for the current
+// user and dataDir, so copies using different data directories can run side
+// by side. An empty dataDir scopes the lock to the user only.
+func NewSingleInstanceForDir(appName, dataDir string) *SingleInstance {
+	name := appName
+	if dataDir != "" {
+		if abs, err := filepath.Abs(dataDir); err == nil {
+			dataDir = abs
+		}
+		sum := sha256.Sum256([]byte(filepath.Clean(dataDir)))
+		name = fmt.Sprintf("%s-%x", appName, sum[:4])
+	}
 
 	return &SingleInstance{
-		lockPath: lockPath,
+		lockPath:   instancePath(name, ".lock"),
+		socketPath: instancePath(name, ".sock"),
 	}
 }
 
 // TryLock attempts to acquire the instance lock
 // Returns true if lock acquired successfully, false if another instance is running
 func (si *SingleInstance) TryLock() (bool, error) {
-	// Try to create lock file with exclusive access
-	// O_CREATE | O_EXCL ensures atomic creation - fails if file exists
-	// O_RDWR for read/write access
-	file, err := os.OpenFile(si.lockPath, os.O_CREATE|os.O_EXCL|os.O_RDWR, 0600)
+	if si.lockFile != nil {
+		return true, nil
+	}
 
-	if err != nil {
-		if os.IsExist(err) {
-			// Lock file exists - check if it's stale
-			if si.isLockStale() {
-				// Remove stale lock and retry
-				os.Remove(si.lockPath)
-				return si.TryLock()
-			}
-			// Another instance is running
-			return false, nil
-		}
-		// Other error occurred
-		return false, fmt.Errorf("failed to create lock file: %w", err)
+	// acquireLock is implemented in platform-specific files:
+	// - singleinstance_windows.go opens the file without sharing
+	// - singleinstance_unix.go takes an advisory flock
+	file, locked, err := acquireLock(si.lockPath)
+	if err != nil || !locked {
+		return false, err
 	}
 
-	// Write PID to lock file for debugging
+	// Record the owner for the fallback check and for debugging
 	pid := os.Getpid()
-	_, err = file.WriteString(fmt.Sprintf("%d", pid))
+	owner := fmt.Sprintf("%d %s", pid, processStartTime(pid))
+	err = file.Truncate(0)
+	if err == nil {
+		_, err = file.WriteAt([]byte(strings.TrimSpace(owner)), 0)
+	}
 	if err != nil {
-		file.Close()
-		os.Remove(si.lockPath)
+		releaseLock(file, si.lockPath)
 		return false, fmt.Errorf("failed to write PID to lock file: %w", err)
 	}
 
@@ -59,32 +75,62 @@ func (si *SingleInstance) TryLock() (bool, error) {
 	return true, nil
 }
 
-// isLockStale is implemented in platform-specific files:
-// - singleinstance_windows.go for Windows
-// - singleinstance_unix.go for Unix-like systems
-
 // Unlock releases the instance lock
 func (si *SingleInstance) Unlock() error {
-	if si.lockFile != nil {
-		// Close the file
-		err := si.lockFile.Close()
-		if err != nil {
-			return fmt.Errorf("failed to close lock file: %w", err)
-		}
-		si.lockFile = nil
+	if si.listener != nil {
+		si.listener.Close()
+		si.listener = nil
+		os.Remove(si.socketPath)
 	}
 
-	// Remove the lock file
-	if _, err := os.Stat(si.lockPath); err == nil {
-		err = os.Remove(si.lockPath)
-		if err != nil {
-			return fmt.Errorf("failed to remove lock file: %w", err)
+	if si.lockFile != nil {
+		file := si.lockFile
+		si.lockFile = nil
+		if err := releaseLock(file, si.lockPath); err != nil {
+			return fmt.Errorf("failed to release lock file: %w", err)
 		}
 	}
 
 	return nil
 }
 
+// RuntimeDir returns the per-user directory for the instance lock and socket:
+// $XDG_RUNTIME_DIR where set, the temp directory otherwise
+func RuntimeDir() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return dir
+	}
+	return os.TempDir()
+}
+
+// instancePath returns the path of an instance's lock or socket file. Outside
+// $XDG_RUNTIME_DIR the temp directory may be shared, so the user id is part
+// of the name.
+func instancePath(name, ext string) string {
+	if os.Getenv("XDG_RUNTIME_DIR") == "" && os.Getuid() >= 0 {
+		name = fmt.Sprintf("%s-%d", name, os.Getuid())
+	}
+	return filepath.Join(RuntimeDir(), name+ext)
+}
+
+// ownerAlive reports whether the process recorded in a lock file ("pid" or
+// "pid starttime") still runs. The start time tells a reused PID apart.
+func ownerAlive(record []byte) bool {
+	var pid int
+	if _, err := fmt.Sscanf(string(record), "%d", &pid); err != nil || pid <= 0 {
+		return false // Invalid PID format, consider it stale
+	}
+	if !isProcessRunning(pid) {
+		return false
+	}
+
+	fields := strings.Fields(string(record))
+	if len(fields) < 2 {
+		return true // Written by an older version; the PID is all we have
+	}
+	return processStartTime(pid) == fields[1]
+}
+
 // GetLockPath returns the path to the lock file (for debugging)
 func (si *SingleInstance) GetLockPath() string {
 	return si.lockPath
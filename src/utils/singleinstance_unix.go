@@ -1,40 +1,106 @@
-// go:build !windows
+//go:build !windows
 // +build !windows
 
 package utils
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"syscall"
 )
 
-// isLockStale checks if the lock file belongs to a dead process (Unix version)
-func (si *SingleInstance) isLockStale() bool {
-	// Read PID from lock file
-	data, err := os.ReadFile(si.lockPath)
-	if err != nil {
-		return true // Can't read, consider it stale
+// acquireLock takes an advisory flock on path. The kernel drops it when the
+// process exits, however it exits. File systems without flock (some network
+// mounts) fall back to checking the owner recorded in the file.
+func acquireLock(path string) (*os.File, bool, error) {
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to create lock file: %w", err)
+		}
+
+		err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		switch {
+		case err == syscall.EWOULDBLOCK:
+			// Another instance is running
+			file.Close()
+			return nil, false, nil
+		case err == syscall.ENOTSUP || err == syscall.ENOLCK || err == syscall.EOPNOTSUPP:
+			return lockByOwner(file)
+		case err != nil:
+			file.Close()
+			return nil, false, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+
+		// Unlock removes the file while holding the lock; if that happened
+		// between our open and flock, we hold an orphan and must start over
+		if info, err := os.Stat(path); err == nil {
+			if opened, err := file.Stat(); err == nil && os.SameFile(info, opened) {
+				return file, true, nil
+			}
+		}
+		file.Close()
 	}
+}
 
-	var pid int
-	_, err = fmt.Sscanf(string(data), "%d", &pid)
+// lockByOwner treats the lock file as taken while the process recorded in it
+// is alive
+func lockByOwner(file *os.File) (*os.File, bool, error) {
+	record, err := io.ReadAll(file)
 	if err != nil {
-		return true // Invalid PID format, consider it stale
+		file.Close()
+		return nil, false, fmt.Errorf("failed to read lock file: %w", err)
 	}
+	if ownerAlive(record) {
+		file.Close()
+		return nil, false, nil
+	}
+	return file, true, nil
+}
 
-	// Check if process exists using kill with signal 0
-	// Signal 0 doesn't actually send a signal but checks if process exists
+// releaseLock removes the lock file while still holding the lock, so no
+// other process can lock the file that is about to disappear
+func releaseLock(file *os.File, path string) error {
+	removeErr := os.Remove(path)
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if removeErr != nil && !os.IsNotExist(removeErr) {
+		return removeErr
+	}
+	return nil
+}
+
+// isProcessRunning checks if a process with the given PID exists (Unix version)
+func isProcessRunning(pid int) bool {
 	process, err := os.FindProcess(pid)
 	if err != nil {
-		return true // Process doesn't exist
+		return false
 	}
 
-	// Send signal 0 to check if process is alive
-	err = process.Signal(syscall.Signal(0))
+	// Signal 0 doesn't actually send a signal but checks if process exists
+	return process.Signal(syscall.Signal(0)) == nil
+}
+
+// processStartTime returns when pid started, in clock ticks after boot, or ""
+// where /proc is not available
+func processStartTime(pid int) string {
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
 	if err != nil {
-		return true // Process doesn't exist or we can't signal it
+		return ""
 	}
 
-	return false // Process exists, lock is valid
+	// The command name in parentheses may contain spaces; starttime is the
+	// 22nd field, the 20th after the name
+	end := strings.LastIndexByte(string(stat), ')')
+	if end < 0 {
+		return ""
+	}
+	fields := strings.Fields(string(stat[end+1:]))
+	if len(fields) < 20 {
+		return ""
+	}
+	return fields[19]
 }
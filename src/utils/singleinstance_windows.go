@@ -1,4 +1,4 @@
-// go:build windows
+//go:build windows
 // +build windows
 
 package utils
@@ -6,13 +6,61 @@ package utils
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"syscall"
 )
 
+const (
+	errorSharingViolation          syscall.Errno = 32
+	processQueryLimitedInformation               = 0x1000
+)
+
+// acquireLock opens path without write sharing. Windows refuses a second
+// such open while the handle exists and closes it when the process exits,
+// however it exits.
+func acquireLock(path string) (*os.File, bool, error) {
+	name, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create lock file: %w", err)
+	}
+
+	handle, err := syscall.CreateFile(name, syscall.GENERIC_READ|syscall.GENERIC_WRITE,
+		syscall.FILE_SHARE_READ, nil, syscall.OPEN_ALWAYS, syscall.FILE_ATTRIBUTE_NORMAL, 0)
+	if err == errorSharingViolation {
+		// Another instance is running
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create lock file: %w", err)
+	}
+	return os.NewFile(uintptr(handle), path), true, nil
+}
+
+// releaseLock closes the lock file before removing it, as Windows cannot
+// delete an open file. Another instance may have opened it in between; then
+// it stays.
+func releaseLock(file *os.File, path string) error {
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) && !isSharingViolation(err) {
+		return err
+	}
+	return nil
+}
+
+// isSharingViolation reports whether err means another process has the file open
+func isSharingViolation(err error) bool {
+	if pathErr, ok := err.(*os.PathError); ok {
+		return pathErr.Err == errorSharingViolation
+	}
+	return false
+}
+
 // isProcessRunning checks if a process with the given PID is running on Windows
 func isProcessRunning(pid int) bool {
 	// Try to open the process handle
-	handle, err := syscall.OpenProcess(syscall.PROCESS_QUERY_INFORMATION, false, uint32(pid))
+	handle, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
 	if err != nil {
 		// Process doesn't exist or we don't have permission
 		return false
@@ -31,20 +79,17 @@ func isProcessRunning(pid int) bool {
 	return exitCode == STILL_ACTIVE
 }
 
-// isLockStale checks if the lock file belongs to a dead process (Windows version)
-func (si *SingleInstance) isLockStale() bool {
-	// Read PID from lock file
-	data, err := os.ReadFile(si.lockPath)
+// processStartTime returns the creation time of pid, or "" if it cannot be read
+func processStartTime(pid int) string {
+	handle, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
 	if err != nil {
-		return true // Can't read, consider it stale
+		return ""
 	}
+	defer syscall.CloseHandle(handle)
 
-	var pid int
-	_, err = fmt.Sscanf(string(data), "%d", &pid)
-	if err != nil {
-		return true // Invalid PID format, consider it stale
+	var creation, exit, kernel, user syscall.Filetime
+	if err := syscall.GetProcessTimes(handle, &creation, &exit, &kernel, &user); err != nil {
+		return ""
 	}
-
-	// Check if process is running using Windows-specific method
-	return !isProcessRunning(pid)
+	return strconv.FormatInt(creation.Nanoseconds(), 10)
 }
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	zoneCache   = make(map[string]*time.Location)
+	zoneCacheMu sync.Mutex
+)
+
+// LocalZone returns the local time zone together with its IANA name.
+// The name is empty when the platform does not expose it (e.g. on Windows),
+// in which case only the UTC offset of a time can be recorded.
+func LocalZone() (*time.Location, string) {
+	// TZ overrides the system zone, as it does for the Go runtime
+	if tz := strings.TrimPrefix(os.Getenv("TZ"), ":"); tz != "" {
+		if loc := LoadZone(tz); loc != nil {
+			return loc, tz
+		}
+	}
+
+	// On Unix-like systems /etc/localtime links into the zoneinfo database
+	if target, err := os.Readlink("/etc/localtime"); err == nil {
+		if idx := strings.Index(target, "zoneinfo/"); idx >= 0 {
+			name := target[idx+len("zoneinfo/"):]
+			if LoadZone(name) != nil {
+				return time.Local, name
+			}
+		}
+	}
+
+	return time.Local, ""
+}
+
+// LoadZone loads an IANA time zone by name, caching the result.
+// It returns nil when the name is empty or unknown.
+func LoadZone(name string) *time.Location {
+	if name == "" {
+		return nil
+	}
+
+	zoneCacheMu.Lock()
+	defer zoneCacheMu.Unlock()
+
+	if loc, ok := zoneCache[name]; ok {
+		return loc
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		loc = nil
+	}
+	zoneCache[name] = loc
+	return loc
+}
+
+// SameDay reports whether a and b fall on the same calendar date,
+// each evaluated in its own location.
+func SameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
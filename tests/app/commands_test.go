@@ -0,0 +1,38 @@
+package app_test
+
+import (
+	"testing"
+	"time"
+
+	"godo/src/app"
+)
+
+func TestParseCommand(t *testing.T) {
+	cmd, err := app.ParseCommand([]string{"--add", "Buy milk", "--date", "2026-10-20", "--show"})
+	if err != nil {
+		t.Fatalf("ParseCommand failed: %v", err)
+	}
+	if cmd.Add != "Buy milk" || !cmd.Show {
+		t.Errorf("Unexpected command %+v", cmd)
+	}
+	if want := time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local); !cmd.Date.Equal(want) {
+		t.Errorf("Expected date %v, got %v", want, cmd.Date)
+	}
+
+	cmd, err = app.ParseCommand(nil)
+	if err != nil || cmd != (app.Command{}) {
+		t.Errorf("Expected an empty command without arguments, got %+v, %v", cmd, err)
+	}
+}
+
+func TestParseCommand_Invalid(t *testing.T) {
+	for _, args := range [][]string{
+		{"--date", "20.10.2026"},
+		{"--unknown"},
+		{"stray"},
+	} {
+		if _, err := app.ParseCommand(args); err == nil {
+			t.Errorf("Expected an error for %q", args)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package caldav_test
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	collectionPath = "/dav/calendars/me/work/"
+	testUser       = "me"
+	testPassword   = "secret"
+)
+
+// fakeObject is a calendar object stored by the fake server
+type fakeObject struct {
+	etag string
+	data string
+}
+
+// fakeServer is an in-process CalDAV server holding one calendar collection.
+// It implements just what the client uses: PROPFIND, the calendar-multiget
+// REPORT, and conditional PUT and DELETE.
+type fakeServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	objects   map[string]*fakeObject
+	nextETag  int
+	fetched   []string // Hrefs requested by REPORT, in order
+	writes    int      // PUT and DELETE requests received
+	omitETags bool     // Leave the ETag header out of PUT replies, as some servers do
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	t.Helper()
+	s := &fakeServer{objects: make(map[string]*fakeObject)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// URL returns the address of the collection
+func (s *fakeServer) URL() string {
+	return s.Server.URL + collectionPath
+}
+
+// put stores an object as another client would
+func (s *fakeServer) put(name, data string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store(collectionPath+name, data)
+}
+
+// remove deletes an object as another client would
+func (s *fakeServer) remove(href string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, href)
+}
+
+// object returns the data at href, or "" if there is none
+func (s *fakeServer) object(href string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if object := s.objects[href]; object != nil {
+		return object.data
+	}
+	return ""
+}
+
+// hrefs returns the hrefs of all objects, sorted
+func (s *fakeServer) hrefs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var hrefs []string
+	for href := range s.objects {
+		hrefs = append(hrefs, href)
+	}
+	sort.Strings(hrefs)
+	return hrefs
+}
+
+// resetLog forgets the requests seen so far
+func (s *fakeServer) resetLog() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fetched, s.writes = nil, 0
+}
+
+func (s *fakeServer) store(href, data string) string {
+	s.nextETag++
+	etag := fmt.Sprintf(`"%d"`, s.nextETag)
+	s.objects[href] = &fakeObject{etag: etag, data: data}
+	return etag
+}
+
+func (s *fakeServer) handle(w http.ResponseWriter, r *http.Request) {
+	if user, password, ok := r.BasicAuth(); !ok || user != testUser || password != testPassword {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	href := r.URL.Path
+	object := s.objects[href]
+	switch r.Method {
+	case "PROPFIND":
+		if href == collectionPath {
+			s.propfindCollection(w, r.Header.Get("Depth"))
+			return
+		}
+		if object == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		s.multistatus(w, []string{href}, false)
+	case "REPORT":
+		var multiget struct {
+			Hrefs []string `xml:"DAV: href"`
+		}
+		if err := xml.Unmarshal(body, &multiget); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.fetched = append(s.fetched, multiget.Hrefs...)
+		s.multistatus(w, multiget.Hrefs, true)
+	case http.MethodPut:
+		s.writes++
+		if match := r.Header.Get("If-Match"); match != "" && (object == nil || object.etag != match) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		if r.Header.Get("If-None-Match") == "*" && object != nil {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		etag := s.store(href, string(body))
+		if !s.omitETags {
+			w.Header().Set("ETag", etag)
+		}
+		if object == nil {
+			w.WriteHeader(http.StatusCreated)
+		} else {
+			w.WriteHeader(http.StatusNoContent)
+		}
+	case http.MethodDelete:
+		s.writes++
+		if object == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if match := r.Header.Get("If-Match"); match != "" && object.etag != match {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		delete(s.objects, href)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *fakeServer) propfindCollection(w http.ResponseWriter, depth string) {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?><d:multistatus xmlns:d="DAV:">`)
+	b.WriteString(`<d:response><d:href>` + collectionPath + `</d:href><d:propstat><d:prop>` +
+		`<d:resourcetype><d:collection/></d:resourcetype></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`)
+	if depth == "1" {
+		for href, object := range s.objects {
+			b.WriteString(`<d:response><d:href>` + href + `</d:href><d:propstat><d:prop><d:getetag>` +
+				xmlEscape(object.etag) + `</d:getetag><d:resourcetype/></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`)
+		}
+	}
+	b.WriteString(`</d:multistatus>`)
+	writeMultistatus(w, b.String())
+}
+
+func (s *fakeServer) multistatus(w http.ResponseWriter, hrefs []string, withData bool) {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?><d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">`)
+	for _, href := range hrefs {
+		object := s.objects[href]
+		if object == nil {
+			b.WriteString(`<d:response><d:href>` + href + `</d:href><d:status>HTTP/1.1 404 Not Found</d:status></d:response>`)
+			continue
+		}
+		b.WriteString(`<d:response><d:href>` + href + `</d:href><d:propstat><d:prop><d:getetag>` + xmlEscape(object.etag) + `</d:getetag>`)
+		if withData {
+			b.WriteString(`<c:calendar-data>` + xmlEscape(object.data) + `</c:calendar-data>`)
+		}
+		b.WriteString(`</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`)
+	}
+	b.WriteString(`</d:multistatus>`)
+	writeMultistatus(w, b.String())
+}
+
+func writeMultistatus(w http.ResponseWriter, body string) {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	io.WriteString(w, body)
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
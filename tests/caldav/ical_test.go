@@ -0,0 +1,117 @@
+package caldav_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"godo/src/caldav"
+	"godo/src/models"
+)
+
+func TestEncodeDecode_RoundTrip(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("Zone database not available")
+	}
+
+	zoned := models.NewTodoItem()
+	zoned.Name = "Meeting; with, commas \\ and more"
+	zoned.Content = "Line one\nLine two with a long text that needs folding because it goes on and on ünd ön"
+	zoned.Place = "Room 1"
+	zoned.Label = "work"
+	zoned.Level = 2
+	zoned.WarnTime = 15
+	zoned.Starred = true
+	zoned.Order = 3
+	zoned.SetZonedTime(time.Date(2025, 11, 3, 9, 30, 0, 0, berlin), "Europe/Berlin")
+	zoned.SetDuration(45)
+
+	task := models.NewTodoItem()
+	task.Name = "Write report"
+	task.Kind = 1
+	task.Done = true
+	task.Level = 3
+	task.Estimate = 4
+	task.Pomodoros = 2
+	task.SetZonedTime(time.Date(2025, 11, 4, 17, 0, 0, 0, time.UTC), "")
+
+	floating := models.NewTodoItem()
+	floating.Name = "Wake up"
+	floating.SetFloatingTime(time.Date(2025, 11, 5, 7, 0, 0, 0, time.UTC))
+
+	allDay := models.NewTodoItem()
+	allDay.Name = "Holiday"
+	allDay.Kind = 1
+	allDay.SetAllDay(time.Date(2025, 11, 6, 0, 0, 0, 0, time.UTC))
+
+	for _, todo := range []*models.TodoItem{zoned, task, floating, allDay} {
+		data := caldav.EncodeTodo(todo, "uid-1")
+		for _, line := range strings.Split(string(data), "\r\n") {
+			if len(line) > 75 {
+				t.Errorf("%s: line longer than 75 octets: %q", todo.Name, line)
+			}
+		}
+
+		decoded, uid, err := caldav.DecodeTodo(data)
+		if err != nil {
+			t.Fatalf("%s: DecodeTodo failed: %v\n%s", todo.Name, err, data)
+		}
+		if uid != "uid-1" {
+			t.Errorf("%s: expected UID uid-1, got %q", todo.Name, uid)
+		}
+		if !models.SameTodo(todo, decoded) {
+			t.Errorf("%s: round trip changed the todo\nwant %+v\ngot  %+v\n%s", todo.Name, todo, decoded, data)
+		}
+	}
+}
+
+func TestDecodeTodo_ForeignObject(t *testing.T) {
+	data := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Other//Client//EN",
+		"BEGIN:VTIMEZONE",
+		"TZID:Europe/Berlin",
+		"BEGIN:STANDARD",
+		"DTSTART:19701025T030000",
+		"TZOFFSETFROM:+0200",
+		"TZOFFSETTO:+0100",
+		"END:STANDARD",
+		"END:VTIMEZONE",
+		"BEGIN:VEVENT",
+		"UID:abc@example.com",
+		"DTSTAMP:20251101T100000Z",
+		"SUMMARY:Team lunch",
+		"DESCRIPTION:Bring\\nfood",
+		"DTSTART;TZID=Europe/Berlin:20251110T120000",
+		"DTEND;TZID=Europe/Berlin:20251110T133",
+		" 000",
+		"PRIORITY:1",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"DESCRIPTION:Reminder",
+		"TRIGGER:-PT1H",
+		"END:VALARM",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	todo, uid, err := caldav.DecodeTodo([]byte(data))
+	if err != nil {
+		t.Fatalf("DecodeTodo failed: %v", err)
+	}
+	if uid != "abc@example.com" || todo.Name != "Team lunch" || todo.Content != "Bring\nfood" {
+		t.Errorf("Unexpected todo %+v (uid %q)", todo, uid)
+	}
+	if todo.Kind != 0 || todo.Level != 3 || todo.WarnTime != 60 || todo.Duration != 90 {
+		t.Errorf("Expected an urgent 90-minute event with a 1h reminder, got %+v", todo)
+	}
+	if todo.TimeZone != "Europe/Berlin" || todo.ZonedTime().Hour() != 12 {
+		t.Errorf("Expected noon in Berlin, got %v in %q", todo.ZonedTime(), todo.TimeZone)
+	}
+
+	if _, _, err := caldav.DecodeTodo([]byte("BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:x\r\nSUMMARY:No date\r\nEND:VTODO\r\nEND:VCALENDAR\r\n")); err == nil {
+		t.Error("Expected an error for a task without a date")
+	}
+}
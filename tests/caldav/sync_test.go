@@ -0,0 +1,312 @@
+package caldav_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"godo/src/caldav"
+	"godo/src/models"
+	"godo/src/persistence"
+)
+
+// calendarDevice is a data directory syncing with the fake server
+type calendarDevice struct {
+	t       *testing.T
+	dir     string
+	manager *persistence.MonthlyManager
+	syncer  *caldav.Syncer
+}
+
+func newCalendarDevice(t *testing.T, server *fakeServer) *calendarDevice {
+	t.Helper()
+	dir := t.TempDir()
+	manager := persistence.NewMonthlyManager(dir)
+	syncer := caldav.NewSyncer(manager, dir)
+	config := models.CalDAVConfig{URL: server.URL(), Username: testUser, Password: testPassword}
+	if err := syncer.Configure(config); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	return &calendarDevice{t: t, dir: dir, manager: manager, syncer: syncer}
+}
+
+func (d *calendarDevice) sync(resolved []*persistence.SyncConflict) *persistence.SyncReport {
+	d.t.Helper()
+	report, err := d.syncer.Sync(resolved)
+	if err != nil {
+		d.t.Fatalf("Sync failed: %v", err)
+	}
+	if len(report.Failed) > 0 {
+		d.t.Fatalf("Sync failed for %v", report.Failed)
+	}
+	return report
+}
+
+func (d *calendarDevice) add(name string, at time.Time, kind int) {
+	d.t.Helper()
+	todo := models.NewTodoItem()
+	todo.Name = name
+	todo.Kind = kind
+	todo.SetZonedTime(at, "")
+	if err := d.manager.AddTodo(todo); err != nil {
+		d.t.Fatal(err)
+	}
+}
+
+func (d *calendarDevice) edit(at time.Time, change func(*models.TodoItem)) {
+	d.t.Helper()
+	todo, err := d.manager.GetTodoByTime(at)
+	if err != nil {
+		d.t.Fatal(err)
+	}
+	updated := *todo
+	change(&updated)
+	if err := d.manager.UpdateTodo(&updated, at); err != nil {
+		d.t.Fatal(err)
+	}
+}
+
+func (d *calendarDevice) todo(at time.Time) *models.TodoItem {
+	d.t.Helper()
+	todo, err := d.manager.GetTodoByTime(at)
+	if err != nil {
+		return nil
+	}
+	return todo
+}
+
+func at(day, hour int) time.Time {
+	return time.Date(2025, 11, day, hour, 0, 0, 0, time.UTC)
+}
+
+// foreignEvent is an event as another calendar client would store it
+func foreignEvent(uid, summary, start string) string {
+	return strings.Join([]string{
+		"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//Other//Client//EN",
+		"BEGIN:VEVENT", "UID:" + uid, "DTSTAMP:20251101T100000Z",
+		"SUMMARY:" + summary, "DTSTART:" + start,
+		"END:VEVENT", "END:VCALENDAR", "",
+	}, "\r\n")
+}
+
+func TestCalDAVSync_PushesAndStaysIdle(t *testing.T) {
+	server := newFakeServer(t)
+	device := newCalendarDevice(t, server)
+	device.add("standup", at(3, 9), 0)
+	device.add("report", at(4, 17), 1)
+
+	if report := device.sync(nil); report.Pushed != 2 || report.Pulled != 0 {
+		t.Fatalf("Expected 2 todos pushed, got %+v", report)
+	}
+	hrefs := server.hrefs()
+	if len(hrefs) != 2 {
+		t.Fatalf("Expected 2 objects on the server, got %v", hrefs)
+	}
+	components := server.object(hrefs[0]) + server.object(hrefs[1])
+	if !strings.Contains(components, "BEGIN:VEVENT") || !strings.Contains(components, "BEGIN:VTODO") {
+		t.Errorf("Expected an event and a task, got\n%s", components)
+	}
+
+	// Unchanged ETags mean nothing is downloaded or uploaded
+	server.resetLog()
+	if report := device.sync(nil); report.Pushed != 0 || report.Pulled != 0 {
+		t.Errorf("Expected an idle sync, got %+v", report)
+	}
+	if len(server.fetched) != 0 || server.writes != 0 {
+		t.Errorf("Expected no transfers, fetched %v and wrote %d", server.fetched, server.writes)
+	}
+}
+
+func TestCalDAVSync_PullsIntoMonthFiles(t *testing.T) {
+	server := newFakeServer(t)
+	server.put("lunch.ics", foreignEvent("lunch@example.com", "Lunch", "20251110T120000Z"))
+	server.put("party.ics", foreignEvent("party@example.com", "Party", "20251231T200000Z"))
+	device := newCalendarDevice(t, server)
+
+	if report := device.sync(nil); report.Pulled != 2 || report.Pushed != 0 {
+		t.Fatalf("Expected 2 todos pulled, got %+v", report)
+	}
+
+	// A fresh manager reads what was written to the month files
+	fresh := persistence.NewMonthlyManager(device.dir)
+	for _, month := range []int{11, 12} {
+		todos, err := fresh.GetTodosForMonth(2025, month)
+		if err != nil || len(todos) != 1 {
+			t.Errorf("Expected 1 todo in month %d, got %v (%v)", month, todos, err)
+		}
+	}
+
+	// Only the object with a new ETag is downloaded again
+	server.put("lunch.ics", foreignEvent("lunch@example.com", "Team lunch", "20251110T120000Z"))
+	server.resetLog()
+	if report := device.sync(nil); report.Pulled != 1 {
+		t.Fatalf("Expected 1 todo pulled, got %+v", report)
+	}
+	if len(server.fetched) != 1 || server.fetched[0] != collectionPath+"lunch.ics" {
+		t.Errorf("Expected only lunch.ics to be fetched, got %v", server.fetched)
+	}
+	if todo := device.todo(time.Date(2025, 11, 10, 12, 0, 0, 0, time.UTC)); todo == nil || todo.Name != "Team lunch" {
+		t.Errorf("Expected the renamed lunch, got %+v", todo)
+	}
+	if server.writes != 0 {
+		t.Errorf("Expected pulled todos not to be sent back, got %d writes", server.writes)
+	}
+}
+
+func TestCalDAVSync_EditsAndDeletes(t *testing.T) {
+	server := newFakeServer(t)
+	server.omitETags = true
+	laptop, desktop := newCalendarDevice(t, server), newCalendarDevice(t, server)
+	laptop.add("standup", at(3, 9), 0)
+	laptop.add("review", at(3, 14), 0)
+	laptop.add("dentist", at(4, 8), 0)
+	laptop.sync(nil)
+	desktop.sync(nil)
+
+	laptop.edit(at(3, 9), func(todo *models.TodoItem) { todo.Done = true })
+	if err := laptop.manager.RemoveTodo(at(4, 8)); err != nil {
+		t.Fatal(err)
+	}
+	desktop.edit(at(3, 14), func(todo *models.TodoItem) { todo.Place = "Room 2" })
+
+	if report := laptop.sync(nil); report.Pushed != 2 {
+		t.Fatalf("Expected an update and a delete pushed, got %+v", report)
+	}
+	if report := desktop.sync(nil); report.Pushed != 1 || report.Pulled != 2 {
+		t.Fatalf("Expected 1 pushed and 2 pulled, got %+v", report)
+	}
+	laptop.sync(nil)
+
+	for _, d := range []*calendarDevice{laptop, desktop} {
+		if todo := d.todo(at(3, 9)); todo == nil || !todo.Done {
+			t.Errorf("Expected standup done, got %+v", todo)
+		}
+		if todo := d.todo(at(3, 14)); todo == nil || todo.Place != "Room 2" {
+			t.Errorf("Expected review in Room 2, got %+v", todo)
+		}
+		if todo := d.todo(at(4, 8)); todo != nil {
+			t.Errorf("Expected dentist deleted, got %+v", todo)
+		}
+	}
+	if hrefs := server.hrefs(); len(hrefs) != 2 {
+		t.Errorf("Expected 2 objects left on the server, got %v", hrefs)
+	}
+
+	// Deleting on the server deletes locally
+	server.remove(server.hrefs()[0])
+	if report := laptop.sync(nil); report.Pulled != 1 {
+		t.Errorf("Expected the deletion pulled, got %+v", report)
+	}
+}
+
+func TestCalDAVSync_ConflictsAreReported(t *testing.T) {
+	server := newFakeServer(t)
+	laptop, desktop := newCalendarDevice(t, server), newCalendarDevice(t, server)
+	laptop.add("standup", at(3, 9), 0)
+	laptop.sync(nil)
+	desktop.sync(nil)
+
+	laptop.edit(at(3, 9), func(todo *models.TodoItem) { todo.Content = "laptop" })
+	desktop.edit(at(3, 9), func(todo *models.TodoItem) { todo.Content = "desktop" })
+	laptop.sync(nil)
+
+	report := desktop.sync(nil)
+	if len(report.Conflicts) != 1 {
+		t.Fatalf("Expected 1 conflict, got %+v", report)
+	}
+	conflict := report.Conflicts[0]
+	if conflict.Local.Content != "desktop" || conflict.Remote.Content != "laptop" || conflict.Base.Content != "" {
+		t.Fatalf("Unexpected conflict %+v %+v %+v", conflict.Base, conflict.Local, conflict.Remote)
+	}
+	if todo := desktop.todo(at(3, 9)); todo.Content != "desktop" {
+		t.Errorf("Expected the local version kept until decided, got %+v", todo)
+	}
+
+	// Keeping this device's version overwrites the server
+	conflict.Choice = persistence.SyncKeepLocal
+	if report := desktop.sync(report.Conflicts); len(report.Conflicts) != 0 || report.Pushed != 1 {
+		t.Fatalf("Expected the local version pushed, got %+v", report)
+	}
+	laptop.sync(nil)
+	if todo := laptop.todo(at(3, 9)); todo.Content != "desktop" {
+		t.Errorf("Expected the desktop version on the laptop, got %+v", todo)
+	}
+}
+
+func TestCalDAVSync_NewTodosAtTheSameTime(t *testing.T) {
+	server := newFakeServer(t)
+	server.put("call.ics", foreignEvent("call@example.com", "Call", "20251103T090000Z"))
+	device := newCalendarDevice(t, server)
+	device.add("Standup", at(3, 9), 0)
+
+	report := device.sync(nil)
+	if len(report.Conflicts) != 1 || report.Conflicts[0].Base != nil {
+		t.Fatalf("Expected an add/add conflict, got %+v", report)
+	}
+	report.Conflicts[0].Choice = persistence.SyncKeepRemote
+	device.sync(report.Conflicts)
+
+	if todo := device.todo(at(3, 9)); todo == nil || todo.Name != "Call" {
+		t.Errorf("Expected the server's todo, got %+v", todo)
+	}
+	if hrefs := server.hrefs(); len(hrefs) != 1 {
+		t.Errorf("Expected no second object on the server, got %v", hrefs)
+	}
+}
+
+func TestCalDAVSync_ReportsTodosSharingATime(t *testing.T) {
+	server := newFakeServer(t)
+	device := newCalendarDevice(t, server)
+	device.add("Standup", at(3, 9), 0)
+	device.sync(nil)
+
+	// A second todo at the time of a linked one leaves both out, not deleted
+	device.add("Call", at(3, 9), 0)
+	device.add("Report", at(4, 17), 1)
+	report, err := device.syncer.Sync(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Failed) != 2 || !strings.Contains(report.Failed[0], "Call") || !strings.Contains(report.Failed[1], "Standup") {
+		t.Fatalf("Expected both todos at 9:00 reported, got %v", report.Failed)
+	}
+	if report.Pushed != 1 || len(server.hrefs()) != 2 {
+		t.Fatalf("Expected only the report pushed, got %+v and %v", report, server.hrefs())
+	}
+	todos, err := device.manager.GetTodosForDay(at(3, 0))
+	if err != nil || len(todos) != 2 {
+		t.Fatalf("Expected both local todos kept, got %d (%v)", len(todos), err)
+	}
+
+	// Moving one of them syncs both again
+	moved := *todos[0]
+	if moved.Name != "Call" {
+		moved = *todos[1]
+	}
+	moved.SetZonedTime(at(3, 10), "")
+	if err := device.manager.UpdateTodo(&moved, at(3, 9)); err != nil {
+		t.Fatal(err)
+	}
+	if report := device.sync(nil); report.Pushed != 1 || len(server.hrefs()) != 3 {
+		t.Errorf("Expected the moved call pushed, got %+v and %v", report, server.hrefs())
+	}
+}
+
+func TestCalDAVSync_RequiresValidServer(t *testing.T) {
+	server := newFakeServer(t)
+	dir := t.TempDir()
+	syncer := caldav.NewSyncer(persistence.NewMonthlyManager(dir), dir)
+
+	if _, err := syncer.Sync(nil); err == nil {
+		t.Error("Expected an error without a server")
+	}
+	if err := syncer.Configure(models.CalDAVConfig{URL: "ftp://example.com/cal"}); err == nil {
+		t.Error("Expected a non-HTTP URL to be refused")
+	}
+	if err := syncer.Configure(models.CalDAVConfig{URL: server.URL(), Username: testUser, Password: "wrong"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := syncer.Sync(nil); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Expected an authentication error, got %v", err)
+	}
+}
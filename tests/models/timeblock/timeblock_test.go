@@ -0,0 +1,100 @@
+package timeblock_test
+
+import (
+	"testing"
+	"time"
+
+	"godo/src/models"
+)
+
+func newEvent(name string, start time.Time, minutes int) *models.TodoItem {
+	todo := models.NewTodoItem()
+	todo.Name = name
+	todo.SetZonedTime(start, "")
+	todo.SetDuration(minutes)
+	return todo
+}
+
+func TestFindConflicts(t *testing.T) {
+	at := func(hour, minute int) time.Time { return time.Date(2025, 11, 19, hour, minute, 0, 0, time.UTC) }
+
+	meeting := newEvent("meeting", at(10, 0), 90)
+	lunch := newEvent("lunch", at(11, 30), 60)
+	call := newEvent("call", at(11, 0), 0)
+	task := newEvent("task", at(10, 30), 30)
+	task.Kind = 1
+	holiday := models.NewTodoItem()
+	holiday.Name = "holiday"
+	holiday.SetAllDay(at(0, 0))
+
+	items := []*models.TodoItem{meeting, lunch, call, task, holiday}
+
+	tests := []struct {
+		name      string
+		candidate *models.TodoItem
+		want      []string
+	}{
+		{"range covers point", meeting, []string{"call"}},
+		{"back-to-back does not overlap", lunch, nil},
+		{"point inside range", call, []string{"meeting"}},
+		{"same start", newEvent("standup", at(10, 0), 0), []string{"meeting"}},
+		{"tasks never conflict", task, nil},
+		{"all-day never conflicts", holiday, nil},
+		{"free slot", newEvent("gym", at(18, 0), 60), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := models.FindConflicts(tt.candidate, items, time.UTC)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %v, got %d conflicts", tt.want, len(got))
+			}
+			for i, todo := range got {
+				if todo.Name != tt.want[i] {
+					t.Errorf("Expected %s, got %s", tt.want[i], todo.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestPlanDay_LanesAndClipping(t *testing.T) {
+	day := time.Date(2025, 11, 19, 0, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+
+	holiday := models.NewTodoItem()
+	holiday.SetAllDay(day)
+	items := []*models.TodoItem{
+		newEvent("a", at(9, 0), 120),
+		newEvent("b", at(10, 0), 60),
+		newEvent("c", at(11, 0), 30),
+		newEvent("d", at(14, 0), 30),
+		newEvent("late", at(23, 0), 120),
+		newEvent("yesterday", at(-2, 0), 60),
+		holiday,
+	}
+
+	allDay, blocks := models.PlanDay(items, day, time.UTC)
+	if len(allDay) != 1 {
+		t.Fatalf("Expected 1 all-day item, got %d", len(allDay))
+	}
+
+	want := map[string][2]int{"a": {0, 2}, "b": {1, 2}, "c": {0, 1}, "d": {0, 1}, "late": {0, 1}}
+	if len(blocks) != len(want) {
+		t.Fatalf("Expected %d blocks, got %d", len(want), len(blocks))
+	}
+	for _, block := range blocks {
+		lanes, ok := want[block.Todo.Name]
+		if !ok {
+			t.Errorf("Unexpected block %s", block.Todo.Name)
+			continue
+		}
+		if block.Lane != lanes[0] || block.Lanes != lanes[1] {
+			t.Errorf("%s: expected lane %d of %d, got %d of %d", block.Todo.Name, lanes[0], lanes[1], block.Lane, block.Lanes)
+		}
+		if block.Todo.Name == "late" && !block.End.Equal(day.AddDate(0, 0, 1)) {
+			t.Errorf("Expected the late block to be clipped at midnight, got %s", block.End)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package persistence_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"godo/src/models"
+	"godo/src/persistence"
+)
+
+// describeEvents lists events as "change:months:names"
+func describeEvents(events []persistence.TodoEvent) string {
+	var parts []string
+	for _, event := range events {
+		var names []string
+		for _, item := range event.Items {
+			names = append(names, item.Name)
+		}
+		parts = append(parts, event.Change.String()+":"+strings.Join(event.Months, "+")+":"+strings.Join(names, "+"))
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestMonthlyManager_PublishesOneEventPerChange(t *testing.T) {
+	repo := persistence.NewMonthlyManager(t.TempDir())
+	var events []persistence.TodoEvent
+	unsubscribe := repo.Events().Subscribe(func(event persistence.TodoEvent) {
+		events = append(events, event)
+	})
+
+	todo := models.NewTodoItem()
+	todo.Name = "Report"
+	todo.SetZonedTime(time.Date(2025, 11, 28, 9, 0, 0, 0, time.Local), "")
+	if err := repo.AddTodo(todo); err != nil {
+		t.Fatal(err)
+	}
+	moved := *todo
+	moved.SetZonedTime(time.Date(2025, 12, 2, 9, 0, 0, 0, time.Local), "")
+	if err := repo.UpdateTodo(&moved, todo.TodoTime); err != nil {
+		t.Fatal(err)
+	}
+	december, err := repo.GetTodosForMonth(2025, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.SaveTodosForMonth(2025, 12, december); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.RemoveTodos([]time.Time{moved.TodoTime}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "added:202511:Report updated:202511+202512:Report saved:202512:Report removed:202512:Report"
+	if got := describeEvents(events); got != want {
+		t.Errorf("Unexpected events\nwant %s\ngot  %s", want, got)
+	}
+
+	// Subscribers get copies, not the cached todos
+	events[0].Items[0].Name = "Changed"
+	if todo.Name != "Report" {
+		t.Error("Expected the event to carry a copy")
+	}
+
+	if !events[1].AffectsDay(time.Date(2025, 11, 30, 0, 0, 0, 0, time.Local)) ||
+		events[1].AffectsDay(time.Date(2026, 1, 15, 0, 0, 0, 0, time.Local)) {
+		t.Error("Expected the move to affect November and December only")
+	}
+	// Days at the edge of a month also show the neighbouring month
+	if !events[2].AffectsDay(time.Date(2025, 11, 30, 0, 0, 0, 0, time.Local)) {
+		t.Error("Expected a change in December to affect the last day of November")
+	}
+
+	unsubscribe()
+	if err := repo.AddTodo(todo); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 4 {
+		t.Errorf("Expected no events after unsubscribing, got %d", len(events))
+	}
+}
+
+func TestTodoTxtRepository_PublishesEditsByOtherPrograms(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.txt")
+	if err := os.WriteFile(path, []byte("Call Ann due:2025-11-03\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repo := persistence.NewTodoTxtRepository(path)
+	if _, err := repo.GetTodosForMonth(2025, 11); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := make(chan persistence.TodoEvent, 1)
+	repo.Events().Subscribe(func(event persistence.TodoEvent) {
+		select {
+		case reloaded <- event:
+		default:
+		}
+	})
+	stop := repo.Watch(10*time.Millisecond, nil)
+	defer stop()
+
+	if err := os.WriteFile(path, []byte("Call Ann due:2025-11-03\nCall Bob due:2025-11-04\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case event := <-reloaded:
+		if event.Change != persistence.TodosReloaded || !event.AffectsDay(time.Now()) {
+			t.Errorf("Expected a reload affecting every day, got %+v", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("Expected the edit to be published")
+	}
+}
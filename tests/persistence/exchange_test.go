@@ -0,0 +1,140 @@
+package persistence_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"godo/src/models"
+	"godo/src/persistence"
+)
+
+// exchangeTodos returns todos using every field the exchange formats carry
+func exchangeTodos(t *testing.T) []*models.TodoItem {
+	berlin := mustZone(t, "Europe/Berlin")
+
+	task := models.NewTodoItem()
+	task.Name = "Write *report*"
+	task.Content = "First draft\n\nthen review"
+	task.Kind = 1
+	task.Level = 3
+	task.Label = "work"
+	task.Place = "Main office"
+	task.WarnTime = 15
+	task.Estimate = 4
+	task.Pomodoros = 2
+	task.Starred = true
+	task.SetZonedTime(time.Date(2025, 11, 4, 17, 0, 0, 0, time.Local), "")
+
+	event := models.NewTodoItem()
+	event.Name = "Meeting"
+	event.Done = true
+	event.SetZonedTime(time.Date(2025, 11, 3, 9, 30, 0, 0, berlin), "Europe/Berlin")
+	event.SetDuration(45)
+
+	allDay := models.NewTodoItem()
+	allDay.Name = "Holiday"
+	allDay.Kind = 1
+	allDay.SetAllDay(time.Date(2025, 11, 6, 0, 0, 0, 0, time.UTC))
+
+	return []*models.TodoItem{task, event, allDay}
+}
+
+// assertSameTodos checks that read holds the same todos as want, in any order
+func assertSameTodos(t *testing.T, want, read []*models.TodoItem) {
+	t.Helper()
+	if len(read) != len(want) {
+		t.Fatalf("Expected %d todos, got %d", len(want), len(read))
+	}
+	for _, todo := range want {
+		found := false
+		for _, other := range read {
+			found = found || models.SameTodo(todo, other)
+		}
+		if !found {
+			t.Errorf("%s: round trip changed the todo\nwant %+v\ngot  %+v", todo.Name, todo, read)
+		}
+	}
+}
+
+func TestTodoMarkdown_RoundTrip(t *testing.T) {
+	todos := exchangeTodos(t)
+	var buf bytes.Buffer
+	if err := persistence.WriteTodoMarkdown(&buf, todos); err != nil {
+		t.Fatal(err)
+	}
+	text := buf.String()
+	for _, want := range []string{"## 2025-11-03 Monday", "- [x] 09:30 **Meeting**", "- [ ] all day **Holiday**", "place: Main office", "  First draft"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in\n%s", want, text)
+		}
+	}
+
+	read, skipped, err := persistence.ReadTodoMarkdown(strings.NewReader(text))
+	if err != nil || len(skipped) > 0 {
+		t.Fatalf("ReadTodoMarkdown failed: %v %v", skipped, err)
+	}
+	assertSameTodos(t, todos, read)
+}
+
+func TestTodoMarkdown_ReportsLines(t *testing.T) {
+	text := "# Plan\n\n" +
+		"- [ ] 10:00 **Too early**\n" +
+		"## 2025-11-03 Monday\n\n" +
+		"- [ ] 25:00 **Bad time**\n" +
+		"- [ ] 11:00 **Fine** — priority: medium\n" +
+		"- [ ] 12:00 **Unknown** — colour: red\n"
+	todos, skipped, err := persistence.ReadTodoMarkdown(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(todos) != 1 || todos[0].Name != "Fine" || todos[0].Level != 1 {
+		t.Errorf("Expected only the fine todo, got %+v", todos)
+	}
+	if len(skipped) != 3 || skipped[0].Line != 3 || skipped[1].Line != 6 || skipped[2].Line != 8 {
+		t.Errorf("Expected lines 3, 6 and 8 skipped, got %v", skipped)
+	}
+}
+
+func TestTodoCSV_RoundTrip(t *testing.T) {
+	todos := exchangeTodos(t)
+	var buf bytes.Buffer
+	if err := persistence.WriteTodoCSV(&buf, todos); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "name,content,place,label,kind,level,todoTime,") {
+		t.Errorf("Unexpected header in\n%s", buf.String())
+	}
+
+	read, skipped, err := persistence.ReadTodoCSV(&buf)
+	if err != nil || len(skipped) > 0 {
+		t.Fatalf("ReadTodoCSV failed: %v %v", skipped, err)
+	}
+	assertSameTodos(t, todos, read)
+}
+
+func TestTodoCSV_Validates(t *testing.T) {
+	text := "\ufefftodoTime,name,level\n" +
+		"2025-11-03 09:30,Standup,2\n" +
+		"2025-11-03 10:00,Too urgent,9\n" +
+		"tomorrow,No time,1\n" +
+		",,\n"
+	todos, skipped, err := persistence.ReadTodoCSV(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(todos) != 1 || todos[0].Name != "Standup" || todos[0].Level != 2 ||
+		!todos[0].TodoTime.Equal(time.Date(2025, 11, 3, 9, 30, 0, 0, time.Local)) {
+		t.Errorf("Expected the standup at 09:30, got %+v", todos)
+	}
+	if len(skipped) != 3 || skipped[0].Line != 3 || skipped[1].Line != 4 || skipped[2].Line != 5 {
+		t.Errorf("Expected lines 3 to 5 skipped, got %v", skipped)
+	}
+
+	for _, header := range []string{"name,colour,todoTime\n", "name,name,todoTime\n", "name,level\n", ""} {
+		if _, _, err := persistence.ReadTodoCSV(strings.NewReader(header)); err == nil {
+			t.Errorf("Expected header %q to be rejected", header)
+		}
+	}
+}
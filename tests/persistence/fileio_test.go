@@ -0,0 +1,261 @@
+package persistence_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"godo/src/models"
+	"godo/src/persistence"
+)
+
+const healthyMonth = `version: 1
+todos:
+    - name: first
+      level: 2
+      kind: 1
+      todotime: 2025-11-19T10:00:00Z
+    - name: second
+      level: 0
+      kind: 0
+      todotime: 2025-11-18T09:00:00Z
+`
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoadTodos_Healthy(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "202511.yaml", healthyMonth)
+
+	todos, err := persistence.NewFileIOManager(dir).LoadTodos(2025, 11)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(todos) != 2 {
+		t.Fatalf("Expected 2 todos, got %d", len(todos))
+	}
+}
+
+func TestLoadTodos_CorruptYAMLIsReportedAndQuarantined(t *testing.T) {
+	dir := t.TempDir()
+	content := "version: 1\ntodos:\n    - name: ok\n      level: [unclosed\n"
+	path := writeFile(t, dir, "202511.yaml", content)
+
+	todos, err := persistence.NewFileIOManager(dir).LoadTodos(2025, 11)
+	if todos != nil {
+		t.Fatalf("Expected no todos for a corrupt file, got %d", len(todos))
+	}
+
+	var corrupt *persistence.CorruptFileError
+	if !errors.As(err, &corrupt) {
+		t.Fatalf("Expected CorruptFileError, got %v", err)
+	}
+	if corrupt.Path != path {
+		t.Errorf("Expected path %s, got %s", path, corrupt.Path)
+	}
+	if corrupt.Line == 0 {
+		t.Error("Expected a line number in the error")
+	}
+	if corrupt.QuarantinePath == "" {
+		t.Fatal("Expected the corrupt file to be quarantined")
+	}
+
+	copied, err := os.ReadFile(corrupt.QuarantinePath)
+	if err != nil || string(copied) != content {
+		t.Fatalf("Quarantine copy does not match the original: %v", err)
+	}
+	original, _ := os.ReadFile(path)
+	if string(original) != content {
+		t.Fatal("Original file must not be modified by loading")
+	}
+}
+
+func TestLoadTodos_BadFieldReportsLine(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "202511.yaml", "version: 1\ntodos:\n    - name: ok\n      level: 1\n    - name: bad\n      level: high\n")
+
+	_, err := persistence.NewFileIOManager(dir).LoadTodos(2025, 11)
+	var corrupt *persistence.CorruptFileError
+	if !errors.As(err, &corrupt) {
+		t.Fatalf("Expected CorruptFileError, got %v", err)
+	}
+	if corrupt.Line != 6 {
+		t.Errorf("Expected line 6, got %d", corrupt.Line)
+	}
+}
+
+func TestLoadTodos_CorruptLegacyTxt(t *testing.T) {
+	dir := t.TempDir()
+	// Second record has a non-numeric level on line 11
+	content := "2\n1\nfirst\n1\nwork\n1\n2025 11 19 10 0\n1\n\n1\n\nfalse 0 0\n1\nsecond\n1\n\nhigh\n"
+	writeFile(t, dir, "202511.txt", content)
+
+	_, err := persistence.NewFileIOManager(dir).LoadTodos(2025, 11)
+	var corrupt *persistence.CorruptFileError
+	if !errors.As(err, &corrupt) {
+		t.Fatalf("Expected CorruptFileError, got %v", err)
+	}
+	if corrupt.Line != 17 {
+		t.Errorf("Expected line 17, got %d", corrupt.Line)
+	}
+}
+
+func TestSaveRefusesToOverwriteCorruptMonth(t *testing.T) {
+	dir := t.TempDir()
+	content := "version: 1\ntodos: [\n"
+	path := writeFile(t, dir, "202511.yaml", content)
+
+	manager := persistence.NewMonthlyManager(dir)
+	todos, err := manager.GetTodosForMonth(2025, 11)
+	if err == nil {
+		t.Fatalf("Expected an error, got %d todos", len(todos))
+	}
+
+	original, _ := os.ReadFile(path)
+	if string(original) != content {
+		t.Fatal("Corrupt file was overwritten")
+	}
+}
+
+func TestRepairMonth_SalvagesNormalizesAndDedupes(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "202511.yaml", `version: 1
+todos:
+    - name: keep
+      level: 9
+      kind: 4
+      todotime: 2025-11-19T10:00:00Z
+    - name: broken
+      level: high
+      todotime: 2025-11-19T11:00:00Z
+    - name: keep
+      level: 1
+      todotime: 2025-11-19T10:00:00Z
+    - name: other
+      level: -2
+      kind: 1
+      todotime: 2025-11-20T08:00:00Z
+`)
+
+	manager := persistence.NewMonthlyManager(dir)
+	report, err := manager.RepairMonth(2025, 11)
+	if err != nil {
+		t.Fatalf("Repair failed: %v", err)
+	}
+
+	if report.Salvaged != 2 || report.Dropped != 1 || report.Normalized != 2 || report.Duplicates != 1 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	if report.QuarantinePath == "" {
+		t.Error("Expected the original file to be quarantined")
+	}
+
+	todos, err := manager.GetTodosForMonth(2025, 11)
+	if err != nil {
+		t.Fatalf("Repaired month should load: %v", err)
+	}
+	if len(todos) != 2 {
+		t.Fatalf("Expected 2 todos after repair, got %d", len(todos))
+	}
+	for _, todo := range todos {
+		if todo.Level < 0 || todo.Level > 3 || (todo.Kind != 0 && todo.Kind != 1) {
+			t.Errorf("Todo %q was not normalized: level=%d kind=%d", todo.Name, todo.Level, todo.Kind)
+		}
+	}
+}
+
+func TestRepairMonth_SyntaxErrorSalvagesOtherRecords(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "202511.yaml", `version: 1
+todos:
+    - name: first
+      todotime: 2025-11-19T10:00:00Z
+    - name: [broken
+      todotime: 2025-11-19T11:00:00Z
+    - name: third
+      todotime: 2025-11-19T12:00:00Z
+`)
+
+	report, err := persistence.NewFileIOManager(dir).RepairTodos(2025, 11)
+	if err != nil {
+		t.Fatalf("Repair failed: %v", err)
+	}
+	if report.Salvaged != 2 || report.Dropped != 1 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+}
+
+func TestRepairMonth_LegacyTxtCountsDroppedRecords(t *testing.T) {
+	dir := t.TempDir()
+	// Four records announced, the second is broken, so it and the two after it are lost
+	content := "4\n1\nfirst\n1\nwork\n1\n2025 11 19 10 0\n1\n\n1\n\nfalse 0 0\n1\nsecond\n1\n\nhigh\n"
+	writeFile(t, dir, "202511.txt", content)
+
+	report, err := persistence.NewFileIOManager(dir).RepairTodos(2025, 11)
+	if err != nil {
+		t.Fatalf("Repair failed: %v", err)
+	}
+	if report.Salvaged != 1 || report.Dropped != 3 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+}
+
+func TestRepairMonth_HealthyFileUntouched(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "202511.yaml", healthyMonth)
+
+	report, err := persistence.NewFileIOManager(dir).RepairTodos(2025, 11)
+	if err != nil {
+		t.Fatalf("Repair failed: %v", err)
+	}
+	if report.Changed() || report.QuarantinePath != "" {
+		t.Errorf("Healthy file should not be changed: %+v", report)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != healthyMonth {
+		t.Error("Healthy file was rewritten")
+	}
+}
+
+func TestDurationAndAllDayRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	manager := persistence.NewMonthlyManager(dir)
+
+	event := newZonedTodo("workshop", time.Date(2025, 11, 19, 10, 0, 0, 0, time.UTC), "")
+	event.SetDuration(90)
+	holiday := models.NewTodoItem()
+	holiday.Name = "holiday"
+	holiday.SetAllDay(time.Date(2025, 11, 20, 15, 0, 0, 0, time.UTC))
+	for _, todo := range []*models.TodoItem{event, holiday} {
+		if err := manager.AddTodo(todo); err != nil {
+			t.Fatalf("AddTodo failed: %v", err)
+		}
+	}
+
+	todos, err := persistence.NewFileIOManager(dir).LoadTodos(2025, 11)
+	if err != nil || len(todos) != 2 {
+		t.Fatalf("Expected 2 todos, got %d (%v)", len(todos), err)
+	}
+	for _, todo := range todos {
+		switch todo.Name {
+		case "workshop":
+			if todo.Duration != 90 || !todo.EndTime(time.UTC).Equal(time.Date(2025, 11, 19, 11, 30, 0, 0, time.UTC)) {
+				t.Errorf("Duration did not round-trip: %+v", todo)
+			}
+		case "holiday":
+			start, end := todo.TimeRange(time.UTC)
+			if !todo.AllDay || start.Day() != 20 || start.Hour() != 0 || end.Sub(start) != 24*time.Hour {
+				t.Errorf("All-day item did not round-trip: %+v", todo)
+			}
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package persistence_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"godo/src/persistence"
+)
+
+func TestReadTodoist_CSV(t *testing.T) {
+	text := "TYPE,CONTENT,DESCRIPTION,PRIORITY,INDENT,AUTHOR,RESPONSIBLE,DATE,DATE_LANG,TIMEZONE,DURATION,DURATION_UNIT\n" +
+		"section,Errands,,,,,,,,,,\n" +
+		"task,Buy milk @shopping @home,Semi-skimmed,1,1,,,2025-11-07 18:00,en,Europe/Berlin,30,minute\n" +
+		"note,Two bottles,,,,,,,,,,\n" +
+		"task,Water plants,,4,1,,,every monday,en,,,\n" +
+		"task,Pay rent,,2,1,,,Nov 1 2025,en,,,\n" +
+		"task,Broken,,7,1,,,,,,,\n"
+	tasks, skipped, err := persistence.ReadTodoist(strings.NewReader(text), "Home")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 3 || len(skipped) != 1 || skipped[0].Line != 7 {
+		t.Fatalf("Expected 3 tasks and line 7 skipped, got %d %v", len(tasks), skipped)
+	}
+
+	milk := tasks[0]
+	berlin := mustZone(t, "Europe/Berlin")
+	if milk.Todo.Name != "Buy milk" || milk.Todo.Label != "Home" || milk.Todo.Level != 3 || milk.Todo.Kind != 1 ||
+		!milk.Dated || !milk.Todo.TodoTime.Equal(time.Date(2025, 11, 7, 18, 0, 0, 0, berlin)) || milk.Todo.Duration != 30 {
+		t.Errorf("Unexpected milk task %+v", milk.Todo)
+	}
+	if strings.Join(milk.Labels, ",") != "shopping,home" {
+		t.Errorf("Expected the labels, got %v", milk.Labels)
+	}
+	for _, want := range []string{"Section: Errands", "Semi-skimmed", "Labels: shopping, home", "Two bottles"} {
+		if !strings.Contains(milk.Todo.Content, want) {
+			t.Errorf("Expected %q in %q", want, milk.Todo.Content)
+		}
+	}
+
+	if plants := tasks[1]; plants.Dated || plants.Todo.Level != 0 || !strings.Contains(plants.Todo.Content, "Todoist date: every monday") {
+		t.Errorf("Expected an undated task keeping its recurrence, got %+v", plants.Todo)
+	}
+	if rent := tasks[2]; !rent.Todo.AllDay || rent.Todo.DisplayTime(time.Local).Day() != 1 || rent.Todo.Level != 2 {
+		t.Errorf("Expected an all-day task on the 1st, got %+v", rent.Todo)
+	}
+}
+
+func TestReadTodoist_JSON(t *testing.T) {
+	text := `{
+		"projects": [{"id": "p1", "name": "Work"}],
+		"items": [
+			{"id": "1", "content": "Ship release", "project_id": "p1", "labels": ["deploy"], "priority": 4,
+			 "checked": true, "due": {"date": "2025-11-04T09:00:00Z", "timezone": "Europe/Berlin"}},
+			{"id": "2", "content": "Someday", "project_id": "p1", "priority": 1},
+			{"id": "3", "content": "Gone", "is_deleted": true}
+		],
+		"reminders": [{"item_id": "1", "type": "relative", "minute_offset": 30}]
+	}`
+	tasks, skipped, err := persistence.ReadTodoist(strings.NewReader(text), "")
+	if err != nil || len(skipped) != 0 || len(tasks) != 2 {
+		t.Fatalf("Expected 2 tasks, got %v %v (%v)", tasks, skipped, err)
+	}
+	ship := tasks[0].Todo
+	if ship.Label != "Work" || ship.Level != 3 || !ship.Done || ship.WarnTime != 30 || ship.TimeZone != "Europe/Berlin" ||
+		!ship.TodoTime.Equal(time.Date(2025, 11, 4, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected task %+v", ship)
+	}
+	if tasks[1].Dated || tasks[1].Todo.Level != 0 {
+		t.Errorf("Expected an undated low priority task, got %+v", tasks[1].Todo)
+	}
+}
+
+func TestReadTrello(t *testing.T) {
+	text := `{
+		"name": "Launch",
+		"lists": [{"id": "l1", "name": "Doing"}, {"id": "l2", "name": "Old", "closed": true}],
+		"labels": [{"id": "a", "name": "High priority"}, {"id": "b", "name": "", "color": "green"}],
+		"cards": [
+			{"id": "c1", "name": "Write copy", "desc": "For the landing page", "idList": "l1", "idLabels": ["a", "b"],
+			 "due": "2025-11-05T15:00:00.000Z", "dueComplete": true, "dueReminder": 60},
+			{"id": "c2", "name": "Archived", "idList": "l1", "closed": true},
+			{"id": "c3", "name": "On old list", "idList": "l2"},
+			{"id": "c4", "name": "No date", "idList": "l1", "dueReminder": -1},
+			{"id": "c5", "name": "Bad date", "idList": "l1", "due": "soon"}
+		],
+		"checklists": [{"idCard": "c1", "name": "Steps", "checkItems": [{"name": "Draft", "state": "complete"}, {"name": "Review", "state": "incomplete"}]}]
+	}`
+	tasks, skipped, err := persistence.ReadTrello(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 2 || len(skipped) != 1 || skipped[0].Line != 5 || skipped[0].Text != "Bad date" {
+		t.Fatalf("Expected 2 tasks and card 5 skipped, got %d %v", len(tasks), skipped)
+	}
+	card := tasks[0].Todo
+	if card.Label != "Launch" || card.Level != 2 || !card.Done || card.WarnTime != 60 ||
+		!card.TodoTime.Equal(time.Date(2025, 11, 5, 15, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected card %+v", card)
+	}
+	for _, want := range []string{"List: Doing", "For the landing page", "- [x] Draft", "- [ ] Review", "Labels: High priority, green"} {
+		if !strings.Contains(card.Content, want) {
+			t.Errorf("Expected %q in %q", want, card.Content)
+		}
+	}
+	if tasks[1].Dated || tasks[1].Todo.WarnTime != 0 {
+		t.Errorf("Expected an undated card without reminder, got %+v", tasks[1].Todo)
+	}
+
+	if _, _, err := persistence.ReadTrello(strings.NewReader(`{"name": "Not a board"}`)); err == nil {
+		t.Error("Expected an error for JSON without cards")
+	}
+}
+
+func TestReadGoogleTasks(t *testing.T) {
+	takeout := `{"kind": "tasks#taskLists", "items": [
+		{"kind": "tasks#taskList", "title": "Personal", "items": [
+			{"kind": "tasks#task", "title": "Renew passport", "notes": "Bring photos", "status": "needsAction", "due": "2025-11-10T00:00:00.000Z"},
+			{"kind": "tasks#task", "title": "Old", "status": "completed"},
+			{"kind": "tasks#task", "title": "Removed", "deleted": true},
+			{"kind": "tasks#task", "title": ""}
+		]},
+		{"kind": "tasks#taskList", "title": "Empty"}
+	]}`
+	tasks, skipped, err := persistence.ReadGoogleTasks(strings.NewReader(takeout), "Tasks")
+	if err != nil || len(skipped) != 0 || len(tasks) != 2 {
+		t.Fatalf("Expected 2 tasks, got %v %v (%v)", tasks, skipped, err)
+	}
+	passport := tasks[0].Todo
+	if passport.Label != "Personal" || !passport.AllDay || passport.DisplayTime(time.Local).Day() != 10 || passport.Content != "Bring photos" {
+		t.Errorf("Unexpected task %+v", passport)
+	}
+	if !tasks[1].Todo.Done || tasks[1].Dated {
+		t.Errorf("Expected an undated completed task, got %+v", tasks[1].Todo)
+	}
+
+	single := `{"kind": "tasks#tasks", "items": [{"kind": "tasks#task", "title": "Call bank", "status": "needsAction"}]}`
+	tasks, _, err = persistence.ReadGoogleTasks(strings.NewReader(single), "Errands")
+	if err != nil || len(tasks) != 1 || tasks[0].Todo.Label != "Errands" {
+		t.Errorf("Expected a task of the named list, got %v (%v)", tasks, err)
+	}
+}
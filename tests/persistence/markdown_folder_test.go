@@ -0,0 +1,159 @@
+package persistence_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"godo/src/models"
+	"godo/src/persistence"
+)
+
+func TestMarkdownFolderRepository_NotePerTodo(t *testing.T) {
+	root := t.TempDir()
+	repo := persistence.NewMarkdownFolderRepository(root)
+
+	standup := models.NewTodoItem()
+	standup.Name = "Standup: daily"
+	standup.Content = "Agenda\n\n- blockers"
+	standup.Place = "Room 1"
+	standup.Level = 2
+	standup.SetZonedTime(time.Date(2025, 11, 3, 9, 30, 0, 0, time.Local), "")
+	holiday := models.NewTodoItem()
+	holiday.Name = "Holiday"
+	holiday.Kind = 1
+	holiday.SetAllDay(time.Date(2025, 12, 24, 0, 0, 0, 0, time.Local))
+	for _, todo := range []*models.TodoItem{standup, holiday} {
+		if err := repo.AddTodo(todo); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	standupPath := filepath.Join(root, "2025", "11", "2025-11-03 0930 Standup- daily.md")
+	data, err := os.ReadFile(standupPath)
+	if err != nil {
+		t.Fatalf("Expected the note of the standup: %v", err)
+	}
+	if text := string(data); !strings.HasPrefix(text, "---\nname: 'Standup: daily'\n") ||
+		!strings.Contains(text, "place: Room 1\n") || !strings.HasSuffix(text, "---\n\nAgenda\n\n- blockers\n") {
+		t.Errorf("Unexpected note:\n%s", data)
+	}
+	if _, err := os.Stat(filepath.Join(root, "2025", "12", "2025-12-24 Holiday.md")); err != nil {
+		t.Errorf("Expected the note of the holiday: %v", err)
+	}
+
+	// A fresh repository reads the same todos back
+	reread := persistence.NewMarkdownFolderRepository(root)
+	for _, want := range []*models.TodoItem{standup, holiday} {
+		got, err := reread.GetTodoByTime(want.TodoTime)
+		if err != nil || !models.SameTodo(want, got) {
+			t.Errorf("%s: round trip changed the todo\nwant %+v\ngot  %+v (%v)", want.Name, want, got, err)
+		}
+	}
+
+	// Renaming or moving a todo renames its note
+	renamed := *standup
+	renamed.Name = "Standup"
+	if err := repo.UpdateTodo(&renamed, standup.TodoTime); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "2025", "11", "2025-11-03 0930 Standup.md")); err != nil {
+		t.Errorf("Expected the note renamed: %v", err)
+	}
+	moved := renamed
+	moved.SetZonedTime(time.Date(2025, 10, 31, 10, 0, 0, 0, time.Local), "")
+	if err := repo.UpdateTodo(&moved, renamed.TodoTime); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "2025", "10", "2025-10-31 1000 Standup.md")); err != nil {
+		t.Errorf("Expected the note moved: %v", err)
+	}
+	entries, _ := os.ReadDir(filepath.Join(root, "2025", "11"))
+	if len(entries) != 0 {
+		t.Errorf("Expected the old notes removed, found %d", len(entries))
+	}
+	if months, _ := repo.GetAllMonths(); strings.Join(months, ",") != "202510,202512" {
+		t.Errorf("Expected October and December, got %v", months)
+	}
+}
+
+func TestMarkdownFolderRepository_SharesNotesWithOtherTools(t *testing.T) {
+	root := t.TempDir()
+	month := filepath.Join(root, "2025", "11")
+	if err := os.MkdirAll(month, 0755); err != nil {
+		t.Fatal(err)
+	}
+	note := "---\n" +
+		"tags: [work]\n" +
+		"name: Write report\n" +
+		"kind: 1\n" +
+		"level: 3\n" +
+		"todotime: 2025-11-04T17:00:00Z\n" +
+		"done: false\n" +
+		"---\n\nDraft first\n"
+	files := map[string]string{
+		"My report.md":   note,
+		"Meeting log.md": "# Notes\n\nNot a todo\n",
+		"Broken.md":      "---\nname: [\n---\n",
+	}
+	for name, text := range files {
+		if err := os.WriteFile(filepath.Join(month, name), []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	repo := persistence.NewMarkdownFolderRepository(root)
+
+	todos, err := repo.GetTodosForMonth(2025, 11)
+	if err != nil || len(todos) != 1 {
+		t.Fatalf("Expected the one todo, got %v (%v)", todos, err)
+	}
+	report := todos[0]
+	if report.Name != "Write report" || report.Content != "Draft first" || report.Level != 3 {
+		t.Errorf("Unexpected todo %+v", report)
+	}
+
+	// Editing in Go Do keeps the file name and the keys of other tools
+	updated := *report
+	updated.Done = true
+	if err := repo.UpdateTodo(&updated, report.TodoTime); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(month, "My report.md"))
+	if err != nil {
+		t.Fatalf("Expected the note to keep its name: %v", err)
+	}
+	if text := string(data); !strings.HasPrefix(text, "---\ntags: [work]\nname: Write report\n") || !strings.Contains(text, "done: true\n") {
+		t.Errorf("Unexpected note:\n%s", data)
+	}
+	if plain, _ := os.ReadFile(filepath.Join(month, "Meeting log.md")); string(plain) != files["Meeting log.md"] {
+		t.Errorf("Expected other notes untouched, got %q", plain)
+	}
+
+	// Edits and renames by another tool are seen by the watcher and the next read
+	changed := make(chan struct{}, 1)
+	stop := repo.Watch(10*time.Millisecond, func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	})
+	defer stop()
+	edited := strings.Replace(string(data), "name: Write report", "name: Write the report", 1)
+	if err := os.WriteFile(filepath.Join(month, "Report.md"), []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(month, "My report.md")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changed:
+	case <-time.After(2 * time.Second):
+		t.Error("Expected the watcher to report the edit")
+	}
+	todo, err := repo.GetTodoByTime(report.TodoTime)
+	if err != nil || todo.Name != "Write the report" || !todo.Done {
+		t.Errorf("Expected the renamed todo, got %+v (%v)", todo, err)
+	}
+}
@@ -0,0 +1,316 @@
+package persistence_test
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"godo/src/models"
+	"godo/src/persistence"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite migration golden files")
+
+// runGolden applies the registry step from version from to an input file and
+// compares the result with the golden file.
+func runGolden(t *testing.T, registry *persistence.MigrationRegistry, from int, input, golden string) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "migrations", input))
+	if err != nil {
+		t.Fatalf("Failed to read input: %v", err)
+	}
+
+	got, err := registry.Steps()[from].Apply(data)
+	if err != nil {
+		t.Fatalf("Migration failed: %v", err)
+	}
+
+	goldenPath := filepath.Join("testdata", "migrations", golden)
+	if *updateGolden {
+		if err := os.WriteFile(goldenPath, got, 0644); err != nil {
+			t.Fatalf("Failed to update golden file: %v", err)
+		}
+	}
+	want, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("Failed to read golden file (run with -update to create it): %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("Migration output differs from %s:\n--- got ---\n%s\n--- want ---\n%s", golden, got, want)
+	}
+}
+
+func TestMonthlyMigration_V0BareList(t *testing.T) {
+	runGolden(t, persistence.MonthlyMigrations(), 0, "monthly_v0_list.yaml", "monthly_v0_list.golden.yaml")
+}
+
+func TestMonthlyMigration_V0ListAfterMarkerAndComment(t *testing.T) {
+	runGolden(t, persistence.MonthlyMigrations(), 0, "monthly_v0_list_marker.yaml", "monthly_v0_list_marker.golden.yaml")
+}
+
+func TestMonthlyMigration_V0UnversionedWrapper(t *testing.T) {
+	runGolden(t, persistence.MonthlyMigrations(), 0, "monthly_v0_wrapper.yaml", "monthly_v0_wrapper.golden.yaml")
+}
+
+func TestMonthlyMigration_V0LegacyTxt(t *testing.T) {
+	runGolden(t, persistence.MonthlyMigrations(), 0, "monthly_v0_legacy.txt", "monthly_v0_legacy.golden.yaml")
+}
+
+func TestMonthlyMigration_V1StampsZones(t *testing.T) {
+	t.Setenv("TZ", "Europe/Berlin")
+	runGolden(t, persistence.MonthlyMigrations(), 1, "monthly_v1_zones.yaml", "monthly_v1_zones.golden.yaml")
+}
+
+func TestMonthlyMigration_V2StampsVersion(t *testing.T) {
+	runGolden(t, persistence.MonthlyMigrations(), 2, "monthly_v2_durations.yaml", "monthly_v2_durations.golden.yaml")
+}
+
+func TestMonthlyMigration_V3StampsVersion(t *testing.T) {
+	runGolden(t, persistence.MonthlyMigrations(), 3, "monthly_v3_estimates.yaml", "monthly_v3_estimates.golden.yaml")
+}
+
+func TestConfigMigration_V0Unversioned(t *testing.T) {
+	runGolden(t, persistence.ConfigMigrations(), 0, "config_v0.json", "config_v0.golden.json")
+}
+
+func TestConfigMigration_LaterStepsOnlyStampVersion(t *testing.T) {
+	steps := persistence.ConfigMigrations().Steps()
+	for _, step := range steps[1:] {
+		input := fmt.Sprintf(`{"version": "%d.0", "ui": {"theme": "dark", "dayLayout": "planner"}}`, step.From)
+		got, err := step.Apply([]byte(input))
+		if err != nil {
+			t.Fatalf("Migration from %d failed: %v", step.From, err)
+		}
+		want := fmt.Sprintf(`"version": "%d.0"`, step.From+1)
+		if !strings.Contains(string(got), want) || !strings.Contains(string(got), `"dayLayout": "planner"`) {
+			t.Errorf("Expected %s with the settings kept, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestConfigManager_SavesCurrentVersion(t *testing.T) {
+	dir := t.TempDir()
+	manager := persistence.NewConfigManager(dir)
+	config := models.NewDefaultConfig()
+	config.Version = "1.0"
+	if err := manager.SaveConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(manager.GetConfigPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := fmt.Sprintf(`"version": "%d.0"`, persistence.CurrentConfigVersion); !strings.Contains(string(data), want) {
+		t.Errorf("Expected %s in the saved config, got:\n%s", want, data)
+	}
+}
+
+func TestMigrationRegistry_StepsAreContiguous(t *testing.T) {
+	for _, registry := range []*persistence.MigrationRegistry{persistence.MonthlyMigrations(), persistence.ConfigMigrations(), persistence.PomodoroMigrations()} {
+		steps := registry.Steps()
+		if len(steps) != registry.Current() {
+			t.Fatalf("Expected %d steps, got %d", registry.Current(), len(steps))
+		}
+		for i, step := range steps {
+			if step.From != i {
+				t.Errorf("Expected step from version %d, got %d", i, step.From)
+			}
+		}
+	}
+}
+
+func TestMigrationRegistry_RunsStepsInOrder(t *testing.T) {
+	registry := persistence.NewMigrationRegistry("test", 3)
+	for _, from := range []int{2, 0, 1} {
+		from := from
+		registry.Register(persistence.Migration{From: from, Apply: func(data []byte) ([]byte, error) {
+			return append(data, byte('0'+from)), nil
+		}})
+	}
+
+	got, err := registry.Migrate([]byte("v"), 1)
+	if err != nil {
+		t.Fatalf("Migration failed: %v", err)
+	}
+	if string(got) != "v12" {
+		t.Errorf("Expected v12, got %s", got)
+	}
+}
+
+func TestMigrationRegistry_RefusesNewerVersion(t *testing.T) {
+	_, err := persistence.MonthlyMigrations().Migrate([]byte("version: 99\n"), 99)
+	var newer *persistence.NewerVersionError
+	if !errors.As(err, &newer) {
+		t.Fatalf("Expected NewerVersionError, got %v", err)
+	}
+}
+
+func TestLoadTodos_UpgradesAndWritesBack(t *testing.T) {
+	dir := t.TempDir()
+	input, _ := os.ReadFile(filepath.Join("testdata", "migrations", "monthly_v0_list.yaml"))
+	path := writeFile(t, dir, "202511.yaml", string(input))
+
+	todos, err := persistence.NewFileIOManager(dir).LoadTodos(2025, 11)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(todos) != 2 {
+		t.Fatalf("Expected 2 todos, got %d", len(todos))
+	}
+
+	upgraded, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(upgraded), fmt.Sprintf("version: %d\n", persistence.CurrentMonthlyVersion)) {
+		t.Errorf("Expected the upgraded file to be written back, got:\n%s", upgraded)
+	}
+}
+
+func TestLoadTodos_UnversionedWrapperIsNotQuarantined(t *testing.T) {
+	dir := t.TempDir()
+	input, _ := os.ReadFile(filepath.Join("testdata", "migrations", "monthly_v0_wrapper.yaml"))
+	writeFile(t, dir, "202511.yaml", string(input))
+
+	todos, err := persistence.NewFileIOManager(dir).LoadTodos(2025, 11)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(todos) != 2 {
+		t.Fatalf("Expected 2 todos, got %d", len(todos))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "quarantine")); !os.IsNotExist(err) {
+		t.Errorf("Expected nothing quarantined, got %v", err)
+	}
+}
+
+func TestLoadTodos_LegacyTxtMigratesToYAML(t *testing.T) {
+	dir := t.TempDir()
+	input, _ := os.ReadFile(filepath.Join("testdata", "migrations", "monthly_v0_legacy.txt"))
+	writeFile(t, dir, "202511.txt", string(input))
+
+	manager := persistence.NewMonthlyManager(dir)
+	if err := manager.MigrateAll(); err != nil {
+		t.Fatalf("MigrateAll failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "202511.yaml")); err != nil {
+		t.Fatalf("Expected YAML file after migration: %v", err)
+	}
+
+	todos, err := manager.GetTodosForMonth(2025, 11)
+	if err != nil || len(todos) != 2 {
+		t.Fatalf("Expected 2 migrated todos, got %d (%v)", len(todos), err)
+	}
+}
+
+func TestLoadTodos_NewerVersionIsNotTouched(t *testing.T) {
+	dir := t.TempDir()
+	content := "version: 7\ntodos: []\n"
+	path := writeFile(t, dir, "202511.yaml", content)
+
+	manager := persistence.NewMonthlyManager(dir)
+	_, err := manager.GetTodosForMonth(2025, 11)
+	var newer *persistence.NewerVersionError
+	if !errors.As(err, &newer) {
+		t.Fatalf("Expected NewerVersionError, got %v", err)
+	}
+	if newer.Version != 7 || newer.Path != path {
+		t.Errorf("Unexpected error details: %+v", newer)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != content {
+		t.Error("Newer file must not be modified")
+	}
+}
+
+func TestLoadConfig_UpgradesAndRefusesNewer(t *testing.T) {
+	dir := t.TempDir()
+	input, _ := os.ReadFile(filepath.Join("testdata", "migrations", "config_v0.json"))
+	path := writeFile(t, dir, "config.json", string(input))
+
+	manager := persistence.NewConfigManager(dir)
+	config, err := manager.LoadConfig()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	current := fmt.Sprintf("%d.0", persistence.CurrentConfigVersion)
+	if config.Version != current || config.GetTheme() != "light" || config.GetViewMode() != "all" {
+		t.Errorf("Unexpected upgraded config: %+v", config)
+	}
+
+	newerContent := fmt.Sprintf(`{"version": "%d.0", "ui": {"theme": "dark"}}`, persistence.CurrentConfigVersion+1)
+	writeFile(t, dir, "config.json", newerContent)
+	_, err = manager.LoadConfig()
+	var newer *persistence.NewerVersionError
+	if !errors.As(err, &newer) {
+		t.Fatalf("Expected NewerVersionError, got %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != newerContent {
+		t.Error("Newer config must not be modified")
+	}
+}
+
+func TestLoadSession_UpgradesAndRefusesNewer(t *testing.T) {
+	dir := t.TempDir()
+	store := persistence.NewPomodoroStore(dir)
+	writeFile(t, dir, "pomodoro.json", `{"version": 1, "timer": {"state": "work", "sessions": 2}}`)
+	session, err := store.LoadSession()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if session.Timer.State != models.PomodoroWork || session.Timer.Sessions != 2 {
+		t.Errorf("Unexpected upgraded session: %+v", session)
+	}
+
+	newerContent := fmt.Sprintf(`{"version": %d, "timer": {"state": "idle"}}`, persistence.CurrentPomodoroVersion+1)
+	path := writeFile(t, dir, "pomodoro.json", newerContent)
+	_, err = store.LoadSession()
+	var newer *persistence.NewerVersionError
+	if !errors.As(err, &newer) {
+		t.Fatalf("Expected NewerVersionError, got %v", err)
+	}
+	if newer.Path != path {
+		t.Errorf("Expected the error to name %s, got %s", path, newer.Path)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != newerContent {
+		t.Error("Newer session must not be modified")
+	}
+}
+
+func TestConfigManager_KeepsConfigPrivate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows has no permission bits")
+	}
+	dir := filepath.Join(t.TempDir(), "data")
+	manager := persistence.NewConfigManager(dir)
+	config := models.NewDefaultConfig()
+	config.API.Token = "secret"
+	if err := manager.SaveConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range map[string]os.FileMode{dir: 0700, manager.GetConfigPath(): 0600} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != want {
+			t.Errorf("Expected %s to have mode %v, got %v", path, want, info.Mode().Perm())
+		}
+	}
+
+	// A config written readable by everyone is restricted when loaded
+	if err := os.Chmod(manager.GetConfigPath(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.LoadConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(manager.GetConfigPath()); err != nil || info.Mode().Perm() != 0600 {
+		t.Error("Expected the loaded config restricted to its owner")
+	}
+}
@@ -0,0 +1,295 @@
+package persistence_test
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"godo/src/models"
+	"godo/src/persistence"
+)
+
+// device is one computer syncing its own data directory with a shared folder
+type device struct {
+	t       *testing.T
+	manager *persistence.MonthlyManager
+	syncer  *persistence.Syncer
+}
+
+func newDevice(t *testing.T, remoteDir string) *device {
+	t.Helper()
+	dir := t.TempDir()
+	manager := persistence.NewMonthlyManager(dir)
+	syncer := persistence.NewSyncer(manager, dir)
+	if err := syncer.SetRemoteDir(remoteDir); err != nil {
+		t.Fatalf("SetRemoteDir failed: %v", err)
+	}
+	return &device{t: t, manager: manager, syncer: syncer}
+}
+
+func (d *device) sync(resolved []*persistence.SyncConflict) *persistence.SyncReport {
+	d.t.Helper()
+	report, err := d.syncer.Sync(resolved)
+	if err != nil {
+		d.t.Fatalf("Sync failed: %v", err)
+	}
+	if len(report.Failed) > 0 {
+		d.t.Fatalf("Sync failed for %v", report.Failed)
+	}
+	return report
+}
+
+func (d *device) add(name string, at time.Time) {
+	d.t.Helper()
+	if err := d.manager.AddTodo(newZonedTodo(name, at, "")); err != nil {
+		d.t.Fatalf("AddTodo failed: %v", err)
+	}
+}
+
+// edit changes a copy of the todo at at, as the todo form does
+func (d *device) edit(at time.Time, change func(*models.TodoItem)) {
+	d.t.Helper()
+	todo, err := d.manager.GetTodoByTime(at)
+	if err != nil {
+		d.t.Fatalf("GetTodoByTime failed: %v", err)
+	}
+	updated := *todo
+	change(&updated)
+	if updated.Name != todo.Name {
+		// UpdateTodo matches by name, so renames replace the todo
+		if err := d.manager.RemoveTodo(at); err != nil {
+			d.t.Fatal(err)
+		}
+		if err := d.manager.AddTodo(&updated); err != nil {
+			d.t.Fatal(err)
+		}
+		return
+	}
+	if err := d.manager.UpdateTodo(&updated, at); err != nil {
+		d.t.Fatalf("UpdateTodo failed: %v", err)
+	}
+}
+
+// names returns the todo names of November 2025 by time, earliest first
+func (d *device) names() []string {
+	d.t.Helper()
+	todos, err := d.manager.GetTodosForMonth(2025, 11)
+	if err != nil {
+		d.t.Fatal(err)
+	}
+	var names []string
+	for i := len(todos) - 1; i >= 0; i-- {
+		name := todos[i].Name
+		if todos[i].Done {
+			name += " (done)"
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func expectNames(t *testing.T, who string, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s: expected %v, got %v", who, want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("%s: expected %v, got %v", who, want, got)
+		}
+	}
+}
+
+func novemberAt(day, hour int) time.Time {
+	return time.Date(2025, 11, day, hour, 0, 0, 0, time.UTC)
+}
+
+func TestSync_MergesDivergentEdits(t *testing.T) {
+	remote := t.TempDir()
+	laptop, desktop := newDevice(t, remote), newDevice(t, remote)
+
+	laptop.add("standup", novemberAt(3, 9))
+	laptop.add("review", novemberAt(3, 14))
+	laptop.add("dentist", novemberAt(4, 8))
+	if report := laptop.sync(nil); report.Pushed != 3 || report.Pulled != 0 {
+		t.Fatalf("Expected 3 todos pushed, got %+v", report)
+	}
+	if report := desktop.sync(nil); report.Pulled != 3 {
+		t.Fatalf("Expected 3 todos pulled, got %+v", report)
+	}
+
+	// Both devices edit the month without syncing in between
+	laptop.edit(novemberAt(3, 9), func(todo *models.TodoItem) { todo.Done = true })
+	laptop.add("gym", novemberAt(5, 18))
+	desktop.edit(novemberAt(3, 14), func(todo *models.TodoItem) { todo.Name = "design review" })
+	if err := desktop.manager.RemoveTodo(novemberAt(4, 8)); err != nil {
+		t.Fatal(err)
+	}
+
+	laptop.sync(nil)
+	// Todos are identified by time and name, so the rename pushes a removal and an addition
+	if report := desktop.sync(nil); len(report.Conflicts) != 0 || report.Pulled != 2 || report.Pushed != 3 {
+		t.Fatalf("Expected a clean merge pulling 2 and pushing 3 todos, got %+v", report)
+	}
+	laptop.sync(nil)
+
+	want := []string{"standup (done)", "design review", "gym"}
+	expectNames(t, "laptop", laptop.names(), want...)
+	expectNames(t, "desktop", desktop.names(), want...)
+
+	// Nothing left to do once both agree
+	if report := laptop.sync(nil); report.Pulled != 0 || report.Pushed != 0 {
+		t.Errorf("Expected an idle sync, got %+v", report)
+	}
+}
+
+func TestSync_ConflictsNeedADecision(t *testing.T) {
+	remote := t.TempDir()
+	laptop, desktop := newDevice(t, remote), newDevice(t, remote)
+	laptop.add("standup", novemberAt(3, 9))
+	laptop.add("review", novemberAt(3, 14))
+	laptop.sync(nil)
+	desktop.sync(nil)
+
+	laptop.edit(novemberAt(3, 9), func(todo *models.TodoItem) { todo.Content = "laptop notes" })
+	laptop.add("lunch", novemberAt(3, 12))
+	desktop.edit(novemberAt(3, 9), func(todo *models.TodoItem) { todo.Content = "desktop notes" })
+	laptop.sync(nil)
+
+	report := desktop.sync(nil)
+	if len(report.Conflicts) != 1 {
+		t.Fatalf("Expected 1 conflict, got %+v", report)
+	}
+	conflict := report.Conflicts[0]
+	if conflict.Base.Content != "" || conflict.Local.Content != "desktop notes" || conflict.Remote.Content != "laptop notes" {
+		t.Fatalf("Unexpected conflict versions %+v %+v %+v", conflict.Base, conflict.Local, conflict.Remote)
+	}
+	// The rest of the month is synced; the conflicting todo waits for the user
+	expectNames(t, "desktop", desktop.names(), "standup", "lunch", "review")
+	if todo, err := desktop.manager.GetTodoByTime(novemberAt(3, 9)); err != nil || todo.Content != "desktop notes" {
+		t.Fatalf("Expected the desktop notes kept until decided, got %+v (%v)", todo, err)
+	}
+
+	// A decision about versions that changed since is not applied
+	desktop.edit(novemberAt(3, 9), func(todo *models.TodoItem) { todo.Content = "newer desktop notes" })
+	conflict.Choice = persistence.SyncKeepRemote
+	report = desktop.sync([]*persistence.SyncConflict{conflict})
+	if len(report.Conflicts) != 1 || report.Conflicts[0].Local.Content != "newer desktop notes" {
+		t.Fatalf("Expected the conflict to be asked again, got %+v", report)
+	}
+
+	conflict = report.Conflicts[0]
+	conflict.Choice = persistence.SyncKeepRemote
+	report = desktop.sync([]*persistence.SyncConflict{conflict})
+	if len(report.Conflicts) != 0 {
+		t.Fatalf("Expected the conflict to be resolved, got %+v", report.Conflicts)
+	}
+	laptop.sync(nil)
+
+	for _, d := range []*device{laptop, desktop} {
+		expectNames(t, "device", d.names(), "standup", "lunch", "review")
+		todo, err := d.manager.GetTodoByTime(novemberAt(3, 9))
+		if err != nil || todo.Content != "laptop notes" {
+			t.Errorf("Expected the laptop notes to win, got %+v (%v)", todo, err)
+		}
+	}
+}
+
+func TestSync_DeleteAgainstEditConflicts(t *testing.T) {
+	remote := t.TempDir()
+	laptop, desktop := newDevice(t, remote), newDevice(t, remote)
+	laptop.add("standup", novemberAt(3, 9))
+	laptop.sync(nil)
+	desktop.sync(nil)
+
+	if err := laptop.manager.RemoveTodo(novemberAt(3, 9)); err != nil {
+		t.Fatal(err)
+	}
+	laptop.sync(nil)
+	desktop.edit(novemberAt(3, 9), func(todo *models.TodoItem) { todo.Starred = true })
+
+	report := desktop.sync(nil)
+	if len(report.Conflicts) != 1 || report.Conflicts[0].Remote != nil || !report.Conflicts[0].Local.Starred {
+		t.Fatalf("Expected a delete/edit conflict, got %+v", report)
+	}
+
+	report.Conflicts[0].Choice = persistence.SyncKeepLocal
+	desktop.sync(report.Conflicts)
+	laptop.sync(nil)
+	expectNames(t, "laptop", laptop.names(), "standup")
+}
+
+func TestSync_FirstSyncOfTwoDevices(t *testing.T) {
+	remote := t.TempDir()
+	laptop, desktop := newDevice(t, remote), newDevice(t, remote)
+	laptop.add("standup", novemberAt(3, 9))
+	desktop.add("standup", novemberAt(3, 9))
+	desktop.add("gym", novemberAt(5, 18))
+	laptop.add("call", novemberAt(3, 10))
+	desktop.add("call", novemberAt(3, 10))
+	desktop.edit(novemberAt(3, 10), func(todo *models.TodoItem) { todo.Content = "agenda" })
+
+	laptop.sync(nil)
+	report := desktop.sync(nil)
+	// Identical todos merge; the same todo added differently is a conflict
+	if len(report.Conflicts) != 1 || report.Conflicts[0].Base != nil {
+		t.Fatalf("Expected one add/add conflict, got %+v", report)
+	}
+	report.Conflicts[0].Choice = persistence.SyncKeepLocal
+	desktop.sync(report.Conflicts)
+	laptop.sync(nil)
+
+	expectNames(t, "laptop", laptop.names(), "standup", "call", "gym")
+	expectNames(t, "desktop", desktop.names(), "standup", "call", "gym")
+	if todo, err := laptop.manager.GetTodoByTime(novemberAt(3, 10)); err != nil || todo.Content != "agenda" {
+		t.Errorf("Expected the desktop version of the call, got %+v (%v)", todo, err)
+	}
+}
+
+func TestSync_KeepsTodosSharingAMinute(t *testing.T) {
+	remote := t.TempDir()
+	laptop, desktop := newDevice(t, remote), newDevice(t, remote)
+	laptop.add("standup", novemberAt(3, 9))
+	laptop.add("call Ann", novemberAt(3, 10))
+	laptop.add("call Bob", novemberAt(3, 10))
+	laptop.sync(nil)
+
+	if report := desktop.sync(nil); report.Pulled != 3 {
+		t.Fatalf("Expected 3 todos pulled, got %+v", report)
+	}
+	desktop.add("call Cleo", novemberAt(3, 10))
+	desktop.sync(nil)
+	laptop.sync(nil)
+
+	for who, d := range map[string]*device{"laptop": laptop, "desktop": desktop} {
+		names := d.names()
+		sort.Strings(names)
+		expectNames(t, who, names, "call Ann", "call Bob", "call Cleo", "standup")
+	}
+}
+
+func TestSync_NewRemoteDoesNotDelete(t *testing.T) {
+	laptop := newDevice(t, t.TempDir())
+	laptop.add("standup", novemberAt(3, 9))
+	laptop.sync(nil)
+
+	// An empty folder has no base snapshot yet, so nothing reads as deleted
+	if err := laptop.syncer.SetRemoteDir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	if report := laptop.sync(nil); report.Pushed != 1 || report.Pulled != 0 {
+		t.Fatalf("Expected the todo to be pushed to the new folder, got %+v", report)
+	}
+	expectNames(t, "laptop", laptop.names(), "standup")
+}
+
+func TestSync_RequiresRemote(t *testing.T) {
+	dir := t.TempDir()
+	syncer := persistence.NewSyncer(persistence.NewMonthlyManager(dir), dir)
+	if _, err := syncer.Sync(nil); err == nil {
+		t.Error("Expected an error without a sync folder")
+	}
+	if err := syncer.SetRemoteDir(dir); err == nil {
+		t.Error("Expected the data directory to be refused as sync folder")
+	}
+}
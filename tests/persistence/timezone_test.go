@@ -0,0 +1,141 @@
+package persistence_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"godo/src/models"
+	"godo/src/persistence"
+)
+
+func mustZone(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("Time zone %s not available: %v", name, err)
+	}
+	return loc
+}
+
+func newZonedTodo(name string, at time.Time, zone string) *models.TodoItem {
+	todo := models.NewTodoItem()
+	todo.Name = name
+	todo.SetZonedTime(at, zone)
+	return todo
+}
+
+func TestAddTodo_BucketsByScheduledZone(t *testing.T) {
+	tokyo := mustZone(t, "Asia/Tokyo")
+	dir := t.TempDir()
+	manager := persistence.NewMonthlyManager(dir)
+
+	// 1 December in Tokyo is still 30 November in UTC
+	at := time.Date(2025, 12, 1, 7, 0, 0, 0, tokyo)
+	if err := manager.AddTodo(newZonedTodo("early", at.UTC(), "Asia/Tokyo")); err != nil {
+		t.Fatalf("AddTodo failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "202512.yaml")); err != nil {
+		t.Fatalf("Expected the todo in the December file: %v", err)
+	}
+
+	// Lookups work with the instant in any zone
+	if _, err := manager.GetTodoByTime(at.UTC()); err != nil {
+		t.Errorf("GetTodoByTime failed: %v", err)
+	}
+	if err := manager.RemoveTodo(at.UTC()); err != nil {
+		t.Fatalf("RemoveTodo failed: %v", err)
+	}
+	todos, _ := manager.GetTodosForMonth(2025, 12)
+	if len(todos) != 0 {
+		t.Errorf("Expected the todo to be removed, %d left", len(todos))
+	}
+}
+
+func TestGetTodosForDay_MidnightAndDST(t *testing.T) {
+	berlin := mustZone(t, "Europe/Berlin")
+	manager := persistence.NewMonthlyManager(t.TempDir())
+
+	// 30 March 2025 has only 23 hours in Berlin
+	for _, todo := range []*models.TodoItem{
+		newZonedTodo("midnight", time.Date(2025, 3, 30, 0, 0, 0, 0, berlin), "Europe/Berlin"),
+		newZonedTodo("late", time.Date(2025, 3, 30, 23, 30, 0, 0, berlin), "Europe/Berlin"),
+		newZonedTodo("next day", time.Date(2025, 3, 31, 0, 0, 0, 0, berlin), "Europe/Berlin"),
+		newZonedTodo("day before", time.Date(2025, 3, 29, 23, 59, 0, 0, berlin), "Europe/Berlin"),
+	} {
+		if err := manager.AddTodo(todo); err != nil {
+			t.Fatalf("AddTodo failed: %v", err)
+		}
+	}
+
+	todos, err := manager.GetTodosForDay(time.Date(2025, 3, 30, 0, 0, 0, 0, berlin))
+	if err != nil {
+		t.Fatalf("GetTodosForDay failed: %v", err)
+	}
+	names := map[string]bool{}
+	for _, todo := range todos {
+		names[todo.Name] = true
+	}
+	if len(todos) != 2 || !names["midnight"] || !names["late"] {
+		t.Errorf("Expected midnight and late, got %v", names)
+	}
+}
+
+func TestGetTodosForDay_SpansMonthFiles(t *testing.T) {
+	tokyo := mustZone(t, "Asia/Tokyo")
+	berlin := mustZone(t, "Europe/Berlin")
+	manager := persistence.NewMonthlyManager(t.TempDir())
+
+	// Scheduled in Tokyo on 1 December, which is 30 November in Berlin
+	at := time.Date(2025, 12, 1, 6, 0, 0, 0, tokyo)
+	if err := manager.AddTodo(newZonedTodo("call", at, "Asia/Tokyo")); err != nil {
+		t.Fatalf("AddTodo failed: %v", err)
+	}
+
+	todos, err := manager.GetTodosForDay(time.Date(2025, 11, 30, 0, 0, 0, 0, berlin))
+	if err != nil {
+		t.Fatalf("GetTodosForDay failed: %v", err)
+	}
+	if len(todos) != 1 {
+		t.Fatalf("Expected the Tokyo todo on 30 November in Berlin, got %d", len(todos))
+	}
+	if got := todos[0].DisplayTime(berlin).Hour(); got != 22 {
+		t.Errorf("Expected 22:00 Berlin time, got %d", got)
+	}
+}
+
+func TestFloatingTodo_KeepsWallClock(t *testing.T) {
+	tokyo := mustZone(t, "Asia/Tokyo")
+	newYork := mustZone(t, "America/New_York")
+
+	todo := models.NewTodoItem()
+	todo.SetFloatingTime(time.Date(2025, 11, 19, 9, 0, 0, 0, tokyo))
+
+	for _, loc := range []*time.Location{tokyo, newYork, time.UTC} {
+		shown := todo.DisplayTime(loc)
+		if shown.Day() != 19 || shown.Hour() != 9 {
+			t.Errorf("Expected 19th 09:00 in %s, got %s", loc, shown)
+		}
+		if !todo.OccursOn(time.Date(2025, 11, 19, 0, 0, 0, 0, loc), loc) {
+			t.Errorf("Expected the floating todo on the 19th in %s", loc)
+		}
+	}
+}
+
+func TestLoadTodos_ZonesRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	manager := persistence.NewMonthlyManager(dir)
+	at := time.Date(2025, 11, 19, 10, 0, 0, 0, mustZone(t, "America/New_York"))
+	if err := manager.AddTodo(newZonedTodo("zoned", at, "America/New_York")); err != nil {
+		t.Fatalf("AddTodo failed: %v", err)
+	}
+
+	todos, err := persistence.NewFileIOManager(dir).LoadTodos(2025, 11)
+	if err != nil || len(todos) != 1 {
+		t.Fatalf("Expected 1 todo, got %d (%v)", len(todos), err)
+	}
+	if todos[0].TimeZone != "America/New_York" || !todos[0].TodoTime.Equal(at) {
+		t.Errorf("Zone did not round-trip: %+v", todos[0])
+	}
+}
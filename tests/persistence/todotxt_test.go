@@ -0,0 +1,172 @@
+package persistence_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"godo/src/models"
+	"godo/src/persistence"
+)
+
+func TestTodoTxt_RoundTrip(t *testing.T) {
+	berlin := mustZone(t, "Europe/Berlin")
+
+	task := models.NewTodoItem()
+	task.Name = "Write report"
+	task.Kind = 1
+	task.Level = 3
+	task.Label = "work"
+	task.Place = "office"
+	task.WarnTime = 15
+	task.Estimate = 4
+	task.Pomodoros = 2
+	task.SetZonedTime(time.Date(2025, 11, 4, 17, 0, 0, 0, time.Local), "")
+
+	done := models.NewTodoItem()
+	done.Name = "Call dentist"
+	done.Kind = 1
+	done.Level = 2
+	done.Done = true
+	done.WarnTime = 120
+	done.SetZonedTime(time.Date(2025, 11, 5, 8, 30, 15, 0, time.Local), "")
+
+	event := models.NewTodoItem()
+	event.Name = "Meeting"
+	event.Starred = true
+	event.SetZonedTime(time.Date(2025, 11, 3, 9, 30, 0, 0, berlin), "Europe/Berlin")
+	event.SetDuration(45)
+
+	allDay := models.NewTodoItem()
+	allDay.Name = "Holiday"
+	allDay.Kind = 1
+	allDay.SetAllDay(time.Date(2025, 11, 6, 0, 0, 0, 0, time.UTC))
+
+	for _, todo := range []*models.TodoItem{task, done, event, allDay} {
+		line := persistence.FormatTodoTxt(todo)
+		parsed, err := persistence.ParseTodoTxt(line)
+		if err != nil {
+			t.Fatalf("%s: ParseTodoTxt(%q) failed: %v", todo.Name, line, err)
+		}
+		if !models.SameTodo(todo, parsed) {
+			t.Errorf("%s: round trip changed the todo\nline %q\nwant %+v\ngot  %+v", todo.Name, line, todo, parsed)
+		}
+	}
+
+	line := persistence.FormatTodoTxt(task)
+	for _, want := range []string{"(A) Write report", "+work", "@office", "due:2025-11-04T17:00", "rem:15m"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("Expected %q in %q", want, line)
+		}
+	}
+	spaced := *task
+	spaced.Place = "Main office"
+	if line := persistence.FormatTodoTxt(&spaced); !strings.Contains(line, " @Main-office ") {
+		t.Errorf("Expected spaces in the context replaced, got %q", line)
+	}
+	if line := persistence.FormatTodoTxt(done); !strings.HasPrefix(line, "x "+time.Now().Format("2006-01-02")+" Call dentist") ||
+		!strings.Contains(line, "pri:B") || !strings.Contains(line, "rem:2h") {
+		t.Errorf("Expected a completed task keeping its priority, got %q", line)
+	}
+}
+
+func TestParseTodoTxt_ForeignLines(t *testing.T) {
+	todo, err := persistence.ParseTodoTxt("(B) 2025-10-30 Buy milk +home +errands @store due:2025-11-07 t:2025-11-01 rem:1d")
+	if err != nil {
+		t.Fatalf("ParseTodoTxt failed: %v", err)
+	}
+	if todo.Name != "Buy milk +errands t:2025-11-01" || todo.Label != "home" || todo.Place != "store" {
+		t.Errorf("Unexpected todo %+v", todo)
+	}
+	if todo.Kind != 1 || todo.Level != 2 || todo.WarnTime != 24*60 || !todo.AllDay || todo.ZonedTime().Day() != 7 {
+		t.Errorf("Expected an all-day high priority task on the 7th, got %+v", todo)
+	}
+
+	if todo, err := persistence.ParseTodoTxt("x 2025-11-02 2025-10-30 Pay rent due:2025-11-01T09:00"); err != nil || !todo.Done || todo.Name != "Pay rent" {
+		t.Errorf("Expected a completed task, got %+v (%v)", todo, err)
+	}
+	if _, err := persistence.ParseTodoTxt("(A) Someday"); !errors.Is(err, persistence.ErrNoDueDate) {
+		t.Errorf("Expected ErrNoDueDate, got %v", err)
+	}
+	if _, err := persistence.ParseTodoTxt("Broken due:tomorrow"); err == nil {
+		t.Error("Expected an error for an invalid due date")
+	}
+
+	todos, skipped, err := persistence.ReadTodoTxt(strings.NewReader("Someday\n\nCall mum due:2025-11-09T18:00\n"))
+	if err != nil || len(todos) != 1 || len(skipped) != 1 || skipped[0].Line != 1 {
+		t.Errorf("Expected 1 todo and line 1 skipped, got %v %v (%v)", todos, skipped, err)
+	}
+}
+
+func TestTodoTxtRepository_SharesFileWithOtherTools(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.txt")
+	original := "(A) 2025-10-01 Pay rent +home due:2025-11-01T09:00 h:1\n" +
+		"Someday learn Go\n" +
+		"Call mum due:2025-11-09T18:00\n"
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repo := persistence.NewTodoTxtRepository(path)
+
+	todos, err := repo.GetTodosForMonth(2025, 11)
+	if err != nil || len(todos) != 2 {
+		t.Fatalf("Expected 2 todos, got %v (%v)", todos, err)
+	}
+
+	// Only the edited line is rewritten; the others keep their text
+	callTime := time.Date(2025, 11, 9, 18, 0, 0, 0, time.Local)
+	call, err := repo.GetTodoByTime(callTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated := *call
+	updated.Done = true
+	if err := repo.UpdateTodo(&updated, callTime); err != nil {
+		t.Fatal(err)
+	}
+	added := models.NewTodoItem()
+	added.Name = "Dentist"
+	added.SetZonedTime(time.Date(2025, 12, 2, 8, 0, 0, 0, time.Local), "")
+	if err := repo.AddTodo(added); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 4 || lines[0] != "(A) 2025-10-01 Pay rent +home due:2025-11-01T09:00 h:1" ||
+		lines[1] != "Someday learn Go" || !strings.HasPrefix(lines[2], "x ") || !strings.HasPrefix(lines[3], "(D) Dentist") {
+		t.Fatalf("Unexpected file:\n%s", data)
+	}
+	if months, _ := repo.GetAllMonths(); len(months) != 2 || months[0] != "202511" || months[1] != "202512" {
+		t.Errorf("Expected November and December, got %v", months)
+	}
+
+	// Edits by another tool are seen by the watcher and the next read
+	changed := make(chan struct{}, 1)
+	stop := repo.Watch(10*time.Millisecond, func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	})
+	defer stop()
+	edited := strings.Replace(string(data), "Pay rent", "Pay the rent", 1) + "Buy milk due:2025-11-07\n"
+	if err := os.WriteFile(path, []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changed:
+	case <-time.After(2 * time.Second):
+		t.Error("Expected the watcher to report the edit")
+	}
+	todos, err = repo.GetTodosForMonth(2025, 11)
+	if err != nil || len(todos) != 3 {
+		t.Fatalf("Expected 3 todos after the edit, got %v (%v)", todos, err)
+	}
+	if todo, err := repo.GetTodoByTime(time.Date(2025, 11, 1, 9, 0, 0, 0, time.Local)); err != nil || todo.Name != "Pay the rent h:1" {
+		t.Errorf("Expected the renamed todo, got %+v (%v)", todo, err)
+	}
+}
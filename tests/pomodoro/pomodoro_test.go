@@ -0,0 +1,937 @@
+package pomodoro_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"godo/src/models"
+	"godo/src/persistence"
+	"godo/src/services"
+	"godo/src/ui/helpers"
+)
+
+// fakeClock is a manually advanced clock
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(minutes float64) {
+	c.now = c.now.Add(time.Duration(minutes * float64(time.Minute)))
+}
+
+// recorder collects the events delivered to a subscriber
+type recorder struct {
+	events []models.PomodoroEvent
+}
+
+func (r *recorder) record(e models.PomodoroEvent) {
+	r.events = append(r.events, e)
+}
+
+func (r *recorder) types() []models.PomodoroEventType {
+	types := make([]models.PomodoroEventType, len(r.events))
+	for i, e := range r.events {
+		types[i] = e.Type
+	}
+	return types
+}
+
+func newTestTimer(autoBreaks, autoWork bool) (*models.PomodoroTimer, *fakeClock, *recorder) {
+	config := models.NewDefaultPomodoroConfig()
+	config.AutoStartBreaks = autoBreaks
+	config.AutoStartWork = autoWork
+	clock := &fakeClock{now: time.Date(2025, 11, 19, 9, 0, 0, 0, time.UTC)}
+	timer := models.NewPomodoroTimerWithClock(config, clock)
+	rec := &recorder{}
+	timer.Subscribe(rec.record)
+	return timer, clock, rec
+}
+
+func TestPomodoroTimer_Transitions(t *testing.T) {
+	type step struct {
+		action  string // start, pause, resume, skip, reset or wait
+		minutes float64
+	}
+
+	tests := []struct {
+		name          string
+		autoBreaks    bool
+		autoWork      bool
+		steps         []step
+		wantState     models.PomodoroState
+		wantNext      models.PomodoroState
+		wantRemaining time.Duration
+		wantSessions  int
+		wantEvents    []models.PomodoroEventType
+	}{
+		{
+			name:          "work finishes into auto-started break",
+			autoBreaks:    true,
+			steps:         []step{{"start", 0}, {"wait", 26}},
+			wantState:     models.PomodoroShortBreak,
+			wantNext:      models.PomodoroShortBreak,
+			wantRemaining: 4 * time.Minute,
+			wantSessions:  1,
+			wantEvents:    []models.PomodoroEventType{models.EventWorkStarted, models.EventWorkFinished, models.EventBreakStarted},
+		},
+		{
+			name:          "work finishes and waits without auto-start",
+			steps:         []step{{"start", 0}, {"wait", 30}},
+			wantState:     models.PomodoroIdle,
+			wantNext:      models.PomodoroShortBreak,
+			wantRemaining: 5 * time.Minute,
+			wantSessions:  1,
+			wantEvents:    []models.PomodoroEventType{models.EventWorkStarted, models.EventWorkFinished},
+		},
+		{
+			name:          "pause during break resumes into the break",
+			autoBreaks:    true,
+			steps:         []step{{"start", 0}, {"wait", 27}, {"pause", 0}, {"wait", 60}, {"resume", 0}},
+			wantState:     models.PomodoroShortBreak,
+			wantNext:      models.PomodoroShortBreak,
+			wantRemaining: 3 * time.Minute,
+			wantSessions:  1,
+			wantEvents: []models.PomodoroEventType{
+				models.EventWorkStarted, models.EventWorkFinished, models.EventBreakStarted,
+				models.EventPaused, models.EventResumed,
+			},
+		},
+		{
+			name:          "start resumes a paused interval",
+			steps:         []step{{"start", 0}, {"wait", 10}, {"pause", 0}, {"wait", 5}, {"start", 0}},
+			wantState:     models.PomodoroWork,
+			wantNext:      models.PomodoroWork,
+			wantRemaining: 15 * time.Minute,
+			wantEvents:    []models.PomodoroEventType{models.EventWorkStarted, models.EventPaused, models.EventResumed},
+		},
+		{
+			name:          "skip running break starts work with auto-start",
+			autoBreaks:    true,
+			autoWork:      true,
+			steps:         []step{{"start", 0}, {"wait", 26}, {"skip", 0}},
+			wantState:     models.PomodoroWork,
+			wantNext:      models.PomodoroWork,
+			wantRemaining: 25 * time.Minute,
+			wantSessions:  1,
+			wantEvents: []models.PomodoroEventType{
+				models.EventWorkStarted, models.EventWorkFinished, models.EventBreakStarted,
+				models.EventSkipped, models.EventWorkStarted,
+			},
+		},
+		{
+			name:          "skip paused break waits for work",
+			autoBreaks:    true,
+			steps:         []step{{"start", 0}, {"wait", 26}, {"pause", 0}, {"skip", 0}},
+			wantState:     models.PomodoroIdle,
+			wantNext:      models.PomodoroWork,
+			wantRemaining: 25 * time.Minute,
+			wantSessions:  1,
+			wantEvents: []models.PomodoroEventType{
+				models.EventWorkStarted, models.EventWorkFinished, models.EventBreakStarted,
+				models.EventPaused, models.EventSkipped,
+			},
+		},
+		{
+			name:          "skip upcoming break",
+			steps:         []step{{"start", 0}, {"wait", 25}, {"skip", 0}},
+			wantState:     models.PomodoroIdle,
+			wantNext:      models.PomodoroWork,
+			wantRemaining: 25 * time.Minute,
+			wantSessions:  1,
+			wantEvents:    []models.PomodoroEventType{models.EventWorkStarted, models.EventWorkFinished, models.EventSkipped},
+		},
+		{
+			name:          "skip ignores work",
+			steps:         []step{{"start", 0}, {"skip", 0}},
+			wantState:     models.PomodoroWork,
+			wantNext:      models.PomodoroWork,
+			wantRemaining: 25 * time.Minute,
+			wantEvents:    []models.PomodoroEventType{models.EventWorkStarted},
+		},
+		{
+			name:          "catch-up over several intervals ends in long break",
+			autoBreaks:    true,
+			autoWork:      true,
+			steps:         []step{{"start", 0}, {"wait", 3*30 + 25 + 1}},
+			wantState:     models.PomodoroLongBreak,
+			wantNext:      models.PomodoroLongBreak,
+			wantRemaining: 14 * time.Minute,
+			wantSessions:  4,
+			wantEvents: []models.PomodoroEventType{
+				models.EventWorkStarted,
+				models.EventWorkFinished, models.EventBreakStarted, models.EventBreakFinished, models.EventWorkStarted,
+				models.EventWorkFinished, models.EventBreakStarted, models.EventBreakFinished, models.EventWorkStarted,
+				models.EventWorkFinished, models.EventBreakStarted, models.EventBreakFinished, models.EventWorkStarted,
+				models.EventWorkFinished, models.EventBreakStarted,
+			},
+		},
+		{
+			name:          "reset clears sessions",
+			autoBreaks:    true,
+			steps:         []step{{"start", 0}, {"wait", 26}, {"reset", 0}},
+			wantState:     models.PomodoroIdle,
+			wantNext:      models.PomodoroWork,
+			wantRemaining: 25 * time.Minute,
+			wantEvents: []models.PomodoroEventType{
+				models.EventWorkStarted, models.EventWorkFinished, models.EventBreakStarted, models.EventReset,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timer, clock, rec := newTestTimer(tt.autoBreaks, tt.autoWork)
+			for _, s := range tt.steps {
+				switch s.action {
+				case "start":
+					timer.Start()
+				case "pause":
+					timer.Pause()
+				case "resume":
+					timer.Resume()
+				case "skip":
+					timer.SkipBreak()
+				case "reset":
+					timer.Reset()
+				case "wait":
+					clock.Advance(s.minutes)
+					timer.Update()
+				}
+			}
+
+			status := timer.Snapshot()
+			if status.State != tt.wantState {
+				t.Errorf("Expected state %s, got %s", tt.wantState, status.State)
+			}
+			if status.Interval() != tt.wantNext {
+				t.Errorf("Expected interval %s, got %s", tt.wantNext, status.Interval())
+			}
+			if status.Remaining != tt.wantRemaining {
+				t.Errorf("Expected %s remaining, got %s", tt.wantRemaining, status.Remaining)
+			}
+			if status.Sessions != tt.wantSessions {
+				t.Errorf("Expected %d sessions, got %d", tt.wantSessions, status.Sessions)
+			}
+			got := rec.types()
+			if len(got) != len(tt.wantEvents) {
+				t.Fatalf("Expected events %v, got %v", tt.wantEvents, got)
+			}
+			for i := range got {
+				if got[i] != tt.wantEvents[i] {
+					t.Fatalf("Expected events %v, got %v", tt.wantEvents, got)
+				}
+			}
+		})
+	}
+}
+
+func TestPomodoroTimer_PausedFromAndProgress(t *testing.T) {
+	timer, clock, _ := newTestTimer(true, false)
+	timer.Start()
+	clock.Advance(26)
+	timer.Pause()
+
+	status := timer.Snapshot()
+	if status.State != models.PomodoroPaused || status.PausedFrom != models.PomodoroShortBreak {
+		t.Fatalf("Expected paused short break, got %s from %s", status.State, status.PausedFrom)
+	}
+	clock.Advance(120)
+	if got := timer.Snapshot(); got.Remaining != 4*time.Minute || got.Progress() != 0.2 {
+		t.Errorf("Expected time to stand still while paused, got %s (%.2f)", got.Remaining, got.Progress())
+	}
+}
+
+func TestPomodoroTimer_EventDetails(t *testing.T) {
+	timer, clock, rec := newTestTimer(true, false)
+	start := clock.now
+	timer.Start()
+
+	// Update long after the work interval ended; the event carries its real end
+	clock.Advance(28)
+	timer.Update()
+
+	finished := rec.events[1]
+	if finished.Type != models.EventWorkFinished {
+		t.Fatalf("Expected work finished, got %s", finished.Type)
+	}
+	if !finished.At.Equal(start.Add(25 * time.Minute)) {
+		t.Errorf("Expected finish at the interval end, got %s", finished.At)
+	}
+	if finished.From != models.PomodoroWork || finished.Sessions != 1 {
+		t.Errorf("Unexpected event %+v", finished)
+	}
+	if started := rec.events[2]; started.From != models.PomodoroIdle || started.To != models.PomodoroShortBreak {
+		t.Errorf("Unexpected break event %+v", started)
+	}
+}
+
+func TestPomodoroRecord_VoidWhilePausedKeepsRealStart(t *testing.T) {
+	timer, clock, rec := newTestTimer(false, false)
+	start := clock.now
+	timer.Start()
+	clock.Advance(5)
+	timer.Pause()
+	clock.Advance(10)
+	timer.Resume()
+	clock.Advance(5)
+	timer.Pause()
+	// Voided long after the pause; neither pause counts as work
+	clock.Advance(30)
+	timer.Void()
+
+	voided := rec.events[len(rec.events)-1]
+	if voided.Type != models.EventVoided {
+		t.Fatalf("Expected the session voided, got %s", voided.Type)
+	}
+	record, ok := models.NewPomodoroRecord(voided)
+	if !ok {
+		t.Fatal("Expected a record for the voided session")
+	}
+	if !record.Start.Equal(start) {
+		t.Errorf("Expected the record to start at %s, got %s", start, record.Start)
+	}
+	if record.End.Sub(record.Start) != 10*time.Minute || !record.Voided {
+		t.Errorf("Expected a voided record of the 10 minutes worked, got %+v", record)
+	}
+}
+
+func TestPomodoroTimer_Unsubscribe(t *testing.T) {
+	timer, _, rec := newTestTimer(false, false)
+	other := &recorder{}
+	unsubscribe := timer.Subscribe(other.record)
+
+	timer.Start()
+	unsubscribe()
+	timer.Reset()
+
+	if len(rec.events) != 2 || len(other.events) != 1 {
+		t.Errorf("Expected 2 and 1 events, got %d and %d", len(rec.events), len(other.events))
+	}
+}
+
+func TestPomodoroTimer_CallbackMayUseTimer(t *testing.T) {
+	timer, clock, _ := newTestTimer(false, false)
+	// A subscriber that starts the break itself must not deadlock
+	timer.Subscribe(func(e models.PomodoroEvent) {
+		if e.Type == models.EventWorkFinished {
+			timer.Start()
+		}
+	})
+	timer.Start()
+	clock.Advance(25)
+	timer.Update()
+
+	if state := timer.Snapshot().State; state != models.PomodoroShortBreak {
+		t.Errorf("Expected short break, got %s", state)
+	}
+}
+
+func TestPomodoroTimer_LongBreakCadence(t *testing.T) {
+	timer, clock, _ := newTestTimer(false, false)
+	timer.SetConfig(&models.PomodoroConfig{WorkDuration: 1, ShortBreakDuration: 1, LongBreakDuration: 2, SessionsUntilLongBreak: 2})
+
+	var breaks []models.PomodoroState
+	for i := 0; i < 4; i++ {
+		timer.Start()
+		clock.Advance(1)
+		breaks = append(breaks, timer.Snapshot().Next)
+		timer.SkipBreak()
+	}
+
+	want := []models.PomodoroState{models.PomodoroShortBreak, models.PomodoroLongBreak, models.PomodoroShortBreak, models.PomodoroLongBreak}
+	for i := range want {
+		if breaks[i] != want[i] {
+			t.Errorf("Break %d: expected %s, got %s", i+1, want[i], breaks[i])
+		}
+	}
+}
+
+func TestPomodoroPresets_SaveAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	manager := persistence.NewConfigManager(dir)
+
+	config := models.NewDefaultConfig()
+	if got := config.GetActivePomodoroPreset(); got.Name != "Classic 25/5" || got.SessionsUntilLongBreak != 4 {
+		t.Fatalf("Expected the classic preset by default, got %+v", got)
+	}
+
+	// Built-in presets cannot be overwritten
+	config.SavePomodoroPreset(models.PomodoroPreset{Name: "Classic 25/5", WorkDuration: 1})
+	custom := models.PomodoroPreset{Name: models.CustomPresetName, WorkDuration: 40, ShortBreakDuration: 8, LongBreakDuration: 20, SessionsUntilLongBreak: 2}
+	config.SavePomodoroPreset(custom)
+	custom.WorkDuration = 45
+	config.SavePomodoroPreset(custom)
+	config.SetActivePomodoroPreset(models.CustomPresetName)
+
+	if err := manager.SaveConfig(config); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+	loaded, err := manager.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	presets := loaded.GetPomodoroPresets()
+	if len(presets) != len(models.DefaultPomodoroPresets())+1 || presets[0].WorkDuration != 25 {
+		t.Fatalf("Unexpected presets %+v", presets)
+	}
+	active := loaded.GetActivePomodoroPreset()
+	if active != custom {
+		t.Errorf("Expected %+v to be restored, got %+v", custom, active)
+	}
+	if cfg := active.Config(); cfg.WorkDuration != 45 || cfg.SessionsUntilLongBreak != 2 {
+		t.Errorf("Unexpected timer config %+v", cfg)
+	}
+
+	// An unknown active preset falls back to the first built-in one
+	loaded.SetActivePomodoroPreset("gone")
+	if got := loaded.GetActivePomodoroPreset(); got.Name != "Classic 25/5" {
+		t.Errorf("Expected fallback to the classic preset, got %s", got.Name)
+	}
+}
+
+func TestPomodoroService_ResumesAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+	clock := &fakeClock{now: time.Date(2025, 11, 19, 9, 0, 0, 0, time.UTC)}
+	start := clock.now
+
+	first := services.NewPomodoroServiceWithClock(persistence.NewPomodoroStore(dir), clock)
+	if err := first.Restore(); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	first.Timer().Start()
+	clock.Advance(10)
+	if err := first.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	// Restart mid-session: the work interval continues where it was
+	clock.Advance(5)
+	second := services.NewPomodoroServiceWithClock(persistence.NewPomodoroStore(dir), clock)
+	if err := second.Restore(); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if status := second.Timer().Snapshot(); status.State != models.PomodoroWork || status.Remaining != 10*time.Minute {
+		t.Fatalf("Expected 10 minutes of work left, got %s %s", status.State, status.Remaining)
+	}
+	second.Stop()
+
+	// Work and the auto-started break both finish while the app is closed
+	clock.Advance(60)
+	third := services.NewPomodoroServiceWithClock(persistence.NewPomodoroStore(dir), clock)
+	if err := third.Restore(); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	status := third.Timer().Snapshot()
+	if status.State != models.PomodoroIdle || status.Next != models.PomodoroWork || status.Sessions != 1 {
+		t.Errorf("Expected idle before the next work session, got %+v", status)
+	}
+
+	completed := third.Completed()
+	if len(completed) != 2 {
+		t.Fatalf("Expected 2 completed intervals, got %+v", completed)
+	}
+	if completed[0].Kind != models.PomodoroWork || !completed[0].Start.Equal(start) || !completed[0].End.Equal(start.Add(25*time.Minute)) {
+		t.Errorf("Unexpected work record %+v", completed[0])
+	}
+	if completed[1].Kind != models.PomodoroShortBreak || !completed[1].End.Equal(start.Add(30*time.Minute)) {
+		t.Errorf("Unexpected break record %+v", completed[1])
+	}
+
+	// The catch-up was saved, so a further restart does not record it again
+	fourth := services.NewPomodoroServiceWithClock(persistence.NewPomodoroStore(dir), clock)
+	if err := fourth.Restore(); err != nil || len(fourth.Completed()) != 2 {
+		t.Errorf("Expected the history to be saved once, got %d (%v)", len(fourth.Completed()), err)
+	}
+}
+
+func TestPomodoroService_PausedSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	clock := &fakeClock{now: time.Date(2025, 11, 19, 9, 0, 0, 0, time.UTC)}
+
+	first := services.NewPomodoroServiceWithClock(persistence.NewPomodoroStore(dir), clock)
+	first.Restore()
+	first.Timer().Start()
+	clock.Advance(26)
+	first.Timer().Pause()
+	first.Stop()
+
+	clock.Advance(600)
+	second := services.NewPomodoroServiceWithClock(persistence.NewPomodoroStore(dir), clock)
+	if err := second.Restore(); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	second.Timer().Resume()
+	status := second.Timer().Snapshot()
+	if status.State != models.PomodoroShortBreak || status.Remaining != 4*time.Minute || status.Sessions != 1 {
+		t.Errorf("Expected the paused break to resume with 4 minutes left, got %+v", status)
+	}
+}
+
+func TestPomodoroService_NewerFileIsNotOverwritten(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pomodoro.json")
+	original := []byte(`{"version": 99, "timer": {"state": "idle"}}`)
+	if err := os.WriteFile(path, original, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	service := services.NewPomodoroService(persistence.NewPomodoroStore(dir))
+	var newer *persistence.NewerVersionError
+	if err := service.Restore(); !errors.As(err, &newer) {
+		t.Fatalf("Expected NewerVersionError, got %v", err)
+	}
+	service.Timer().Start()
+	service.Stop()
+
+	data, _ := os.ReadFile(path)
+	if string(data) != string(original) {
+		t.Errorf("Newer pomodoro file was overwritten:\n%s", data)
+	}
+}
+
+func TestPomodoroService_CreditsTask(t *testing.T) {
+	dir := t.TempDir()
+	clock := &fakeClock{now: time.Date(2025, 11, 19, 9, 0, 0, 0, time.UTC)}
+	todo := models.NewTodoItem()
+	todo.Name = "Write report"
+	todo.SetTime(time.Date(2025, 11, 19, 14, 0, 0, 0, time.UTC))
+
+	service := services.NewPomodoroServiceWithClock(persistence.NewPomodoroStore(dir), clock)
+	var credited []models.PomodoroTask
+	service.SetOnTaskPomodoro(func(task models.PomodoroTask) {
+		credited = append(credited, task)
+	})
+
+	// Starting a task during a break skips the break and starts work
+	service.Timer().Start()
+	clock.Advance(26)
+	service.StartTask(todo)
+	if status := service.Timer().Snapshot(); status.State != models.PomodoroWork || status.Remaining != 25*time.Minute {
+		t.Fatalf("Expected a fresh work session, got %s %s", status.State, status.Remaining)
+	}
+	clock.Advance(25)
+	service.Timer().Update()
+
+	if len(credited) != 1 || !credited[0].Matches(todo) {
+		t.Fatalf("Expected one Pomodoro credited to the todo, got %+v", credited)
+	}
+	completed := service.Completed()
+	if completed[0].Task != nil || !completed[len(completed)-1].Task.Matches(todo) {
+		t.Errorf("Expected only the task's work session to reference it, got %+v", completed)
+	}
+	service.Stop()
+
+	restarted := services.NewPomodoroServiceWithClock(persistence.NewPomodoroStore(dir), clock)
+	if err := restarted.Restore(); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if !restarted.Task().Matches(todo) {
+		t.Errorf("Expected the task link to survive a restart, got %+v", restarted.Task())
+	}
+	restarted.ClearTask()
+	if restarted.Task() != nil {
+		t.Error("Expected ClearTask to unlink the todo")
+	}
+}
+
+func TestPomodoroEstimate_RoundTripAndProgress(t *testing.T) {
+	dir := t.TempDir()
+	manager := persistence.NewMonthlyManager(dir)
+
+	todo := models.NewTodoItem()
+	todo.Name = "Write report"
+	todo.SetTime(time.Date(2025, 11, 19, 14, 0, 0, 0, time.UTC))
+	todo.SetEstimate(4)
+	todo.Pomodoros = 2
+	if err := manager.AddTodo(todo); err != nil {
+		t.Fatalf("AddTodo failed: %v", err)
+	}
+
+	todos, err := persistence.NewFileIOManager(dir).LoadTodos(2025, 11)
+	if err != nil || len(todos) != 1 {
+		t.Fatalf("Expected 1 todo, got %d (%v)", len(todos), err)
+	}
+	loaded := todos[0]
+	if loaded.Estimate != 4 || loaded.Pomodoros != 2 || loaded.EstimateReached() {
+		t.Errorf("Estimate did not round-trip: %+v", loaded)
+	}
+
+	tests := []struct {
+		estimate, done int
+		want           string
+	}{
+		{0, 0, ""},
+		{4, 2, "●●○○"},
+		{4, 4, "●●●●"},
+		{2, 3, "●●+1"},
+		{0, 3, "●●●"},
+		{20, 5, "●5/20"},
+	}
+	for _, tt := range tests {
+		item := &models.TodoItem{Estimate: tt.estimate, Pomodoros: tt.done}
+		if got := helpers.FormatPomodoroProgress(item); got != tt.want {
+			t.Errorf("%d of %d: expected %q, got %q", tt.done, tt.estimate, tt.want, got)
+		}
+	}
+}
+
+func TestPomodoroSequence_ParseAndFormat(t *testing.T) {
+	tests := []struct {
+		text       string
+		wantSteps  []models.PomodoroStep
+		wantRepeat int
+		wantText   string
+	}{
+		{"90, 20 x3", []models.PomodoroStep{{Kind: models.PomodoroWork, Minutes: 90}, {Kind: models.PomodoroShortBreak, Minutes: 20}}, 3, "90, 20 x3"},
+		{"25,5,25,15l", []models.PomodoroStep{
+			{Kind: models.PomodoroWork, Minutes: 25}, {Kind: models.PomodoroShortBreak, Minutes: 5},
+			{Kind: models.PomodoroWork, Minutes: 25}, {Kind: models.PomodoroLongBreak, Minutes: 15},
+		}, 0, "25, 5, 25, 15L"},
+		{" 50 ×2 ", []models.PomodoroStep{{Kind: models.PomodoroWork, Minutes: 50}}, 2, "50 x2"},
+	}
+	for _, tt := range tests {
+		steps, repeat, err := models.ParsePomodoroSequence(tt.text)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", tt.text, err)
+			continue
+		}
+		if len(steps) != len(tt.wantSteps) || repeat != tt.wantRepeat {
+			t.Errorf("%q: got %+v x%d", tt.text, steps, repeat)
+			continue
+		}
+		for i := range steps {
+			if steps[i] != tt.wantSteps[i] {
+				t.Errorf("%q: step %d is %+v, expected %+v", tt.text, i, steps[i], tt.wantSteps[i])
+			}
+		}
+		if got := models.FormatPomodoroSequence(steps, repeat); got != tt.wantText {
+			t.Errorf("%q: formatted as %q, expected %q", tt.text, got, tt.wantText)
+		}
+	}
+
+	for _, bad := range []string{"", "x3", "90, 20 x0", "90, abc", "0, 5", "300"} {
+		if _, _, err := models.ParsePomodoroSequence(bad); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
+
+func TestPomodoroTimer_Flowtime(t *testing.T) {
+	timer, clock, rec := newTestTimer(true, false)
+	config := models.NewDefaultPomodoroConfig()
+	config.Mode = models.ModeFlowtime
+	config.FlowtimeRatio = 5
+	config.AutoStartBreaks = true
+	timer.SetConfig(config)
+
+	timer.Start()
+	clock.Advance(37)
+	status := timer.Snapshot()
+	if status.State != models.PomodoroWork || !status.OpenEnded || status.Elapsed != 37*time.Minute {
+		t.Fatalf("Expected open-ended work at 37 minutes, got %+v", status)
+	}
+	if p := status.Progress(); p < 0.47 || p > 0.49 {
+		t.Errorf("Expected the second lap of the ring to be 12/25 full, got %v", p)
+	}
+
+	// Work never ends by itself
+	clock.Advance(500)
+	timer.Update()
+	timer.Pause()
+	clock.Advance(10)
+	timer.FinishWork()
+
+	status = timer.Snapshot()
+	wantBreak := models.FlowtimeBreak(537*time.Minute, 5)
+	if status.State != models.PomodoroShortBreak || status.Remaining != wantBreak || status.Sessions != 1 {
+		t.Fatalf("Expected a %s break, got %+v", wantBreak, status)
+	}
+	var finished models.PomodoroEvent
+	for _, e := range rec.events {
+		if e.Type == models.EventWorkFinished {
+			finished = e
+		}
+	}
+	if finished.Duration != 537*time.Minute {
+		t.Errorf("Expected the finished work to last 537 minutes, got %s", finished.Duration)
+	}
+
+	clock.Advance(wantBreak.Minutes())
+	timer.Update()
+	if status := timer.Snapshot(); status.State != models.PomodoroIdle || status.Next != models.PomodoroWork {
+		t.Errorf("Expected idle before the next focus session, got %+v", status)
+	}
+
+	if got := models.FlowtimeBreak(3*time.Minute, 5); got != time.Minute {
+		t.Errorf("Expected breaks of at least a minute, got %s", got)
+	}
+}
+
+func TestPomodoroTimer_SequenceRepeatsAndStops(t *testing.T) {
+	timer, clock, _ := newTestTimer(true, true)
+	steps, repeat, _ := models.ParsePomodoroSequence("90, 20 x2")
+	config := models.NewDefaultPomodoroConfig()
+	config.Mode = models.ModeSequence
+	config.Sequence = steps
+	config.SequenceRepeat = repeat
+	config.AutoStartBreaks = true
+	config.AutoStartWork = true
+	timer.SetConfig(config)
+
+	timer.Start()
+	var seen []models.PomodoroState
+	var remaining []time.Duration
+	for i := 0; i < 4; i++ {
+		status := timer.Snapshot()
+		seen = append(seen, status.State)
+		remaining = append(remaining, status.Remaining)
+		clock.Advance(status.Remaining.Minutes())
+		timer.Update()
+	}
+
+	want := []models.PomodoroState{models.PomodoroWork, models.PomodoroShortBreak, models.PomodoroWork, models.PomodoroShortBreak}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Errorf("Interval %d: expected %s, got %s", i+1, want[i], seen[i])
+		}
+	}
+	if remaining[0] != 90*time.Minute || remaining[1] != 20*time.Minute {
+		t.Errorf("Unexpected interval lengths %v", remaining)
+	}
+
+	// After the last round the timer waits at the start of the sequence
+	status := timer.Snapshot()
+	if status.State != models.PomodoroIdle || status.Next != models.PomodoroWork || status.Step != 0 || status.Sessions != 2 {
+		t.Errorf("Expected the sequence to stop after 2 rounds, got %+v", status)
+	}
+}
+
+func TestPomodoroService_SequencePositionSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	clock := &fakeClock{now: time.Date(2025, 11, 19, 9, 0, 0, 0, time.UTC)}
+	preset := models.PomodoroPreset{Name: "Test", Mode: models.ModeSequence, Sequence: "50, 10, 30, 15L"}
+
+	first := services.NewPomodoroServiceWithClock(persistence.NewPomodoroStore(dir), clock)
+	first.Timer().SetConfig(preset.Config())
+	first.Restore()
+	first.Timer().Start()
+	clock.Advance(50)
+	first.Timer().Update()
+	first.Timer().Start()
+	clock.Advance(12)
+	first.Stop()
+
+	second := services.NewPomodoroServiceWithClock(persistence.NewPomodoroStore(dir), clock)
+	second.Timer().SetConfig(preset.Config())
+	if err := second.Restore(); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	status := second.Timer().Snapshot()
+	if status.State != models.PomodoroIdle || status.Next != models.PomodoroWork || status.Step != 2 {
+		t.Fatalf("Expected to wait for the third step, got %+v", status)
+	}
+	second.Timer().Start()
+	if status := second.Timer().Snapshot(); status.Remaining != 30*time.Minute {
+		t.Errorf("Expected the 30 minute step, got %s", status.Remaining)
+	}
+}
+
+func TestPomodoroSettings_MiniPositionRoundTrip(t *testing.T) {
+	manager := persistence.NewConfigManager(t.TempDir())
+
+	config := models.NewDefaultConfig()
+	if config.Pomodoro.MiniPosition != nil {
+		t.Fatalf("Expected no saved position by default, got %+v", config.Pomodoro.MiniPosition)
+	}
+	config.Pomodoro.MiniPosition = &models.WindowPosition{X: 1720, Y: -40}
+	if err := manager.SaveConfig(config); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+
+	loaded, err := manager.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if pos := loaded.Pomodoro.MiniPosition; pos == nil || *pos != (models.WindowPosition{X: 1720, Y: -40}) {
+		t.Errorf("Expected the compact window position to be restored, got %+v", pos)
+	}
+}
+
+func TestPomodoroService_Interruptions(t *testing.T) {
+	dir := t.TempDir()
+	clock := &fakeClock{now: time.Date(2025, 11, 19, 9, 0, 0, 0, time.UTC)}
+	todo := models.NewTodoItem()
+	todo.Name = "Write report"
+	todo.SetTime(time.Date(2025, 11, 19, 14, 0, 0, 0, time.UTC))
+
+	service := services.NewPomodoroServiceWithClock(persistence.NewPomodoroStore(dir), clock)
+	var credited []models.PomodoroTask
+	service.SetOnTaskPomodoro(func(task models.PomodoroTask) {
+		credited = append(credited, task)
+	})
+
+	if service.LogInterruption(models.InterruptionInternal, "too early", false) {
+		t.Fatal("Expected interruptions to be refused outside of work")
+	}
+
+	// Noting an interruption keeps the Pomodoro going
+	service.StartTask(todo)
+	clock.Advance(5)
+	if !service.LogInterruption(models.InterruptionExternal, "  Call back Anna\nabout the invoice ", false) {
+		t.Fatal("Expected the interruption to be logged")
+	}
+	if status := service.Timer().Snapshot(); status.State != models.PomodoroWork || status.Remaining != 20*time.Minute {
+		t.Fatalf("Expected work to continue, got %s %s", status.State, status.Remaining)
+	}
+
+	// Voiding abandons it: no session, no credit, no break
+	clock.Advance(5)
+	service.LogInterruption(models.InterruptionInternal, "", true)
+	status := service.Timer().Snapshot()
+	if status.State != models.PomodoroIdle || status.Next != models.PomodoroWork || status.Sessions != 0 {
+		t.Fatalf("Expected the voided Pomodoro to wait for new work, got %+v", status)
+	}
+	if len(credited) != 0 {
+		t.Errorf("Expected a voided Pomodoro not to be credited, got %+v", credited)
+	}
+	completed := service.Completed()
+	if len(completed) != 1 || !completed[0].Voided || completed[0].End.Sub(completed[0].Start) != 10*time.Minute {
+		t.Errorf("Expected one voided 10 minute record, got %+v", completed)
+	}
+	service.Stop()
+
+	restarted := services.NewPomodoroServiceWithClock(persistence.NewPomodoroStore(dir), clock)
+	if err := restarted.Restore(); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	today := restarted.Interruptions(clock.now)
+	if len(today) != 2 || len(restarted.Interruptions(clock.now.AddDate(0, 0, 1))) != 0 {
+		t.Fatalf("Expected 2 interruptions today and none tomorrow, got %+v", today)
+	}
+	first := today[0]
+	if first.Kind.Symbol() != "-" || first.Note != "Call back Anna\nabout the invoice" || !first.Task.Matches(todo) || first.Voided {
+		t.Errorf("Unexpected first interruption %+v", first)
+	}
+	if !today[1].Voided || today[1].Kind.Symbol() != "'" {
+		t.Errorf("Unexpected second interruption %+v", today[1])
+	}
+
+	// The note becomes a task; the conversion is remembered
+	due := time.Date(2025, 11, 19, 10, 0, 0, 0, time.UTC)
+	converted := first.Todo(due)
+	if converted.Name != "Call back Anna" || converted.Content != first.Note || converted.Kind != 1 || !converted.TodoTime.Equal(due) {
+		t.Errorf("Unexpected todo %+v", converted)
+	}
+	restarted.MarkInterruptionConverted(first.At)
+	if !restarted.Interruptions(clock.now)[0].Converted {
+		t.Error("Expected the interruption to be marked as converted")
+	}
+}
+
+func TestPomodoroStats_FocusHeatmapAndLabels(t *testing.T) {
+	loc := time.UTC
+	at := func(day, hour, minute int) time.Time { return time.Date(2025, 11, day, hour, minute, 0, 0, loc) }
+	report := &models.PomodoroTask{Time: at(19, 14, 0), Name: "Write report"}
+	mail := &models.PomodoroTask{Time: at(20, 8, 0), Name: "Answer mail"}
+	records := []models.PomodoroRecord{
+		{Kind: models.PomodoroWork, Start: at(19, 9, 50), End: at(19, 10, 15), Task: report},           // Wednesday, split 10/15
+		{Kind: models.PomodoroShortBreak, Start: at(19, 10, 15), End: at(19, 10, 20)},                  // breaks are not focus
+		{Kind: models.PomodoroWork, Start: at(19, 23, 50), End: at(20, 0, 15), Task: mail},             // crosses midnight
+		{Kind: models.PomodoroWork, Start: at(20, 9, 0), End: at(20, 9, 10), Task: mail, Voided: true}, // abandoned
+		{Kind: models.PomodoroWork, Start: at(10, 9, 0), End: at(10, 9, 25)},                           // before the range
+	}
+	interruptions := []models.Interruption{
+		{Kind: models.InterruptionInternal, At: at(19, 10, 0)},
+		{Kind: models.InterruptionExternal, At: at(20, 9, 10), Voided: true},
+		{Kind: models.InterruptionExternal, At: at(10, 9, 5)},
+	}
+	labelOf := func(task *models.PomodoroTask) string {
+		if task.Name == "Write report" {
+			return "work"
+		}
+		return ""
+	}
+
+	stats := models.ComputePomodoroStats(records, interruptions, at(17, 12, 0), at(24, 0, 0), labelOf)
+
+	if len(stats.Days) != 7 || !stats.Days[0].Start.Equal(at(17, 0, 0)) {
+		t.Fatalf("Expected 7 days from Monday, got %+v", stats.Days)
+	}
+	if stats.Days[2].Focus != 35*time.Minute || stats.Days[3].Focus != 15*time.Minute {
+		t.Errorf("Unexpected daily focus %v / %v", stats.Days[2].Focus, stats.Days[3].Focus)
+	}
+	if len(stats.Weeks) != 1 || stats.Weeks[0].Focus != 50*time.Minute || stats.Total != 50*time.Minute {
+		t.Errorf("Expected 50 minutes in one week, got %+v total %v", stats.Weeks, stats.Total)
+	}
+	if stats.Heatmap[time.Wednesday][9] != 10*time.Minute || stats.Heatmap[time.Wednesday][10] != 15*time.Minute ||
+		stats.Heatmap[time.Wednesday][23] != 10*time.Minute || stats.Heatmap[time.Thursday][0] != 15*time.Minute {
+		t.Errorf("Unexpected heatmap %v / %v", stats.Heatmap[time.Wednesday], stats.Heatmap[time.Thursday])
+	}
+	if stats.Completed != 2 || stats.Abandoned != 1 || stats.Interruptions != 2 {
+		t.Errorf("Expected 2 completed, 1 abandoned and 2 interruptions, got %+v", stats)
+	}
+	if rate := stats.CompletionRate(); rate < 0.66 || rate > 0.67 {
+		t.Errorf("Expected a completion rate of 2/3, got %v", rate)
+	}
+	if avg := stats.InterruptionsPerPomodoro(); avg < 0.66 || avg > 0.67 {
+		t.Errorf("Expected 2/3 interruptions per Pomodoro, got %v", avg)
+	}
+	want := []models.LabelFocus{{Label: "", Focus: 25 * time.Minute}, {Label: "work", Focus: 25 * time.Minute}}
+	if len(stats.Labels) != 2 || stats.Labels[0] != want[0] || stats.Labels[1] != want[1] {
+		t.Errorf("Expected %+v, got %+v", want, stats.Labels)
+	}
+}
+
+func TestPomodoroCSV_Export(t *testing.T) {
+	start := time.Date(2025, 11, 19, 9, 0, 0, 0, time.UTC)
+	task := &models.PomodoroTask{Time: start.Add(5 * time.Hour), Name: "Write, report"}
+	records := []models.PomodoroRecord{
+		{Kind: models.PomodoroWork, Start: start, End: start.Add(25 * time.Minute), Task: task},
+		{Kind: models.PomodoroShortBreak, Start: start.Add(25 * time.Minute), End: start.Add(30 * time.Minute)},
+		{Kind: models.PomodoroWork, Start: start.Add(30 * time.Minute), End: start.Add(37*time.Minute + 30*time.Second), Voided: true},
+	}
+	interruptions := []models.Interruption{
+		{Kind: models.InterruptionInternal, At: start.Add(3 * time.Minute)},
+		{Kind: models.InterruptionExternal, At: start.Add(37*time.Minute + 30*time.Second), Voided: true},
+	}
+
+	var out strings.Builder
+	err := persistence.WritePomodoroCSV(&out, records, interruptions, func(*models.PomodoroTask) string { return "work" })
+	if err != nil {
+		t.Fatalf("WritePomodoroCSV failed: %v", err)
+	}
+
+	want := `kind,start,end,minutes,status,task,task_time,label,interruptions
+work,2025-11-19T09:00:00Z,2025-11-19T09:25:00Z,25.0,completed,"Write, report",2025-11-19T14:00:00Z,work,1
+short_break,2025-11-19T09:25:00Z,2025-11-19T09:30:00Z,5.0,completed,,,,0
+work,2025-11-19T09:30:00Z,2025-11-19T09:37:30Z,7.5,voided,,,,1
+`
+	if out.String() != want {
+		t.Errorf("Unexpected CSV:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestFormatPomodoroStatus(t *testing.T) {
+	cases := []struct {
+		status models.PomodoroStatus
+		want   string
+	}{
+		{models.PomodoroStatus{State: models.PomodoroIdle}, "Ready"},
+		{models.PomodoroStatus{State: models.PomodoroWork, Remaining: 12*time.Minute + time.Second}, "Work · 13 min left"},
+		{models.PomodoroStatus{State: models.PomodoroShortBreak, Remaining: 5 * time.Second}, "Short Break · 1 min left"},
+		{models.PomodoroStatus{State: models.PomodoroPaused, PausedFrom: models.PomodoroWork, Remaining: 20 * time.Minute}, "Paused (Work) · 20 min left"},
+		{models.PomodoroStatus{State: models.PomodoroWork, OpenEnded: true, Elapsed: 42*time.Minute + 59*time.Second}, "Work · 42 min"},
+	}
+	for _, c := range cases {
+		if got := helpers.FormatPomodoroStatus(c.status); got != c.want {
+			t.Errorf("FormatPomodoroStatus(%+v) = %q, want %q", c.status, got, c.want)
+		}
+	}
+}
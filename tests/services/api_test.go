@@ -0,0 +1,219 @@
+package services_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"godo/src/models"
+	"godo/src/persistence"
+	"godo/src/services"
+)
+
+const testToken = "secret"
+
+// apiTodo mirrors the JSON of a todo returned by the API
+type apiTodo struct {
+	ID string `json:"id"`
+	models.TodoItem
+}
+
+// newTestAPI serves a fresh data directory and counts change notifications
+func newTestAPI(t *testing.T) (*httptest.Server, *int) {
+	t.Helper()
+	dir := t.TempDir()
+	pomodoro := services.NewPomodoroService(persistence.NewPomodoroStore(dir))
+	api := services.NewAPIServer(persistence.NewMonthlyManager(dir), pomodoro)
+	api.SetToken(testToken)
+	changes := 0
+	api.SetOnChanged(func() { changes++ })
+
+	server := httptest.NewServer(api.Handler())
+	t.Cleanup(server.Close)
+	return server, &changes
+}
+
+// call sends a request and decodes the JSON answer into out, if given
+func call(t *testing.T, server *httptest.Server, method, path, body string, out interface{}) int {
+	t.Helper()
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(resp.Body)
+	if out != nil && resp.StatusCode < 300 {
+		if err := json.Unmarshal(data, out); err != nil {
+			t.Fatalf("%s %s returned invalid JSON %q: %v", method, path, data, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func todoPath(id string, action string) string {
+	path := "/api/todos/" + url.PathEscape(id)
+	if action != "" {
+		path += "/" + action
+	}
+	return path
+}
+
+func TestAPI_RequiresToken(t *testing.T) {
+	server, _ := newTestAPI(t)
+
+	for _, header := range []string{"", "Bearer wrong"} {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/todos", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Expected 401 for %q, got %d", header, resp.StatusCode)
+		}
+	}
+
+	// The description is public
+	resp, err := http.Get(server.URL + "/api/openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(string(spec), "openapi: 3") {
+		t.Errorf("Expected the OpenAPI description, got %d %.40q", resp.StatusCode, spec)
+	}
+}
+
+func TestAPI_TodoCRUD(t *testing.T) {
+	server, changes := newTestAPI(t)
+	at := time.Date(2026, 10, 20, 9, 0, 0, 0, time.Local)
+	body := `{"name": "Write report", "kind": 1, "todoTime": "` + at.Format(time.RFC3339) + `"}`
+
+	var created apiTodo
+	if status := call(t, server, http.MethodPost, "/api/todos", body, &created); status != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", status)
+	}
+	if created.Name != "Write report" || !created.TodoTime.Equal(at) {
+		t.Fatalf("Unexpected todo %+v", created)
+	}
+	if status := call(t, server, http.MethodPost, "/api/todos", body, nil); status != http.StatusConflict {
+		t.Errorf("Expected 409 for a second todo at the same time, got %d", status)
+	}
+	if status := call(t, server, http.MethodPost, "/api/todos", `{"name": ""}`, nil); status != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid todo, got %d", status)
+	}
+
+	var listed []apiTodo
+	call(t, server, http.MethodGet, "/api/todos?day=2026-10-20", "", &listed)
+	if len(listed) != 1 || listed[0].ID != created.ID {
+		t.Fatalf("Expected the new todo in the day list, got %+v", listed)
+	}
+	call(t, server, http.MethodGet, "/api/todos?month=2026-10", "", &listed)
+	if len(listed) != 1 {
+		t.Errorf("Expected the new todo in the month list, got %+v", listed)
+	}
+	call(t, server, http.MethodGet, "/api/todos?from=2026-10-21&to=2026-10-31", "", &listed)
+	if len(listed) != 0 {
+		t.Errorf("Expected an empty range, got %+v", listed)
+	}
+
+	var updated apiTodo
+	call(t, server, http.MethodPost, todoPath(created.ID, "done"), "", &updated)
+	if !updated.Done {
+		t.Error("Expected the todo to be done")
+	}
+	call(t, server, http.MethodPost, todoPath(created.ID, "star"), `{"starred": true}`, &updated)
+	if !updated.Starred || !updated.Done {
+		t.Errorf("Expected the todo to be starred and still done, got %+v", updated)
+	}
+
+	// PATCH renames and keeps the other fields
+	call(t, server, http.MethodPatch, todoPath(created.ID, ""), `{"name": "Send report"}`, &updated)
+	var fetched apiTodo
+	if status := call(t, server, http.MethodGet, todoPath(created.ID, ""), "", &fetched); status != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", status)
+	}
+	if fetched.Name != "Send report" || !fetched.Starred || fetched.Kind != 1 {
+		t.Errorf("Unexpected todo after PATCH %+v", fetched)
+	}
+
+	if status := call(t, server, http.MethodDelete, todoPath(created.ID, ""), "", nil); status != http.StatusNoContent {
+		t.Errorf("Expected 204, got %d", status)
+	}
+	if status := call(t, server, http.MethodGet, todoPath(created.ID, ""), "", nil); status != http.StatusNotFound {
+		t.Errorf("Expected 404 after delete, got %d", status)
+	}
+
+	// POST, done, star, PATCH and DELETE each refresh the windows
+	if *changes != 5 {
+		t.Errorf("Expected 5 change notifications, got %d", *changes)
+	}
+}
+
+func TestAPI_Reorder(t *testing.T) {
+	server, _ := newTestAPI(t)
+	var ids []string
+	for hour := 9; hour <= 11; hour++ {
+		at := time.Date(2026, 10, 20, hour, 0, 0, 0, time.Local)
+		var created apiTodo
+		call(t, server, http.MethodPost, "/api/todos", `{"name": "Todo", "todoTime": "`+at.Format(time.RFC3339)+`"}`, &created)
+		ids = append(ids, created.ID)
+	}
+
+	// Without an order the day lists the latest first: 11, 10, 9
+	var day []apiTodo
+	if status := call(t, server, http.MethodPost, todoPath(ids[0], "reorder"), `{"position": 0}`, &day); status != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", status)
+	}
+	var listed []apiTodo
+	call(t, server, http.MethodGet, "/api/todos?day=2026-10-20", "", &listed)
+	got := []string{listed[0].ID, listed[1].ID, listed[2].ID}
+	want := []string{ids[0], ids[2], ids[1]}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected order %v, got %v", want, got)
+	}
+}
+
+func TestAPI_Pomodoro(t *testing.T) {
+	server, _ := newTestAPI(t)
+
+	var status struct {
+		State     string  `json:"state"`
+		Interval  string  `json:"interval"`
+		Remaining float64 `json:"remaining"`
+	}
+	call(t, server, http.MethodGet, "/api/pomodoro", "", &status)
+	if status.State != "idle" || status.Interval != "work" {
+		t.Fatalf("Expected an idle timer before work, got %+v", status)
+	}
+
+	call(t, server, http.MethodPost, "/api/pomodoro/start", "", &status)
+	if status.State != "work" || status.Remaining <= 0 {
+		t.Errorf("Expected running work, got %+v", status)
+	}
+	call(t, server, http.MethodPost, "/api/pomodoro/pause", "", &status)
+	if status.State != "paused" {
+		t.Errorf("Expected a paused timer, got %+v", status)
+	}
+	if code := call(t, server, http.MethodPost, "/api/pomodoro/explode", "", nil); code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown action, got %d", code)
+	}
+	if code := call(t, server, http.MethodPost, "/api/pomodoro/start", `{"todo": "2026-10-20T09:00:00Z"}`, nil); code != http.StatusNotFound {
+		t.Errorf("Expected 404 when starting on a missing todo, got %d", code)
+	}
+}
@@ -0,0 +1,136 @@
+package services_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"godo/src/models"
+	"godo/src/persistence"
+	"godo/src/services"
+)
+
+// addTodo adds a todo at a local time
+func addTodo(t *testing.T, repo persistence.TodoRepository, name string, at time.Time) {
+	t.Helper()
+	todo := models.NewTodoItem()
+	todo.Name = name
+	todo.SetZonedTime(at, "")
+	if err := repo.AddTodo(todo); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExportService_Ranges(t *testing.T) {
+	repo := persistence.NewMonthlyManager(t.TempDir())
+	addTodo(t, repo, "October", time.Date(2025, 10, 31, 23, 30, 0, 0, time.Local))
+	addTodo(t, repo, "Late", time.Date(2025, 11, 3, 18, 0, 0, 0, time.Local))
+	addTodo(t, repo, "Early", time.Date(2025, 11, 3, 8, 0, 0, 0, time.Local))
+	addTodo(t, repo, "November", time.Date(2025, 11, 30, 12, 0, 0, 0, time.Local))
+	addTodo(t, repo, "Next year", time.Date(2026, 1, 2, 9, 0, 0, 0, time.Local))
+	exporter := services.NewExportService(repo)
+
+	names := func(r services.ExportRange) string {
+		t.Helper()
+		todos, err := exporter.Todos(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, todo := range todos {
+			names = append(names, todo.Name)
+		}
+		return strings.Join(names, ",")
+	}
+
+	if got := names(services.DayRange(time.Date(2025, 11, 3, 12, 0, 0, 0, time.Local))); got != "Early,Late" {
+		t.Errorf("Day: got %s", got)
+	}
+	if got := names(services.MonthRange(2025, 11)); got != "Early,Late,November" {
+		t.Errorf("Month: got %s", got)
+	}
+	dates, err := services.DateRange(time.Date(2025, 10, 31, 0, 0, 0, 0, time.Local), time.Date(2025, 11, 3, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := names(dates); got != "October,Early,Late" {
+		t.Errorf("Dates: got %s", got)
+	}
+	if got := names(services.ExportRange{}); got != "October,Early,Late,November,Next year" {
+		t.Errorf("All: got %s", got)
+	}
+	if _, err := services.DateRange(time.Date(2025, 11, 4, 0, 0, 0, 0, time.Local), time.Date(2025, 11, 3, 0, 0, 0, 0, time.Local)); err == nil {
+		t.Error("Expected an error for a range ending before it starts")
+	}
+}
+
+func TestExportService_ImportIntoOtherRepository(t *testing.T) {
+	source := persistence.NewMonthlyManager(t.TempDir())
+	addTodo(t, source, "Standup", time.Date(2025, 11, 3, 9, 30, 0, 0, time.Local))
+	addTodo(t, source, "Review", time.Date(2025, 11, 4, 14, 0, 0, 0, time.Local))
+
+	for _, format := range services.ExportFormats {
+		var buf bytes.Buffer
+		if n, err := services.NewExportService(source).Export(&buf, format, services.ExportRange{}); err != nil || n != 2 {
+			t.Fatalf("%s: export returned %d (%v)", format, n, err)
+		}
+		exported := buf.String()
+
+		target := persistence.NewMonthlyManager(t.TempDir())
+		addTodo(t, target, "Standup", time.Date(2025, 11, 3, 9, 30, 0, 0, time.Local))
+		report, err := services.NewExportService(target).Import(strings.NewReader(exported), format)
+		if err != nil {
+			t.Fatalf("%s: import failed: %v", format, err)
+		}
+		if report.Added != 1 || report.Existing != 1 || len(report.Skipped) != 0 {
+			t.Errorf("%s: expected 1 added and 1 existing, got %+v", format, report)
+		}
+		if todo, err := target.GetTodoByTime(time.Date(2025, 11, 4, 14, 0, 0, 0, time.Local)); err != nil || todo.Name != "Review" {
+			t.Errorf("%s: expected the review imported, got %+v (%v)", format, todo, err)
+		}
+	}
+}
+
+func TestAPI_ExportImport(t *testing.T) {
+	server, changes := newTestAPI(t)
+
+	csv := "name,todoTime,level\n" +
+		"Standup,2025-11-03 09:30,2\n" +
+		"Broken,someday,1\n"
+	var report struct {
+		Added    int `json:"added"`
+		Existing int `json:"existing"`
+		Skipped  []struct {
+			Line    int    `json:"line"`
+			Message string `json:"message"`
+		} `json:"skipped"`
+	}
+	if status := call(t, server, http.MethodPost, "/api/import?format=csv", csv, &report); status != http.StatusOK {
+		t.Fatalf("Import returned %d", status)
+	}
+	if report.Added != 1 || len(report.Skipped) != 1 || report.Skipped[0].Line != 3 || *changes != 1 {
+		t.Errorf("Unexpected report %+v after %d changes", report, *changes)
+	}
+	if status := call(t, server, http.MethodPost, "/api/import?format=csv", "colour\nred\n", nil); status != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown column, got %d", status)
+	}
+	if status := call(t, server, http.MethodPost, "/api/import?format=xml", csv, nil); status != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown format, got %d", status)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/export?format=markdown&day=2025-11-03", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/markdown") ||
+		!strings.Contains(string(body), "- [ ] 09:30 **Standup** — priority: high") {
+		t.Errorf("Unexpected export %d %q:\n%s", resp.StatusCode, resp.Header.Get("Content-Type"), body)
+	}
+}
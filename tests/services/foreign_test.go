@@ -0,0 +1,77 @@
+package services_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"godo/src/persistence"
+	"godo/src/services"
+)
+
+func TestExportService_ImportsOtherToolsOnce(t *testing.T) {
+	dir := t.TempDir()
+	repo := persistence.NewMonthlyManager(dir)
+	addTodo(t, repo, "Standup", time.Date(2025, 11, 3, 9, 30, 0, 0, time.Local))
+	exporter := services.NewExportService(repo)
+
+	export := "TYPE,CONTENT,PRIORITY,DATE\n" +
+		"task,Buy milk @shopping,2,2025-11-07\n" +
+		"task,Clean desk,4,2025-11-07\n" +
+		"task,Review budget,1,2025-12-01 09:00\n" +
+		"task,Learn Go,,\n" +
+		"task,Learn Go,,\n"
+	preview := func(undated time.Time) *services.ImportPreview {
+		t.Helper()
+		tasks, skipped, err := services.ReadForeign(strings.NewReader(export), services.FormatTodoist, "/exports/Home.csv")
+		if err != nil {
+			t.Fatal(err)
+		}
+		preview, err := exporter.Preview(tasks, skipped, undated)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return preview
+	}
+
+	first := preview(time.Date(2025, 11, 7, 0, 0, 0, 0, time.Local))
+	if first.New() != 4 || !first.Tasks[4].Duplicate {
+		t.Fatalf("Expected 4 new tasks and the repeated one marked, got %d", first.New())
+	}
+	if first.Tasks[0].Todo.Label != "Home" {
+		t.Errorf("Expected the file name as project, got %q", first.Tasks[0].Todo.Label)
+	}
+	report, err := exporter.AddPreview(first)
+	if err != nil || report.Added != 4 || report.Existing != 1 {
+		t.Fatalf("Unexpected report %+v (%v)", report, err)
+	}
+
+	// All-day tasks on the same day are kept apart by their time
+	todos, err := repo.GetTodosForMonth(2025, 11)
+	if err != nil || len(todos) != 4 {
+		t.Fatalf("Expected 4 todos in November, got %d (%v)", len(todos), err)
+	}
+	times := make(map[time.Time]bool)
+	for _, todo := range todos {
+		times[todo.TodoTime] = true
+	}
+	if len(times) != 4 {
+		t.Errorf("Expected distinct times, got %v", times)
+	}
+	for _, file := range []string{"202511.yaml", "202512.yaml"} {
+		if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+			t.Errorf("Expected %s to be written: %v", file, err)
+		}
+	}
+
+	// A newer export adds nothing already imported, wherever undated tasks go
+	again := preview(time.Date(2025, 11, 20, 0, 0, 0, 0, time.Local))
+	if again.New() != 0 {
+		t.Errorf("Expected nothing new on re-import, got %d", again.New())
+	}
+	if report, err := exporter.AddPreview(again); err != nil || report.Added != 0 || report.Existing != 5 {
+		t.Errorf("Unexpected report %+v (%v)", report, err)
+	}
+}
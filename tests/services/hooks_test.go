@@ -0,0 +1,208 @@
+package services_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"godo/src/models"
+	"godo/src/persistence"
+	"godo/src/services"
+)
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+// writeHook writes a shell script to the hooks folder
+func writeHook(t *testing.T, dir, name, script string, mode os.FileMode) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readPayloads returns the payloads the hooks appended to events.log
+func readPayloads(t *testing.T, dir string) []services.HookPayload {
+	t.Helper()
+	file, err := os.Open(filepath.Join(dir, "events.log"))
+	if err != nil {
+		t.Fatalf("Expected the hooks to have run: %v", err)
+	}
+	defer file.Close()
+
+	var payloads []services.HookPayload
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var payload services.HookPayload
+		if err := json.Unmarshal(scanner.Bytes(), &payload); err != nil {
+			t.Fatalf("Unexpected payload %q: %v", scanner.Text(), err)
+		}
+		payloads = append(payloads, payload)
+	}
+	return payloads
+}
+
+func TestHookedRepository_RunsHooksOnChanges(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are shell scripts")
+	}
+	dir := t.TempDir()
+	for _, event := range []services.HookEvent{services.HookTodoAdded, services.HookTodoUpdated,
+		services.HookTodoCompleted, services.HookTodoDeleted} {
+		writeHook(t, dir, string(event)+".sh", "cat >> events.log\necho >> events.log\n", 0755)
+	}
+	// Files that are not executable are not hooks
+	writeHook(t, dir, "todo-added.notes", "echo ignored >> events.log\n", 0644)
+
+	hooks := services.NewHooks(dir)
+	repo := services.NewHookedRepository(persistence.NewMonthlyManager(t.TempDir()), hooks)
+	addTodo(t, repo, "Call Ann", time.Date(2025, 11, 3, 9, 30, 0, 0, time.Local))
+	todo, err := repo.GetTodoByTime(time.Date(2025, 11, 3, 9, 30, 0, 0, time.Local))
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := *todo
+	done.Done = true
+	if err := repo.UpdateTodo(&done, todo.TodoTime); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.RemoveTodo(todo.TodoTime); err != nil {
+		t.Fatal(err)
+	}
+	hooks.Stop()
+
+	payloads := readPayloads(t, dir)
+	var events []string
+	for _, payload := range payloads {
+		events = append(events, string(payload.Event))
+		if payload.Todo == nil || payload.Todo.Name != "Call Ann" {
+			t.Errorf("%s: expected the todo, got %+v", payload.Event, payload.Todo)
+		}
+	}
+	if got := strings.Join(events, ","); got != "todo-added,todo-updated,todo-completed,todo-deleted" {
+		t.Fatalf("Unexpected events %s", got)
+	}
+	if previous := payloads[1].Previous; previous == nil || previous.Done || !payloads[1].Todo.Done {
+		t.Errorf("Expected the update to carry the todo before and after, got %+v", payloads[1])
+	}
+}
+
+func TestHooks_KillsHooksAfterTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are shell scripts")
+	}
+	dir := t.TempDir()
+	writeHook(t, dir, "reminder.1.sh", "sleep 30\n", 0755)
+	writeHook(t, dir, "reminder.2.sh", "cat >> events.log\necho >> events.log\nexit 3\n", 0755)
+
+	hooks := services.NewHooks(dir)
+	hooks.SetTimeout(200 * time.Millisecond)
+	start := time.Now()
+	hooks.Fire(services.HookPayload{Event: services.HookReminder})
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("Expected Fire to return at once, took %v", elapsed)
+	}
+	hooks.Stop()
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the slow hook killed, took %v", elapsed)
+	}
+	// A hook failing or timing out does not keep the next from running
+	if payloads := readPayloads(t, dir); len(payloads) != 1 || payloads[0].Event != services.HookReminder {
+		t.Errorf("Expected the second hook to run once, got %+v", payloads)
+	}
+}
+
+func TestReminderService_FiresEachReminderOnce(t *testing.T) {
+	repo := persistence.NewMonthlyManager(t.TempDir())
+	meeting := models.NewTodoItem()
+	meeting.Name = "Meeting"
+	meeting.WarnTime = 15
+	meeting.SetZonedTime(time.Date(2025, 11, 30, 23, 50, 0, 0, time.Local), "")
+	// Reminders look into the next month
+	review := models.NewTodoItem()
+	review.Name = "Review"
+	review.WarnTime = 30
+	review.SetZonedTime(time.Date(2025, 12, 1, 0, 10, 0, 0, time.Local), "")
+	done := models.NewTodoItem()
+	done.Name = "Done"
+	done.WarnTime = 15
+	done.Done = true
+	done.SetZonedTime(time.Date(2025, 11, 30, 23, 50, 0, 0, time.Local), "")
+	for _, todo := range []*models.TodoItem{meeting, review, done} {
+		if err := repo.AddTodo(todo); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	clock := &fakeClock{now: time.Date(2025, 11, 30, 23, 0, 0, 0, time.Local)}
+	reminders := services.NewReminderServiceWithClock(repo, clock)
+	var fired []string
+	reminders.SetOnReminder(func(todo *models.TodoItem) {
+		fired = append(fired, todo.Name)
+	})
+	check := func(at time.Time) string {
+		clock.now = at
+		reminders.Check()
+		got := strings.Join(fired, ",")
+		fired = nil
+		return got
+	}
+
+	if got := check(clock.now); got != "" {
+		t.Errorf("Expected no reminders on the first check, got %s", got)
+	}
+	if got := check(time.Date(2025, 11, 30, 23, 36, 0, 0, time.Local)); got != "Meeting" {
+		t.Errorf("Expected the meeting reminder, got %s", got)
+	}
+	if got := check(time.Date(2025, 11, 30, 23, 41, 0, 0, time.Local)); got != "Review" {
+		t.Errorf("Expected the review reminder, got %s", got)
+	}
+	if got := check(time.Date(2025, 11, 30, 23, 55, 0, 0, time.Local)); got != "" {
+		t.Errorf("Expected each reminder once, got %s", got)
+	}
+}
+
+func TestReminderService_FindsRemindersOutsideNearbyMonths(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skip("time zone data not available")
+	}
+	repo := persistence.NewMonthlyManager(t.TempDir())
+	// Filed in December by its own zone, though it is January in UTC
+	party := models.NewTodoItem()
+	party.Name = "Party"
+	party.WarnTime = 30
+	party.SetZonedTime(time.Date(2025, 12, 31, 20, 0, 0, 0, la), "America/Los_Angeles")
+	// Imported reminders can come months early
+	renewal := models.NewTodoItem()
+	renewal.Name = "Renewal"
+	renewal.WarnTime = 90 * 24 * 60
+	renewal.SetZonedTime(time.Date(2026, 4, 1, 3, 45, 0, 0, time.UTC), "")
+	for _, todo := range []*models.TodoItem{party, renewal} {
+		if err := repo.AddTodo(todo); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 3, 0, 0, 0, time.UTC)}
+	reminders := services.NewReminderServiceWithClock(repo, clock)
+	var fired []string
+	reminders.SetOnReminder(func(todo *models.TodoItem) {
+		fired = append(fired, todo.Name)
+	})
+	reminders.Check()
+	clock.now = time.Date(2026, 1, 1, 3, 50, 0, 0, time.UTC)
+	reminders.Check()
+	sort.Strings(fired)
+	if got := strings.Join(fired, ","); got != "Party,Renewal" {
+		t.Errorf("Expected both reminders, got %s", got)
+	}
+}
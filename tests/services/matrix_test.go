@@ -0,0 +1,149 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"godo/src/models"
+	"godo/src/persistence"
+	"godo/src/services"
+)
+
+// quadrantNames lists the names of the todos in a quadrant
+func quadrantNames(todos []*models.TodoItem) string {
+	var names []string
+	for _, todo := range todos {
+		names = append(names, todo.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestMatrixScope_Range(t *testing.T) {
+	// Wednesday
+	day := time.Date(2025, 11, 5, 15, 0, 0, 0, time.Local)
+	week := services.MatrixWeek.Range(day)
+	if !week.From.Equal(time.Date(2025, 11, 3, 0, 0, 0, 0, time.Local)) ||
+		!week.To.Equal(time.Date(2025, 11, 9, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("Expected Monday to Sunday, got %v to %v", week.From, week.To)
+	}
+	// Sunday belongs to the week before
+	sunday := services.MatrixWeek.Range(time.Date(2025, 11, 9, 8, 0, 0, 0, time.Local))
+	if !sunday.From.Equal(week.From) {
+		t.Errorf("Expected Sunday in the week from %v, got %v", week.From, sunday.From)
+	}
+	if all := services.MatrixAll.Range(day); !all.From.IsZero() || !all.To.IsZero() {
+		t.Errorf("Expected no limits for all todos, got %v to %v", all.From, all.To)
+	}
+}
+
+func TestMatrixService_SortsOpenTodosIntoQuadrants(t *testing.T) {
+	repo := persistence.NewMonthlyManager(t.TempDir())
+	add := func(name string, level int, done bool, at time.Time) {
+		todo := models.NewTodoItem()
+		todo.Name = name
+		todo.Level = level
+		todo.Done = done
+		todo.SetZonedTime(at, "")
+		if err := repo.AddTodo(todo); err != nil {
+			t.Fatal(err)
+		}
+	}
+	add("Deploy", int(models.PriorityUrgent), false, time.Date(2025, 11, 3, 9, 0, 0, 0, time.Local))
+	add("Shipped", int(models.PriorityUrgent), true, time.Date(2025, 11, 3, 10, 0, 0, 0, time.Local))
+	add("Plan", int(models.PriorityHigh), false, time.Date(2025, 11, 4, 9, 0, 0, 0, time.Local))
+	add("Broken", 9, false, time.Date(2025, 11, 3, 11, 0, 0, 0, time.Local))
+	add("Next week", int(models.PriorityUrgent), false, time.Date(2025, 11, 10, 9, 0, 0, 0, time.Local))
+
+	matrix := services.NewMatrixService(repo)
+	day := time.Date(2025, 11, 3, 0, 0, 0, 0, time.Local)
+	board, err := matrix.Load(services.MatrixDay, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := quadrantNames(board.Quadrant(models.PriorityUrgent, false)); got != "Deploy" {
+		t.Errorf("Expected only the open todo of the day in Do, got %s", got)
+	}
+	// Levels out of range land in Eliminate
+	if got := quadrantNames(board.Quadrant(models.PriorityLow, false)); got != "Broken" {
+		t.Errorf("Expected the unknown level in Eliminate, got %s", got)
+	}
+
+	board, err = matrix.Load(services.MatrixWeek, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := quadrantNames(board.Quadrant(models.PriorityHigh, false)); got != "Plan" {
+		t.Errorf("Expected the week's todo in Schedule, got %s", got)
+	}
+	board, err = matrix.Load(services.MatrixAll, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := quadrantNames(board.Quadrant(models.PriorityUrgent, false)); got != "Deploy,Next week" {
+		t.Errorf("Expected every open todo in Do, got %s", got)
+	}
+}
+
+func TestMatrixService_MovesAndQuickAdds(t *testing.T) {
+	repo := persistence.NewMonthlyManager(t.TempDir())
+	addTodo(t, repo, "Review", time.Date(2025, 11, 3, 9, 0, 0, 0, time.Local))
+	var events []persistence.TodoEvent
+	repo.Events().Subscribe(func(event persistence.TodoEvent) {
+		events = append(events, event)
+	})
+
+	matrix := services.NewMatrixService(repo)
+	day := time.Date(2025, 11, 3, 0, 0, 0, 0, time.Local)
+	board, err := matrix.Load(services.MatrixDay, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	review := board.Quadrant(models.PriorityLow, false)[0]
+	if err := matrix.Move(review, models.PriorityUrgent); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := repo.GetTodoByTime(review.TodoTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Level != int(models.PriorityUrgent) {
+		t.Errorf("Expected the todo moved to Do, got level %d", stored.Level)
+	}
+	if len(events) != 1 || events[0].Change != persistence.TodosUpdated {
+		t.Errorf("Expected one update event, got %+v", events)
+	}
+
+	// Quick adds are unscheduled and each takes a free minute of the day,
+	// whatever quadrant it goes to
+	for _, add := range []struct {
+		level models.PriorityLevel
+		name  string
+	}{{models.PriorityHigh, "Call Ann"}, {models.PriorityUrgent, "Call Bob"}, {models.PriorityHigh, "Call Cid"}} {
+		if _, err := matrix.QuickAdd(add.level, add.name, day); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := matrix.QuickAdd(models.PriorityHigh, "  ", day); err == nil {
+		t.Error("Expected a todo without a name to be refused")
+	}
+	board, err = matrix.Load(services.MatrixDay, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := quadrantNames(board.Quadrant(models.PriorityHigh, true)); got != "Call Ann,Call Cid" {
+		t.Errorf("Expected both quick adds in Schedule, got %s", got)
+	}
+	if got := quadrantNames(board.Quadrant(models.PriorityUrgent, true)); got != "Call Bob" {
+		t.Errorf("Expected the quick add in Do and the scheduled todo hidden, got %s", got)
+	}
+	seen := make(map[time.Time]bool)
+	for _, level := range models.MatrixLevels {
+		for _, todo := range board.Quadrant(level, true) {
+			if seen[todo.TodoTime] || todo.DisplayTime(time.Local).Day() != 3 {
+				t.Errorf("Expected %s on a minute of its own on the day, got %v", todo.Name, todo.TodoTime)
+			}
+			seen[todo.TodoTime] = true
+		}
+	}
+}
@@ -1,9 +1,13 @@
 package utils_test
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"godo/src/utils"
 )
@@ -91,44 +95,154 @@ func TestSingleInstance_Unlock(t *testing.T) {
 }
 
 func TestSingleInstance_StaleLock(t *testing.T) {
-	// Create a fake stale lock file with invalid PID
-	lockPath := filepath.Join(os.TempDir(), "test-stale-app.lock")
-
-	// Write a very high PID that likely doesn't exist
-	err := os.WriteFile(lockPath, []byte("999999999"), 0600)
-	if err != nil {
-		t.Fatalf("Failed to create fake lock file: %v", err)
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	// Lock files left behind by dead instances, including one whose PID is
+	// alive again but belongs to a process started at another time
+	records := []string{"999999999", fmt.Sprintf("%d 1", os.Getpid()), "garbage"}
+	for _, record := range records {
+		si := utils.NewSingleInstance("test-stale-app")
+		if err := os.WriteFile(si.GetLockPath(), []byte(record), 0600); err != nil {
+			t.Fatalf("Failed to create fake lock file: %v", err)
+		}
+
+		locked, err := si.TryLock()
+		if err != nil {
+			t.Fatalf("Failed to acquire lock with stale lock %q present: %v", record, err)
+		}
+		if !locked {
+			t.Fatalf("Expected to acquire lock over stale lock %q, but failed", record)
+		}
+		si.Unlock()
 	}
+}
 
-	// Try to acquire lock - should detect stale lock and succeed
-	si := utils.NewSingleInstance("test-stale-app")
-	locked, err := si.TryLock()
-	if err != nil {
-		t.Fatalf("Failed to acquire lock with stale lock present: %v", err)
+func TestSingleInstance_Concurrent(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	const launches = 8
+	instances := make([]*utils.SingleInstance, launches)
+	results := make(chan bool, launches)
+	var wg sync.WaitGroup
+	for i := range instances {
+		instances[i] = utils.NewSingleInstance("test-concurrent-app")
+		wg.Add(1)
+		go func(si *utils.SingleInstance) {
+			defer wg.Done()
+			locked, err := si.TryLock()
+			if err != nil {
+				t.Errorf("TryLock failed: %v", err)
+			}
+			results <- locked
+		}(instances[i])
+	}
+	wg.Wait()
+	close(results)
+	defer func() {
+		for _, si := range instances {
+			si.Unlock()
+		}
+	}()
+
+	winners := 0
+	for locked := range results {
+		if locked {
+			winners++
+		}
+	}
+	if winners != 1 {
+		t.Fatalf("Expected exactly one launch to get the lock, got %d", winners)
 	}
-	if !locked {
-		t.Fatal("Expected to acquire lock (stale lock should be removed), but failed")
-	}
-
-	defer si.Unlock()
 }
 
 func TestSingleInstance_LockPath(t *testing.T) {
+	runtimeDir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+
 	si := utils.NewSingleInstance("test-path-app")
 	lockPath := si.GetLockPath()
 
-	// Verify lock path contains temp directory and app name
-	if lockPath == "" {
-		t.Fatal("Lock path is empty")
+	// The lock lives in the user's runtime directory
+	if filepath.Dir(lockPath) != runtimeDir {
+		t.Fatalf("Expected lock path to be in %s, got %s", runtimeDir, filepath.Dir(lockPath))
 	}
-
-	expectedDir := os.TempDir()
-	if filepath.Dir(lockPath) != expectedDir {
-		t.Fatalf("Expected lock path to be in %s, got %s", expectedDir, filepath.Dir(lockPath))
-	}
-
 	expectedName := "test-path-app.lock"
 	if filepath.Base(lockPath) != expectedName {
 		t.Fatalf("Expected lock file name to be %s, got %s", expectedName, filepath.Base(lockPath))
 	}
 }
+
+func TestSingleInstance_PerDataDirectory(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	dataDir := t.TempDir()
+
+	first := utils.NewSingleInstanceForDir("test-datadir-app", dataDir)
+	if locked, err := first.TryLock(); err != nil || !locked {
+		t.Fatalf("Expected to acquire lock, got %v, %v", locked, err)
+	}
+	defer first.Unlock()
+
+	// The same directory, spelled differently, is taken
+	same := utils.NewSingleInstanceForDir("test-datadir-app", dataDir+string(filepath.Separator)+".")
+	if locked, _ := same.TryLock(); locked {
+		t.Fatal("Expected the lock of the same data directory to be taken")
+	}
+
+	// Another directory runs side by side
+	other := utils.NewSingleInstanceForDir("test-datadir-app", t.TempDir())
+	if locked, err := other.TryLock(); err != nil || !locked {
+		t.Fatalf("Expected another data directory to get its own lock, got %v, %v", locked, err)
+	}
+	defer other.Unlock()
+}
+
+func TestSingleInstance_ForwardArguments(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	first := utils.NewSingleInstance("test-forward-app")
+	if locked, err := first.TryLock(); err != nil || !locked {
+		t.Fatalf("Expected to acquire lock, got %v, %v", locked, err)
+	}
+	defer first.Unlock()
+
+	received := make(chan []string, 1)
+	if err := first.Listen(func(args []string) { received <- args }); err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+
+	second := utils.NewSingleInstance("test-forward-app")
+	if locked, _ := second.TryLock(); locked {
+		t.Fatal("Expected second lock to fail")
+	}
+	want := []string{"--add", "Buy milk", "--date", "2026-10-20"}
+	if err := second.Forward(want); err != nil {
+		t.Fatalf("Forward failed: %v", err)
+	}
+
+	select {
+	case args := <-received:
+		if strings.Join(args, "|") != strings.Join(want, "|") {
+			t.Errorf("Expected %q, got %q", want, args)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Running instance did not receive the arguments")
+	}
+
+	// The socket goes away with the lock
+	first.Unlock()
+	if _, err := os.Stat(first.GetSocketPath()); !os.IsNotExist(err) {
+		t.Errorf("Expected socket to be removed, got %v", err)
+	}
+	if err := second.Forward(nil); err == nil {
+		t.Error("Expected forwarding to fail without a running instance")
+	}
+}
+
+func TestSingleInstance_ListenRequiresLock(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	si := utils.NewSingleInstance("test-listen-app")
+	if err := si.Listen(func([]string) {}); err == nil {
+		t.Error("Expected Listen to fail without the lock")
+	}
+}
@@ -0,0 +1,66 @@
+//go:build !windows
+// +build !windows
+
+package utils_test
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"testing"
+
+	"godo/src/utils"
+)
+
+// TestHelperHoldLock is not a test: run by TestSingleInstance_ReleasedOnCrash
+// in a child process, it takes the lock and waits to be killed
+func TestHelperHoldLock(t *testing.T) {
+	if os.Getenv("GODO_HOLD_LOCK") != "1" {
+		t.Skip("helper process")
+	}
+	si := utils.NewSingleInstance("test-crash-app")
+	if locked, err := si.TryLock(); err != nil || !locked {
+		fmt.Println("failed")
+		os.Exit(1)
+	}
+	fmt.Println("locked")
+	select {}
+}
+
+func TestSingleInstance_ReleasedOnCrash(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHelperHoldLock$")
+	cmd.Env = append(os.Environ(), "GODO_HOLD_LOCK=1")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start helper: %v", err)
+	}
+	defer cmd.Process.Kill()
+
+	line, _ := bufio.NewReader(stdout).ReadString('\n')
+	if line != "locked\n" {
+		t.Fatalf("Helper did not take the lock: %q", line)
+	}
+
+	si := utils.NewSingleInstance("test-crash-app")
+	if locked, _ := si.TryLock(); locked {
+		t.Fatal("Expected the lock to be held by the helper")
+	}
+
+	// A killed process leaves its lock file behind, but not the lock
+	cmd.Process.Kill()
+	cmd.Wait()
+	if _, err := os.Stat(si.GetLockPath()); err != nil {
+		t.Fatalf("Expected the lock file to be left behind: %v", err)
+	}
+	locked, err := si.TryLock()
+	if err != nil || !locked {
+		t.Fatalf("Expected to take over the lock of a crashed instance, got %v, %v", locked, err)
+	}
+	si.Unlock()
+}